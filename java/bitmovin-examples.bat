@@ -0,0 +1,3 @@
+if not exist target\bitmovin-api-sdk-example-1.0-SNAPSHOT-jar-with-dependencies.jar call mvn -q package
+java -cp target/bitmovin-api-sdk-example-1.0-SNAPSHOT-jar-with-dependencies.jar common.ExamplesCli %*
+exit /b %errorlevel%
@@ -1,5 +1,10 @@
-set file_name=%1
-shift
-
-mvn package
-java -cp target/bitmovin-api-sdk-example-1.0-SNAPSHOT-jar-with-dependencies.jar %file_name% %*
+@echo off
+rem in quiet mode, only the outcome of the example is written to stdout
+echo %* | findstr /c:"--quiet" >nul
+if %errorlevel% equ 0 (
+  call mvn -q package 1>&2
+) else (
+  call mvn package
+)
+java -cp target/bitmovin-api-sdk-example-1.0-SNAPSHOT-jar-with-dependencies.jar common.ExampleLauncher %*
+exit /b %errorlevel%
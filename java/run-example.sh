@@ -2,5 +2,10 @@
 file_name=$1
 shift
 
-mvn package
-java -cp target/bitmovin-api-sdk-example-1.0-SNAPSHOT-jar-with-dependencies.jar $file_name "$@"
+# in quiet mode, only the outcome of the example is written to stdout
+if [[ " $* " == *" --quiet "* ]]; then
+  mvn -q package >&2 || exit 1
+else
+  mvn package
+fi
+java -cp target/bitmovin-api-sdk-example-1.0-SNAPSHOT-jar-with-dependencies.jar common.ExampleLauncher $file_name "$@"
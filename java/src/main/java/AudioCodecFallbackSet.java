@@ -0,0 +1,575 @@
+import com.bitmovin.api.sdk.BitmovinApi;
+import com.bitmovin.api.sdk.common.BitmovinException;
+import com.bitmovin.api.sdk.model.AacAudioConfiguration;
+import com.bitmovin.api.sdk.model.AclEntry;
+import com.bitmovin.api.sdk.model.AclPermission;
+import com.bitmovin.api.sdk.model.AudioMediaInfo;
+import com.bitmovin.api.sdk.model.CodecConfiguration;
+import com.bitmovin.api.sdk.model.Encoding;
+import com.bitmovin.api.sdk.model.EncodingOutput;
+import com.bitmovin.api.sdk.model.Fmp4Muxing;
+import com.bitmovin.api.sdk.model.H264VideoConfiguration;
+import com.bitmovin.api.sdk.model.HeAacV1AudioConfiguration;
+import com.bitmovin.api.sdk.model.HeAacV2AudioConfiguration;
+import com.bitmovin.api.sdk.model.HlsManifest;
+import com.bitmovin.api.sdk.model.HttpInput;
+import com.bitmovin.api.sdk.model.Input;
+import com.bitmovin.api.sdk.model.MessageType;
+import com.bitmovin.api.sdk.model.MuxingStream;
+import com.bitmovin.api.sdk.model.Output;
+import com.bitmovin.api.sdk.model.PresetConfiguration;
+import com.bitmovin.api.sdk.model.S3Output;
+import com.bitmovin.api.sdk.model.StartEncodingRequest;
+import com.bitmovin.api.sdk.model.Status;
+import com.bitmovin.api.sdk.model.Stream;
+import com.bitmovin.api.sdk.model.StreamInfo;
+import com.bitmovin.api.sdk.model.StreamInput;
+import com.bitmovin.api.sdk.model.Task;
+import common.ApiClientFactory;
+import common.BudgetTag;
+import common.ConfigProvider;
+import common.EncodingFailedException;
+import common.PreviewTrimming;
+import common.SegmentSharding;
+import java.nio.file.Paths;
+import java.util.Arrays;
+import java.util.List;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+
+/**
+ * This example demonstrates how to deliver audio in multiple AAC profiles at different bitrates, so
+ * that players on constrained networks can fall back to more efficient low-bitrate audio. The
+ * following audio renditions are created from the same input:
+ *
+ * <ul>
+ *   <li>AAC-LC at 128 kbit/s for maximum compatibility
+ *   <li>HE-AACv1 at 64 kbit/s
+ *   <li>HE-AACv2 at 32 kbit/s, which is limited to stereo output
+ * </ul>
+ *
+ * <p>Every audio rendition is put into a separate HLS audio group, because all renditions within
+ * one group have to share the same codec. Each video rendition is then referenced once per audio
+ * group, so the CODECS attribute of every variant stream correctly describes the combination of
+ * video and audio codec.
+ *
+ * <p>Note that this example does not create an xHE-AAC rendition: the Bitmovin Java SDK version
+ * used by these examples (see pom.xml) has no codec configuration for xHE-AAC. Once the SDK is
+ * updated, it can be added as another audio group in the same way.
+ *
+ * <p>The following configuration parameters are expected:
+ *
+ * <ul>
+ *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin API
+ *   <li>BITMOVIN_TENANT_ORG_ID - (optional) The ID of the Organisation in which you want to perform
+ *       the encoding.
+ *   <li>BITMOVIN_API_BASE_URL - (optional) The base URL of the Bitmovin API, e.g. of a mock server
+ *       or an API gateway. Default: https://api.bitmovin.com/v1
+ *   <li>HTTP_INPUT_HOST - The Hostname or IP address of the HTTP server hosting your input files,
+ *       e.g.: my-storage.biz
+ *   <li>HTTP_INPUT_FILE_PATH - The path to your input file on the provided HTTP server Example:
+ *       videos/1080p_Sintel.mp4
+ *   <li>S3_OUTPUT_BUCKET_NAME - The name of your S3 output bucket. Example: my-bucket-name
+ *   <li>S3_OUTPUT_ACCESS_KEY - The access key of your S3 output bucket
+ *   <li>S3_OUTPUT_SECRET_KEY - The secret key of your S3 output bucket
+ *   <li>S3_OUTPUT_BASE_PATH - The base path on your S3 output bucket where content will be written.
+ *       Example: /outputs
+ * </ul>
+ *
+ * <p>Configuration parameters will be retrieved from these sources in the listed order:
+ *
+ * <ol>
+ *   <li>command line arguments (eg BITMOVIN_API_KEY=xyz)
+ *   <li>properties file located in the root folder of the JAVA examples at ./examples.properties
+ *       (see examples.properties.template as reference)
+ *   <li>environment variables
+ *   <li>properties file located in the home folder at ~/.bitmovin/examples.properties (see
+ *       examples.properties.template as reference)
+ * </ol>
+ */
+public class AudioCodecFallbackSet {
+  private static final Logger logger = LoggerFactory.getLogger(AudioCodecFallbackSet.class);
+
+  private static BitmovinApi bitmovinApi;
+  private static ConfigProvider configProvider;
+
+  /** This list defines the video renditions that will be generated */
+  private static List<VideoRendition> videoRenditions =
+      Arrays.asList(
+          new VideoRendition(1080, 4_800_000L),
+          new VideoRendition(720, 2_400_000L),
+          new VideoRendition(480, 1_200_000L),
+          new VideoRendition(360, 800_000L));
+
+  public static void main(String[] args) throws Exception {
+    configProvider = new ConfigProvider(args);
+    bitmovinApi =
+        ApiClientFactory.builder(configProvider)
+            .withApiKey(configProvider.getBitmovinApiKey())
+            .build();
+
+    Encoding encoding =
+        createEncoding(
+            "Audio codec fallback set", "Encoding with AAC-LC, HE-AACv1 and HE-AACv2 audio");
+
+    HttpInput input = createHttpInput(configProvider.getHttpInputHost());
+    Output output =
+        createS3Output(
+            configProvider.getS3OutputBucketName(),
+            configProvider.getS3OutputAccessKey(),
+            configProvider.getS3OutputSecretKey());
+
+    String inputFilePath = configProvider.getHttpInputFilePath();
+
+    for (VideoRendition videoRendition : videoRenditions) {
+      H264VideoConfiguration videoConfiguration =
+          createH264VideoConfig(videoRendition.height, videoRendition.bitrate);
+      videoRendition.stream = createStream(encoding, input, inputFilePath, videoConfiguration);
+      videoRendition.muxing =
+          createFmp4Muxing(
+              encoding, output, videoRendition.getSegmentPath(), videoRendition.stream);
+    }
+
+    List<AudioRendition> audioRenditions =
+        Arrays.asList(
+            new AudioRendition("audio-aac-lc", "AAC-LC", createAacAudioConfig()),
+            new AudioRendition("audio-he-aac-v1", "HE-AACv1", createHeAacV1AudioConfig()),
+            new AudioRendition("audio-he-aac-v2", "HE-AACv2", createHeAacV2AudioConfig()));
+
+    for (AudioRendition audioRendition : audioRenditions) {
+      audioRendition.stream =
+          createStream(encoding, input, inputFilePath, audioRendition.codecConfiguration);
+      audioRendition.muxing =
+          createFmp4Muxing(
+              encoding, output, audioRendition.getSegmentPath(), audioRendition.stream);
+    }
+
+    executeEncoding(encoding);
+
+    HlsManifest hlsManifest = createHlsMasterManifest("master.m3u8", output, "/");
+
+    for (AudioRendition audioRendition : audioRenditions) {
+      addAudioMediaInfo(encoding, hlsManifest, audioRendition);
+
+      for (VideoRendition videoRendition : videoRenditions) {
+        addVariantStream(encoding, hlsManifest, videoRendition, audioRendition);
+      }
+    }
+
+    executeHlsManifestCreation(hlsManifest);
+  }
+
+  /**
+   * Creates a configuration for the HE-AACv1 audio codec, which adds spectral band replication
+   * (SBR) to AAC-LC for better quality at low bitrates.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsAudioHeAacV1
+   */
+  private static HeAacV1AudioConfiguration createHeAacV1AudioConfig() throws BitmovinException {
+    HeAacV1AudioConfiguration config = new HeAacV1AudioConfiguration();
+    config.setName("HE-AACv1 64 kbit/s");
+    config.setBitrate(64_000L);
+
+    return bitmovinApi.encoding.configurations.audio.heAacV1.create(config);
+  }
+
+  /**
+   * Creates a configuration for the HE-AACv2 audio codec, which additionally uses parametric stereo
+   * and is therefore best suited for very low bitrate stereo audio.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsAudioHeAacV2
+   */
+  private static HeAacV2AudioConfiguration createHeAacV2AudioConfig() throws BitmovinException {
+    HeAacV2AudioConfiguration config = new HeAacV2AudioConfiguration();
+    config.setName("HE-AACv2 32 kbit/s");
+    config.setBitrate(32_000L);
+
+    return bitmovinApi.encoding.configurations.audio.heAacV2.create(config);
+  }
+
+  /**
+   * Adds the audio media playlist of an audio rendition to the HLS manifest. The group ID of the
+   * rendition is used to reference it from the variant streams.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsHlsMediaAudioByManifestId
+   *
+   * @param encoding The encoding to which the audio stream belongs to
+   * @param manifest The HLS manifest to add the media playlist to
+   * @param audioRendition The audio rendition to add
+   */
+  private static void addAudioMediaInfo(
+      Encoding encoding, HlsManifest manifest, AudioRendition audioRendition)
+      throws BitmovinException {
+    AudioMediaInfo audioMediaInfo = new AudioMediaInfo();
+    audioMediaInfo.setName(String.format("English (%s)", audioRendition.name));
+    audioMediaInfo.setUri(audioRendition.groupId + ".m3u8");
+    audioMediaInfo.setGroupId(audioRendition.groupId);
+    audioMediaInfo.setEncodingId(encoding.getId());
+    audioMediaInfo.setStreamId(audioRendition.stream.getId());
+    audioMediaInfo.setMuxingId(audioRendition.muxing.getId());
+    audioMediaInfo.setLanguage("en");
+    audioMediaInfo.setAutoselect(true);
+    audioMediaInfo.setIsDefault(true);
+    audioMediaInfo.setSegmentPath(audioRendition.getSegmentPath());
+
+    bitmovinApi.encoding.manifests.hls.media.audio.create(manifest.getId(), audioMediaInfo);
+  }
+
+  /**
+   * Adds a variant stream to the HLS manifest, which combines a video rendition with the audio
+   * group of an audio rendition.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsHlsStreamsByManifestId
+   *
+   * @param encoding The encoding to which the streams belong to
+   * @param manifest The HLS manifest to add the variant stream to
+   * @param videoRendition The video rendition of the variant stream
+   * @param audioRendition The audio rendition referenced by the variant stream
+   */
+  private static void addVariantStream(
+      Encoding encoding,
+      HlsManifest manifest,
+      VideoRendition videoRendition,
+      AudioRendition audioRendition)
+      throws BitmovinException {
+    StreamInfo streamInfo = new StreamInfo();
+    streamInfo.setUri(
+        String.format("video_%dp_%s.m3u8", videoRendition.height, audioRendition.groupId));
+    streamInfo.setEncodingId(encoding.getId());
+    streamInfo.setStreamId(videoRendition.stream.getId());
+    streamInfo.setMuxingId(videoRendition.muxing.getId());
+    streamInfo.setAudio(audioRendition.groupId);
+    streamInfo.setSegmentPath(videoRendition.getSegmentPath());
+
+    bitmovinApi.encoding.manifests.hls.streams.create(manifest.getId(), streamInfo);
+  }
+
+  private static class VideoRendition {
+
+    private int height;
+    private long bitrate;
+    private Stream stream;
+    private Fmp4Muxing muxing;
+
+    /**
+     * @param height The target output height of the rendition
+     * @param bitrate The target output bitrate of the rendition
+     */
+    private VideoRendition(int height, long bitrate) {
+      this.height = height;
+      this.bitrate = bitrate;
+    }
+
+    private String getSegmentPath() {
+      return "video/" + height;
+    }
+  }
+
+  private static class AudioRendition {
+
+    private String groupId;
+    private String name;
+    private CodecConfiguration codecConfiguration;
+    private Stream stream;
+    private Fmp4Muxing muxing;
+
+    /**
+     * @param groupId The HLS audio group the rendition is added to
+     * @param name A human readable name of the audio codec
+     * @param codecConfiguration The codec configuration applied to the audio stream
+     */
+    private AudioRendition(String groupId, String name, CodecConfiguration codecConfiguration) {
+      this.groupId = groupId;
+      this.name = name;
+      this.codecConfiguration = codecConfiguration;
+    }
+
+    private String getSegmentPath() {
+      return "audio/" + groupId;
+    }
+  }
+
+  /** Creates the HLS master manifest. */
+  private static HlsManifest createHlsMasterManifest(String name, Output output, String outputPath)
+      throws BitmovinException {
+
+    HlsManifest hlsManifest = new HlsManifest();
+    hlsManifest.setName(name);
+    hlsManifest.addOutputsItem(buildEncodingOutput(output, outputPath));
+
+    return bitmovinApi.encoding.manifests.hls.create(hlsManifest);
+  }
+
+  /**
+   * Creates a fragmented MP4 muxing. This will generate segments with a given segment length for
+   * adaptive streaming.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/all#/Encoding/PostEncodingEncodingsMuxingsFmp4ByEncodingId
+   *
+   * @param encoding The encoding where to add the muxing to
+   * @param output The output that should be used for the muxing to write the segments to
+   * @param outputPath The output path where the fragmented segments will be written to
+   * @param stream The stream that is associated with the muxing
+   */
+  private static Fmp4Muxing createFmp4Muxing(
+      Encoding encoding, Output output, String outputPath, Stream stream) throws BitmovinException {
+    MuxingStream muxingStream = new MuxingStream();
+    muxingStream.setStreamId(stream.getId());
+
+    Fmp4Muxing muxing = new Fmp4Muxing();
+    muxing.addOutputsItem(buildEncodingOutput(output, outputPath));
+    muxing.addStreamsItem(muxingStream);
+    muxing.setSegmentLength(4.0);
+    SegmentSharding.apply(configProvider, muxing);
+
+    return bitmovinApi.encoding.encodings.muxings.fmp4.create(encoding.getId(), muxing);
+  }
+
+  /**
+   * Creates a resource representing an HTTP server providing the input files. For alternative input
+   * methods see <a
+   * href="https://bitmovin.com/docs/encoding/articles/supported-input-output-storages">list of
+   * supported input and output storages</a>
+   *
+   * <p>For reasons of simplicity, a new input resource is created on each execution of this
+   * example. In production use, this method should be replaced by a <a
+   * href="https://bitmovin.com/docs/encoding/api-reference/sections/inputs#/Encoding/GetEncodingInputsHttpByInputId">get
+   * call</a> to retrieve an existing resource.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/inputs#/Encoding/PostEncodingInputsHttp
+   *
+   * @param host The hostname or IP address of the HTTP server e.g.: my-storage.biz
+   */
+  private static HttpInput createHttpInput(String host) throws BitmovinException {
+    HttpInput input = new HttpInput();
+    input.setHost(host);
+
+    return bitmovinApi.encoding.inputs.http.create(input);
+  }
+
+  /**
+   * Creates a resource representing an AWS S3 cloud storage bucket to which generated content will
+   * be transferred. For alternative output methods see <a
+   * href="https://bitmovin.com/docs/encoding/articles/supported-input-output-storages">list of
+   * supported input and output storages</a>
+   *
+   * <p>The provided credentials need to allow <i>read</i>, <i>write</i> and <i>list</i> operations.
+   * <i>delete</i> should also be granted to allow overwriting of existings files. See <a
+   * href="https://bitmovin.com/docs/encoding/faqs/how-do-i-create-a-aws-s3-bucket-which-can-be-used-as-output-location">creating
+   * an S3 bucket and setting permissions</a> for further information
+   *
+   * <p>For reasons of simplicity, a new output resource is created on each execution of this
+   * example. In production use, this method should be replaced by a <a
+   * href="https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/GetEncodingOutputsS3">get
+   * call</a> retrieving an existing resource.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/PostEncodingOutputsS3
+   *
+   * @param bucketName The name of the S3 bucket
+   * @param accessKey The access key of your S3 account
+   * @param secretKey The secret key of your S3 account
+   */
+  private static S3Output createS3Output(String bucketName, String accessKey, String secretKey)
+      throws BitmovinException {
+
+    S3Output s3Output = new S3Output();
+    s3Output.setBucketName(bucketName);
+    s3Output.setAccessKey(accessKey);
+    s3Output.setSecretKey(secretKey);
+
+    return bitmovinApi.encoding.outputs.s3.create(s3Output);
+  }
+
+  /**
+   * Creates an encoding object. This is the base object to configure your encoding.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodings
+   *
+   * @param name This is the name of the encoding
+   * @param description This is the description of the encoding
+   */
+  private static Encoding createEncoding(String name, String description) throws BitmovinException {
+    Encoding encoding = new Encoding();
+    encoding.setName(name);
+    encoding.setDescription(description);
+    new BudgetTag(configProvider).applyTo(encoding);
+
+    return bitmovinApi.encoding.encodings.create(encoding);
+  }
+
+  /**
+   * Creates a stream which binds an input file to a codec configuration. The stream is used for
+   * muxings later on.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsStreamsByEncodingId
+   *
+   * @param encoding The encoding to add the stream onto
+   * @param input The input that should be used
+   * @param inputPath The path to the input file
+   * @param codecConfiguration The codec configuration to be applied to the stream
+   */
+  private static Stream createStream(
+      Encoding encoding, Input input, String inputPath, CodecConfiguration codecConfiguration)
+      throws BitmovinException {
+    StreamInput streamInput =
+        PreviewTrimming.createStreamInput(bitmovinApi, configProvider, encoding, input, inputPath);
+
+    Stream stream = new Stream();
+    stream.addInputStreamsItem(streamInput);
+    stream.setCodecConfigId(codecConfiguration.getId());
+
+    return bitmovinApi.encoding.encodings.streams.create(encoding.getId(), stream);
+  }
+
+  /**
+   * Creates a configuration for the H.264 video codec to be applied to video streams.
+   *
+   * <p>The output resolution is defined by setting only the height. Width will be determined
+   * automatically to maintain the aspect ratio of your input video.
+   *
+   * <p>To keep things simple, we use a quality-optimized VoD preset configuration, which will apply
+   * proven settings for the codec. See <a
+   * href="https://bitmovin.com/docs/encoding/tutorials/how-to-optimize-your-h264-codec-configuration-for-different-use-cases">How
+   * to optimize your H264 codec configuration for different use-cases</a> for alternative presets.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsVideoH264
+   *
+   * @param height The height of the output video
+   * @param bitrate The target bitrate of the output video
+   */
+  private static H264VideoConfiguration createH264VideoConfig(int height, long bitrate)
+      throws BitmovinException {
+    H264VideoConfiguration config = new H264VideoConfiguration();
+    config.setName(String.format("H.264 %dp", height));
+    config.setPresetConfiguration(PresetConfiguration.VOD_STANDARD);
+    config.setHeight(height);
+    config.setBitrate(bitrate);
+
+    return bitmovinApi.encoding.configurations.video.h264.create(config);
+  }
+
+  /**
+   * Creates a configuration for the AAC audio codec to be applied to audio streams.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsAudioAac
+   */
+  private static AacAudioConfiguration createAacAudioConfig() throws BitmovinException {
+    AacAudioConfiguration config = new AacAudioConfiguration();
+    config.setName("AAC 128 kbit/s");
+    config.setBitrate(128_000L);
+
+    return bitmovinApi.encoding.configurations.audio.aac.create(config);
+  }
+
+  /**
+   * Builds an EncodingOutput object which defines where the output content (e.g. of a muxing) will
+   * be written to. Public read permissions will be set for the files written, so they can be
+   * accessed easily via HTTP.
+   *
+   * @param output The output resource to be used by the EncodingOutput
+   * @param outputPath The path where the content will be written to
+   */
+  private static EncodingOutput buildEncodingOutput(Output output, String outputPath) {
+    AclEntry aclEntry = new AclEntry();
+    aclEntry.setPermission(AclPermission.PUBLIC_READ);
+
+    EncodingOutput encodingOutput = new EncodingOutput();
+    encodingOutput.setOutputPath(buildAbsolutePath(outputPath));
+    encodingOutput.setOutputId(output.getId());
+    encodingOutput.addAclItem(aclEntry);
+    return encodingOutput;
+  }
+
+  /**
+   * Builds an absolute path by concatenating the S3_OUTPUT_BASE_PATH configuration parameter, the
+   * name of this example class and the given relative path
+   *
+   * <p>e.g.: /s3/base/path/ClassName/relative/path
+   *
+   * @param relativePath The relative path that is concatenated
+   * @return The absolute path
+   */
+  public static String buildAbsolutePath(String relativePath) {
+    String className = AudioCodecFallbackSet.class.getSimpleName();
+    return Paths.get(configProvider.getS3OutputBasePath(), className, relativePath).toString();
+  }
+
+  /**
+   * Starts the actual encoding process and periodically polls its status until it reaches a final
+   * state
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/all#/Encoding/PostEncodingEncodingsStartByEncodingId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/GetEncodingEncodingsStatusByEncodingId
+   *
+   * <p>Please note that you can also use our webhooks API instead of polling the status. For more
+   * information consult the API spec:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/notifications-webhooks
+   *
+   * @param encoding The encoding to be started
+   */
+  private static void executeEncoding(Encoding encoding)
+      throws InterruptedException, BitmovinException {
+    bitmovinApi.encoding.encodings.start(encoding.getId(), new StartEncodingRequest());
+
+    Task task;
+    do {
+      Thread.sleep(5000);
+      task = bitmovinApi.encoding.encodings.status(encoding.getId());
+      logger.info("encoding status is {} (progress: {} %)", task.getStatus(), task.getProgress());
+    } while (task.getStatus() != Status.FINISHED
+        && task.getStatus() != Status.ERROR
+        && task.getStatus() != Status.CANCELED);
+
+    if (task.getStatus() != Status.FINISHED) {
+      logTaskErrors(task);
+      throw new EncodingFailedException(task.getStatus());
+    }
+    logger.info("encoding finished successfully");
+  }
+
+  /**
+   * Starts the HLS manifest creation and periodically polls its status until it reaches a final
+   * state
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsHlsStartByManifestId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/GetEncodingManifestsHlsStatusByManifestId
+   *
+   * @param hlsManifest The HLS manifest to be created
+   */
+  private static void executeHlsManifestCreation(HlsManifest hlsManifest)
+      throws BitmovinException, InterruptedException {
+
+    bitmovinApi.encoding.manifests.hls.start(hlsManifest.getId());
+
+    Task task;
+    do {
+      Thread.sleep(1000);
+      task = bitmovinApi.encoding.manifests.hls.status(hlsManifest.getId());
+    } while (task.getStatus() != Status.FINISHED && task.getStatus() != Status.ERROR);
+
+    if (task.getStatus() == Status.ERROR) {
+      logTaskErrors(task);
+      throw new RuntimeException("HLS manifest creation failed");
+    }
+    logger.info("HLS manifest creation finished successfully");
+  }
+
+  private static void logTaskErrors(Task task) {
+    task.getMessages().stream()
+        .filter(msg -> msg.getType() == MessageType.ERROR)
+        .forEach(msg -> logger.error(msg.getText()));
+  }
+}
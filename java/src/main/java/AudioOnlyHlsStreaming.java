@@ -0,0 +1,473 @@
+import com.bitmovin.api.sdk.BitmovinApi;
+import com.bitmovin.api.sdk.common.BitmovinException;
+import com.bitmovin.api.sdk.model.AacAudioConfiguration;
+import com.bitmovin.api.sdk.model.AclEntry;
+import com.bitmovin.api.sdk.model.AclPermission;
+import com.bitmovin.api.sdk.model.CodecConfiguration;
+import com.bitmovin.api.sdk.model.Encoding;
+import com.bitmovin.api.sdk.model.EncodingOutput;
+import com.bitmovin.api.sdk.model.Fmp4Muxing;
+import com.bitmovin.api.sdk.model.HlsManifest;
+import com.bitmovin.api.sdk.model.HttpInput;
+import com.bitmovin.api.sdk.model.Input;
+import com.bitmovin.api.sdk.model.MessageType;
+import com.bitmovin.api.sdk.model.Muxing;
+import com.bitmovin.api.sdk.model.MuxingStream;
+import com.bitmovin.api.sdk.model.Output;
+import com.bitmovin.api.sdk.model.S3Output;
+import com.bitmovin.api.sdk.model.StartEncodingRequest;
+import com.bitmovin.api.sdk.model.Status;
+import com.bitmovin.api.sdk.model.Stream;
+import com.bitmovin.api.sdk.model.StreamInfo;
+import com.bitmovin.api.sdk.model.StreamInput;
+import com.bitmovin.api.sdk.model.Task;
+import com.bitmovin.api.sdk.model.TsMuxing;
+import common.ApiClientFactory;
+import common.BudgetTag;
+import common.ConfigProvider;
+import common.EncodingFailedException;
+import common.PreviewTrimming;
+import common.SegmentSharding;
+import java.nio.file.Paths;
+import java.util.Arrays;
+import java.util.List;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+
+/**
+ * This example demonstrates how to create an audio-only HLS stream, e.g. for music or radio
+ * streaming services, which do not need a video track at all.
+ *
+ * <p>The audio track of the input file is encoded into an AAC bitrate ladder. Every rendition is
+ * packaged twice:
+ *
+ * <ul>
+ *   <li>as fragmented MP4 segments, which are supported by current players on all platforms
+ *   <li>as MPEG-TS segments, which carry the AAC frames in ADTS format and are supported by older
+ *       devices, set-top boxes and car infotainment systems
+ * </ul>
+ *
+ * <p>For each packaging format, an HLS master playlist is created which contains one variant stream
+ * per audio rendition, so the player can switch between the bitrates depending on the available
+ * bandwidth. As the variant streams do not reference any video, the master playlists only announce
+ * the AAC codec.
+ *
+ * <p>The following configuration parameters are expected:
+ *
+ * <ul>
+ *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin API
+ *   <li>BITMOVIN_TENANT_ORG_ID - (optional) The ID of the Organisation in which you want to perform
+ *       the encoding.
+ *   <li>BITMOVIN_API_BASE_URL - (optional) The base URL of the Bitmovin API, e.g. of a mock server
+ *       or an API gateway. Default: https://api.bitmovin.com/v1
+ *   <li>HTTP_INPUT_HOST - The Hostname or IP address of the HTTP server hosting your input files,
+ *       e.g.: my-storage.biz
+ *   <li>HTTP_INPUT_FILE_PATH - The path to your input file on the provided HTTP server Example:
+ *       videos/1080p_Sintel.mp4
+ *   <li>S3_OUTPUT_BUCKET_NAME - The name of your S3 output bucket. Example: my-bucket-name
+ *   <li>S3_OUTPUT_ACCESS_KEY - The access key of your S3 output bucket
+ *   <li>S3_OUTPUT_SECRET_KEY - The secret key of your S3 output bucket
+ *   <li>S3_OUTPUT_BASE_PATH - The base path on your S3 output bucket where content will be written.
+ *       Example: /outputs
+ * </ul>
+ *
+ * <p>Configuration parameters will be retrieved from these sources in the listed order:
+ *
+ * <ol>
+ *   <li>command line arguments (eg BITMOVIN_API_KEY=xyz)
+ *   <li>properties file located in the root folder of the JAVA examples at ./examples.properties
+ *       (see examples.properties.template as reference)
+ *   <li>environment variables
+ *   <li>properties file located in the home folder at ~/.bitmovin/examples.properties (see
+ *       examples.properties.template as reference)
+ * </ol>
+ */
+public class AudioOnlyHlsStreaming {
+  private static final Logger logger = LoggerFactory.getLogger(AudioOnlyHlsStreaming.class);
+
+  private static BitmovinApi bitmovinApi;
+  private static ConfigProvider configProvider;
+
+  /** This list defines the audio renditions that will be generated */
+  private static List<AudioRendition> audioRenditions =
+      Arrays.asList(
+          new AudioRendition(256_000L), new AudioRendition(128_000L), new AudioRendition(64_000L));
+
+  public static void main(String[] args) throws Exception {
+    configProvider = new ConfigProvider(args);
+    bitmovinApi =
+        ApiClientFactory.builder(configProvider)
+            .withApiKey(configProvider.getBitmovinApiKey())
+            .build();
+
+    Encoding encoding =
+        createEncoding("Audio-only HLS", "Encoding with fMP4 and TS audio-only renditions");
+
+    HttpInput input = createHttpInput(configProvider.getHttpInputHost());
+    Output output =
+        createS3Output(
+            configProvider.getS3OutputBucketName(),
+            configProvider.getS3OutputAccessKey(),
+            configProvider.getS3OutputSecretKey());
+
+    String inputFilePath = configProvider.getHttpInputFilePath();
+
+    for (AudioRendition audioRendition : audioRenditions) {
+      AacAudioConfiguration aacConfig = createAacAudioConfig(audioRendition.bitrate);
+      audioRendition.stream = createStream(encoding, input, inputFilePath, aacConfig);
+      audioRendition.fmp4Muxing =
+          createFmp4Muxing(
+              encoding, output, audioRendition.getSegmentPath("fmp4"), audioRendition.stream);
+      audioRendition.tsMuxing =
+          createTsMuxing(
+              encoding, output, audioRendition.getSegmentPath("ts"), audioRendition.stream);
+    }
+
+    executeEncoding(encoding);
+
+    HlsManifest fmp4Manifest = createHlsMasterManifest("audio_fmp4.m3u8", output, "/");
+    for (AudioRendition audioRendition : audioRenditions) {
+      addAudioVariantStream(
+          encoding, fmp4Manifest, audioRendition, audioRendition.fmp4Muxing, "fmp4");
+    }
+    executeHlsManifestCreation(fmp4Manifest);
+
+    HlsManifest tsManifest = createHlsMasterManifest("audio_ts.m3u8", output, "/");
+    for (AudioRendition audioRendition : audioRenditions) {
+      addAudioVariantStream(encoding, tsManifest, audioRendition, audioRendition.tsMuxing, "ts");
+    }
+    executeHlsManifestCreation(tsManifest);
+  }
+
+  /**
+   * Creates a configuration for the AAC audio codec with the given bitrate
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsAudioAac
+   *
+   * @param bitrate The target output bitrate of the audio rendition
+   */
+  private static AacAudioConfiguration createAacAudioConfig(long bitrate)
+      throws BitmovinException {
+    AacAudioConfiguration config = new AacAudioConfiguration();
+    config.setName(String.format("AAC %d kbit/s", bitrate / 1000));
+    config.setBitrate(bitrate);
+
+    return bitmovinApi.encoding.configurations.audio.aac.create(config);
+  }
+
+  /**
+   * Adds a variant stream for an audio rendition to an HLS master playlist. As no video stream is
+   * referenced, the variant stream is played as audio-only stream.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsHlsStreamsByManifestId
+   *
+   * @param encoding The encoding to which the muxing belongs to
+   * @param hlsManifest The HLS manifest to add the variant stream to
+   * @param audioRendition The audio rendition
+   * @param muxing The muxing holding the segments of the audio rendition
+   * @param format The packaging format of the muxing, which is part of its segment path
+   */
+  private static void addAudioVariantStream(
+      Encoding encoding,
+      HlsManifest hlsManifest,
+      AudioRendition audioRendition,
+      Muxing muxing,
+      String format)
+      throws BitmovinException {
+    StreamInfo streamInfo = new StreamInfo();
+    streamInfo.setUri(audioRendition.getPlaylistName(format));
+    streamInfo.setEncodingId(encoding.getId());
+    streamInfo.setStreamId(audioRendition.stream.getId());
+    streamInfo.setMuxingId(muxing.getId());
+    streamInfo.setSegmentPath(audioRendition.getSegmentPath(format));
+
+    bitmovinApi.encoding.manifests.hls.streams.create(hlsManifest.getId(), streamInfo);
+  }
+
+  private static class AudioRendition {
+
+    private long bitrate;
+    private Stream stream;
+    private Fmp4Muxing fmp4Muxing;
+    private TsMuxing tsMuxing;
+
+    /** @param bitrate The target output bitrate of the audio rendition */
+    private AudioRendition(long bitrate) {
+      this.bitrate = bitrate;
+    }
+
+    private String getSegmentPath(String format) {
+      return String.format("audio/%s/%d", format, bitrate / 1000);
+    }
+
+    private String getPlaylistName(String format) {
+      return String.format("audio_%s_%dk.m3u8", format, bitrate / 1000);
+    }
+  }
+
+  /** Creates the HLS master manifest. */
+  private static HlsManifest createHlsMasterManifest(String name, Output output, String outputPath)
+      throws BitmovinException {
+
+    HlsManifest hlsManifest = new HlsManifest();
+    hlsManifest.setName(name);
+    hlsManifest.addOutputsItem(buildEncodingOutput(output, outputPath));
+
+    return bitmovinApi.encoding.manifests.hls.create(hlsManifest);
+  }
+
+  /**
+   * Creates a resource representing an HTTP server providing the input files. For alternative input
+   * methods see <a
+   * href="https://bitmovin.com/docs/encoding/articles/supported-input-output-storages">list of
+   * supported input and output storages</a>
+   *
+   * <p>For reasons of simplicity, a new input resource is created on each execution of this
+   * example. In production use, this method should be replaced by a <a
+   * href="https://bitmovin.com/docs/encoding/api-reference/sections/inputs#/Encoding/GetEncodingInputsHttpByInputId">get
+   * call</a> to retrieve an existing resource.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/inputs#/Encoding/PostEncodingInputsHttp
+   *
+   * @param host The hostname or IP address of the HTTP server e.g.: my-storage.biz
+   */
+  private static HttpInput createHttpInput(String host) throws BitmovinException {
+    HttpInput input = new HttpInput();
+    input.setHost(host);
+
+    return bitmovinApi.encoding.inputs.http.create(input);
+  }
+
+  /**
+   * Creates a resource representing an AWS S3 cloud storage bucket to which generated content will
+   * be transferred. For alternative output methods see <a
+   * href="https://bitmovin.com/docs/encoding/articles/supported-input-output-storages">list of
+   * supported input and output storages</a>
+   *
+   * <p>The provided credentials need to allow <i>read</i>, <i>write</i> and <i>list</i> operations.
+   * <i>delete</i> should also be granted to allow overwriting of existings files. See <a
+   * href="https://bitmovin.com/docs/encoding/faqs/how-do-i-create-a-aws-s3-bucket-which-can-be-used-as-output-location">creating
+   * an S3 bucket and setting permissions</a> for further information
+   *
+   * <p>For reasons of simplicity, a new output resource is created on each execution of this
+   * example. In production use, this method should be replaced by a <a
+   * href="https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/GetEncodingOutputsS3">get
+   * call</a> retrieving an existing resource.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/PostEncodingOutputsS3
+   *
+   * @param bucketName The name of the S3 bucket
+   * @param accessKey The access key of your S3 account
+   * @param secretKey The secret key of your S3 account
+   */
+  private static S3Output createS3Output(String bucketName, String accessKey, String secretKey)
+      throws BitmovinException {
+
+    S3Output s3Output = new S3Output();
+    s3Output.setBucketName(bucketName);
+    s3Output.setAccessKey(accessKey);
+    s3Output.setSecretKey(secretKey);
+
+    return bitmovinApi.encoding.outputs.s3.create(s3Output);
+  }
+
+  /**
+   * Creates an encoding object. This is the base object to configure your encoding.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodings
+   *
+   * @param name This is the name of the encoding
+   * @param description This is the description of the encoding
+   */
+  private static Encoding createEncoding(String name, String description) throws BitmovinException {
+    Encoding encoding = new Encoding();
+    encoding.setName(name);
+    encoding.setDescription(description);
+    new BudgetTag(configProvider).applyTo(encoding);
+
+    return bitmovinApi.encoding.encodings.create(encoding);
+  }
+
+  /**
+   * Creates a stream which binds an input file to a codec configuration. The stream is used for
+   * muxings later on.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsStreamsByEncodingId
+   *
+   * @param encoding The encoding to add the stream onto
+   * @param input The input that should be used
+   * @param inputPath The path to the input file
+   * @param codecConfiguration The codec configuration to be applied to the stream
+   */
+  private static Stream createStream(
+      Encoding encoding, Input input, String inputPath, CodecConfiguration codecConfiguration)
+      throws BitmovinException {
+    StreamInput streamInput =
+        PreviewTrimming.createStreamInput(bitmovinApi, configProvider, encoding, input, inputPath);
+
+    Stream stream = new Stream();
+    stream.addInputStreamsItem(streamInput);
+    stream.setCodecConfigId(codecConfiguration.getId());
+
+    return bitmovinApi.encoding.encodings.streams.create(encoding.getId(), stream);
+  }
+
+  /**
+   * Creates a fragmented MP4 muxing. This will generate segments with a given segment length for
+   * adaptive streaming.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/all#/Encoding/PostEncodingEncodingsMuxingsFmp4ByEncodingId
+   *
+   * @param encoding The encoding where to add the muxing to
+   * @param output The output that should be used for the muxing to write the segments to
+   * @param outputPath The output path where the fragmented segments will be written to
+   * @param stream The stream that is associated with the muxing
+   */
+  private static Fmp4Muxing createFmp4Muxing(
+      Encoding encoding, Output output, String outputPath, Stream stream) throws BitmovinException {
+    MuxingStream muxingStream = new MuxingStream();
+    muxingStream.setStreamId(stream.getId());
+
+    Fmp4Muxing muxing = new Fmp4Muxing();
+    muxing.addOutputsItem(buildEncodingOutput(output, outputPath));
+    muxing.addStreamsItem(muxingStream);
+    muxing.setSegmentLength(4.0);
+    SegmentSharding.apply(configProvider, muxing);
+
+    return bitmovinApi.encoding.encodings.muxings.fmp4.create(encoding.getId(), muxing);
+  }
+
+  /**
+   * Creates a TS muxing. This will generate segments with a given segment length for
+   * adaptive streaming.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/all#/Encoding/PostEncodingEncodingsMuxingsTsByEncodingId
+   *
+   * @param encoding The encoding where to add the muxing to
+   * @param output The output that should be used for the muxing to write the segments to
+   * @param outputPath The output path where the segments will be written to
+   * @param stream The stream that is associated with the muxing
+   */
+  private static TsMuxing createTsMuxing(
+      Encoding encoding, Output output, String outputPath, Stream stream) throws BitmovinException {
+    MuxingStream muxingStream = new MuxingStream();
+    muxingStream.setStreamId(stream.getId());
+
+    TsMuxing muxing = new TsMuxing();
+    muxing.addOutputsItem(buildEncodingOutput(output, outputPath));
+    muxing.addStreamsItem(muxingStream);
+    muxing.setSegmentLength(4.0);
+    SegmentSharding.apply(configProvider, muxing);
+
+    return bitmovinApi.encoding.encodings.muxings.ts.create(encoding.getId(), muxing);
+  }
+
+  /**
+   * Builds an EncodingOutput object which defines where the output content (e.g. of a muxing) will
+   * be written to. Public read permissions will be set for the files written, so they can be
+   * accessed easily via HTTP.
+   *
+   * @param output The output resource to be used by the EncodingOutput
+   * @param outputPath The path where the content will be written to
+   */
+  private static EncodingOutput buildEncodingOutput(Output output, String outputPath) {
+    AclEntry aclEntry = new AclEntry();
+    aclEntry.setPermission(AclPermission.PUBLIC_READ);
+
+    EncodingOutput encodingOutput = new EncodingOutput();
+    encodingOutput.setOutputPath(buildAbsolutePath(outputPath));
+    encodingOutput.setOutputId(output.getId());
+    encodingOutput.addAclItem(aclEntry);
+    return encodingOutput;
+  }
+
+  /**
+   * Builds an absolute path by concatenating the S3_OUTPUT_BASE_PATH configuration parameter, the
+   * name of this example class and the given relative path
+   *
+   * <p>e.g.: /s3/base/path/ClassName/relative/path
+   *
+   * @param relativePath The relative path that is concatenated
+   * @return The absolute path
+   */
+  public static String buildAbsolutePath(String relativePath) {
+    String className = AudioOnlyHlsStreaming.class.getSimpleName();
+    return Paths.get(configProvider.getS3OutputBasePath(), className, relativePath).toString();
+  }
+
+  /**
+   * Starts the actual encoding process and periodically polls its status until it reaches a final
+   * state
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/all#/Encoding/PostEncodingEncodingsStartByEncodingId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/GetEncodingEncodingsStatusByEncodingId
+   *
+   * <p>Please note that you can also use our webhooks API instead of polling the status. For more
+   * information consult the API spec:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/notifications-webhooks
+   *
+   * @param encoding The encoding to be started
+   */
+  private static void executeEncoding(Encoding encoding)
+      throws InterruptedException, BitmovinException {
+    bitmovinApi.encoding.encodings.start(encoding.getId(), new StartEncodingRequest());
+
+    Task task;
+    do {
+      Thread.sleep(5000);
+      task = bitmovinApi.encoding.encodings.status(encoding.getId());
+      logger.info("encoding status is {} (progress: {} %)", task.getStatus(), task.getProgress());
+    } while (task.getStatus() != Status.FINISHED
+        && task.getStatus() != Status.ERROR
+        && task.getStatus() != Status.CANCELED);
+
+    if (task.getStatus() != Status.FINISHED) {
+      logTaskErrors(task);
+      throw new EncodingFailedException(task.getStatus());
+    }
+    logger.info("encoding finished successfully");
+  }
+
+  /**
+   * Starts the HLS manifest creation and periodically polls its status until it reaches a final
+   * state
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsHlsStartByManifestId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/GetEncodingManifestsHlsStatusByManifestId
+   *
+   * @param hlsManifest The HLS manifest to be created
+   */
+  private static void executeHlsManifestCreation(HlsManifest hlsManifest)
+      throws BitmovinException, InterruptedException {
+
+    bitmovinApi.encoding.manifests.hls.start(hlsManifest.getId());
+
+    Task task;
+    do {
+      Thread.sleep(1000);
+      task = bitmovinApi.encoding.manifests.hls.status(hlsManifest.getId());
+    } while (task.getStatus() != Status.FINISHED && task.getStatus() != Status.ERROR);
+
+    if (task.getStatus() == Status.ERROR) {
+      logTaskErrors(task);
+      throw new RuntimeException("HLS manifest creation failed");
+    }
+    logger.info("HLS manifest creation finished successfully");
+  }
+
+  private static void logTaskErrors(Task task) {
+    task.getMessages().stream()
+        .filter(msg -> msg.getType() == MessageType.ERROR)
+        .forEach(msg -> logger.error(msg.getText()));
+  }
+}
@@ -0,0 +1,577 @@
+import com.bitmovin.api.sdk.BitmovinApi;
+import com.bitmovin.api.sdk.common.BitmovinException;
+import com.bitmovin.api.sdk.model.AacAudioConfiguration;
+import com.bitmovin.api.sdk.model.AclEntry;
+import com.bitmovin.api.sdk.model.AclPermission;
+import com.bitmovin.api.sdk.model.AwsAccount;
+import com.bitmovin.api.sdk.model.AwsAccountRegionSettings;
+import com.bitmovin.api.sdk.model.AwsCloudRegion;
+import com.bitmovin.api.sdk.model.CloudRegion;
+import com.bitmovin.api.sdk.model.CodecConfiguration;
+import com.bitmovin.api.sdk.model.DashManifest;
+import com.bitmovin.api.sdk.model.DashManifestDefault;
+import com.bitmovin.api.sdk.model.DashManifestDefaultVersion;
+import com.bitmovin.api.sdk.model.Encoding;
+import com.bitmovin.api.sdk.model.EncodingOutput;
+import com.bitmovin.api.sdk.model.Fmp4Muxing;
+import com.bitmovin.api.sdk.model.H264VideoConfiguration;
+import com.bitmovin.api.sdk.model.HlsManifest;
+import com.bitmovin.api.sdk.model.HlsManifestDefault;
+import com.bitmovin.api.sdk.model.HlsManifestDefaultVersion;
+import com.bitmovin.api.sdk.model.HttpInput;
+import com.bitmovin.api.sdk.model.InfrastructureSettings;
+import com.bitmovin.api.sdk.model.Input;
+import com.bitmovin.api.sdk.model.MessageType;
+import com.bitmovin.api.sdk.model.MuxingStream;
+import com.bitmovin.api.sdk.model.Output;
+import com.bitmovin.api.sdk.model.PresetConfiguration;
+import com.bitmovin.api.sdk.model.S3Output;
+import com.bitmovin.api.sdk.model.StartEncodingRequest;
+import com.bitmovin.api.sdk.model.Status;
+import com.bitmovin.api.sdk.model.Stream;
+import com.bitmovin.api.sdk.model.StreamInput;
+import com.bitmovin.api.sdk.model.Task;
+import common.ApiClientFactory;
+import common.BudgetTag;
+import common.ConfigProvider;
+import common.EncodingFailedException;
+import common.PreviewTrimming;
+import common.SegmentSharding;
+import java.nio.file.Paths;
+import java.util.Arrays;
+import java.util.List;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+
+/**
+ * This example demonstrates how to run an encoding in your own AWS account (AWS Connect) instead of
+ * the Bitmovin managed cloud. The encoding instances are started in your account, so you benefit
+ * from your own AWS pricing and commitments, and the content never leaves your infrastructure if
+ * input and output are in the same account.
+ *
+ * <p>The AWS account is registered as an infrastructure resource, together with the settings of
+ * each AWS region encodings may run in, like the security group and subnet of the encoding
+ * instances. If AWS_INFRASTRUCTURE_ID is configured, that existing infrastructure is used as-is.
+ * Otherwise a new infrastructure resource is created from the AWS_ACCOUNT_* parameters, and the
+ * region settings are added for AWS_INFRASTRUCTURE_REGION. Both only have to be created once, so
+ * pass the logged infrastructure ID in later runs.
+ *
+ * <p>The encoding selects the infrastructure and region via its InfrastructureSettings. Note that
+ * the AWS account has to be prepared for Bitmovin before the first encoding, e.g. the IAM user has
+ * to be allowed to manage EC2 instances and the instance quotas have to be sufficient. See the AWS
+ * Connect setup guide in the Bitmovin documentation for details.
+ *
+ * <p>The following configuration parameters are expected:
+ *
+ * <ul>
+ *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin API
+ *   <li>BITMOVIN_TENANT_ORG_ID - (optional) The ID of the Organisation in which you want to perform
+ *       the encoding.
+ *   <li>BITMOVIN_API_BASE_URL - (optional) The base URL of the Bitmovin API, e.g. of a mock server
+ *       or an API gateway. Default: https://api.bitmovin.com/v1
+ *   <li>HTTP_INPUT_HOST - The Hostname or IP address of the HTTP server hosting your input files,
+ *       e.g.: my-storage.biz
+ *   <li>HTTP_INPUT_FILE_PATH - The path to your input file on the provided HTTP server Example:
+ *       videos/1080p_Sintel.mp4
+ *   <li>S3_OUTPUT_BUCKET_NAME - The name of your S3 output bucket. Example: my-bucket-name
+ *   <li>S3_OUTPUT_ACCESS_KEY - The access key of your S3 output bucket
+ *   <li>S3_OUTPUT_SECRET_KEY - The secret key of your S3 output bucket
+ *   <li>S3_OUTPUT_BASE_PATH - The base path on your S3 output bucket where content will be written.
+ *       Example: /outputs
+ *   <li>AWS_INFRASTRUCTURE_ID - (optional) The ID of an existing AWS infrastructure resource. If
+ *       not set, a new one is created from the following parameters.
+ *   <li>AWS_ACCOUNT_ACCESS_KEY - The access key of the IAM user Bitmovin manages the encoding
+ *       instances with
+ *   <li>AWS_ACCOUNT_SECRET_KEY - The secret key of the IAM user Bitmovin manages the encoding
+ *       instances with
+ *   <li>AWS_ACCOUNT_NUMBER - The number of your AWS account. Example: 123456789012
+ *   <li>AWS_INFRASTRUCTURE_REGION - (optional) The AWS region the encoding runs in, as a constant
+ *       of AwsCloudRegion. Default: EU_WEST_1
+ *   <li>AWS_INFRASTRUCTURE_SECURITY_GROUP_ID - The ID of the security group of the encoding
+ *       instances. Only required when creating a new infrastructure resource. Example:
+ *       sg-0123456789abcdef0
+ *   <li>AWS_INFRASTRUCTURE_SUBNET_ID - (optional) The ID of the subnet the encoding instances are
+ *       started in. Example: subnet-0123456789abcdef0
+ * </ul>
+ *
+ * <p>Configuration parameters will be retrieved from these sources in the listed order:
+ *
+ * <ol>
+ *   <li>command line arguments (eg BITMOVIN_API_KEY=xyz)
+ *   <li>properties file located in the root folder of the JAVA examples at ./examples.properties
+ *       (see examples.properties.template as reference)
+ *   <li>environment variables
+ *   <li>properties file located in the home folder at ~/.bitmovin/examples.properties (see
+ *       examples.properties.template as reference)
+ * </ol>
+ */
+public class AwsInfrastructureEncoding {
+  private static final Logger logger = LoggerFactory.getLogger(AwsInfrastructureEncoding.class);
+
+  private static BitmovinApi bitmovinApi;
+  private static ConfigProvider configProvider;
+
+  /** This list defines the video renditions that will be generated */
+  private static List<VideoRendition> videoRenditions =
+      Arrays.asList(
+          new VideoRendition(1080, 4_800_000L),
+          new VideoRendition(720, 2_400_000L),
+          new VideoRendition(480, 1_200_000L));
+
+  public static void main(String[] args) throws Exception {
+    configProvider = new ConfigProvider(args);
+    bitmovinApi =
+        ApiClientFactory.builder(configProvider)
+            .withApiKey(configProvider.getBitmovinApiKey())
+            .build();
+
+    AwsCloudRegion awsRegion =
+        AwsCloudRegion.valueOf(
+            configProvider.getParameterByKey("AWS_INFRASTRUCTURE_REGION", "EU_WEST_1"));
+
+    String infrastructureId = configProvider.getParameterByKey("AWS_INFRASTRUCTURE_ID", null);
+    if (infrastructureId == null) {
+      infrastructureId = createAwsInfrastructure(awsRegion).getId();
+      logger.info(
+          "Created AWS infrastructure {}. Pass AWS_INFRASTRUCTURE_ID={} to reuse it.",
+          infrastructureId,
+          infrastructureId);
+    }
+
+    Encoding encoding =
+        createEncoding(
+            "AWS Connect encoding",
+            "Encoding running in your own AWS account",
+            infrastructureId,
+            awsRegion);
+
+    HttpInput input = createHttpInput(configProvider.getHttpInputHost());
+    Output output =
+        createS3Output(
+            configProvider.getS3OutputBucketName(),
+            configProvider.getS3OutputAccessKey(),
+            configProvider.getS3OutputSecretKey());
+
+    String inputFilePath = configProvider.getHttpInputFilePath();
+
+    for (VideoRendition videoRendition : videoRenditions) {
+      H264VideoConfiguration videoConfiguration =
+          createH264VideoConfig(videoRendition.height, videoRendition.bitrate);
+      Stream videoStream = createStream(encoding, input, inputFilePath, videoConfiguration);
+      createFmp4Muxing(encoding, output, "video/" + videoRendition.height, videoStream);
+    }
+
+    AacAudioConfiguration aacConfig = createAacAudioConfig();
+    Stream audioStream = createStream(encoding, input, inputFilePath, aacConfig);
+    createFmp4Muxing(encoding, output, "audio", audioStream);
+
+    executeEncoding(encoding);
+
+    generateDashManifest(encoding, output, "/");
+    generateHlsManifest(encoding, output, "/");
+  }
+
+  /**
+   * Registers your AWS account as an infrastructure resource, and adds the settings of the region
+   * the encoding runs in. Encodings can only be started in regions that have been added to the
+   * infrastructure.
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/infrastructure#/Encoding/PostEncodingInfrastructureAws
+   * https://bitmovin.com/docs/encoding/api-reference/sections/infrastructure#/Encoding/PostEncodingInfrastructureAwsRegionsByInfrastructureIdAndRegion
+   *
+   * @param awsRegion The AWS region the encoding runs in
+   */
+  private static AwsAccount createAwsInfrastructure(AwsCloudRegion awsRegion)
+      throws BitmovinException {
+    AwsAccount awsAccount = new AwsAccount();
+    awsAccount.setName("AWS Connect example");
+    awsAccount.setAccessKey(configProvider.getParameterByKey("AWS_ACCOUNT_ACCESS_KEY"));
+    awsAccount.setSecretKey(configProvider.getParameterByKey("AWS_ACCOUNT_SECRET_KEY"));
+    awsAccount.setAccountNumber(configProvider.getParameterByKey("AWS_ACCOUNT_NUMBER"));
+    awsAccount = bitmovinApi.encoding.infrastructure.aws.create(awsAccount);
+
+    AwsAccountRegionSettings regionSettings = new AwsAccountRegionSettings();
+    regionSettings.setSecurityGroupId(
+        configProvider.getParameterByKey("AWS_INFRASTRUCTURE_SECURITY_GROUP_ID"));
+    regionSettings.setSubnetId(
+        configProvider.getParameterByKey("AWS_INFRASTRUCTURE_SUBNET_ID", null));
+    bitmovinApi.encoding.infrastructure.aws.regions.create(
+        awsAccount.getId(), awsRegion, regionSettings);
+
+    return awsAccount;
+  }
+
+  /**
+   * Creates an Encoding object which runs on the given AWS infrastructure
+   *
+   * <p>The cloud region of the encoding has to match the region of the infrastructure settings.
+   * The AWS regions are available as both AwsCloudRegion and CloudRegion constants, the latter
+   * prefixed with AWS_.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodings
+   *
+   * @param name A name that will help you identify the encoding in our dashboard (required)
+   * @param description A description of the encoding (optional)
+   * @param infrastructureId The ID of the AWS infrastructure resource
+   * @param awsRegion The AWS region the encoding runs in
+   */
+  private static Encoding createEncoding(
+      String name, String description, String infrastructureId, AwsCloudRegion awsRegion)
+      throws BitmovinException {
+    CloudRegion cloudRegion = CloudRegion.valueOf("AWS_" + awsRegion.name());
+
+    InfrastructureSettings infrastructureSettings = new InfrastructureSettings();
+    infrastructureSettings.setInfrastructureId(infrastructureId);
+    infrastructureSettings.setCloudRegion(cloudRegion);
+
+    Encoding encoding = new Encoding();
+    encoding.setName(name);
+    encoding.setDescription(description);
+    encoding.setCloudRegion(cloudRegion);
+    encoding.setInfrastructure(infrastructureSettings);
+    new BudgetTag(configProvider).applyTo(encoding);
+
+    return bitmovinApi.encoding.encodings.create(encoding);
+  }
+
+  private static class VideoRendition {
+
+    private int height;
+    private long bitrate;
+
+    /**
+     * @param height The target output height of the rendition
+     * @param bitrate The target output bitrate of the rendition
+     */
+    private VideoRendition(int height, long bitrate) {
+      this.height = height;
+      this.bitrate = bitrate;
+    }
+  }
+
+  /**
+   * Creates a resource representing an HTTP server providing the input files. For alternative input
+   * methods see <a
+   * href="https://bitmovin.com/docs/encoding/articles/supported-input-output-storages">list of
+   * supported input and output storages</a>
+   *
+   * <p>For reasons of simplicity, a new input resource is created on each execution of this
+   * example. In production use, this method should be replaced by a <a
+   * href="https://bitmovin.com/docs/encoding/api-reference/sections/inputs#/Encoding/GetEncodingInputsHttpByInputId">get
+   * call</a> to retrieve an existing resource.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/inputs#/Encoding/PostEncodingInputsHttp
+   *
+   * @param host The hostname or IP address of the HTTP server e.g.: my-storage.biz
+   */
+  private static HttpInput createHttpInput(String host) throws BitmovinException {
+    HttpInput input = new HttpInput();
+    input.setHost(host);
+
+    return bitmovinApi.encoding.inputs.http.create(input);
+  }
+
+  /**
+   * Creates a resource representing an AWS S3 cloud storage bucket to which generated content will
+   * be transferred. For alternative output methods see <a
+   * href="https://bitmovin.com/docs/encoding/articles/supported-input-output-storages">list of
+   * supported input and output storages</a>
+   *
+   * <p>The provided credentials need to allow <i>read</i>, <i>write</i> and <i>list</i> operations.
+   * <i>delete</i> should also be granted to allow overwriting of existings files. See <a
+   * href="https://bitmovin.com/docs/encoding/faqs/how-do-i-create-a-aws-s3-bucket-which-can-be-used-as-output-location">creating
+   * an S3 bucket and setting permissions</a> for further information
+   *
+   * <p>For reasons of simplicity, a new output resource is created on each execution of this
+   * example. In production use, this method should be replaced by a <a
+   * href="https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/GetEncodingOutputsS3">get
+   * call</a> retrieving an existing resource.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/PostEncodingOutputsS3
+   *
+   * @param bucketName The name of the S3 bucket
+   * @param accessKey The access key of your S3 account
+   * @param secretKey The secret key of your S3 account
+   */
+  private static S3Output createS3Output(String bucketName, String accessKey, String secretKey)
+      throws BitmovinException {
+
+    S3Output s3Output = new S3Output();
+    s3Output.setBucketName(bucketName);
+    s3Output.setAccessKey(accessKey);
+    s3Output.setSecretKey(secretKey);
+
+    return bitmovinApi.encoding.outputs.s3.create(s3Output);
+  }
+
+  /**
+   * Creates a stream which binds an input file to a codec configuration. The stream is used for
+   * muxings later on.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsStreamsByEncodingId
+   *
+   * @param encoding The encoding to add the stream onto
+   * @param input The input that should be used
+   * @param inputPath The path to the input file
+   * @param codecConfiguration The codec configuration to be applied to the stream
+   */
+  private static Stream createStream(
+      Encoding encoding, Input input, String inputPath, CodecConfiguration codecConfiguration)
+      throws BitmovinException {
+    StreamInput streamInput =
+        PreviewTrimming.createStreamInput(bitmovinApi, configProvider, encoding, input, inputPath);
+
+    Stream stream = new Stream();
+    stream.addInputStreamsItem(streamInput);
+    stream.setCodecConfigId(codecConfiguration.getId());
+
+    return bitmovinApi.encoding.encodings.streams.create(encoding.getId(), stream);
+  }
+
+  /**
+   * Creates a configuration for the H.264 video codec to be applied to video streams.
+   *
+   * <p>The output resolution is defined by setting only the height. Width will be determined
+   * automatically to maintain the aspect ratio of your input video.
+   *
+   * <p>To keep things simple, we use a quality-optimized VoD preset configuration, which will apply
+   * proven settings for the codec. See <a
+   * href="https://bitmovin.com/docs/encoding/tutorials/how-to-optimize-your-h264-codec-configuration-for-different-use-cases">How
+   * to optimize your H264 codec configuration for different use-cases</a> for alternative presets.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsVideoH264
+   *
+   * @param height The height of the output video
+   * @param bitrate The target bitrate of the output video
+   */
+  private static H264VideoConfiguration createH264VideoConfig(int height, long bitrate)
+      throws BitmovinException {
+    H264VideoConfiguration config = new H264VideoConfiguration();
+    config.setName(String.format("H.264 %dp", height));
+    config.setPresetConfiguration(PresetConfiguration.VOD_STANDARD);
+    config.setHeight(height);
+    config.setBitrate(bitrate);
+
+    return bitmovinApi.encoding.configurations.video.h264.create(config);
+  }
+
+  /**
+   * Creates a configuration for the AAC audio codec to be applied to audio streams.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsAudioAac
+   */
+  private static AacAudioConfiguration createAacAudioConfig() throws BitmovinException {
+    AacAudioConfiguration config = new AacAudioConfiguration();
+    config.setName("AAC 128 kbit/s");
+    config.setBitrate(128_000L);
+
+    return bitmovinApi.encoding.configurations.audio.aac.create(config);
+  }
+
+  /**
+   * Creates a fragmented MP4 muxing. This will generate segments with a given segment length for
+   * adaptive streaming.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/all#/Encoding/PostEncodingEncodingsMuxingsFmp4ByEncodingId
+   *
+   * @param encoding The encoding where to add the muxing to
+   * @param output The output that should be used for the muxing to write the segments to
+   * @param outputPath The output path where the fragmented segments will be written to
+   * @param stream The stream that is associated with the muxing
+   */
+  private static Fmp4Muxing createFmp4Muxing(
+      Encoding encoding, Output output, String outputPath, Stream stream) throws BitmovinException {
+    MuxingStream muxingStream = new MuxingStream();
+    muxingStream.setStreamId(stream.getId());
+
+    Fmp4Muxing muxing = new Fmp4Muxing();
+    muxing.addOutputsItem(buildEncodingOutput(output, outputPath));
+    muxing.addStreamsItem(muxingStream);
+    muxing.setSegmentLength(4.0);
+    SegmentSharding.apply(configProvider, muxing);
+
+    return bitmovinApi.encoding.encodings.muxings.fmp4.create(encoding.getId(), muxing);
+  }
+
+  /**
+   * Builds an EncodingOutput object which defines where the output content (e.g. of a muxing) will
+   * be written to. Public read permissions will be set for the files written, so they can be
+   * accessed easily via HTTP.
+   *
+   * @param output The output resource to be used by the EncodingOutput
+   * @param outputPath The path where the content will be written to
+   */
+  private static EncodingOutput buildEncodingOutput(Output output, String outputPath) {
+    AclEntry aclEntry = new AclEntry();
+    aclEntry.setPermission(AclPermission.PUBLIC_READ);
+
+    EncodingOutput encodingOutput = new EncodingOutput();
+    encodingOutput.setOutputPath(buildAbsolutePath(outputPath));
+    encodingOutput.setOutputId(output.getId());
+    encodingOutput.addAclItem(aclEntry);
+    return encodingOutput;
+  }
+
+  /**
+   * Builds an absolute path by concatenating the S3_OUTPUT_BASE_PATH configuration parameter, the
+   * name of this example class and the given relative path
+   *
+   * <p>e.g.: /s3/base/path/ClassName/relative/path
+   *
+   * @param relativePath The relative path that is concatenated
+   * @return The absolute path
+   */
+  public static String buildAbsolutePath(String relativePath) {
+    String className = AwsInfrastructureEncoding.class.getSimpleName();
+    return Paths.get(configProvider.getS3OutputBasePath(), className, relativePath).toString();
+  }
+
+  /**
+   * Starts the actual encoding process and periodically polls its status until it reaches a final
+   * state
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/all#/Encoding/PostEncodingEncodingsStartByEncodingId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/GetEncodingEncodingsStatusByEncodingId
+   *
+   * <p>Please note that you can also use our webhooks API instead of polling the status. For more
+   * information consult the API spec:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/notifications-webhooks
+   *
+   * @param encoding The encoding to be started
+   */
+  private static void executeEncoding(Encoding encoding)
+      throws InterruptedException, BitmovinException {
+    bitmovinApi.encoding.encodings.start(encoding.getId(), new StartEncodingRequest());
+
+    Task task;
+    do {
+      Thread.sleep(5000);
+      task = bitmovinApi.encoding.encodings.status(encoding.getId());
+      logger.info("encoding status is {} (progress: {} %)", task.getStatus(), task.getProgress());
+    } while (task.getStatus() != Status.FINISHED
+        && task.getStatus() != Status.ERROR
+        && task.getStatus() != Status.CANCELED);
+
+    if (task.getStatus() != Status.FINISHED) {
+      logTaskErrors(task);
+      throw new EncodingFailedException(task.getStatus());
+    }
+    logger.info("encoding finished successfully");
+  }
+
+  /**
+   * Creates an HLS default manifest that automatically includes all representations configured in
+   * the encoding.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsHlsDefault
+   *
+   * @param encoding The encoding for which the manifest should be generated
+   * @param output The output to which the manifest should be written
+   * @param outputPath The path to which the manifest should be written
+   */
+  private static void generateHlsManifest(Encoding encoding, Output output, String outputPath)
+      throws Exception {
+    HlsManifestDefault hlsManifestDefault = new HlsManifestDefault();
+    hlsManifestDefault.setEncodingId(encoding.getId());
+    hlsManifestDefault.addOutputsItem(buildEncodingOutput(output, outputPath));
+    hlsManifestDefault.setName("master.m3u8");
+    hlsManifestDefault.setVersion(HlsManifestDefaultVersion.V1);
+
+    hlsManifestDefault = bitmovinApi.encoding.manifests.hls.defaultapi.create(hlsManifestDefault);
+    executeHlsManifestCreation(hlsManifestDefault);
+  }
+
+  /**
+   * Creates a DASH default manifest that automatically includes all representations configured in
+   * the encoding.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsDash
+   *
+   * @param encoding The encoding for which the manifest should be generated
+   * @param output The output to which the manifest should be written
+   * @param outputPath The path to which the manifest should be written
+   */
+  private static void generateDashManifest(Encoding encoding, Output output, String outputPath)
+      throws Exception {
+    DashManifestDefault dashManifestDefault = new DashManifestDefault();
+    dashManifestDefault.setEncodingId(encoding.getId());
+    dashManifestDefault.setManifestName("stream.mpd");
+    dashManifestDefault.setVersion(DashManifestDefaultVersion.V1);
+    dashManifestDefault.addOutputsItem(buildEncodingOutput(output, outputPath));
+    dashManifestDefault =
+        bitmovinApi.encoding.manifests.dash.defaultapi.create(dashManifestDefault);
+    executeDashManifestCreation(dashManifestDefault);
+  }
+
+  /**
+   * Starts the DASH manifest creation and periodically polls its status until it reaches a final
+   * state
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsDashStartByManifestId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/GetEncodingManifestsDashStatusByManifestId
+   *
+   * @param dashManifest The DASH manifest to be created
+   */
+  private static void executeDashManifestCreation(DashManifest dashManifest)
+      throws BitmovinException, InterruptedException {
+    bitmovinApi.encoding.manifests.dash.start(dashManifest.getId());
+
+    Task task;
+    do {
+      Thread.sleep(1000);
+      task = bitmovinApi.encoding.manifests.dash.status(dashManifest.getId());
+    } while (task.getStatus() != Status.FINISHED && task.getStatus() != Status.ERROR);
+
+    if (task.getStatus() == Status.ERROR) {
+      logTaskErrors(task);
+      throw new RuntimeException("DASH manifest creation failed");
+    }
+    logger.info("DASH manifest creation finished successfully");
+  }
+
+  /**
+   * Starts the HLS manifest creation and periodically polls its status until it reaches a final
+   * state
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsHlsStartByManifestId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/GetEncodingManifestsHlsStatusByManifestId
+   *
+   * @param hlsManifest The HLS manifest to be created
+   */
+  private static void executeHlsManifestCreation(HlsManifest hlsManifest)
+      throws BitmovinException, InterruptedException {
+
+    bitmovinApi.encoding.manifests.hls.start(hlsManifest.getId());
+
+    Task task;
+    do {
+      Thread.sleep(1000);
+      task = bitmovinApi.encoding.manifests.hls.status(hlsManifest.getId());
+    } while (task.getStatus() != Status.FINISHED && task.getStatus() != Status.ERROR);
+
+    if (task.getStatus() == Status.ERROR) {
+      logTaskErrors(task);
+      throw new RuntimeException("HLS manifest creation failed");
+    }
+    logger.info("HLS manifest creation finished successfully");
+  }
+
+  private static void logTaskErrors(Task task) {
+    task.getMessages().stream()
+        .filter(msg -> msg.getType() == MessageType.ERROR)
+        .forEach(msg -> logger.error(msg.getText()));
+  }
+}
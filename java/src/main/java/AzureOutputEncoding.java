@@ -0,0 +1,479 @@
+import com.bitmovin.api.sdk.BitmovinApi;
+import com.bitmovin.api.sdk.common.BitmovinException;
+import com.bitmovin.api.sdk.model.AacAudioConfiguration;
+import com.bitmovin.api.sdk.model.AzureOutput;
+import com.bitmovin.api.sdk.model.CodecConfiguration;
+import com.bitmovin.api.sdk.model.DashManifest;
+import com.bitmovin.api.sdk.model.DashManifestDefault;
+import com.bitmovin.api.sdk.model.DashManifestDefaultVersion;
+import com.bitmovin.api.sdk.model.Encoding;
+import com.bitmovin.api.sdk.model.EncodingOutput;
+import com.bitmovin.api.sdk.model.Fmp4Muxing;
+import com.bitmovin.api.sdk.model.H264VideoConfiguration;
+import com.bitmovin.api.sdk.model.HlsManifest;
+import com.bitmovin.api.sdk.model.HlsManifestDefault;
+import com.bitmovin.api.sdk.model.HlsManifestDefaultVersion;
+import com.bitmovin.api.sdk.model.HttpInput;
+import com.bitmovin.api.sdk.model.Input;
+import com.bitmovin.api.sdk.model.MessageType;
+import com.bitmovin.api.sdk.model.MuxingStream;
+import com.bitmovin.api.sdk.model.Output;
+import com.bitmovin.api.sdk.model.PresetConfiguration;
+import com.bitmovin.api.sdk.model.StartEncodingRequest;
+import com.bitmovin.api.sdk.model.Status;
+import com.bitmovin.api.sdk.model.Stream;
+import com.bitmovin.api.sdk.model.StreamInput;
+import com.bitmovin.api.sdk.model.Task;
+import common.ApiClientFactory;
+import common.BudgetTag;
+import common.ConfigProvider;
+import common.EncodingFailedException;
+import common.PreviewTrimming;
+import common.SegmentSharding;
+import java.nio.file.Paths;
+import java.util.Arrays;
+import java.util.List;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+
+/**
+ * This example demonstrates how to write a DASH and HLS package to a container of Azure Blob
+ * Storage. The output resource references the container by the name and access key of its storage
+ * account.
+ *
+ * <p>In contrast to S3, Azure Blob Storage does not support per-object access control lists. The
+ * ACL entries the other examples add to their outputs, granting public read access to each written
+ * file, are therefore omitted here. To allow players to fetch the content via HTTP, set the public
+ * access level of the container to "Blob" (anonymous read access for blobs only), or serve it
+ * through Azure CDN or shared access signatures. If the content is played in a browser from a
+ * different origin, CORS rules have to be configured on the storage account as well.
+ *
+ * <p>The files are written to the path S3_OUTPUT_BASE_PATH/AzureOutputEncoding of the container,
+ * and can be accessed at https://{accountName}.blob.core.windows.net/{container}/ once the
+ * container allows public access.
+ *
+ * <p>The following configuration parameters are expected:
+ *
+ * <ul>
+ *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin API
+ *   <li>BITMOVIN_TENANT_ORG_ID - (optional) The ID of the Organisation in which you want to perform
+ *       the encoding.
+ *   <li>BITMOVIN_API_BASE_URL - (optional) The base URL of the Bitmovin API, e.g. of a mock server
+ *       or an API gateway. Default: https://api.bitmovin.com/v1
+ *   <li>HTTP_INPUT_HOST - The Hostname or IP address of the HTTP server hosting your input files,
+ *       e.g.: my-storage.biz
+ *   <li>HTTP_INPUT_FILE_PATH - The path to your input file on the provided HTTP server Example:
+ *       videos/1080p_Sintel.mp4
+ *   <li>AZURE_OUTPUT_ACCOUNT_NAME - The name of your Azure storage account
+ *   <li>AZURE_OUTPUT_ACCOUNT_KEY - The access key of your Azure storage account
+ *   <li>AZURE_OUTPUT_CONTAINER_NAME - The name of your Azure storage container
+ *   <li>S3_OUTPUT_BASE_PATH - The base path in your Azure storage container where content will be
+ *       written. Example: /outputs
+ * </ul>
+ *
+ * <p>Configuration parameters will be retrieved from these sources in the listed order:
+ *
+ * <ol>
+ *   <li>command line arguments (eg BITMOVIN_API_KEY=xyz)
+ *   <li>properties file located in the root folder of the JAVA examples at ./examples.properties
+ *       (see examples.properties.template as reference)
+ *   <li>environment variables
+ *   <li>properties file located in the home folder at ~/.bitmovin/examples.properties (see
+ *       examples.properties.template as reference)
+ * </ol>
+ */
+public class AzureOutputEncoding {
+  private static final Logger logger = LoggerFactory.getLogger(AzureOutputEncoding.class);
+
+  private static BitmovinApi bitmovinApi;
+  private static ConfigProvider configProvider;
+
+  /** This list defines the video renditions that will be generated */
+  private static List<VideoRendition> videoRenditions =
+      Arrays.asList(
+          new VideoRendition(1080, 4_800_000L),
+          new VideoRendition(720, 2_400_000L),
+          new VideoRendition(480, 1_200_000L));
+
+  public static void main(String[] args) throws Exception {
+    configProvider = new ConfigProvider(args);
+    bitmovinApi =
+        ApiClientFactory.builder(configProvider)
+            .withApiKey(configProvider.getBitmovinApiKey())
+            .build();
+
+    Encoding encoding =
+        createEncoding("Azure output", "Encoding writing to Azure Blob Storage");
+
+    HttpInput input = createHttpInput(configProvider.getHttpInputHost());
+    Output output =
+        createAzureOutput(
+            configProvider.getAzureOutputAccountName(),
+            configProvider.getAzureOutputAccountKey(),
+            configProvider.getAzureOutputContainerName());
+
+    String inputFilePath = configProvider.getHttpInputFilePath();
+
+    for (VideoRendition videoRendition : videoRenditions) {
+      H264VideoConfiguration videoConfiguration =
+          createH264VideoConfig(videoRendition.height, videoRendition.bitrate);
+      Stream videoStream = createStream(encoding, input, inputFilePath, videoConfiguration);
+      createFmp4Muxing(encoding, output, "video/" + videoRendition.height, videoStream);
+    }
+
+    AacAudioConfiguration aacConfig = createAacAudioConfig();
+    Stream audioStream = createStream(encoding, input, inputFilePath, aacConfig);
+    createFmp4Muxing(encoding, output, "audio", audioStream);
+
+    executeEncoding(encoding);
+
+    generateDashManifest(encoding, output, "/");
+    generateHlsManifest(encoding, output, "/");
+  }
+
+  /**
+   * Creates a resource representing an Azure Blob Storage container to which generated content
+   * will be transferred
+   *
+   * <p>For reasons of simplicity, a new output resource is created on each execution of this
+   * example. In production use, this method should be replaced by a <a
+   * href="https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/GetEncodingOutputsAzureByOutputId">get
+   * call</a> retrieving an existing resource.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/PostEncodingOutputsAzure
+   *
+   * @param accountName The name of your Azure storage account
+   * @param accountKey The access key of your Azure storage account
+   * @param container The name of your Azure storage container
+   */
+  private static AzureOutput createAzureOutput(
+      String accountName, String accountKey, String container) throws BitmovinException {
+    AzureOutput azureOutput = new AzureOutput();
+    azureOutput.setAccountName(accountName);
+    azureOutput.setAccountKey(accountKey);
+    azureOutput.setContainer(container);
+
+    return bitmovinApi.encoding.outputs.azure.create(azureOutput);
+  }
+
+  /**
+   * Builds an EncodingOutput object which defines where the output content (e.g. of a muxing) will
+   * be written to. Unlike for S3, no ACL entries are set: Azure Blob Storage has no per-object
+   * permissions, the public access level of the container defines whether the files can be
+   * accessed via HTTP.
+   *
+   * @param output The output resource to be used by the EncodingOutput
+   * @param outputPath The path where the content will be written to
+   */
+  private static EncodingOutput buildEncodingOutput(Output output, String outputPath) {
+    EncodingOutput encodingOutput = new EncodingOutput();
+    encodingOutput.setOutputPath(buildAbsolutePath(outputPath));
+    encodingOutput.setOutputId(output.getId());
+    return encodingOutput;
+  }
+
+  private static class VideoRendition {
+
+    private int height;
+    private long bitrate;
+
+    /**
+     * @param height The target output height of the rendition
+     * @param bitrate The target output bitrate of the rendition
+     */
+    private VideoRendition(int height, long bitrate) {
+      this.height = height;
+      this.bitrate = bitrate;
+    }
+  }
+
+  /**
+   * Creates a resource representing an HTTP server providing the input files. For alternative input
+   * methods see <a
+   * href="https://bitmovin.com/docs/encoding/articles/supported-input-output-storages">list of
+   * supported input and output storages</a>
+   *
+   * <p>For reasons of simplicity, a new input resource is created on each execution of this
+   * example. In production use, this method should be replaced by a <a
+   * href="https://bitmovin.com/docs/encoding/api-reference/sections/inputs#/Encoding/GetEncodingInputsHttpByInputId">get
+   * call</a> to retrieve an existing resource.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/inputs#/Encoding/PostEncodingInputsHttp
+   *
+   * @param host The hostname or IP address of the HTTP server e.g.: my-storage.biz
+   */
+  private static HttpInput createHttpInput(String host) throws BitmovinException {
+    HttpInput input = new HttpInput();
+    input.setHost(host);
+
+    return bitmovinApi.encoding.inputs.http.create(input);
+  }
+
+  /**
+   * Creates an encoding object. This is the base object to configure your encoding.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodings
+   *
+   * @param name This is the name of the encoding
+   * @param description This is the description of the encoding
+   */
+  private static Encoding createEncoding(String name, String description) throws BitmovinException {
+    Encoding encoding = new Encoding();
+    encoding.setName(name);
+    encoding.setDescription(description);
+    new BudgetTag(configProvider).applyTo(encoding);
+
+    return bitmovinApi.encoding.encodings.create(encoding);
+  }
+
+  /**
+   * Creates a stream which binds an input file to a codec configuration. The stream is used for
+   * muxings later on.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsStreamsByEncodingId
+   *
+   * @param encoding The encoding to add the stream onto
+   * @param input The input that should be used
+   * @param inputPath The path to the input file
+   * @param codecConfiguration The codec configuration to be applied to the stream
+   */
+  private static Stream createStream(
+      Encoding encoding, Input input, String inputPath, CodecConfiguration codecConfiguration)
+      throws BitmovinException {
+    StreamInput streamInput =
+        PreviewTrimming.createStreamInput(bitmovinApi, configProvider, encoding, input, inputPath);
+
+    Stream stream = new Stream();
+    stream.addInputStreamsItem(streamInput);
+    stream.setCodecConfigId(codecConfiguration.getId());
+
+    return bitmovinApi.encoding.encodings.streams.create(encoding.getId(), stream);
+  }
+
+  /**
+   * Creates a configuration for the H.264 video codec to be applied to video streams.
+   *
+   * <p>The output resolution is defined by setting only the height. Width will be determined
+   * automatically to maintain the aspect ratio of your input video.
+   *
+   * <p>To keep things simple, we use a quality-optimized VoD preset configuration, which will apply
+   * proven settings for the codec. See <a
+   * href="https://bitmovin.com/docs/encoding/tutorials/how-to-optimize-your-h264-codec-configuration-for-different-use-cases">How
+   * to optimize your H264 codec configuration for different use-cases</a> for alternative presets.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsVideoH264
+   *
+   * @param height The height of the output video
+   * @param bitrate The target bitrate of the output video
+   */
+  private static H264VideoConfiguration createH264VideoConfig(int height, long bitrate)
+      throws BitmovinException {
+    H264VideoConfiguration config = new H264VideoConfiguration();
+    config.setName(String.format("H.264 %dp", height));
+    config.setPresetConfiguration(PresetConfiguration.VOD_STANDARD);
+    config.setHeight(height);
+    config.setBitrate(bitrate);
+
+    return bitmovinApi.encoding.configurations.video.h264.create(config);
+  }
+
+  /**
+   * Creates a configuration for the AAC audio codec to be applied to audio streams.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsAudioAac
+   */
+  private static AacAudioConfiguration createAacAudioConfig() throws BitmovinException {
+    AacAudioConfiguration config = new AacAudioConfiguration();
+    config.setName("AAC 128 kbit/s");
+    config.setBitrate(128_000L);
+
+    return bitmovinApi.encoding.configurations.audio.aac.create(config);
+  }
+
+  /**
+   * Creates a fragmented MP4 muxing. This will generate segments with a given segment length for
+   * adaptive streaming.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/all#/Encoding/PostEncodingEncodingsMuxingsFmp4ByEncodingId
+   *
+   * @param encoding The encoding where to add the muxing to
+   * @param output The output that should be used for the muxing to write the segments to
+   * @param outputPath The output path where the fragmented segments will be written to
+   * @param stream The stream that is associated with the muxing
+   */
+  private static Fmp4Muxing createFmp4Muxing(
+      Encoding encoding, Output output, String outputPath, Stream stream) throws BitmovinException {
+    MuxingStream muxingStream = new MuxingStream();
+    muxingStream.setStreamId(stream.getId());
+
+    Fmp4Muxing muxing = new Fmp4Muxing();
+    muxing.addOutputsItem(buildEncodingOutput(output, outputPath));
+    muxing.addStreamsItem(muxingStream);
+    muxing.setSegmentLength(4.0);
+    SegmentSharding.apply(configProvider, muxing);
+
+    return bitmovinApi.encoding.encodings.muxings.fmp4.create(encoding.getId(), muxing);
+  }
+
+  /**
+   * Builds an absolute path by concatenating the S3_OUTPUT_BASE_PATH configuration parameter, the
+   * name of this example class and the given relative path
+   *
+   * <p>e.g.: /s3/base/path/ClassName/relative/path
+   *
+   * @param relativePath The relative path that is concatenated
+   * @return The absolute path
+   */
+  public static String buildAbsolutePath(String relativePath) {
+    String className = AzureOutputEncoding.class.getSimpleName();
+    return Paths.get(configProvider.getS3OutputBasePath(), className, relativePath).toString();
+  }
+
+  /**
+   * Starts the actual encoding process and periodically polls its status until it reaches a final
+   * state
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/all#/Encoding/PostEncodingEncodingsStartByEncodingId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/GetEncodingEncodingsStatusByEncodingId
+   *
+   * <p>Please note that you can also use our webhooks API instead of polling the status. For more
+   * information consult the API spec:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/notifications-webhooks
+   *
+   * @param encoding The encoding to be started
+   */
+  private static void executeEncoding(Encoding encoding)
+      throws InterruptedException, BitmovinException {
+    bitmovinApi.encoding.encodings.start(encoding.getId(), new StartEncodingRequest());
+
+    Task task;
+    do {
+      Thread.sleep(5000);
+      task = bitmovinApi.encoding.encodings.status(encoding.getId());
+      logger.info("encoding status is {} (progress: {} %)", task.getStatus(), task.getProgress());
+    } while (task.getStatus() != Status.FINISHED
+        && task.getStatus() != Status.ERROR
+        && task.getStatus() != Status.CANCELED);
+
+    if (task.getStatus() != Status.FINISHED) {
+      logTaskErrors(task);
+      throw new EncodingFailedException(task.getStatus());
+    }
+    logger.info("encoding finished successfully");
+  }
+
+  /**
+   * Creates an HLS default manifest that automatically includes all representations configured in
+   * the encoding.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsHlsDefault
+   *
+   * @param encoding The encoding for which the manifest should be generated
+   * @param output The output to which the manifest should be written
+   * @param outputPath The path to which the manifest should be written
+   */
+  private static void generateHlsManifest(Encoding encoding, Output output, String outputPath)
+      throws Exception {
+    HlsManifestDefault hlsManifestDefault = new HlsManifestDefault();
+    hlsManifestDefault.setEncodingId(encoding.getId());
+    hlsManifestDefault.addOutputsItem(buildEncodingOutput(output, outputPath));
+    hlsManifestDefault.setName("master.m3u8");
+    hlsManifestDefault.setVersion(HlsManifestDefaultVersion.V1);
+
+    hlsManifestDefault = bitmovinApi.encoding.manifests.hls.defaultapi.create(hlsManifestDefault);
+    executeHlsManifestCreation(hlsManifestDefault);
+  }
+
+  /**
+   * Creates a DASH default manifest that automatically includes all representations configured in
+   * the encoding.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsDash
+   *
+   * @param encoding The encoding for which the manifest should be generated
+   * @param output The output to which the manifest should be written
+   * @param outputPath The path to which the manifest should be written
+   */
+  private static void generateDashManifest(Encoding encoding, Output output, String outputPath)
+      throws Exception {
+    DashManifestDefault dashManifestDefault = new DashManifestDefault();
+    dashManifestDefault.setEncodingId(encoding.getId());
+    dashManifestDefault.setManifestName("stream.mpd");
+    dashManifestDefault.setVersion(DashManifestDefaultVersion.V1);
+    dashManifestDefault.addOutputsItem(buildEncodingOutput(output, outputPath));
+    dashManifestDefault =
+        bitmovinApi.encoding.manifests.dash.defaultapi.create(dashManifestDefault);
+    executeDashManifestCreation(dashManifestDefault);
+  }
+
+  /**
+   * Starts the DASH manifest creation and periodically polls its status until it reaches a final
+   * state
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsDashStartByManifestId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/GetEncodingManifestsDashStatusByManifestId
+   *
+   * @param dashManifest The DASH manifest to be created
+   */
+  private static void executeDashManifestCreation(DashManifest dashManifest)
+      throws BitmovinException, InterruptedException {
+    bitmovinApi.encoding.manifests.dash.start(dashManifest.getId());
+
+    Task task;
+    do {
+      Thread.sleep(1000);
+      task = bitmovinApi.encoding.manifests.dash.status(dashManifest.getId());
+    } while (task.getStatus() != Status.FINISHED && task.getStatus() != Status.ERROR);
+
+    if (task.getStatus() == Status.ERROR) {
+      logTaskErrors(task);
+      throw new RuntimeException("DASH manifest creation failed");
+    }
+    logger.info("DASH manifest creation finished successfully");
+  }
+
+  /**
+   * Starts the HLS manifest creation and periodically polls its status until it reaches a final
+   * state
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsHlsStartByManifestId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/GetEncodingManifestsHlsStatusByManifestId
+   *
+   * @param hlsManifest The HLS manifest to be created
+   */
+  private static void executeHlsManifestCreation(HlsManifest hlsManifest)
+      throws BitmovinException, InterruptedException {
+
+    bitmovinApi.encoding.manifests.hls.start(hlsManifest.getId());
+
+    Task task;
+    do {
+      Thread.sleep(1000);
+      task = bitmovinApi.encoding.manifests.hls.status(hlsManifest.getId());
+    } while (task.getStatus() != Status.FINISHED && task.getStatus() != Status.ERROR);
+
+    if (task.getStatus() == Status.ERROR) {
+      logTaskErrors(task);
+      throw new RuntimeException("HLS manifest creation failed");
+    }
+    logger.info("HLS manifest creation finished successfully");
+  }
+
+  private static void logTaskErrors(Task task) {
+    task.getMessages().stream()
+        .filter(msg -> msg.getType() == MessageType.ERROR)
+        .forEach(msg -> logger.error(msg.getText()));
+  }
+}
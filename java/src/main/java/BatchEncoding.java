@@ -5,6 +5,9 @@ import com.bitmovin.api.sdk.model.AacAudioConfiguration;
 import com.bitmovin.api.sdk.model.AclEntry;
 import com.bitmovin.api.sdk.model.AclPermission;
 import com.bitmovin.api.sdk.model.AudioConfiguration;
+import com.bitmovin.api.sdk.model.CencDrm;
+import com.bitmovin.api.sdk.model.CencFairPlay;
+import com.bitmovin.api.sdk.model.CencWidevine;
 import com.bitmovin.api.sdk.model.CodecConfiguration;
 import com.bitmovin.api.sdk.model.Encoding;
 import com.bitmovin.api.sdk.model.EncodingOutput;
@@ -20,20 +23,34 @@ import com.bitmovin.api.sdk.model.PaginationResponse;
 import com.bitmovin.api.sdk.model.PresetConfiguration;
 import com.bitmovin.api.sdk.model.RetryHint;
 import com.bitmovin.api.sdk.model.S3Output;
+import com.bitmovin.api.sdk.model.Scheduling;
 import com.bitmovin.api.sdk.model.StartEncodingRequest;
 import com.bitmovin.api.sdk.model.Status;
 import com.bitmovin.api.sdk.model.Stream;
 import com.bitmovin.api.sdk.model.StreamInput;
-import com.bitmovin.api.sdk.model.StreamSelectionMode;
 import com.bitmovin.api.sdk.model.Task;
 import com.bitmovin.api.sdk.model.VideoConfiguration;
+import com.fasterxml.jackson.annotation.JsonAutoDetect.Visibility;
+import com.fasterxml.jackson.annotation.PropertyAccessor;
+import com.fasterxml.jackson.core.type.TypeReference;
+import com.fasterxml.jackson.databind.ObjectMapper;
+import common.ApiClientFactory;
+import common.BudgetTag;
 import common.ConfigProvider;
-import feign.Logger.Level;
-import feign.slf4j.Slf4jLogger;
+import common.DrmKeyMaterial;
+import common.EncodingLimitGuard;
+import common.PreviewTrimming;
+import common.SegmentSharding;
+import java.io.IOException;
+import java.nio.file.Files;
+import java.nio.file.Path;
 import java.nio.file.Paths;
+import java.nio.file.StandardCopyOption;
 import java.util.ArrayList;
 import java.util.Arrays;
+import java.util.HashMap;
 import java.util.List;
+import java.util.Map;
 import java.util.stream.Collectors;
 import org.apache.commons.lang3.StringUtils;
 import org.slf4j.Logger;
@@ -45,9 +62,24 @@ import org.slf4j.LoggerFactory;
  * some encodings queued. Encodings will therefore be started in a way to maintain a constant queue
  * size.
  *
- * <p>The same list of jobs will be executed on each start. In order to continue a batch after
- * restarting, you will have to extend the JobDispatcher class to use a persistent data store (e.g.
- * a database)
+ * <p>The same list of jobs will be executed on each start. The state of all jobs is written to a
+ * checkpoint file after every polling cycle. Jobs that could not be finished successfully form the
+ * failure queue: when the example is run again with the --retry-failed command line argument, the
+ * jobs are loaded from the checkpoint file instead, and only the failed jobs are re-submitted, with
+ * an elevated priority so they are processed before other encodings waiting in the queue. Jobs that
+ * were still waiting or running when the previous run was interrupted are continued as well. For
+ * production use, you may want to replace the checkpoint file with a persistent data store (e.g. a
+ * database).
+ *
+ * <p>If BATCH_DRM_KEYS_FILE is configured, the segments of every job are protected with CENC DRM,
+ * using keys that are specific to the asset instead of the global DRM_* configuration parameters.
+ * The file is a CSV file with the columns assetId, key, kid, iv and pssh, where the asset ID
+ * matches the name of the job, and iv and pssh may be left empty to omit FairPlay or Widevine for
+ * an asset. An optional header line is skipped.
+ *
+ * <p>All rows are validated before any encoding is created. A job without a row in the file is
+ * given up without being started, so no asset is accidentally written unprotected. Keys of the
+ * format expected here can be generated with {@link DrmKeyMaterial}.
  *
  * <p>Be aware that our webhooks API provides a more advanced way to keep track of your encodings
  * than constantly polling their status. This approach has been chosen solely for reasons of
@@ -59,6 +91,8 @@ import org.slf4j.LoggerFactory;
  *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin API
  *   <li>BITMOVIN_TENANT_ORG_ID - (optional) The ID of the Organisation in which you want to perform
  *       the encoding.
+ *   <li>BITMOVIN_API_BASE_URL - (optional) The base URL of the Bitmovin API, e.g. of a mock server
+ *       or an API gateway. Default: https://api.bitmovin.com/v1
  *   <li>HTTP_INPUT_HOST - The Hostname or IP address of the HTTP server hosting your input files,
  *       e.g.: my-storage.biz
  *   <li>S3_OUTPUT_BUCKET_NAME - The name of your S3 output bucket. Example: my-bucket-name
@@ -66,6 +100,19 @@ import org.slf4j.LoggerFactory;
  *   <li>S3_OUTPUT_SECRET_KEY - The secret key of your S3 output bucket
  *   <li>S3_OUTPUT_BASE_PATH - The base path on your S3 output bucket where content will be written.
  *       Example: /outputs
+ *   <li>LIMIT_GUARD_MAX_ACTIVE_ENCODINGS - (optional) The maximum number of queued and running
+ *       encodings in your account, see {@link EncodingLimitGuard}
+ *   <li>LIMIT_GUARD_MAX_MONTHLY_MINUTES - (optional) The maximum number of minutes to be encoded in
+ *       the current month
+ *   <li>LIMIT_GUARD_MODE - (optional) WARN (default) or REFUSE to stop starting further jobs of
+ *       the batch if a limit is exceeded
+ *   <li>BUDGET_TAG - (optional) A tag stored in the custom data of all encodings, e.g. the name of
+ *       the team they are charged to, see {@link BudgetTag}
+ *   <li>BATCH_CHECKPOINT_FILE - (optional) The path of the checkpoint file. Default:
+ *       BatchEncoding.checkpoint.json
+ *   <li>BATCH_DRM_KEYS_FILE - (optional) The path of the CSV file with the DRM keys per asset
+ *   <li>DRM_FAIRPLAY_URI - (optional) URI of the FairPlay licensing server, required if the keys
+ *       file contains an iv for any asset
  * </ul>
  *
  * <p>Configuration parameters will be retrieved from these sources in the listed order:
@@ -84,6 +131,10 @@ public class BatchEncoding {
 
   private static BitmovinApi bitmovinApi;
   private static ConfigProvider configProvider;
+  private static BudgetTag budgetTag;
+
+  /** The DRM keys per asset ID, or null if the segments are not encrypted */
+  private static Map<String, AssetKeys> assetKeys;
 
   /**
    * The example will strive to always keep this number of encodings in state 'queued'. Make sure
@@ -99,17 +150,31 @@ public class BatchEncoding {
    */
   private static int maxRetries = 2;
 
+  /**
+   * The priority of jobs re-submitted with --retry-failed. Encodings are started with priority 50
+   * by default, so re-submitted jobs are preferred over other queued encodings of your account.
+   */
+  private static int retryFailedPriority = 80;
+
   public static void main(String[] args) throws Exception {
+    boolean retryFailed = Arrays.asList(args).contains("--retry-failed");
     configProvider = new ConfigProvider(args);
     bitmovinApi =
-        BitmovinApi.builder()
+        ApiClientFactory.builder(configProvider)
             .withApiKey(configProvider.getBitmovinApiKey())
-            // uncomment the following line if you are working with a multi-tenant account
-            // .withTenantOrgId(configProvider.getBitmovinTenantOrgId())
-            .withLogger(
-                new Slf4jLogger(), Level.BASIC) // set the logger and log level for the API client
             .build();
 
+    budgetTag = new BudgetTag(configProvider);
+
+    String drmKeysFile = configProvider.getParameterByKey("BATCH_DRM_KEYS_FILE", null);
+    if (drmKeysFile != null) {
+      assetKeys = readAssetKeys(Paths.get(drmKeysFile));
+    }
+
+    // make sure that starting the jobs does not exceed the configured account limits
+    EncodingLimitGuard limitGuard = new EncodingLimitGuard(bitmovinApi, configProvider);
+    boolean startRefused = false;
+
     HttpInput input = createHttpInput(configProvider.getHttpInputHost());
     Output output =
         createS3Output(
@@ -124,15 +189,28 @@ public class BatchEncoding {
             createH264VideoConfig(1080, 2_000_000L),
             createAacAudioConfig());
 
-    JobDispatcher jobDispatcher = new JobDispatcher();
+    Path checkpointFile =
+        Paths.get(
+            configProvider.getParameterByKey(
+                "BATCH_CHECKPOINT_FILE", "BatchEncoding.checkpoint.json"));
+    JobDispatcher jobDispatcher =
+        retryFailed
+            ? new JobDispatcher(checkpointFile, retryFailedPriority)
+            : new JobDispatcher(checkpointFile);
 
     do {
       long queuedEncodingsCount = countQueuedEncodings();
       long freeSlots = targetQueueSize - queuedEncodingsCount;
-      if (freeSlots > 0) {
+      if (startRefused) {
+        logger.info(
+            "Waiting for {} jobs to finish before stopping the batch.",
+            jobDispatcher.getStartedJobs().size());
+      } else if (freeSlots > 0) {
         List<EncodingJob> jobsToStart = jobDispatcher.getJobsToStart(freeSlots);
 
-        if (!jobsToStart.isEmpty()) {
+        if (!jobsToStart.isEmpty() && !limitGuard.allowsStart(jobsToStart.size())) {
+          startRefused = true;
+        } else if (!jobsToStart.isEmpty()) {
           logger.info(
               "There are currently {} encodings queued. Starting {} more to reach target queue size of {}",
               queuedEncodingsCount,
@@ -156,8 +234,18 @@ public class BatchEncoding {
         updateEncodingJob(job);
         Thread.sleep(300);
       }
-    } while (!jobDispatcher.allJobsFinished());
-    logger.info("All encoding jobs are finished!");
+      jobDispatcher.saveCheckpoint();
+    } while (!jobDispatcher.allJobsFinished()
+        && !(startRefused && jobDispatcher.getStartedJobs().isEmpty()));
+
+    if (startRefused) {
+      logger.warn(
+          "{} jobs have not been started, as configured limits are exceeded. Run the example with"
+              + " --retry-failed once the limits allow it to start them.",
+          jobDispatcher.getJobsToStart(Long.MAX_VALUE).size());
+    } else {
+      logger.info("All encoding jobs are finished!");
+    }
 
     jobDispatcher.logFailedJobs();
   }
@@ -192,13 +280,35 @@ public class BatchEncoding {
       throws BitmovinException, InterruptedException {
     for (EncodingJob job : jobsToStart) {
       if (StringUtils.isBlank(job.encodingId)) {
+        AssetKeys keys = null;
+        if (assetKeys != null) {
+          keys = assetKeys.get(job.encodingName);
+          if (keys == null) {
+            logger.error("No DRM keys found for '{}'. Giving up.", job.encodingName);
+            job.status = EncodingJobStatus.GIVEN_UP;
+            job.errorMessages.add("The keys file does not contain the asset " + job.encodingName);
+            continue;
+          }
+        }
         Encoding encoding =
             createAndConfigureEncoding(
-                input, job.inputFilePath, codecConfigs, job.encodingName, output, job.outputPath);
+                input,
+                job.inputFilePath,
+                codecConfigs,
+                job.encodingName,
+                output,
+                job.outputPath,
+                keys);
         job.encodingId = encoding.getId();
       }
       try {
-        bitmovinApi.encoding.encodings.start(job.encodingId, new StartEncodingRequest());
+        StartEncodingRequest startEncodingRequest = new StartEncodingRequest();
+        if (job.priority != null) {
+          Scheduling scheduling = new Scheduling();
+          scheduling.setPriority(job.priority);
+          startEncodingRequest.setScheduling(scheduling);
+        }
+        bitmovinApi.encoding.encodings.start(job.encodingId, startEncodingRequest);
         job.status = EncodingJobStatus.STARTED;
         logger.info("Encoding {} ('{}') has been started.", job.encodingId, job.encodingName);
       } catch (BitmovinException ex) {
@@ -292,6 +402,8 @@ public class BatchEncoding {
    *     renditions to be generated
    * @param encodingName A name for the encoding
    * @param output The output that should be used for the encoding
+   * @param outputPath The output path where the segments of the encoding will be written to
+   * @param keys The DRM keys of the asset, or null to write the segments unencrypted
    */
   private static Encoding createAndConfigureEncoding(
       Input input,
@@ -299,10 +411,12 @@ public class BatchEncoding {
       List<CodecConfiguration> codecConfigs,
       String encodingName,
       Output output,
-      String outputPath)
+      String outputPath,
+      AssetKeys keys)
       throws BitmovinException {
     Encoding encoding = new Encoding();
     encoding.setName(encodingName);
+    budgetTag.applyTo(encoding);
 
     encoding = bitmovinApi.encoding.encodings.create(encoding);
 
@@ -319,7 +433,12 @@ public class BatchEncoding {
             String.format(
                 "%s/audio/%s", outputPath, ((AudioConfiguration) codecConfig).getBitrate() / 1000);
       }
-      createFmp4Muxing(encoding, stream, output, muxingOutputPath);
+      if (keys == null) {
+        createFmp4Muxing(encoding, stream, output, muxingOutputPath);
+      } else {
+        Fmp4Muxing muxing = createFmp4Muxing(encoding, stream, null, muxingOutputPath);
+        createCencDrm(encoding, muxing, output, muxingOutputPath, keys);
+      }
     }
     return encoding;
   }
@@ -333,7 +452,8 @@ public class BatchEncoding {
    *
    * @param encoding The encoding to add the FMP4 muxing to
    * @param stream The stream that is associated with the muxing
-   * @param output The output that should be used for the muxing to write the segments to
+   * @param output The output that should be used for the muxing to write the segments to, or null
+   *     if the segments are written by a DRM configuration instead
    * @param outputPath The output path where the fragmented segments will be written to
    */
   private static Fmp4Muxing createFmp4Muxing(
@@ -342,13 +462,108 @@ public class BatchEncoding {
     muxingStream.setStreamId(stream.getId());
 
     Fmp4Muxing muxing = new Fmp4Muxing();
-    muxing.addOutputsItem(buildEncodingOutput(output, outputPath));
+    if (output != null) {
+      muxing.addOutputsItem(buildEncodingOutput(output, outputPath));
+    }
     muxing.addStreamsItem(muxingStream);
     muxing.setSegmentLength(4.0);
+    SegmentSharding.apply(configProvider, muxing);
 
     return bitmovinApi.encoding.encodings.muxings.fmp4.create(encoding.getId(), muxing);
   }
 
+  /**
+   * Adds an MPEG-CENC DRM configuration with the keys of the asset to the muxing, to encrypt its
+   * segments. Widevine and FairPlay signaling is only added if the keys file contains a pssh or iv
+   * for the asset.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsMuxingsFmp4DrmCencByEncodingIdAndMuxingId
+   *
+   * @param encoding The encoding to which the muxing belongs
+   * @param muxing The muxing to apply the encryption to
+   * @param output The output resource to which the encrypted segments will be written to
+   * @param outputPath The output path where the encrypted segments will be written to
+   * @param keys The DRM keys of the asset
+   */
+  private static CencDrm createCencDrm(
+      Encoding encoding, Fmp4Muxing muxing, Output output, String outputPath, AssetKeys keys)
+      throws BitmovinException {
+    CencDrm cencDrm = new CencDrm();
+    cencDrm.addOutputsItem(buildEncodingOutput(output, outputPath));
+    cencDrm.setKey(keys.key);
+    cencDrm.setKid(keys.kid);
+
+    if (keys.pssh != null) {
+      CencWidevine widevineDrm = new CencWidevine();
+      widevineDrm.setPssh(keys.pssh);
+      cencDrm.setWidevine(widevineDrm);
+    }
+
+    if (keys.iv != null) {
+      CencFairPlay cencFairPlay = new CencFairPlay();
+      cencFairPlay.setIv(keys.iv);
+      cencFairPlay.setUri(configProvider.getDrmFairplayUri());
+      cencDrm.setFairPlay(cencFairPlay);
+    }
+
+    return bitmovinApi.encoding.encodings.muxings.fmp4.drm.cenc.create(
+        encoding.getId(), muxing.getId(), cencDrm);
+  }
+
+  /**
+   * Reads and validates the DRM keys per asset from the keys file. Blank lines and a header line
+   * starting with "assetId" are skipped.
+   *
+   * @param keysFile The CSV file with the columns assetId, key, kid, iv and pssh
+   * @throws IllegalArgumentException if a line is malformed or contains invalid keys
+   */
+  private static Map<String, AssetKeys> readAssetKeys(Path keysFile) throws IOException {
+    Map<String, AssetKeys> keysByAssetId = new HashMap<>();
+    boolean fairPlayUsed = false;
+    List<String> lines = Files.readAllLines(keysFile);
+    for (int lineIndex = 0; lineIndex < lines.size(); lineIndex++) {
+      String line = lines.get(lineIndex);
+      if (line.trim().isEmpty() || line.startsWith("assetId,")) {
+        continue;
+      }
+      String source = String.format("%s line %d", keysFile.getFileName(), lineIndex + 1);
+
+      String[] columns = line.split(",", -1);
+      if (columns.length != 5) {
+        throw new IllegalArgumentException(
+            "Expected the columns assetId,key,kid,iv,pssh in " + source + ", but got: " + line);
+      }
+      String assetId = columns[0].trim();
+      AssetKeys keys =
+          new AssetKeys(
+              columns[1].trim(),
+              columns[2].trim(),
+              StringUtils.trimToNull(columns[3]),
+              StringUtils.trimToNull(columns[4]));
+
+      DrmKeyMaterial.parseHex(source + ", column key", keys.key);
+      DrmKeyMaterial.parseHex(source + ", column kid", keys.kid);
+      if (keys.iv != null) {
+        DrmKeyMaterial.parseHex(source + ", column iv", keys.iv);
+        fairPlayUsed = true;
+      }
+      if (keys.pssh != null) {
+        DrmKeyMaterial.validateWidevinePssh(source + ", column pssh", keys.pssh, keys.kid);
+      }
+      if (keysByAssetId.put(assetId, keys) != null) {
+        throw new IllegalArgumentException(
+            "The keys file contains the asset " + assetId + " twice, again in " + source);
+      }
+    }
+
+    if (fairPlayUsed) {
+      DrmKeyMaterial.validateFairPlayUri("DRM_FAIRPLAY_URI", configProvider.getDrmFairplayUri());
+    }
+    logger.info("Read DRM keys for {} assets from {}", keysByAssetId.size(), keysFile);
+    return keysByAssetId;
+  }
+
   /**
    * Creates a stream which binds an input file and input stream to a codec configuration. The
    * stream is used for muxings later on.
@@ -365,10 +580,8 @@ public class BatchEncoding {
       Encoding encoding, Input input, String inputPath, CodecConfiguration codecConfiguration)
       throws BitmovinException {
 
-    StreamInput streamInput = new StreamInput();
-    streamInput.setInputId(input.getId());
-    streamInput.setInputPath(inputPath);
-    streamInput.setSelectionMode(StreamSelectionMode.AUTO);
+    StreamInput streamInput =
+        PreviewTrimming.createStreamInput(bitmovinApi, configProvider, encoding, input, inputPath);
 
     Stream stream = new Stream();
     stream.addInputStreamsItem(streamInput);
@@ -512,14 +725,25 @@ public class BatchEncoding {
    * Helper class managing the encodings to be processed in the batch
    *
    * <p>NOTE: This is a dummy implementation that will process the same jobs on each execution of
-   * the example. For production use, we suggest using a persistent data store (eg. a database) to
-   * save and reload the job list.
+   * the example, unless failed jobs are re-submitted from the checkpoint file. For production use,
+   * we suggest using a persistent data store (eg. a database) to save and reload the job list.
    */
   private static class JobDispatcher {
 
+    private static final ObjectMapper checkpointMapper =
+        new ObjectMapper().setVisibility(PropertyAccessor.FIELD, Visibility.ANY);
+
     private static List<EncodingJob> encodingJobs;
 
-    public JobDispatcher() {
+    private final Path checkpointFile;
+
+    /**
+     * Creates the job list of a new batch, replacing the given checkpoint file once it is saved
+     *
+     * @param checkpointFile The file the state of the jobs is written to
+     */
+    public JobDispatcher(Path checkpointFile) {
+      this.checkpointFile = checkpointFile;
       encodingJobs =
           Arrays.asList(
               new EncodingJob(
@@ -538,6 +762,33 @@ public class BatchEncoding {
                   "/path/to/your/input/file7.mkv", "/path/to/your/output/encoding7", "encoding7"));
     }
 
+    /**
+     * Loads the jobs of a previous run from the checkpoint file and re-queues the failed ones with
+     * the given priority. Successful jobs are kept in the checkpoint, but not started again.
+     *
+     * @param checkpointFile The file the state of the jobs has been written to
+     * @param priority The priority the failed jobs are re-submitted with
+     */
+    public JobDispatcher(Path checkpointFile, int priority) throws IOException {
+      this.checkpointFile = checkpointFile;
+      encodingJobs =
+          checkpointMapper.readValue(
+              checkpointFile.toFile(), new TypeReference<List<EncodingJob>>() {});
+
+      List<EncodingJob> failedJobs = getFailedJobs();
+      logger.info(
+          "Re-submitting {} failed jobs from {} with priority {}",
+          failedJobs.size(),
+          checkpointFile.toAbsolutePath(),
+          priority);
+      for (EncodingJob job : failedJobs) {
+        job.status = EncodingJobStatus.WAITING;
+        job.retryCount = 0;
+        job.errorMessages.clear();
+        job.priority = priority;
+      }
+    }
+
     public List<EncodingJob> getJobsToStart(long limit) {
       return encodingJobs.stream()
           .filter(job -> job.status == EncodingJobStatus.WAITING)
@@ -559,16 +810,40 @@ public class BatchEncoding {
                       || job.status == EncodingJobStatus.GIVEN_UP);
     }
 
-    public void logFailedJobs() {
-      encodingJobs.stream()
+    /** Returns the failure queue, i.e. the jobs that have been given up */
+    public List<EncodingJob> getFailedJobs() {
+      return encodingJobs.stream()
           .filter(job -> job.status == EncodingJobStatus.GIVEN_UP)
-          .forEach(
-              encodingJob ->
-                  logger.error(
-                      "Encoding {} ('{}') could not be finished successfully: {}",
-                      encodingJob.encodingId,
-                      encodingJob.encodingName,
-                      encodingJob.errorMessages));
+          .collect(Collectors.toList());
+    }
+
+    public void logFailedJobs() {
+      List<EncodingJob> failedJobs = getFailedJobs();
+      failedJobs.forEach(
+          encodingJob ->
+              logger.error(
+                  "Encoding {} ('{}') could not be finished successfully: {}",
+                  encodingJob.encodingId,
+                  encodingJob.encodingName,
+                  encodingJob.errorMessages));
+      if (!failedJobs.isEmpty()) {
+        logger.info("Run the example with --retry-failed to re-submit the failed jobs.");
+      }
+    }
+
+    /**
+     * Writes the state of all jobs to a temporary file first, which then replaces the checkpoint
+     * file. This way the checkpoint file is never left half-written if the process crashes while
+     * saving.
+     */
+    public void saveCheckpoint() throws IOException {
+      Path tempFile = checkpointFile.resolveSibling(checkpointFile.getFileName() + ".tmp");
+      checkpointMapper.writerWithDefaultPrettyPrinter().writeValue(tempFile.toFile(), encodingJobs);
+      Files.move(
+          tempFile,
+          checkpointFile,
+          StandardCopyOption.REPLACE_EXISTING,
+          StandardCopyOption.ATOMIC_MOVE);
     }
   }
 
@@ -584,8 +859,12 @@ public class BatchEncoding {
     private String encodingId;
     private int retryCount;
     private EncodingJobStatus status;
+    private Integer priority;
     private List<String> errorMessages = new ArrayList<>();
 
+    /** Used to load the job from the checkpoint file */
+    private EncodingJob() {}
+
     private EncodingJob(String inputFilePath, String outputPath, String encodingName) {
       this.inputFilePath = inputFilePath;
       this.outputPath = outputPath;
@@ -594,6 +873,28 @@ public class BatchEncoding {
     }
   }
 
+  /** Helper class holding the DRM keys of a single asset, as read from the keys file */
+  private static class AssetKeys {
+
+    private final String key;
+    private final String kid;
+    private final String iv;
+    private final String pssh;
+
+    /**
+     * @param key The encryption key, represented as 32 hexadecimal characters
+     * @param kid The key ID, represented as 32 hexadecimal characters
+     * @param iv The FairPlay initialization vector, or null to omit FairPlay
+     * @param pssh The Base64 encoded Widevine PSSH payload, or null to omit Widevine
+     */
+    private AssetKeys(String key, String kid, String iv, String pssh) {
+      this.key = key;
+      this.kid = kid;
+      this.iv = iv;
+      this.pssh = pssh;
+    }
+  }
+
   public enum EncodingJobStatus {
     WAITING,
     STARTED,
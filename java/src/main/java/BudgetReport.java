@@ -0,0 +1,160 @@
+import com.bitmovin.api.sdk.BitmovinApi;
+import com.bitmovin.api.sdk.common.BitmovinException;
+import com.bitmovin.api.sdk.encoding.encodings.EncodingListQueryParams;
+import com.bitmovin.api.sdk.model.Encoding;
+import com.bitmovin.api.sdk.model.EncodingStats;
+import com.bitmovin.api.sdk.model.Status;
+import common.ApiClientFactory;
+import common.BudgetTag;
+import common.ConfigProvider;
+import java.nio.charset.StandardCharsets;
+import java.nio.file.Files;
+import java.nio.file.Path;
+import java.nio.file.Paths;
+import java.time.YearMonth;
+import java.time.ZoneOffset;
+import java.util.ArrayList;
+import java.util.Date;
+import java.util.List;
+import java.util.Locale;
+import java.util.Map;
+import java.util.TreeMap;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+
+/**
+ * This tool creates a monthly report of the encoded minutes per budget tag, which can be used for
+ * charging back encoding costs to internal teams. Encodings are tagged by setting the BUDGET_TAG
+ * configuration parameter when running any of the examples, see {@link BudgetTag}.
+ *
+ * <p>All finished encodings created in the given month are listed, and their statistics are
+ * aggregated by the budget tag stored in their custom data. Encodings without a budget tag are
+ * reported as "untagged". The report is logged and written to a CSV file.
+ *
+ * <p>The following configuration parameters are expected:
+ *
+ * <ul>
+ *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin API
+ *   <li>BITMOVIN_TENANT_ORG_ID - (optional) The ID of the Organisation in which you want to perform
+ *       the encoding.
+ *   <li>BITMOVIN_API_BASE_URL - (optional) The base URL of the Bitmovin API, e.g. of a mock server
+ *       or an API gateway. Default: https://api.bitmovin.com/v1
+ *   <li>BUDGET_REPORT_MONTH - (optional) The month to report, in the format YYYY-MM. Default: the
+ *       current month (UTC)
+ *   <li>BUDGET_REPORT_FILE - (optional) The CSV file the report is written to. Default:
+ *       budget_report_{month}.csv
+ * </ul>
+ *
+ * <p>Configuration parameters will be retrieved from these sources in the listed order:
+ *
+ * <ol>
+ *   <li>command line arguments (eg BITMOVIN_API_KEY=xyz)
+ *   <li>properties file located in the root folder of the JAVA examples at ./examples.properties
+ *       (see examples.properties.template as reference)
+ *   <li>environment variables
+ *   <li>properties file located in the home folder at ~/.bitmovin/examples.properties (see
+ *       examples.properties.template as reference)
+ * </ol>
+ */
+public class BudgetReport {
+  private static final Logger logger = LoggerFactory.getLogger(BudgetReport.class);
+
+  private static BitmovinApi bitmovinApi;
+  private static ConfigProvider configProvider;
+
+  private static final int PAGE_SIZE = 100;
+
+  public static void main(String[] args) throws Exception {
+    configProvider = new ConfigProvider(args);
+    bitmovinApi =
+        ApiClientFactory.builder(configProvider)
+            .withApiKey(configProvider.getBitmovinApiKey())
+            .build();
+
+    YearMonth month =
+        YearMonth.parse(
+            configProvider.getParameterByKey(
+                "BUDGET_REPORT_MONTH", YearMonth.now(ZoneOffset.UTC).toString()));
+    Path reportFile =
+        Paths.get(
+            configProvider.getParameterByKey(
+                "BUDGET_REPORT_FILE", String.format("budget_report_%s.csv", month)));
+
+    Map<String, BudgetUsage> usagePerTag = new TreeMap<>();
+
+    for (Encoding encoding : listFinishedEncodings(month)) {
+      String tag = BudgetTag.readFrom(bitmovinApi, encoding.getId());
+      EncodingStats statistics = bitmovinApi.encoding.statistics.encodings.get(encoding.getId());
+
+      BudgetUsage usage = usagePerTag.computeIfAbsent(tag, key -> new BudgetUsage());
+      usage.encodings++;
+      if (statistics.getTimeEncoded() != null) {
+        usage.encodedSeconds += statistics.getTimeEncoded();
+      }
+      if (statistics.getBillableMinutes() != null) {
+        usage.billableMinutes += statistics.getBillableMinutes();
+      }
+    }
+
+    List<String> lines = new ArrayList<>();
+    lines.add("budget_tag,encodings,encoded_minutes,billable_minutes");
+
+    logger.info("Encoding usage per budget tag for {}", month);
+    for (Map.Entry<String, BudgetUsage> entry : usagePerTag.entrySet()) {
+      BudgetUsage usage = entry.getValue();
+      logger.info(
+          "  {}: {} encodings, {} encoded minutes, {} billable minutes",
+          entry.getKey(),
+          usage.encodings,
+          usage.encodedSeconds / 60,
+          String.format("%.2f", usage.billableMinutes));
+      lines.add(
+          String.format(
+              Locale.ROOT,
+              "\"%s\",%d,%d,%.2f",
+              entry.getKey().replace("\"", "\"\""),
+              usage.encodings,
+              usage.encodedSeconds / 60,
+              usage.billableMinutes));
+    }
+
+    Files.write(reportFile, lines, StandardCharsets.UTF_8);
+    logger.info("Report written to {}", reportFile.toAbsolutePath());
+  }
+
+  /**
+   * Lists all finished encodings which have been created in the given month, requesting as many
+   * pages as needed.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/GetEncodingEncodings
+   *
+   * @param month The month the encodings have been created in
+   */
+  private static List<Encoding> listFinishedEncodings(YearMonth month) throws BitmovinException {
+    EncodingListQueryParams queryParams = new EncodingListQueryParams();
+    queryParams.setStatus(Status.FINISHED.toString());
+    queryParams.setCreatedAtNewerThan(
+        Date.from(month.atDay(1).atStartOfDay(ZoneOffset.UTC).toInstant()));
+    queryParams.setCreatedAtOlderThan(
+        Date.from(month.plusMonths(1).atDay(1).atStartOfDay(ZoneOffset.UTC).toInstant()));
+    queryParams.setLimit(PAGE_SIZE);
+
+    List<Encoding> encodings = new ArrayList<>();
+    List<Encoding> page;
+    do {
+      queryParams.setOffset(encodings.size());
+      page = bitmovinApi.encoding.encodings.list(queryParams).getItems();
+      encodings.addAll(page);
+    } while (page.size() == PAGE_SIZE);
+
+    return encodings;
+  }
+
+  private static class BudgetUsage {
+
+    private int encodings;
+    private long encodedSeconds;
+    private double billableMinutes;
+  }
+}
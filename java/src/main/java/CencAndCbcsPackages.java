@@ -0,0 +1,758 @@
+import com.bitmovin.api.sdk.BitmovinApi;
+import com.bitmovin.api.sdk.common.BitmovinException;
+import com.bitmovin.api.sdk.model.AacAudioConfiguration;
+import com.bitmovin.api.sdk.model.AclEntry;
+import com.bitmovin.api.sdk.model.AclPermission;
+import com.bitmovin.api.sdk.model.AudioAdaptationSet;
+import com.bitmovin.api.sdk.model.AudioMediaInfo;
+import com.bitmovin.api.sdk.model.CencDrm;
+import com.bitmovin.api.sdk.model.CencFairPlay;
+import com.bitmovin.api.sdk.model.CencPlayReady;
+import com.bitmovin.api.sdk.model.CencWidevine;
+import com.bitmovin.api.sdk.model.CodecConfiguration;
+import com.bitmovin.api.sdk.model.ContentProtection;
+import com.bitmovin.api.sdk.model.DashFmp4DrmRepresentation;
+import com.bitmovin.api.sdk.model.DashManifest;
+import com.bitmovin.api.sdk.model.DashProfile;
+import com.bitmovin.api.sdk.model.DashRepresentationType;
+import com.bitmovin.api.sdk.model.Encoding;
+import com.bitmovin.api.sdk.model.EncodingOutput;
+import com.bitmovin.api.sdk.model.EncryptionMode;
+import com.bitmovin.api.sdk.model.Fmp4Muxing;
+import com.bitmovin.api.sdk.model.H264VideoConfiguration;
+import com.bitmovin.api.sdk.model.HlsManifest;
+import com.bitmovin.api.sdk.model.HttpInput;
+import com.bitmovin.api.sdk.model.Input;
+import com.bitmovin.api.sdk.model.MessageType;
+import com.bitmovin.api.sdk.model.MuxingStream;
+import com.bitmovin.api.sdk.model.Output;
+import com.bitmovin.api.sdk.model.Period;
+import com.bitmovin.api.sdk.model.PresetConfiguration;
+import com.bitmovin.api.sdk.model.S3Output;
+import com.bitmovin.api.sdk.model.StartEncodingRequest;
+import com.bitmovin.api.sdk.model.Status;
+import com.bitmovin.api.sdk.model.Stream;
+import com.bitmovin.api.sdk.model.StreamInfo;
+import com.bitmovin.api.sdk.model.StreamInput;
+import com.bitmovin.api.sdk.model.Task;
+import com.bitmovin.api.sdk.model.VideoAdaptationSet;
+import common.ApiClientFactory;
+import common.BudgetTag;
+import common.ConfigProvider;
+import common.EncodingFailedException;
+import common.PreviewTrimming;
+import common.SegmentSharding;
+import java.nio.file.Paths;
+import java.util.ArrayList;
+import java.util.Arrays;
+import java.util.Collections;
+import java.util.List;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+
+/**
+ * This example demonstrates how to produce two packages of the same content, one encrypted with the
+ * cenc scheme (AES-CTR) and one with the cbcs scheme (AES-CBC with pattern encryption). Devices
+ * support either or both schemes depending on their age and ecosystem, so packaging both allows to
+ * serve every device while encoding the content only once.
+ *
+ * <p>Each video and audio stream is muxed twice, by two fragmented MP4 muxings that reference the
+ * same stream. One muxing gets a CENC DRM configuration in CTR mode signaling Widevine and
+ * PlayReady, the other one a CENC DRM configuration in CBC mode additionally signaling FairPlay.
+ * The encrypted segments of each scheme are written to a separate folder, "cenc" and "cbcs". As
+ * default manifests would reference the muxings of both packages, the manifests are created
+ * manually: each package gets a DASH manifest, and the cbcs package additionally an HLS manifest,
+ * as FairPlay requires cbcs. Serve the cenc package to devices that do not support cbcs, e.g. older
+ * smart TVs and Widevine clients before version 3.5, and the cbcs package to all others.
+ *
+ * <p>Both packages use the same key, so a single license covers both. If your security requirements
+ * demand different keys per scheme, configure a different key and key ID for one of the DRM
+ * configurations.
+ *
+ * <p>The following configuration parameters are expected:
+ *
+ * <ul>
+ *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin API
+ *   <li>BITMOVIN_TENANT_ORG_ID - (optional) The ID of the Organisation in which you want to perform
+ *       the encoding.
+ *   <li>BITMOVIN_API_BASE_URL - (optional) The base URL of the Bitmovin API, e.g. of a mock server
+ *       or an API gateway. Default: https://api.bitmovin.com/v1
+ *   <li>HTTP_INPUT_HOST - The Hostname or IP address of the HTTP server hosting your input files,
+ *       e.g.: my-storage.biz
+ *   <li>HTTP_INPUT_FILE_PATH - The path to your input file on the provided HTTP server Example:
+ *       videos/1080p_Sintel.mp4
+ *   <li>S3_OUTPUT_BUCKET_NAME - The name of your S3 output bucket. Example: my-bucket-name
+ *   <li>S3_OUTPUT_ACCESS_KEY - The access key of your S3 output bucket
+ *   <li>S3_OUTPUT_SECRET_KEY - The secret key of your S3 output bucket
+ *   <li>S3_OUTPUT_BASE_PATH - The base path on your S3 output bucket where content will be written.
+ *       Example: /outputs
+ *   <li>DRM_KEY - 16 byte encryption key, represented as 32 hexadecimal characters Example:
+ *       cab5b529ae28d5cc5e3e7bc3fd4a544d
+ *   <li>DRM_FAIRPLAY_IV - 16 byte initialization vector, represented as 32 hexadecimal characters
+ *       Example: 08eecef4b026deec395234d94218273d
+ *   <li>DRM_FAIRPLAY_URI - URI of the licensing server Example:
+ *       skd://userspecifc?custom=information
+ *   <li>DRM_WIDEVINE_KID - 16 byte encryption key id, represented as 32 hexadecimal characters
+ *       Example: 08eecef4b026deec395234d94218273d
+ *   <li>DRM_WIDEVINE_PSSH - Base64 encoded PSSH payload Example: QWRvYmVhc2Rmc2FkZmFzZg==
+ *   <li>DRM_PLAYREADY_LA_URL - The URL of the PlayReady license server. Example:
+ *       https://playready.example.com/rightsmanager.asmx
+ * </ul>
+ *
+ * <p>Configuration parameters will be retrieved from these sources in the listed order:
+ *
+ * <ol>
+ *   <li>command line arguments (eg BITMOVIN_API_KEY=xyz)
+ *   <li>properties file located in the root folder of the JAVA examples at ./examples.properties
+ *       (see examples.properties.template as reference)
+ *   <li>environment variables
+ *   <li>properties file located in the home folder at ~/.bitmovin/examples.properties (see
+ *       examples.properties.template as reference)
+ * </ol>
+ */
+public class CencAndCbcsPackages {
+  private static final Logger logger = LoggerFactory.getLogger(CencAndCbcsPackages.class);
+
+  private static BitmovinApi bitmovinApi;
+  private static ConfigProvider configProvider;
+
+  /** This list defines the video renditions that will be generated */
+  private static List<VideoRendition> videoRenditions =
+      Arrays.asList(
+          new VideoRendition(1080, 4_800_000L),
+          new VideoRendition(720, 2_400_000L),
+          new VideoRendition(480, 1_200_000L),
+          new VideoRendition(360, 800_000L));
+
+  private static final String AUDIO_GROUP_ID = "audio";
+
+  public static void main(String[] args) throws Exception {
+    configProvider = new ConfigProvider(args);
+    bitmovinApi =
+        ApiClientFactory.builder(configProvider)
+            .withApiKey(configProvider.getBitmovinApiKey())
+            .build();
+
+    Encoding encoding =
+        createEncoding("CENC and cbcs packages", "Encoding with parallel cenc and cbcs packages");
+
+    HttpInput input = createHttpInput(configProvider.getHttpInputHost());
+    Output output =
+        createS3Output(
+            configProvider.getS3OutputBucketName(),
+            configProvider.getS3OutputAccessKey(),
+            configProvider.getS3OutputSecretKey());
+
+    String inputFilePath = configProvider.getHttpInputFilePath();
+
+    DrmPackage cencPackage = new DrmPackage(EncryptionScheme.CENC);
+    DrmPackage cbcsPackage = new DrmPackage(EncryptionScheme.CBCS);
+    List<DrmPackage> drmPackages = Arrays.asList(cencPackage, cbcsPackage);
+
+    for (VideoRendition videoRendition : videoRenditions) {
+      H264VideoConfiguration videoConfiguration =
+          createH264VideoConfig(videoRendition.height, videoRendition.bitrate);
+      Stream videoStream = createStream(encoding, input, inputFilePath, videoConfiguration);
+      for (DrmPackage drmPackage : drmPackages) {
+        drmPackage.videoMuxings.add(
+            createEncryptedMuxing(
+                encoding,
+                output,
+                drmPackage.scheme,
+                videoStream,
+                "video/" + videoRendition.height));
+      }
+    }
+
+    AacAudioConfiguration aacConfig = createAacAudioConfig();
+    Stream audioStream = createStream(encoding, input, inputFilePath, aacConfig);
+    for (DrmPackage drmPackage : drmPackages) {
+      drmPackage.audioMuxing =
+          createEncryptedMuxing(encoding, output, drmPackage.scheme, audioStream, "audio");
+    }
+
+    executeEncoding(encoding);
+
+    for (DrmPackage drmPackage : drmPackages) {
+      createPackageDashManifest(encoding, output, drmPackage);
+    }
+    createPackageHlsManifest(encoding, output, cbcsPackage);
+  }
+
+  /**
+   * Creates a fragmented MP4 muxing of a stream and encrypts it with the given scheme. The muxing
+   * has no output, the encrypted segments are written by the DRM configuration to the folder of the
+   * scheme instead.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsMuxingsFmp4ByEncodingId
+   *
+   * @param encoding The encoding to which the muxing will be added
+   * @param output The output resource to which the encrypted segments will be written to
+   * @param scheme The encryption scheme of the package
+   * @param stream The stream to be muxed
+   * @param segmentPath The path of the segments, relative to the folder of the package
+   */
+  private static EncryptedMuxing createEncryptedMuxing(
+      Encoding encoding,
+      Output output,
+      EncryptionScheme scheme,
+      Stream stream,
+      String segmentPath)
+      throws BitmovinException {
+    MuxingStream muxingStream = new MuxingStream();
+    muxingStream.setStreamId(stream.getId());
+
+    Fmp4Muxing muxing = new Fmp4Muxing();
+    muxing.addStreamsItem(muxingStream);
+    muxing.setSegmentLength(4.0);
+    SegmentSharding.apply(configProvider, muxing);
+    muxing = bitmovinApi.encoding.encodings.muxings.fmp4.create(encoding.getId(), muxing);
+
+    CencDrm drm =
+        createCencDrm(encoding, muxing, output, scheme, scheme.folder + "/" + segmentPath);
+
+    return new EncryptedMuxing(stream, muxing, drm, segmentPath);
+  }
+
+  /**
+   * Adds a CENC DRM configuration using the given encryption scheme to a muxing. Both schemes
+   * signal Widevine and PlayReady, the cbcs scheme additionally signals FairPlay, which only
+   * supports cbcs.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsMuxingsFmp4DrmCencByEncodingIdAndMuxingId
+   *
+   * @param encoding The encoding to which the muxing belongs to
+   * @param muxing The muxing to apply the encryption to
+   * @param output The output resource to which the encrypted segments will be written to
+   * @param scheme The encryption scheme to apply
+   * @param outputPath The output path where the encrypted segments will be written to
+   */
+  private static CencDrm createCencDrm(
+      Encoding encoding,
+      Fmp4Muxing muxing,
+      Output output,
+      EncryptionScheme scheme,
+      String outputPath)
+      throws BitmovinException {
+    CencDrm cencDrm = new CencDrm();
+    cencDrm.addOutputsItem(buildEncodingOutput(output, outputPath));
+    cencDrm.setKey(configProvider.getDrmKey());
+    cencDrm.setKid(configProvider.getDrmWidevineKid());
+    cencDrm.setEncryptionMode(scheme.encryptionMode);
+
+    CencWidevine widevine = new CencWidevine();
+    widevine.setPssh(configProvider.getDrmWidevinePssh());
+    cencDrm.setWidevine(widevine);
+
+    CencPlayReady playReady = new CencPlayReady();
+    playReady.setLaUrl(configProvider.getParameterByKey("DRM_PLAYREADY_LA_URL"));
+    cencDrm.setPlayReady(playReady);
+
+    if (scheme == EncryptionScheme.CBCS) {
+      CencFairPlay fairPlay = new CencFairPlay();
+      fairPlay.setIv(configProvider.getDrmFairplayIv());
+      fairPlay.setUri(configProvider.getDrmFairplayUri());
+      cencDrm.setFairPlay(fairPlay);
+    }
+
+    return bitmovinApi.encoding.encodings.muxings.fmp4.drm.cenc.create(
+        encoding.getId(), muxing.getId(), cencDrm);
+  }
+
+  /**
+   * Creates a DASH manifest in the folder of a package, referencing only the muxings of that
+   * package. Each adaptation set gets a ContentProtection element for the DRM configuration of its
+   * muxings.
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsDashPeriodsAdaptationsetsContentprotectionByManifestIdAndPeriodIdAndAdaptationsetId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsDashPeriodsAdaptationsetsRepresentationsFmp4DrmByManifestIdAndPeriodIdAndAdaptationsetId
+   *
+   * @param encoding The encoding to which the muxings belong to
+   * @param output The output resource to which the manifest will be written to
+   * @param drmPackage The package to create the manifest for
+   */
+  private static void createPackageDashManifest(
+      Encoding encoding, Output output, DrmPackage drmPackage) throws BitmovinException {
+    DashManifest dashManifest =
+        createDashManifest("stream.mpd", DashProfile.LIVE, output, drmPackage.scheme.folder);
+    Period period =
+        bitmovinApi.encoding.manifests.dash.periods.create(dashManifest.getId(), new Period());
+
+    VideoAdaptationSet videoAdaptationSet =
+        bitmovinApi.encoding.manifests.dash.periods.adaptationsets.video.create(
+            dashManifest.getId(), period.getId(), new VideoAdaptationSet());
+    addDashAdaptationSetMuxings(
+        encoding, dashManifest, period, videoAdaptationSet.getId(), drmPackage.videoMuxings);
+
+    AudioAdaptationSet audioAdaptationSet = new AudioAdaptationSet();
+    audioAdaptationSet.setLang("en");
+    audioAdaptationSet =
+        bitmovinApi.encoding.manifests.dash.periods.adaptationsets.audio.create(
+            dashManifest.getId(), period.getId(), audioAdaptationSet);
+    addDashAdaptationSetMuxings(
+        encoding,
+        dashManifest,
+        period,
+        audioAdaptationSet.getId(),
+        Collections.singletonList(drmPackage.audioMuxing));
+
+    executeDashManifestCreation(dashManifest);
+  }
+
+  /**
+   * Adds the ContentProtection element and a representation per encrypted muxing to an adaptation
+   * set. All muxings of a package share the same key, so the DRM configuration of the first muxing
+   * is signaled for the whole adaptation set.
+   *
+   * @param encoding The encoding to which the muxings belong to
+   * @param dashManifest The DASH manifest to add the representations to
+   * @param period The period to add the representations to
+   * @param adaptationSetId The ID of the adaptation set to add the representations to
+   * @param muxings The encrypted muxings of the adaptation set
+   */
+  private static void addDashAdaptationSetMuxings(
+      Encoding encoding,
+      DashManifest dashManifest,
+      Period period,
+      String adaptationSetId,
+      List<EncryptedMuxing> muxings)
+      throws BitmovinException {
+    ContentProtection contentProtection = new ContentProtection();
+    contentProtection.setEncodingId(encoding.getId());
+    contentProtection.setMuxingId(muxings.get(0).muxing.getId());
+    contentProtection.setDrmId(muxings.get(0).drm.getId());
+
+    bitmovinApi.encoding.manifests.dash.periods.adaptationsets.contentprotection.create(
+        dashManifest.getId(), period.getId(), adaptationSetId, contentProtection);
+
+    for (EncryptedMuxing encryptedMuxing : muxings) {
+      DashFmp4DrmRepresentation representation = new DashFmp4DrmRepresentation();
+      representation.setType(DashRepresentationType.TEMPLATE);
+      representation.setEncodingId(encoding.getId());
+      representation.setMuxingId(encryptedMuxing.muxing.getId());
+      representation.setDrmId(encryptedMuxing.drm.getId());
+      representation.setSegmentPath(encryptedMuxing.segmentPath);
+
+      bitmovinApi.encoding.manifests.dash.periods.adaptationsets.representations.fmp4.drm.create(
+          dashManifest.getId(), period.getId(), adaptationSetId, representation);
+    }
+  }
+
+  /**
+   * Creates an HLS manifest in the folder of a package, referencing only the muxings of that
+   * package.
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsHlsMediaAudioByManifestId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsHlsStreamsByManifestId
+   *
+   * @param encoding The encoding to which the muxings belong to
+   * @param output The output resource to which the manifest will be written to
+   * @param drmPackage The package to create the manifest for
+   */
+  private static void createPackageHlsManifest(
+      Encoding encoding, Output output, DrmPackage drmPackage) throws BitmovinException {
+    HlsManifest hlsManifest =
+        createHlsMasterManifest("master.m3u8", output, drmPackage.scheme.folder);
+
+    EncryptedMuxing audioMuxing = drmPackage.audioMuxing;
+    AudioMediaInfo audioMediaInfo = new AudioMediaInfo();
+    audioMediaInfo.setName("English");
+    audioMediaInfo.setUri("audio.m3u8");
+    audioMediaInfo.setGroupId(AUDIO_GROUP_ID);
+    audioMediaInfo.setEncodingId(encoding.getId());
+    audioMediaInfo.setStreamId(audioMuxing.stream.getId());
+    audioMediaInfo.setMuxingId(audioMuxing.muxing.getId());
+    audioMediaInfo.setDrmId(audioMuxing.drm.getId());
+    audioMediaInfo.setLanguage("en");
+    audioMediaInfo.setAutoselect(true);
+    audioMediaInfo.setIsDefault(true);
+    audioMediaInfo.setSegmentPath(audioMuxing.segmentPath);
+
+    bitmovinApi.encoding.manifests.hls.media.audio.create(hlsManifest.getId(), audioMediaInfo);
+
+    for (EncryptedMuxing videoMuxing : drmPackage.videoMuxings) {
+      StreamInfo streamInfo = new StreamInfo();
+      streamInfo.setUri(videoMuxing.segmentPath.replace('/', '_') + ".m3u8");
+      streamInfo.setEncodingId(encoding.getId());
+      streamInfo.setStreamId(videoMuxing.stream.getId());
+      streamInfo.setMuxingId(videoMuxing.muxing.getId());
+      streamInfo.setDrmId(videoMuxing.drm.getId());
+      streamInfo.setAudio(AUDIO_GROUP_ID);
+      streamInfo.setSegmentPath(videoMuxing.segmentPath);
+
+      bitmovinApi.encoding.manifests.hls.streams.create(hlsManifest.getId(), streamInfo);
+    }
+
+    executeHlsManifestCreation(hlsManifest);
+  }
+
+  /** The encryption schemes a package is created for, and the folder the package is written to */
+  private enum EncryptionScheme {
+    CENC(EncryptionMode.CTR, "cenc"),
+    CBCS(EncryptionMode.CBC, "cbcs");
+
+    private final EncryptionMode encryptionMode;
+    private final String folder;
+
+    EncryptionScheme(EncryptionMode encryptionMode, String folder) {
+      this.encryptionMode = encryptionMode;
+      this.folder = folder;
+    }
+  }
+
+  /** The encrypted muxings of a package */
+  private static class DrmPackage {
+
+    private final EncryptionScheme scheme;
+    private final List<EncryptedMuxing> videoMuxings = new ArrayList<>();
+    private EncryptedMuxing audioMuxing;
+
+    private DrmPackage(EncryptionScheme scheme) {
+      this.scheme = scheme;
+    }
+  }
+
+  private static class EncryptedMuxing {
+
+    private final Stream stream;
+    private final Fmp4Muxing muxing;
+    private final CencDrm drm;
+    private final String segmentPath;
+
+    /**
+     * @param stream The muxed stream
+     * @param muxing The muxing without output
+     * @param drm The DRM configuration writing the encrypted segments
+     * @param segmentPath The path of the segments, relative to the folder of the package
+     */
+    private EncryptedMuxing(Stream stream, Fmp4Muxing muxing, CencDrm drm, String segmentPath) {
+      this.stream = stream;
+      this.muxing = muxing;
+      this.drm = drm;
+      this.segmentPath = segmentPath;
+    }
+  }
+
+  private static class VideoRendition {
+
+    private int height;
+    private long bitrate;
+
+    /**
+     * @param height The target output height of the rendition
+     * @param bitrate The target output bitrate of the rendition
+     */
+    private VideoRendition(int height, long bitrate) {
+      this.height = height;
+      this.bitrate = bitrate;
+    }
+  }
+
+  /**
+   * Creates a resource representing an HTTP server providing the input files. For alternative input
+   * methods see <a
+   * href="https://bitmovin.com/docs/encoding/articles/supported-input-output-storages">list of
+   * supported input and output storages</a>
+   *
+   * <p>For reasons of simplicity, a new input resource is created on each execution of this
+   * example. In production use, this method should be replaced by a <a
+   * href="https://bitmovin.com/docs/encoding/api-reference/sections/inputs#/Encoding/GetEncodingInputsHttpByInputId">get
+   * call</a> to retrieve an existing resource.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/inputs#/Encoding/PostEncodingInputsHttp
+   *
+   * @param host The hostname or IP address of the HTTP server e.g.: my-storage.biz
+   */
+  private static HttpInput createHttpInput(String host) throws BitmovinException {
+    HttpInput input = new HttpInput();
+    input.setHost(host);
+
+    return bitmovinApi.encoding.inputs.http.create(input);
+  }
+
+  /**
+   * Creates a resource representing an AWS S3 cloud storage bucket to which generated content will
+   * be transferred. For alternative output methods see <a
+   * href="https://bitmovin.com/docs/encoding/articles/supported-input-output-storages">list of
+   * supported input and output storages</a>
+   *
+   * <p>The provided credentials need to allow <i>read</i>, <i>write</i> and <i>list</i> operations.
+   * <i>delete</i> should also be granted to allow overwriting of existings files. See <a
+   * href="https://bitmovin.com/docs/encoding/faqs/how-do-i-create-a-aws-s3-bucket-which-can-be-used-as-output-location">creating
+   * an S3 bucket and setting permissions</a> for further information
+   *
+   * <p>For reasons of simplicity, a new output resource is created on each execution of this
+   * example. In production use, this method should be replaced by a <a
+   * href="https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/GetEncodingOutputsS3">get
+   * call</a> retrieving an existing resource.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/PostEncodingOutputsS3
+   *
+   * @param bucketName The name of the S3 bucket
+   * @param accessKey The access key of your S3 account
+   * @param secretKey The secret key of your S3 account
+   */
+  private static S3Output createS3Output(String bucketName, String accessKey, String secretKey)
+      throws BitmovinException {
+
+    S3Output s3Output = new S3Output();
+    s3Output.setBucketName(bucketName);
+    s3Output.setAccessKey(accessKey);
+    s3Output.setSecretKey(secretKey);
+
+    return bitmovinApi.encoding.outputs.s3.create(s3Output);
+  }
+
+  /**
+   * Creates an encoding object. This is the base object to configure your encoding.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodings
+   *
+   * @param name This is the name of the encoding
+   * @param description This is the description of the encoding
+   */
+  private static Encoding createEncoding(String name, String description) throws BitmovinException {
+    Encoding encoding = new Encoding();
+    encoding.setName(name);
+    encoding.setDescription(description);
+    new BudgetTag(configProvider).applyTo(encoding);
+
+    return bitmovinApi.encoding.encodings.create(encoding);
+  }
+
+  /**
+   * Creates a stream which binds an input file to a codec configuration. The stream is used for
+   * muxings later on.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsStreamsByEncodingId
+   *
+   * @param encoding The encoding to add the stream onto
+   * @param input The input that should be used
+   * @param inputPath The path to the input file
+   * @param codecConfiguration The codec configuration to be applied to the stream
+   */
+  private static Stream createStream(
+      Encoding encoding, Input input, String inputPath, CodecConfiguration codecConfiguration)
+      throws BitmovinException {
+    StreamInput streamInput =
+        PreviewTrimming.createStreamInput(bitmovinApi, configProvider, encoding, input, inputPath);
+
+    Stream stream = new Stream();
+    stream.addInputStreamsItem(streamInput);
+    stream.setCodecConfigId(codecConfiguration.getId());
+
+    return bitmovinApi.encoding.encodings.streams.create(encoding.getId(), stream);
+  }
+
+  /**
+   * Creates a configuration for the H.264 video codec to be applied to video streams.
+   *
+   * <p>The output resolution is defined by setting only the height. Width will be determined
+   * automatically to maintain the aspect ratio of your input video.
+   *
+   * <p>To keep things simple, we use a quality-optimized VoD preset configuration, which will apply
+   * proven settings for the codec. See <a
+   * href="https://bitmovin.com/docs/encoding/tutorials/how-to-optimize-your-h264-codec-configuration-for-different-use-cases">How
+   * to optimize your H264 codec configuration for different use-cases</a> for alternative presets.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsVideoH264
+   *
+   * @param height The height of the output video
+   * @param bitrate The target bitrate of the output video
+   */
+  private static H264VideoConfiguration createH264VideoConfig(int height, long bitrate)
+      throws BitmovinException {
+    H264VideoConfiguration config = new H264VideoConfiguration();
+    config.setName(String.format("H.264 %dp", height));
+    config.setPresetConfiguration(PresetConfiguration.VOD_STANDARD);
+    config.setHeight(height);
+    config.setBitrate(bitrate);
+
+    return bitmovinApi.encoding.configurations.video.h264.create(config);
+  }
+
+  /**
+   * Creates a configuration for the AAC audio codec to be applied to audio streams.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsAudioAac
+   */
+  private static AacAudioConfiguration createAacAudioConfig() throws BitmovinException {
+    AacAudioConfiguration config = new AacAudioConfiguration();
+    config.setName("AAC 128 kbit/s");
+    config.setBitrate(128_000L);
+
+    return bitmovinApi.encoding.configurations.audio.aac.create(config);
+  }
+
+  /**
+   * Builds an EncodingOutput object which defines where the output content (e.g. of a muxing) will
+   * be written to. Public read permissions will be set for the files written, so they can be
+   * accessed easily via HTTP.
+   *
+   * @param output The output resource to be used by the EncodingOutput
+   * @param outputPath The path where the content will be written to
+   */
+  private static EncodingOutput buildEncodingOutput(Output output, String outputPath) {
+    AclEntry aclEntry = new AclEntry();
+    aclEntry.setPermission(AclPermission.PUBLIC_READ);
+
+    EncodingOutput encodingOutput = new EncodingOutput();
+    encodingOutput.setOutputPath(buildAbsolutePath(outputPath));
+    encodingOutput.setOutputId(output.getId());
+    encodingOutput.addAclItem(aclEntry);
+    return encodingOutput;
+  }
+
+  /**
+   * Builds an absolute path by concatenating the S3_OUTPUT_BASE_PATH configuration parameter, the
+   * name of this example class and the given relative path
+   *
+   * <p>e.g.: /s3/base/path/ClassName/relative/path
+   *
+   * @param relativePath The relative path that is concatenated
+   * @return The absolute path
+   */
+  public static String buildAbsolutePath(String relativePath) {
+    String className = CencAndCbcsPackages.class.getSimpleName();
+    return Paths.get(configProvider.getS3OutputBasePath(), className, relativePath).toString();
+  }
+
+  /**
+   * Starts the actual encoding process and periodically polls its status until it reaches a final
+   * state
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/all#/Encoding/PostEncodingEncodingsStartByEncodingId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/GetEncodingEncodingsStatusByEncodingId
+   *
+   * <p>Please note that you can also use our webhooks API instead of polling the status. For more
+   * information consult the API spec:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/notifications-webhooks
+   *
+   * @param encoding The encoding to be started
+   */
+  private static void executeEncoding(Encoding encoding)
+      throws InterruptedException, BitmovinException {
+    bitmovinApi.encoding.encodings.start(encoding.getId(), new StartEncodingRequest());
+
+    Task task;
+    do {
+      Thread.sleep(5000);
+      task = bitmovinApi.encoding.encodings.status(encoding.getId());
+      logger.info("encoding status is {} (progress: {} %)", task.getStatus(), task.getProgress());
+    } while (task.getStatus() != Status.FINISHED
+        && task.getStatus() != Status.ERROR
+        && task.getStatus() != Status.CANCELED);
+
+    if (task.getStatus() != Status.FINISHED) {
+      logTaskErrors(task);
+      throw new EncodingFailedException(task.getStatus());
+    }
+    logger.info("encoding finished successfully");
+  }
+
+  /** Creates the HLS master manifest. */
+  private static HlsManifest createHlsMasterManifest(String name, Output output, String outputPath)
+      throws BitmovinException {
+
+    HlsManifest hlsManifest = new HlsManifest();
+    hlsManifest.setName(name);
+    hlsManifest.addOutputsItem(buildEncodingOutput(output, outputPath));
+
+    return bitmovinApi.encoding.manifests.hls.create(hlsManifest);
+  }
+
+  /**
+   * Creates a DASH manifest
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsDash
+   *
+   * @param name the resource name
+   * @param dashProfile the DASH profile of the manifest (ON_DEMAND, LIVE)
+   * @param output the output of the manifest
+   * @param outputPath the output path where the manifest is written to
+   * @return the created manifest
+   */
+  private static DashManifest createDashManifest(
+      String name, DashProfile dashProfile, Output output, String outputPath)
+      throws BitmovinException {
+    DashManifest dashManifest = new DashManifest();
+    dashManifest.setName(name);
+    dashManifest.setProfile(dashProfile);
+    dashManifest.addOutputsItem(buildEncodingOutput(output, outputPath));
+
+    return bitmovinApi.encoding.manifests.dash.create(dashManifest);
+  }
+
+  /**
+   * Starts the HLS manifest creation and periodically polls its status until it reaches a final
+   * state
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsHlsStartByManifestId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/GetEncodingManifestsHlsStatusByManifestId
+   *
+   * @param hlsManifest The HLS manifest to be created
+   */
+  private static void executeHlsManifestCreation(HlsManifest hlsManifest)
+      throws BitmovinException, InterruptedException {
+
+    bitmovinApi.encoding.manifests.hls.start(hlsManifest.getId());
+
+    Task task;
+    do {
+      Thread.sleep(1000);
+      task = bitmovinApi.encoding.manifests.hls.status(hlsManifest.getId());
+    } while (task.getStatus() != Status.FINISHED && task.getStatus() != Status.ERROR);
+
+    if (task.getStatus() == Status.ERROR) {
+      logTaskErrors(task);
+      throw new RuntimeException("HLS manifest creation failed");
+    }
+    logger.info("HLS manifest creation finished successfully");
+  }
+
+  /**
+   * Starts the DASH manifest creation and periodically polls its status until it reaches a final
+   * state
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsDashStartByManifestId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/GetEncodingManifestsDashStatusByManifestId
+   *
+   * @param dashManifest The DASH manifest to be created
+   */
+  private static void executeDashManifestCreation(DashManifest dashManifest)
+      throws BitmovinException, InterruptedException {
+    bitmovinApi.encoding.manifests.dash.start(dashManifest.getId());
+
+    Task task;
+    do {
+      Thread.sleep(1000);
+      task = bitmovinApi.encoding.manifests.dash.status(dashManifest.getId());
+    } while (task.getStatus() != Status.FINISHED && task.getStatus() != Status.ERROR);
+
+    if (task.getStatus() == Status.ERROR) {
+      logTaskErrors(task);
+      throw new RuntimeException("DASH manifest creation failed");
+    }
+    logger.info("DASH manifest creation finished successfully");
+  }
+
+  private static void logTaskErrors(Task task) {
+    task.getMessages().stream()
+        .filter(msg -> msg.getType() == MessageType.ERROR)
+        .forEach(msg -> logger.error(msg.getText()));
+  }
+}
@@ -30,12 +30,16 @@ import com.bitmovin.api.sdk.model.Status;
 import com.bitmovin.api.sdk.model.Stream;
 import com.bitmovin.api.sdk.model.StreamInput;
 import com.bitmovin.api.sdk.model.StreamMode;
-import com.bitmovin.api.sdk.model.StreamSelectionMode;
 import com.bitmovin.api.sdk.model.Task;
+import common.ApiClientFactory;
+import common.BudgetTag;
 import common.ConfigProvider;
-import feign.Logger.Level;
-import feign.slf4j.Slf4jLogger;
+import common.ConfigProvider.MissingArgumentException;
+import common.EncodingFailedException;
+import common.PreviewTrimming;
+import common.SegmentSharding;
 import java.nio.file.Paths;
+import java.util.UUID;
 import org.slf4j.Logger;
 import org.slf4j.LoggerFactory;
 
@@ -44,12 +48,20 @@ import org.slf4j.LoggerFactory;
  * encryption is configured to be compatible with both FairPlay and Widevine, using the MPEG-CENC
  * standard.
  *
+ * <p>Both DRM systems are optional: if only the Widevine configuration parameters are set, the
+ * content is protected for Widevine only, and if only the FairPlay configuration parameters are
+ * set, for FairPlay only. At least one of them has to be configured. The configuration is checked
+ * before any resource is created, so a missing parameter does not leave an incomplete encoding
+ * behind.
+ *
  * <p>The following configuration parameters are expected:
  *
  * <ul>
  *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin API
  *   <li>BITMOVIN_TENANT_ORG_ID - (optional) The ID of the Organisation in which you want to perform
  *       the encoding.
+ *   <li>BITMOVIN_API_BASE_URL - (optional) The base URL of the Bitmovin API, e.g. of a mock server
+ *       or an API gateway. Default: https://api.bitmovin.com/v1
  *   <li>HTTP_INPUT_HOST - The Hostname or IP address of the HTTP server hosting your input files,
  *       e.g.: my-storage.biz
  *   <li>HTTP_INPUT_FILE_PATH - The path to your input file on the provided HTTP server Example:
@@ -61,13 +73,15 @@ import org.slf4j.LoggerFactory;
  *       Example: /outputs
  *   <li>DRM_KEY - 16 byte encryption key, represented as 32 hexadecimal characters Example:
  *       cab5b529ae28d5cc5e3e7bc3fd4a544d
- *   <li>DRM_FAIRPLAY_IV - 16 byte initialization vector, represented as 32 hexadecimal characters
- *       Example: 08eecef4b026deec395234d94218273d
- *   <li>DRM_FAIRPLAY_URI - URI of the licensing server Example:
- *       skd://userspecifc?custom=information
- *   <li>DRM_WIDEVINE_KID - 16 byte encryption key id, represented as 32 hexadecimal characters
- *       Example: 08eecef4b026deec395234d94218273d
- *   <li>DRM_WIDEVINE_PSSH - Base64 encoded PSSH payload Example: QWRvYmVhc2Rmc2FkZmFzZg==
+ *   <li>DRM_FAIRPLAY_IV - (optional) 16 byte initialization vector, represented as 32 hexadecimal
+ *       characters, required for FairPlay Example: 08eecef4b026deec395234d94218273d
+ *   <li>DRM_FAIRPLAY_URI - (optional) URI of the licensing server, required if DRM_FAIRPLAY_IV is
+ *       set Example: skd://userspecifc?custom=information
+ *   <li>DRM_WIDEVINE_KID - (optional) 16 byte encryption key id, represented as 32 hexadecimal
+ *       characters, required for Widevine. If only FairPlay is configured, a random key id is used
+ *       if it is not set Example: 08eecef4b026deec395234d94218273d
+ *   <li>DRM_WIDEVINE_PSSH - (optional) Base64 encoded PSSH payload, required for Widevine Example:
+ *       QWRvYmVhc2Rmc2FkZmFzZg==
  * </ul>
  *
  * <p>Configuration parameters will be retrieved from these sources in the listed order:
@@ -90,14 +104,12 @@ public class CencDrmContentProtection {
   public static void main(String[] args) throws Exception {
     configProvider = new ConfigProvider(args);
     bitmovinApi =
-        BitmovinApi.builder()
+        ApiClientFactory.builder(configProvider)
             .withApiKey(configProvider.getBitmovinApiKey())
-            // uncomment the following line if you are working with a multi-tenant account
-            // .withTenantOrgId(configProvider.getBitmovinTenantOrgId())
-            .withLogger(
-                new Slf4jLogger(), Level.BASIC) // set the logger and log level for the API client
             .build();
 
+    DrmSettings drmSettings = readDrmSettings();
+
     Encoding encoding =
         createEncoding("fMP4 muxing with CENC DRM", "Example with CENC DRM content protection");
 
@@ -119,8 +131,8 @@ public class CencDrmContentProtection {
     Fmp4Muxing videoMuxing = createFmp4Muxing(encoding, videoStream);
     Fmp4Muxing audioMuxing = createFmp4Muxing(encoding, audioStream);
 
-    createDrmConfig(encoding, videoMuxing, output, "video");
-    createDrmConfig(encoding, audioMuxing, output, "audio");
+    createDrmConfig(encoding, videoMuxing, output, "video", drmSettings);
+    createDrmConfig(encoding, audioMuxing, output, "audio", drmSettings);
 
     executeEncoding(encoding);
 
@@ -141,6 +153,7 @@ public class CencDrmContentProtection {
     Encoding encoding = new Encoding();
     encoding.setName(name);
     encoding.setDescription(description);
+    new BudgetTag(configProvider).applyTo(encoding);
 
     return bitmovinApi.encoding.encodings.create(encoding);
   }
@@ -159,10 +172,8 @@ public class CencDrmContentProtection {
   private static Stream createStream(
       Encoding encoding, Input input, String inputPath, CodecConfiguration codecConfiguration)
       throws BitmovinException {
-    StreamInput streamInput = new StreamInput();
-    streamInput.setInputId(input.getId());
-    streamInput.setInputPath(inputPath);
-    streamInput.setSelectionMode(StreamSelectionMode.AUTO);
+    StreamInput streamInput =
+        PreviewTrimming.createStreamInput(bitmovinApi, configProvider, encoding, input, inputPath);
 
     Stream stream = new Stream();
     stream.addInputStreamsItem(streamInput);
@@ -244,6 +255,7 @@ public class CencDrmContentProtection {
       throws BitmovinException {
     Fmp4Muxing muxing = new Fmp4Muxing();
     muxing.setSegmentLength(4.0);
+    SegmentSharding.apply(configProvider, muxing);
 
     MuxingStream muxingStream = new MuxingStream();
     muxingStream.setStreamId(stream.getId());
@@ -252,10 +264,40 @@ public class CencDrmContentProtection {
     return bitmovinApi.encoding.encodings.muxings.fmp4.create(encoding.getId(), muxing);
   }
 
+  /**
+   * Reads the DRM configuration parameters and checks that at least one DRM system is configured
+   * completely. Only the parameters of the configured DRM systems are required.
+   */
+  private static DrmSettings readDrmSettings() {
+    DrmSettings drmSettings = new DrmSettings();
+    drmSettings.widevinePssh = configProvider.getParameterByKey("DRM_WIDEVINE_PSSH", null);
+    drmSettings.fairPlayIv = configProvider.getParameterByKey("DRM_FAIRPLAY_IV", null);
+    if (drmSettings.widevinePssh == null && drmSettings.fairPlayIv == null) {
+      throw new MissingArgumentException(
+          "DRM_WIDEVINE_PSSH or DRM_FAIRPLAY_IV",
+          "Neither Widevine nor FairPlay is configured, set the parameters of one or both of them");
+    }
+
+    drmSettings.key = configProvider.getDrmKey();
+    if (drmSettings.widevinePssh != null) {
+      drmSettings.kid = configProvider.getDrmWidevineKid();
+    } else {
+      // FairPlay players retrieve the key from the licensing server without using the key id
+      drmSettings.kid =
+          configProvider.getParameterByKey(
+              "DRM_WIDEVINE_KID", UUID.randomUUID().toString().replace("-", ""));
+    }
+    if (drmSettings.fairPlayIv != null) {
+      drmSettings.fairPlayUri = configProvider.getDrmFairplayUri();
+    }
+
+    return drmSettings;
+  }
+
   /**
    * Adds an MPEG-CENC DRM configuration to the muxing to encrypt its output. Widevine and FairPlay
    * specific fields will be included into DASH and HLS manifests to enable key retrieval using
-   * either DRM method.
+   * either DRM method. A DRM method is only included if its configuration parameters are set.
    *
    * <p>API endpoint:
    * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsMuxingsFmp4DrmCencByEncodingIdAndMuxingId
@@ -264,22 +306,32 @@ public class CencDrmContentProtection {
    * @param muxing The muxing to apply the encryption to
    * @param output The output resource to which the encrypted segments will be written to
    * @param outputPath The output path where the encrypted segments will be written to
+   * @param drmSettings The keys and the configuration of the DRM systems
    */
   private static CencDrm createDrmConfig(
-      Encoding encoding, Muxing muxing, Output output, String outputPath) throws BitmovinException {
+      Encoding encoding,
+      Muxing muxing,
+      Output output,
+      String outputPath,
+      DrmSettings drmSettings)
+      throws BitmovinException {
     CencDrm cencDrm = new CencDrm();
     cencDrm.addOutputsItem(buildEncodingOutput(output, outputPath));
-    cencDrm.setKey(configProvider.getDrmKey());
-    cencDrm.setKid(configProvider.getDrmWidevineKid());
+    cencDrm.setKey(drmSettings.key);
+    cencDrm.setKid(drmSettings.kid);
 
-    CencWidevine widevineDrm = new CencWidevine();
-    widevineDrm.setPssh(configProvider.getDrmWidevinePssh());
-    cencDrm.setWidevine(widevineDrm);
+    if (drmSettings.widevinePssh != null) {
+      CencWidevine widevineDrm = new CencWidevine();
+      widevineDrm.setPssh(drmSettings.widevinePssh);
+      cencDrm.setWidevine(widevineDrm);
+    }
 
-    CencFairPlay cencFairPlay = new CencFairPlay();
-    cencFairPlay.setIv(configProvider.getDrmFairplayIv());
-    cencFairPlay.setUri(configProvider.getDrmFairplayUri());
-    cencDrm.setFairPlay(cencFairPlay);
+    if (drmSettings.fairPlayIv != null) {
+      CencFairPlay cencFairPlay = new CencFairPlay();
+      cencFairPlay.setIv(drmSettings.fairPlayIv);
+      cencFairPlay.setUri(drmSettings.fairPlayUri);
+      cencDrm.setFairPlay(cencFairPlay);
+    }
 
     return bitmovinApi.encoding.encodings.muxings.fmp4.drm.cenc.create(
         encoding.getId(), muxing.getId(), cencDrm);
@@ -379,11 +431,13 @@ public class CencDrmContentProtection {
       Thread.sleep(5000);
       task = bitmovinApi.encoding.encodings.status(encoding.getId());
       logger.info("encoding status is {} (progress: {} %)", task.getStatus(), task.getProgress());
-    } while (task.getStatus() != Status.FINISHED && task.getStatus() != Status.ERROR);
+    } while (task.getStatus() != Status.FINISHED
+        && task.getStatus() != Status.ERROR
+        && task.getStatus() != Status.CANCELED);
 
-    if (task.getStatus() == Status.ERROR) {
+    if (task.getStatus() != Status.FINISHED) {
       logTaskErrors(task);
-      throw new RuntimeException("Encoding failed");
+      throw new EncodingFailedException(task.getStatus());
     }
     logger.info("encoding finished successfully");
   }
@@ -494,4 +548,14 @@ public class CencDrmContentProtection {
         .filter(msg -> msg.getType() == MessageType.ERROR)
         .forEach(msg -> logger.error(msg.getText()));
   }
+
+  /** Helper class holding the keys and the configuration of the DRM systems */
+  private static class DrmSettings {
+
+    private String key;
+    private String kid;
+    private String widevinePssh;
+    private String fairPlayIv;
+    private String fairPlayUri;
+  }
 }
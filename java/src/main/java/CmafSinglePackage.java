@@ -0,0 +1,626 @@
+import com.bitmovin.api.sdk.BitmovinApi;
+import com.bitmovin.api.sdk.common.BitmovinException;
+import com.bitmovin.api.sdk.model.AacAudioConfiguration;
+import com.bitmovin.api.sdk.model.AclEntry;
+import com.bitmovin.api.sdk.model.AclPermission;
+import com.bitmovin.api.sdk.model.AudioAdaptationSet;
+import com.bitmovin.api.sdk.model.AudioMediaInfo;
+import com.bitmovin.api.sdk.model.CmafMuxing;
+import com.bitmovin.api.sdk.model.CodecConfiguration;
+import com.bitmovin.api.sdk.model.DashCmafRepresentation;
+import com.bitmovin.api.sdk.model.DashManifest;
+import com.bitmovin.api.sdk.model.DashProfile;
+import com.bitmovin.api.sdk.model.DashRepresentationType;
+import com.bitmovin.api.sdk.model.Encoding;
+import com.bitmovin.api.sdk.model.EncodingOutput;
+import com.bitmovin.api.sdk.model.H264VideoConfiguration;
+import com.bitmovin.api.sdk.model.HlsManifest;
+import com.bitmovin.api.sdk.model.HttpInput;
+import com.bitmovin.api.sdk.model.Input;
+import com.bitmovin.api.sdk.model.MessageType;
+import com.bitmovin.api.sdk.model.MuxingStream;
+import com.bitmovin.api.sdk.model.Output;
+import com.bitmovin.api.sdk.model.Period;
+import com.bitmovin.api.sdk.model.PresetConfiguration;
+import com.bitmovin.api.sdk.model.S3Output;
+import com.bitmovin.api.sdk.model.StartEncodingRequest;
+import com.bitmovin.api.sdk.model.Status;
+import com.bitmovin.api.sdk.model.Stream;
+import com.bitmovin.api.sdk.model.StreamInfo;
+import com.bitmovin.api.sdk.model.StreamInput;
+import com.bitmovin.api.sdk.model.Task;
+import com.bitmovin.api.sdk.model.VideoAdaptationSet;
+import common.ApiClientFactory;
+import common.BudgetTag;
+import common.ConfigProvider;
+import common.EncodingFailedException;
+import common.PreviewTrimming;
+import common.SegmentSharding;
+import java.nio.file.Paths;
+import java.util.Arrays;
+import java.util.List;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+
+/**
+ * This example demonstrates how to package content once in CMAF and deliver it with both HLS and
+ * DASH. Only one set of fragmented MP4 segments is written per rendition, and both the HLS
+ * multi-variant playlist and the DASH MPD reference the same files, so no content is stored twice
+ * and CDN caches are shared between the two protocols.
+ *
+ * <p>Both manifests are created manually, so it is visible that the HLS variant streams and audio
+ * media playlists as well as the DASH representations point to the same CMAF muxings and segment
+ * paths. Video and audio are packaged into separate muxings, as CMAF requires one track per file
+ * and HLS needs audio to be referenced as an alternative rendition in that case.
+ *
+ * <p>The segments are addressed individually, i.e. each segment is written to a separate file that
+ * is referenced by a segment template in DASH and by its own URI in the HLS media playlists. This
+ * works with any HTTP server or CDN and allows every segment to be cached independently. Byte-range
+ * addressing, where all fragments of a rendition are stored in one file and referenced by byte
+ * offsets, reduces the number of files on the storage, but requires the CDN to support and cache
+ * range requests and is only available for progressive MP4 muxings.
+ *
+ * <p>The following configuration parameters are expected:
+ *
+ * <ul>
+ *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin API
+ *   <li>BITMOVIN_TENANT_ORG_ID - (optional) The ID of the Organisation in which you want to perform
+ *       the encoding.
+ *   <li>BITMOVIN_API_BASE_URL - (optional) The base URL of the Bitmovin API, e.g. of a mock server
+ *       or an API gateway. Default: https://api.bitmovin.com/v1
+ *   <li>HTTP_INPUT_HOST - The Hostname or IP address of the HTTP server hosting your input files,
+ *       e.g.: my-storage.biz
+ *   <li>HTTP_INPUT_FILE_PATH - The path to your input file on the provided HTTP server Example:
+ *       videos/1080p_Sintel.mp4
+ *   <li>S3_OUTPUT_BUCKET_NAME - The name of your S3 output bucket. Example: my-bucket-name
+ *   <li>S3_OUTPUT_ACCESS_KEY - The access key of your S3 output bucket
+ *   <li>S3_OUTPUT_SECRET_KEY - The secret key of your S3 output bucket
+ *   <li>S3_OUTPUT_BASE_PATH - The base path on your S3 output bucket where content will be written.
+ *       Example: /outputs
+ * </ul>
+ *
+ * <p>Configuration parameters will be retrieved from these sources in the listed order:
+ *
+ * <ol>
+ *   <li>command line arguments (eg BITMOVIN_API_KEY=xyz)
+ *   <li>properties file located in the root folder of the JAVA examples at ./examples.properties
+ *       (see examples.properties.template as reference)
+ *   <li>environment variables
+ *   <li>properties file located in the home folder at ~/.bitmovin/examples.properties (see
+ *       examples.properties.template as reference)
+ * </ol>
+ */
+public class CmafSinglePackage {
+  private static final Logger logger = LoggerFactory.getLogger(CmafSinglePackage.class);
+
+  private static BitmovinApi bitmovinApi;
+  private static ConfigProvider configProvider;
+
+  /** This list defines the video renditions that will be generated */
+  private static List<VideoRendition> videoRenditions =
+      Arrays.asList(
+          new VideoRendition(1080, 4_800_000L),
+          new VideoRendition(720, 2_400_000L),
+          new VideoRendition(480, 1_200_000L),
+          new VideoRendition(360, 800_000L));
+
+  private static final String AUDIO_GROUP_ID = "audio";
+  private static final String AUDIO_SEGMENT_PATH = "audio";
+
+  public static void main(String[] args) throws Exception {
+    configProvider = new ConfigProvider(args);
+    bitmovinApi =
+        ApiClientFactory.builder(configProvider)
+            .withApiKey(configProvider.getBitmovinApiKey())
+            .build();
+
+    Encoding encoding =
+        createEncoding("CMAF single package", "Encoding with CMAF segments for HLS and DASH");
+
+    HttpInput input = createHttpInput(configProvider.getHttpInputHost());
+    Output output =
+        createS3Output(
+            configProvider.getS3OutputBucketName(),
+            configProvider.getS3OutputAccessKey(),
+            configProvider.getS3OutputSecretKey());
+
+    String inputFilePath = configProvider.getHttpInputFilePath();
+
+    for (VideoRendition videoRendition : videoRenditions) {
+      H264VideoConfiguration videoConfiguration =
+          createH264VideoConfig(videoRendition.height, videoRendition.bitrate);
+      videoRendition.stream = createStream(encoding, input, inputFilePath, videoConfiguration);
+      videoRendition.muxing =
+          createCmafMuxing(
+              encoding, output, videoRendition.getSegmentPath(), videoRendition.stream);
+    }
+
+    AacAudioConfiguration aacConfig = createAacAudioConfig();
+    Stream audioStream = createStream(encoding, input, inputFilePath, aacConfig);
+    CmafMuxing audioMuxing = createCmafMuxing(encoding, output, AUDIO_SEGMENT_PATH, audioStream);
+
+    executeEncoding(encoding);
+
+    DashManifest dashManifest = createDashManifest("stream.mpd", DashProfile.LIVE, output, "/");
+    addDashRepresentations(encoding, dashManifest, audioMuxing);
+    executeDashManifestCreation(dashManifest);
+
+    HlsManifest hlsManifest = createHlsMasterManifest("master.m3u8", output, "/");
+    addHlsPlaylists(encoding, hlsManifest, audioStream, audioMuxing);
+    executeHlsManifestCreation(hlsManifest);
+  }
+
+  /**
+   * Adds a period with one video and one audio adaptation set to the DASH manifest, which contain
+   * a representation for each of the CMAF muxings.
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsDashPeriodsByManifestId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsDashPeriodsAdaptationsetsVideoByManifestIdAndPeriodId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsDashPeriodsAdaptationsetsAudioByManifestIdAndPeriodId
+   *
+   * @param encoding The encoding to which the muxings belong to
+   * @param dashManifest The DASH manifest to add the representations to
+   * @param audioMuxing The CMAF muxing of the audio stream
+   */
+  private static void addDashRepresentations(
+      Encoding encoding, DashManifest dashManifest, CmafMuxing audioMuxing)
+      throws BitmovinException {
+    Period period =
+        bitmovinApi.encoding.manifests.dash.periods.create(dashManifest.getId(), new Period());
+
+    VideoAdaptationSet videoAdaptationSet =
+        bitmovinApi.encoding.manifests.dash.periods.adaptationsets.video.create(
+            dashManifest.getId(), period.getId(), new VideoAdaptationSet());
+
+    for (VideoRendition videoRendition : videoRenditions) {
+      createDashCmafRepresentation(
+          encoding,
+          videoRendition.muxing,
+          dashManifest,
+          period,
+          videoRendition.getSegmentPath(),
+          videoAdaptationSet.getId());
+    }
+
+    AudioAdaptationSet audioAdaptationSet = new AudioAdaptationSet();
+    audioAdaptationSet.setLang("en");
+    audioAdaptationSet =
+        bitmovinApi.encoding.manifests.dash.periods.adaptationsets.audio.create(
+            dashManifest.getId(), period.getId(), audioAdaptationSet);
+
+    createDashCmafRepresentation(
+        encoding,
+        audioMuxing,
+        dashManifest,
+        period,
+        AUDIO_SEGMENT_PATH,
+        audioAdaptationSet.getId());
+  }
+
+  /**
+   * Creates a DASH representation for a CMAF muxing, which references its segments with a segment
+   * template.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsDashPeriodsAdaptationsetsRepresentationsCmafByManifestIdAndPeriodIdAndAdaptationsetId
+   *
+   * @param encoding The encoding to which the muxing belongs to
+   * @param muxing The CMAF muxing referenced by the representation
+   * @param dashManifest The DASH manifest to add the representation to
+   * @param period The period to add the representation to
+   * @param segmentPath The path to the segments, relative to the manifest
+   * @param adaptationSetId The ID of the adaptation set to add the representation to
+   */
+  private static void createDashCmafRepresentation(
+      Encoding encoding,
+      CmafMuxing muxing,
+      DashManifest dashManifest,
+      Period period,
+      String segmentPath,
+      String adaptationSetId)
+      throws BitmovinException {
+    DashCmafRepresentation dashCmafRepresentation = new DashCmafRepresentation();
+    dashCmafRepresentation.setType(DashRepresentationType.TEMPLATE);
+    dashCmafRepresentation.setEncodingId(encoding.getId());
+    dashCmafRepresentation.setMuxingId(muxing.getId());
+    dashCmafRepresentation.setSegmentPath(segmentPath);
+
+    bitmovinApi.encoding.manifests.dash.periods.adaptationsets.representations.cmaf.create(
+        dashManifest.getId(), period.getId(), adaptationSetId, dashCmafRepresentation);
+  }
+
+  /**
+   * Adds an audio media playlist and a variant stream for each video rendition to the HLS
+   * manifest. The playlists reference the same CMAF segments as the DASH manifest.
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsHlsMediaAudioByManifestId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsHlsStreamsByManifestId
+   *
+   * @param encoding The encoding to which the muxings belong to
+   * @param hlsManifest The HLS manifest to add the playlists to
+   * @param audioStream The audio stream
+   * @param audioMuxing The CMAF muxing of the audio stream
+   */
+  private static void addHlsPlaylists(
+      Encoding encoding, HlsManifest hlsManifest, Stream audioStream, CmafMuxing audioMuxing)
+      throws BitmovinException {
+    AudioMediaInfo audioMediaInfo = new AudioMediaInfo();
+    audioMediaInfo.setName("English");
+    audioMediaInfo.setUri("audio.m3u8");
+    audioMediaInfo.setGroupId(AUDIO_GROUP_ID);
+    audioMediaInfo.setEncodingId(encoding.getId());
+    audioMediaInfo.setStreamId(audioStream.getId());
+    audioMediaInfo.setMuxingId(audioMuxing.getId());
+    audioMediaInfo.setLanguage("en");
+    audioMediaInfo.setAutoselect(true);
+    audioMediaInfo.setIsDefault(true);
+    audioMediaInfo.setSegmentPath(AUDIO_SEGMENT_PATH);
+
+    bitmovinApi.encoding.manifests.hls.media.audio.create(hlsManifest.getId(), audioMediaInfo);
+
+    for (VideoRendition videoRendition : videoRenditions) {
+      StreamInfo streamInfo = new StreamInfo();
+      streamInfo.setUri(String.format("video_%dp.m3u8", videoRendition.height));
+      streamInfo.setEncodingId(encoding.getId());
+      streamInfo.setStreamId(videoRendition.stream.getId());
+      streamInfo.setMuxingId(videoRendition.muxing.getId());
+      streamInfo.setAudio(AUDIO_GROUP_ID);
+      streamInfo.setSegmentPath(videoRendition.getSegmentPath());
+
+      bitmovinApi.encoding.manifests.hls.streams.create(hlsManifest.getId(), streamInfo);
+    }
+  }
+
+  private static class VideoRendition {
+
+    private int height;
+    private long bitrate;
+    private Stream stream;
+    private CmafMuxing muxing;
+
+    /**
+     * @param height The target output height of the rendition
+     * @param bitrate The target output bitrate of the rendition
+     */
+    private VideoRendition(int height, long bitrate) {
+      this.height = height;
+      this.bitrate = bitrate;
+    }
+
+    private String getSegmentPath() {
+      return "video/" + height;
+    }
+  }
+
+  /**
+   * Creates a DASH manifest
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsDash
+   *
+   * @param name the resource name
+   * @param dashProfile the DASH profile of the manifest (ON_DEMAND, LIVE)
+   * @param output the output of the manifest
+   * @param outputPath the output path where the manifest is written to
+   * @return the created manifest
+   */
+  private static DashManifest createDashManifest(
+      String name, DashProfile dashProfile, Output output, String outputPath)
+      throws BitmovinException {
+    DashManifest dashManifest = new DashManifest();
+    dashManifest.setName(name);
+    dashManifest.setProfile(dashProfile);
+    dashManifest.addOutputsItem(buildEncodingOutput(output, outputPath));
+
+    return bitmovinApi.encoding.manifests.dash.create(dashManifest);
+  }
+
+  /** Creates the HLS master manifest. */
+  private static HlsManifest createHlsMasterManifest(String name, Output output, String outputPath)
+      throws BitmovinException {
+
+    HlsManifest hlsManifest = new HlsManifest();
+    hlsManifest.setName(name);
+    hlsManifest.addOutputsItem(buildEncodingOutput(output, outputPath));
+
+    return bitmovinApi.encoding.manifests.hls.create(hlsManifest);
+  }
+
+  /**
+   * Creates a CMAF muxing. This will generate segments with a given segment length for adaptive
+   * streaming.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/all#/Encoding/PostEncodingEncodingsMuxingsCmafByEncodingId
+   *
+   * @param encoding The encoding to add the muxing to
+   * @param output The output that should be used for the muxing to write the segments to
+   * @param outputPath The output path where the fragmented segments will be written to
+   * @param stream The stream that is associated with the muxing
+   */
+  private static CmafMuxing createCmafMuxing(
+      Encoding encoding, Output output, String outputPath, Stream stream) throws BitmovinException {
+    MuxingStream muxingStream = new MuxingStream();
+    muxingStream.setStreamId(stream.getId());
+
+    CmafMuxing muxing = new CmafMuxing();
+    muxing.addOutputsItem(buildEncodingOutput(output, outputPath));
+    muxing.addStreamsItem(muxingStream);
+    muxing.setSegmentLength(4.0);
+    SegmentSharding.apply(configProvider, muxing);
+
+    return bitmovinApi.encoding.encodings.muxings.cmaf.create(encoding.getId(), muxing);
+  }
+
+  /**
+   * Creates a resource representing an HTTP server providing the input files. For alternative input
+   * methods see <a
+   * href="https://bitmovin.com/docs/encoding/articles/supported-input-output-storages">list of
+   * supported input and output storages</a>
+   *
+   * <p>For reasons of simplicity, a new input resource is created on each execution of this
+   * example. In production use, this method should be replaced by a <a
+   * href="https://bitmovin.com/docs/encoding/api-reference/sections/inputs#/Encoding/GetEncodingInputsHttpByInputId">get
+   * call</a> to retrieve an existing resource.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/inputs#/Encoding/PostEncodingInputsHttp
+   *
+   * @param host The hostname or IP address of the HTTP server e.g.: my-storage.biz
+   */
+  private static HttpInput createHttpInput(String host) throws BitmovinException {
+    HttpInput input = new HttpInput();
+    input.setHost(host);
+
+    return bitmovinApi.encoding.inputs.http.create(input);
+  }
+
+  /**
+   * Creates a resource representing an AWS S3 cloud storage bucket to which generated content will
+   * be transferred. For alternative output methods see <a
+   * href="https://bitmovin.com/docs/encoding/articles/supported-input-output-storages">list of
+   * supported input and output storages</a>
+   *
+   * <p>The provided credentials need to allow <i>read</i>, <i>write</i> and <i>list</i> operations.
+   * <i>delete</i> should also be granted to allow overwriting of existings files. See <a
+   * href="https://bitmovin.com/docs/encoding/faqs/how-do-i-create-a-aws-s3-bucket-which-can-be-used-as-output-location">creating
+   * an S3 bucket and setting permissions</a> for further information
+   *
+   * <p>For reasons of simplicity, a new output resource is created on each execution of this
+   * example. In production use, this method should be replaced by a <a
+   * href="https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/GetEncodingOutputsS3">get
+   * call</a> retrieving an existing resource.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/PostEncodingOutputsS3
+   *
+   * @param bucketName The name of the S3 bucket
+   * @param accessKey The access key of your S3 account
+   * @param secretKey The secret key of your S3 account
+   */
+  private static S3Output createS3Output(String bucketName, String accessKey, String secretKey)
+      throws BitmovinException {
+
+    S3Output s3Output = new S3Output();
+    s3Output.setBucketName(bucketName);
+    s3Output.setAccessKey(accessKey);
+    s3Output.setSecretKey(secretKey);
+
+    return bitmovinApi.encoding.outputs.s3.create(s3Output);
+  }
+
+  /**
+   * Creates an encoding object. This is the base object to configure your encoding.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodings
+   *
+   * @param name This is the name of the encoding
+   * @param description This is the description of the encoding
+   */
+  private static Encoding createEncoding(String name, String description) throws BitmovinException {
+    Encoding encoding = new Encoding();
+    encoding.setName(name);
+    encoding.setDescription(description);
+    new BudgetTag(configProvider).applyTo(encoding);
+
+    return bitmovinApi.encoding.encodings.create(encoding);
+  }
+
+  /**
+   * Creates a stream which binds an input file to a codec configuration. The stream is used for
+   * muxings later on.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsStreamsByEncodingId
+   *
+   * @param encoding The encoding to add the stream onto
+   * @param input The input that should be used
+   * @param inputPath The path to the input file
+   * @param codecConfiguration The codec configuration to be applied to the stream
+   */
+  private static Stream createStream(
+      Encoding encoding, Input input, String inputPath, CodecConfiguration codecConfiguration)
+      throws BitmovinException {
+    StreamInput streamInput =
+        PreviewTrimming.createStreamInput(bitmovinApi, configProvider, encoding, input, inputPath);
+
+    Stream stream = new Stream();
+    stream.addInputStreamsItem(streamInput);
+    stream.setCodecConfigId(codecConfiguration.getId());
+
+    return bitmovinApi.encoding.encodings.streams.create(encoding.getId(), stream);
+  }
+
+  /**
+   * Creates a configuration for the H.264 video codec to be applied to video streams.
+   *
+   * <p>The output resolution is defined by setting only the height. Width will be determined
+   * automatically to maintain the aspect ratio of your input video.
+   *
+   * <p>To keep things simple, we use a quality-optimized VoD preset configuration, which will apply
+   * proven settings for the codec. See <a
+   * href="https://bitmovin.com/docs/encoding/tutorials/how-to-optimize-your-h264-codec-configuration-for-different-use-cases">How
+   * to optimize your H264 codec configuration for different use-cases</a> for alternative presets.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsVideoH264
+   *
+   * @param height The height of the output video
+   * @param bitrate The target bitrate of the output video
+   */
+  private static H264VideoConfiguration createH264VideoConfig(int height, long bitrate)
+      throws BitmovinException {
+    H264VideoConfiguration config = new H264VideoConfiguration();
+    config.setName(String.format("H.264 %dp", height));
+    config.setPresetConfiguration(PresetConfiguration.VOD_STANDARD);
+    config.setHeight(height);
+    config.setBitrate(bitrate);
+
+    return bitmovinApi.encoding.configurations.video.h264.create(config);
+  }
+
+  /**
+   * Creates a configuration for the AAC audio codec to be applied to audio streams.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsAudioAac
+   */
+  private static AacAudioConfiguration createAacAudioConfig() throws BitmovinException {
+    AacAudioConfiguration config = new AacAudioConfiguration();
+    config.setName("AAC 128 kbit/s");
+    config.setBitrate(128_000L);
+
+    return bitmovinApi.encoding.configurations.audio.aac.create(config);
+  }
+
+  /**
+   * Builds an EncodingOutput object which defines where the output content (e.g. of a muxing) will
+   * be written to. Public read permissions will be set for the files written, so they can be
+   * accessed easily via HTTP.
+   *
+   * @param output The output resource to be used by the EncodingOutput
+   * @param outputPath The path where the content will be written to
+   */
+  private static EncodingOutput buildEncodingOutput(Output output, String outputPath) {
+    AclEntry aclEntry = new AclEntry();
+    aclEntry.setPermission(AclPermission.PUBLIC_READ);
+
+    EncodingOutput encodingOutput = new EncodingOutput();
+    encodingOutput.setOutputPath(buildAbsolutePath(outputPath));
+    encodingOutput.setOutputId(output.getId());
+    encodingOutput.addAclItem(aclEntry);
+    return encodingOutput;
+  }
+
+  /**
+   * Builds an absolute path by concatenating the S3_OUTPUT_BASE_PATH configuration parameter, the
+   * name of this example class and the given relative path
+   *
+   * <p>e.g.: /s3/base/path/ClassName/relative/path
+   *
+   * @param relativePath The relative path that is concatenated
+   * @return The absolute path
+   */
+  public static String buildAbsolutePath(String relativePath) {
+    String className = CmafSinglePackage.class.getSimpleName();
+    return Paths.get(configProvider.getS3OutputBasePath(), className, relativePath).toString();
+  }
+
+  /**
+   * Starts the actual encoding process and periodically polls its status until it reaches a final
+   * state
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/all#/Encoding/PostEncodingEncodingsStartByEncodingId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/GetEncodingEncodingsStatusByEncodingId
+   *
+   * <p>Please note that you can also use our webhooks API instead of polling the status. For more
+   * information consult the API spec:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/notifications-webhooks
+   *
+   * @param encoding The encoding to be started
+   */
+  private static void executeEncoding(Encoding encoding)
+      throws InterruptedException, BitmovinException {
+    bitmovinApi.encoding.encodings.start(encoding.getId(), new StartEncodingRequest());
+
+    Task task;
+    do {
+      Thread.sleep(5000);
+      task = bitmovinApi.encoding.encodings.status(encoding.getId());
+      logger.info("encoding status is {} (progress: {} %)", task.getStatus(), task.getProgress());
+    } while (task.getStatus() != Status.FINISHED
+        && task.getStatus() != Status.ERROR
+        && task.getStatus() != Status.CANCELED);
+
+    if (task.getStatus() != Status.FINISHED) {
+      logTaskErrors(task);
+      throw new EncodingFailedException(task.getStatus());
+    }
+    logger.info("encoding finished successfully");
+  }
+
+  /**
+   * Starts the DASH manifest creation and periodically polls its status until it reaches a final
+   * state
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsDashStartByManifestId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/GetEncodingManifestsDashStatusByManifestId
+   *
+   * @param dashManifest The DASH manifest to be created
+   */
+  private static void executeDashManifestCreation(DashManifest dashManifest)
+      throws BitmovinException, InterruptedException {
+    bitmovinApi.encoding.manifests.dash.start(dashManifest.getId());
+
+    Task task;
+    do {
+      Thread.sleep(1000);
+      task = bitmovinApi.encoding.manifests.dash.status(dashManifest.getId());
+    } while (task.getStatus() != Status.FINISHED && task.getStatus() != Status.ERROR);
+
+    if (task.getStatus() == Status.ERROR) {
+      logTaskErrors(task);
+      throw new RuntimeException("DASH manifest creation failed");
+    }
+    logger.info("DASH manifest creation finished successfully");
+  }
+
+  /**
+   * Starts the HLS manifest creation and periodically polls its status until it reaches a final
+   * state
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsHlsStartByManifestId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/GetEncodingManifestsHlsStatusByManifestId
+   *
+   * @param hlsManifest The HLS manifest to be created
+   */
+  private static void executeHlsManifestCreation(HlsManifest hlsManifest)
+      throws BitmovinException, InterruptedException {
+
+    bitmovinApi.encoding.manifests.hls.start(hlsManifest.getId());
+
+    Task task;
+    do {
+      Thread.sleep(1000);
+      task = bitmovinApi.encoding.manifests.hls.status(hlsManifest.getId());
+    } while (task.getStatus() != Status.FINISHED && task.getStatus() != Status.ERROR);
+
+    if (task.getStatus() == Status.ERROR) {
+      logTaskErrors(task);
+      throw new RuntimeException("HLS manifest creation failed");
+    }
+    logger.info("HLS manifest creation finished successfully");
+  }
+
+  private static void logTaskErrors(Task task) {
+    task.getMessages().stream()
+        .filter(msg -> msg.getType() == MessageType.ERROR)
+        .forEach(msg -> logger.error(msg.getText()));
+  }
+}
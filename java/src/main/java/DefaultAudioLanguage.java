@@ -0,0 +1,736 @@
+import com.bitmovin.api.sdk.BitmovinApi;
+import com.bitmovin.api.sdk.common.BitmovinException;
+import com.bitmovin.api.sdk.model.AacAudioConfiguration;
+import com.bitmovin.api.sdk.model.AclEntry;
+import com.bitmovin.api.sdk.model.AclPermission;
+import com.bitmovin.api.sdk.model.AdaptationSetRole;
+import com.bitmovin.api.sdk.model.AudioAdaptationSet;
+import com.bitmovin.api.sdk.model.AudioMediaInfo;
+import com.bitmovin.api.sdk.model.CodecConfiguration;
+import com.bitmovin.api.sdk.model.DashFmp4Representation;
+import com.bitmovin.api.sdk.model.DashManifest;
+import com.bitmovin.api.sdk.model.DashProfile;
+import com.bitmovin.api.sdk.model.DashRepresentationType;
+import com.bitmovin.api.sdk.model.Encoding;
+import com.bitmovin.api.sdk.model.EncodingOutput;
+import com.bitmovin.api.sdk.model.Fmp4Muxing;
+import com.bitmovin.api.sdk.model.H264VideoConfiguration;
+import com.bitmovin.api.sdk.model.HlsManifest;
+import com.bitmovin.api.sdk.model.HttpInput;
+import com.bitmovin.api.sdk.model.Input;
+import com.bitmovin.api.sdk.model.MessageType;
+import com.bitmovin.api.sdk.model.MuxingStream;
+import com.bitmovin.api.sdk.model.Output;
+import com.bitmovin.api.sdk.model.Period;
+import com.bitmovin.api.sdk.model.PresetConfiguration;
+import com.bitmovin.api.sdk.model.S3Output;
+import com.bitmovin.api.sdk.model.StartEncodingRequest;
+import com.bitmovin.api.sdk.model.Status;
+import com.bitmovin.api.sdk.model.Stream;
+import com.bitmovin.api.sdk.model.StreamInfo;
+import com.bitmovin.api.sdk.model.StreamInput;
+import com.bitmovin.api.sdk.model.StreamSelectionMode;
+import com.bitmovin.api.sdk.model.Task;
+import com.bitmovin.api.sdk.model.VideoAdaptationSet;
+import common.ApiClientFactory;
+import common.BudgetTag;
+import common.ConfigProvider;
+import common.EncodingFailedException;
+import common.PreviewTrimming;
+import common.SegmentSharding;
+import java.nio.file.Paths;
+import java.util.ArrayList;
+import java.util.Arrays;
+import java.util.List;
+import java.util.stream.Collectors;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+
+/**
+ * This example demonstrates how to control which audio language players select by default, for an
+ * input file with multiple audio tracks.
+ *
+ * <p>In the HLS manifest, the audio track of the default language is marked with DEFAULT=YES.
+ * Players that take the language settings of the device into account only choose among tracks
+ * marked with AUTOSELECT=YES, so the default track might be overruled on some devices. To pin the
+ * default language regardless of the device settings, AUDIO_PIN_DEFAULT can be set to true, which
+ * marks all other tracks with AUTOSELECT=NO.
+ *
+ * <p>In the DASH manifest, there is no default flag. The audio adaptation set of the default
+ * language is marked with the role "main" and added as the first adaptation set, as players which
+ * do not evaluate roles usually pick the first one.
+ *
+ * <p>The following configuration parameters are expected:
+ *
+ * <ul>
+ *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin API
+ *   <li>BITMOVIN_TENANT_ORG_ID - (optional) The ID of the Organisation in which you want to perform
+ *       the encoding.
+ *   <li>BITMOVIN_API_BASE_URL - (optional) The base URL of the Bitmovin API, e.g. of a mock server
+ *       or an API gateway. Default: https://api.bitmovin.com/v1
+ *   <li>HTTP_INPUT_HOST - The Hostname or IP address of the HTTP server hosting your input files,
+ *       e.g.: my-storage.biz
+ *   <li>HTTP_INPUT_FILE_PATH - The path to your input file on the provided HTTP server Example:
+ *       videos/1080p_Sintel.mp4
+ *   <li>S3_OUTPUT_BUCKET_NAME - The name of your S3 output bucket. Example: my-bucket-name
+ *   <li>S3_OUTPUT_ACCESS_KEY - The access key of your S3 output bucket
+ *   <li>S3_OUTPUT_SECRET_KEY - The secret key of your S3 output bucket
+ *   <li>S3_OUTPUT_BASE_PATH - The base path on your S3 output bucket where content will be written.
+ *       Example: /outputs
+ *   <li>AUDIO_LANGUAGES - The languages of the audio tracks in the input file in the order of the
+ *       tracks, as comma-separated ISO 639-1 codes. Example: en,de,es
+ *   <li>AUDIO_DEFAULT_LANGUAGE - The language to be selected by default. Example: de
+ *   <li>AUDIO_PIN_DEFAULT - (optional) If set to true, the tracks of all other languages are
+ *       excluded from automatic selection in HLS. Default: false
+ * </ul>
+ *
+ * <p>Configuration parameters will be retrieved from these sources in the listed order:
+ *
+ * <ol>
+ *   <li>command line arguments (eg BITMOVIN_API_KEY=xyz)
+ *   <li>properties file located in the root folder of the JAVA examples at ./examples.properties
+ *       (see examples.properties.template as reference)
+ *   <li>environment variables
+ *   <li>properties file located in the home folder at ~/.bitmovin/examples.properties (see
+ *       examples.properties.template as reference)
+ * </ol>
+ */
+public class DefaultAudioLanguage {
+  private static final Logger logger = LoggerFactory.getLogger(DefaultAudioLanguage.class);
+
+  private static final String AUDIO_GROUP_ID = "audio";
+
+  private static BitmovinApi bitmovinApi;
+  private static ConfigProvider configProvider;
+
+  /** This list defines the video renditions that will be generated */
+  private static List<Rendition> renditions =
+      Arrays.asList(
+          new Rendition(1080, 4_800_000L),
+          new Rendition(720, 2_400_000L),
+          new Rendition(480, 1_200_000L));
+
+  public static void main(String[] args) throws Exception {
+    configProvider = new ConfigProvider(args);
+    bitmovinApi =
+        ApiClientFactory.builder(configProvider)
+            .withApiKey(configProvider.getBitmovinApiKey())
+            .build();
+
+    List<String> languages =
+        Arrays.stream(configProvider.getParameterByKey("AUDIO_LANGUAGES").split(","))
+            .map(String::trim)
+            .collect(Collectors.toList());
+    String defaultLanguage = configProvider.getParameterByKey("AUDIO_DEFAULT_LANGUAGE");
+    boolean pinDefault =
+        Boolean.parseBoolean(configProvider.getParameterByKey("AUDIO_PIN_DEFAULT", "false"));
+
+    if (!languages.contains(defaultLanguage)) {
+      throw new IllegalArgumentException(
+          String.format("The default language %s is not one of %s", defaultLanguage, languages));
+    }
+
+    Encoding encoding =
+        createEncoding("Default audio language", "Encoding with a default audio language");
+
+    HttpInput input = createHttpInput(configProvider.getHttpInputHost());
+    Output output =
+        createS3Output(
+            configProvider.getS3OutputBucketName(),
+            configProvider.getS3OutputAccessKey(),
+            configProvider.getS3OutputSecretKey());
+
+    String inputFilePath = configProvider.getHttpInputFilePath();
+
+    for (Rendition rendition : renditions) {
+      H264VideoConfiguration videoConfiguration =
+          createH264VideoConfig(rendition.height, rendition.bitrate);
+      rendition.stream = createStream(encoding, input, inputFilePath, videoConfiguration);
+      rendition.muxing =
+          createFmp4Muxing(encoding, output, rendition.getSegmentPath(), rendition.stream);
+    }
+
+    // The audio track of the default language is processed first, so it ends up first in the
+    // manifests
+    List<AudioTrack> audioTracks = new ArrayList<>();
+    for (int position = 0; position < languages.size(); position++) {
+      AudioTrack audioTrack = new AudioTrack(languages.get(position), position);
+      int index = audioTrack.language.equals(defaultLanguage) ? 0 : audioTracks.size();
+      audioTracks.add(index, audioTrack);
+    }
+
+    AacAudioConfiguration aacConfig = createAacAudioConfig();
+    for (AudioTrack audioTrack : audioTracks) {
+      audioTrack.stream =
+          createAudioStream(encoding, input, inputFilePath, aacConfig, audioTrack.position);
+      audioTrack.muxing =
+          createFmp4Muxing(encoding, output, audioTrack.getSegmentPath(), audioTrack.stream);
+    }
+
+    executeEncoding(encoding);
+
+    HlsManifest hlsManifest = createHlsMasterManifest("master.m3u8", output, "/");
+    for (AudioTrack audioTrack : audioTracks) {
+      boolean isDefault = audioTrack.language.equals(defaultLanguage);
+      addAudioMediaInfo(encoding, hlsManifest, audioTrack, isDefault, isDefault || !pinDefault);
+    }
+    for (Rendition rendition : renditions) {
+      addVariantStream(encoding, hlsManifest, rendition);
+    }
+    executeHlsManifestCreation(hlsManifest);
+
+    DashManifest dashManifest =
+        createDashManifest("stream.mpd", DashProfile.ON_DEMAND, output, "/");
+    Period period =
+        bitmovinApi.encoding.manifests.dash.periods.create(dashManifest.getId(), new Period());
+
+    VideoAdaptationSet videoAdaptationSet =
+        bitmovinApi.encoding.manifests.dash.periods.adaptationsets.video.create(
+            dashManifest.getId(), period.getId(), new VideoAdaptationSet());
+    for (Rendition rendition : renditions) {
+      createDashFmp4Representation(
+          encoding,
+          dashManifest,
+          period,
+          videoAdaptationSet.getId(),
+          rendition.muxing,
+          rendition.getSegmentPath());
+    }
+
+    for (AudioTrack audioTrack : audioTracks) {
+      boolean isDefault = audioTrack.language.equals(defaultLanguage);
+      AudioAdaptationSet audioAdaptationSet =
+          createAudioAdaptationSet(dashManifest, period, audioTrack.language, isDefault);
+      createDashFmp4Representation(
+          encoding,
+          dashManifest,
+          period,
+          audioAdaptationSet.getId(),
+          audioTrack.muxing,
+          audioTrack.getSegmentPath());
+    }
+    executeDashManifestCreation(dashManifest);
+  }
+
+  /**
+   * Creates a stream which binds an audio track of the input file to a codec configuration. The
+   * audio track is selected by its position among the audio tracks of the input file.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsStreamsByEncodingId
+   *
+   * @param encoding The encoding to add the stream onto
+   * @param input The input that should be used
+   * @param inputPath The path to the input file
+   * @param codecConfiguration The codec configuration to be applied to the stream
+   * @param audioTrackPosition The position of the audio track, starting with 0
+   */
+  private static Stream createAudioStream(
+      Encoding encoding,
+      Input input,
+      String inputPath,
+      CodecConfiguration codecConfiguration,
+      int audioTrackPosition)
+      throws BitmovinException {
+    StreamInput streamInput =
+        PreviewTrimming.createStreamInput(
+            bitmovinApi,
+            configProvider,
+            encoding,
+            input,
+            inputPath,
+            StreamSelectionMode.AUDIO_RELATIVE,
+            audioTrackPosition);
+
+    Stream stream = new Stream();
+    stream.addInputStreamsItem(streamInput);
+    stream.setCodecConfigId(codecConfiguration.getId());
+
+    return bitmovinApi.encoding.encodings.streams.create(encoding.getId(), stream);
+  }
+
+  /**
+   * Adds an audio media playlist to the HLS manifest.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsHlsMediaAudioByManifestId
+   *
+   * @param encoding The encoding to which the audio stream belongs to
+   * @param manifest The HLS manifest to add the media playlist to
+   * @param audioTrack The audio track to add
+   * @param isDefault Whether the track should be marked with DEFAULT=YES
+   * @param autoselect Whether the track should be marked with AUTOSELECT=YES
+   */
+  private static void addAudioMediaInfo(
+      Encoding encoding,
+      HlsManifest manifest,
+      AudioTrack audioTrack,
+      boolean isDefault,
+      boolean autoselect)
+      throws BitmovinException {
+    AudioMediaInfo audioMediaInfo = new AudioMediaInfo();
+    audioMediaInfo.setName(audioTrack.language);
+    audioMediaInfo.setUri("audio_" + audioTrack.language + ".m3u8");
+    audioMediaInfo.setGroupId(AUDIO_GROUP_ID);
+    audioMediaInfo.setEncodingId(encoding.getId());
+    audioMediaInfo.setStreamId(audioTrack.stream.getId());
+    audioMediaInfo.setMuxingId(audioTrack.muxing.getId());
+    audioMediaInfo.setLanguage(audioTrack.language);
+    audioMediaInfo.setIsDefault(isDefault);
+    audioMediaInfo.setAutoselect(autoselect);
+    audioMediaInfo.setSegmentPath(audioTrack.getSegmentPath());
+
+    bitmovinApi.encoding.manifests.hls.media.audio.create(manifest.getId(), audioMediaInfo);
+  }
+
+  /**
+   * Adds a variant stream to the HLS manifest, which combines a video rendition with the audio
+   * group.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsHlsStreamsByManifestId
+   *
+   * @param encoding The encoding to which the streams belong to
+   * @param manifest The HLS manifest to add the variant stream to
+   * @param rendition The video rendition of the variant stream
+   */
+  private static void addVariantStream(Encoding encoding, HlsManifest manifest, Rendition rendition)
+      throws BitmovinException {
+    StreamInfo streamInfo = new StreamInfo();
+    streamInfo.setUri(String.format("video_%dp.m3u8", rendition.height));
+    streamInfo.setEncodingId(encoding.getId());
+    streamInfo.setStreamId(rendition.stream.getId());
+    streamInfo.setMuxingId(rendition.muxing.getId());
+    streamInfo.setAudio(AUDIO_GROUP_ID);
+    streamInfo.setSegmentPath(rendition.getSegmentPath());
+
+    bitmovinApi.encoding.manifests.hls.streams.create(manifest.getId(), streamInfo);
+  }
+
+  /**
+   * Creates an audio adaptation set for the DASH manifest. The adaptation set of the default
+   * language is marked with the role "main", all others with the role "alternate".
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsDashPeriodsAdaptationsetsAudioByManifestIdAndPeriodId
+   *
+   * @param dashManifest The DASH manifest to add the adaptation set to
+   * @param period The period to add the adaptation set to
+   * @param language The language of the audio track
+   * @param isDefault Whether the language is the default language
+   */
+  private static AudioAdaptationSet createAudioAdaptationSet(
+      DashManifest dashManifest, Period period, String language, boolean isDefault)
+      throws BitmovinException {
+    AudioAdaptationSet audioAdaptationSet = new AudioAdaptationSet();
+    audioAdaptationSet.setLang(language);
+    audioAdaptationSet.addRolesItem(
+        isDefault ? AdaptationSetRole.MAIN : AdaptationSetRole.ALTERNATE);
+
+    return bitmovinApi.encoding.manifests.dash.periods.adaptationsets.audio.create(
+        dashManifest.getId(), period.getId(), audioAdaptationSet);
+  }
+
+  /**
+   * Adds a representation of a fragmented MP4 muxing to an adaptation set of the DASH manifest.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsDashPeriodsAdaptationsetsRepresentationsFmp4ByManifestIdAndPeriodIdAndAdaptationsetId
+   *
+   * @param encoding The encoding to which the muxing belongs to
+   * @param dashManifest The DASH manifest to add the representation to
+   * @param period The period to add the representation to
+   * @param adaptationSetId The ID of the adaptation set to add the representation to
+   * @param muxing The muxing of the representation
+   * @param segmentPath The path of the segments, relative to the manifest
+   */
+  private static void createDashFmp4Representation(
+      Encoding encoding,
+      DashManifest dashManifest,
+      Period period,
+      String adaptationSetId,
+      Fmp4Muxing muxing,
+      String segmentPath)
+      throws BitmovinException {
+    DashFmp4Representation representation = new DashFmp4Representation();
+    representation.setType(DashRepresentationType.TEMPLATE);
+    representation.setEncodingId(encoding.getId());
+    representation.setMuxingId(muxing.getId());
+    representation.setSegmentPath(segmentPath);
+
+    bitmovinApi.encoding.manifests.dash.periods.adaptationsets.representations.fmp4.create(
+        dashManifest.getId(), period.getId(), adaptationSetId, representation);
+  }
+
+  private static class Rendition {
+
+    private int height;
+    private long bitrate;
+    private Stream stream;
+    private Fmp4Muxing muxing;
+
+    /**
+     * @param height The target output height of the rendition
+     * @param bitrate The target output bitrate of the rendition
+     */
+    private Rendition(int height, long bitrate) {
+      this.height = height;
+      this.bitrate = bitrate;
+    }
+
+    private String getSegmentPath() {
+      return "video/" + height;
+    }
+  }
+
+  private static class AudioTrack {
+
+    private String language;
+    private int position;
+    private Stream stream;
+    private Fmp4Muxing muxing;
+
+    /**
+     * @param language The language of the audio track
+     * @param position The position of the audio track in the input file
+     */
+    private AudioTrack(String language, int position) {
+      this.language = language;
+      this.position = position;
+    }
+
+    private String getSegmentPath() {
+      return "audio/" + language;
+    }
+  }
+
+  /**
+   * Creates a resource representing an HTTP server providing the input files. For alternative input
+   * methods see <a
+   * href="https://bitmovin.com/docs/encoding/articles/supported-input-output-storages">list of
+   * supported input and output storages</a>
+   *
+   * <p>For reasons of simplicity, a new input resource is created on each execution of this
+   * example. In production use, this method should be replaced by a <a
+   * href="https://bitmovin.com/docs/encoding/api-reference/sections/inputs#/Encoding/GetEncodingInputsHttpByInputId">get
+   * call</a> to retrieve an existing resource.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/inputs#/Encoding/PostEncodingInputsHttp
+   *
+   * @param host The hostname or IP address of the HTTP server e.g.: my-storage.biz
+   */
+  private static HttpInput createHttpInput(String host) throws BitmovinException {
+    HttpInput input = new HttpInput();
+    input.setHost(host);
+
+    return bitmovinApi.encoding.inputs.http.create(input);
+  }
+
+  /**
+   * Creates a resource representing an AWS S3 cloud storage bucket to which generated content will
+   * be transferred. For alternative output methods see <a
+   * href="https://bitmovin.com/docs/encoding/articles/supported-input-output-storages">list of
+   * supported input and output storages</a>
+   *
+   * <p>The provided credentials need to allow <i>read</i>, <i>write</i> and <i>list</i> operations.
+   * <i>delete</i> should also be granted to allow overwriting of existings files. See <a
+   * href="https://bitmovin.com/docs/encoding/faqs/how-do-i-create-a-aws-s3-bucket-which-can-be-used-as-output-location">creating
+   * an S3 bucket and setting permissions</a> for further information
+   *
+   * <p>For reasons of simplicity, a new output resource is created on each execution of this
+   * example. In production use, this method should be replaced by a <a
+   * href="https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/GetEncodingOutputsS3">get
+   * call</a> retrieving an existing resource.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/PostEncodingOutputsS3
+   *
+   * @param bucketName The name of the S3 bucket
+   * @param accessKey The access key of your S3 account
+   * @param secretKey The secret key of your S3 account
+   */
+  private static S3Output createS3Output(String bucketName, String accessKey, String secretKey)
+      throws BitmovinException {
+
+    S3Output s3Output = new S3Output();
+    s3Output.setBucketName(bucketName);
+    s3Output.setAccessKey(accessKey);
+    s3Output.setSecretKey(secretKey);
+
+    return bitmovinApi.encoding.outputs.s3.create(s3Output);
+  }
+
+  /**
+   * Creates an encoding object. This is the base object to configure your encoding.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodings
+   *
+   * @param name This is the name of the encoding
+   * @param description This is the description of the encoding
+   */
+  private static Encoding createEncoding(String name, String description) throws BitmovinException {
+    Encoding encoding = new Encoding();
+    encoding.setName(name);
+    encoding.setDescription(description);
+    new BudgetTag(configProvider).applyTo(encoding);
+
+    return bitmovinApi.encoding.encodings.create(encoding);
+  }
+
+  /**
+   * Creates a stream which binds an input file to a codec configuration. The stream is used for
+   * muxings later on.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsStreamsByEncodingId
+   *
+   * @param encoding The encoding to add the stream onto
+   * @param input The input that should be used
+   * @param inputPath The path to the input file
+   * @param codecConfiguration The codec configuration to be applied to the stream
+   */
+  private static Stream createStream(
+      Encoding encoding, Input input, String inputPath, CodecConfiguration codecConfiguration)
+      throws BitmovinException {
+    StreamInput streamInput =
+        PreviewTrimming.createStreamInput(bitmovinApi, configProvider, encoding, input, inputPath);
+
+    Stream stream = new Stream();
+    stream.addInputStreamsItem(streamInput);
+    stream.setCodecConfigId(codecConfiguration.getId());
+
+    return bitmovinApi.encoding.encodings.streams.create(encoding.getId(), stream);
+  }
+
+  /**
+   * Creates a configuration for the H.264 video codec to be applied to video streams.
+   *
+   * <p>The output resolution is defined by setting only the height. Width will be determined
+   * automatically to maintain the aspect ratio of your input video.
+   *
+   * <p>To keep things simple, we use a quality-optimized VoD preset configuration, which will apply
+   * proven settings for the codec. See <a
+   * href="https://bitmovin.com/docs/encoding/tutorials/how-to-optimize-your-h264-codec-configuration-for-different-use-cases">How
+   * to optimize your H264 codec configuration for different use-cases</a> for alternative presets.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsVideoH264
+   *
+   * @param height The height of the output video
+   * @param bitrate The target bitrate of the output video
+   */
+  private static H264VideoConfiguration createH264VideoConfig(int height, long bitrate)
+      throws BitmovinException {
+    H264VideoConfiguration config = new H264VideoConfiguration();
+    config.setName(String.format("H.264 %dp", height));
+    config.setPresetConfiguration(PresetConfiguration.VOD_STANDARD);
+    config.setHeight(height);
+    config.setBitrate(bitrate);
+
+    return bitmovinApi.encoding.configurations.video.h264.create(config);
+  }
+
+  /**
+   * Creates a configuration for the AAC audio codec to be applied to audio streams.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsAudioAac
+   */
+  private static AacAudioConfiguration createAacAudioConfig() throws BitmovinException {
+    AacAudioConfiguration config = new AacAudioConfiguration();
+    config.setName("AAC 128 kbit/s");
+    config.setBitrate(128_000L);
+
+    return bitmovinApi.encoding.configurations.audio.aac.create(config);
+  }
+
+  /**
+   * Creates a fragmented MP4 muxing. This will generate segments with a given segment length for
+   * adaptive streaming.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/all#/Encoding/PostEncodingEncodingsMuxingsFmp4ByEncodingId
+   *
+   * @param encoding The encoding where to add the muxing to
+   * @param output The output that should be used for the muxing to write the segments to
+   * @param outputPath The output path where the fragmented segments will be written to
+   * @param stream The stream that is associated with the muxing
+   */
+  private static Fmp4Muxing createFmp4Muxing(
+      Encoding encoding, Output output, String outputPath, Stream stream) throws BitmovinException {
+    MuxingStream muxingStream = new MuxingStream();
+    muxingStream.setStreamId(stream.getId());
+
+    Fmp4Muxing muxing = new Fmp4Muxing();
+    muxing.addOutputsItem(buildEncodingOutput(output, outputPath));
+    muxing.addStreamsItem(muxingStream);
+    muxing.setSegmentLength(4.0);
+    SegmentSharding.apply(configProvider, muxing);
+
+    return bitmovinApi.encoding.encodings.muxings.fmp4.create(encoding.getId(), muxing);
+  }
+
+  /**
+   * Builds an EncodingOutput object which defines where the output content (e.g. of a muxing) will
+   * be written to. Public read permissions will be set for the files written, so they can be
+   * accessed easily via HTTP.
+   *
+   * @param output The output resource to be used by the EncodingOutput
+   * @param outputPath The path where the content will be written to
+   */
+  private static EncodingOutput buildEncodingOutput(Output output, String outputPath) {
+    AclEntry aclEntry = new AclEntry();
+    aclEntry.setPermission(AclPermission.PUBLIC_READ);
+
+    EncodingOutput encodingOutput = new EncodingOutput();
+    encodingOutput.setOutputPath(buildAbsolutePath(outputPath));
+    encodingOutput.setOutputId(output.getId());
+    encodingOutput.addAclItem(aclEntry);
+    return encodingOutput;
+  }
+
+  /**
+   * Builds an absolute path by concatenating the S3_OUTPUT_BASE_PATH configuration parameter, the
+   * name of this example class and the given relative path
+   *
+   * <p>e.g.: /s3/base/path/ClassName/relative/path
+   *
+   * @param relativePath The relative path that is concatenated
+   * @return The absolute path
+   */
+  public static String buildAbsolutePath(String relativePath) {
+    String className = DefaultAudioLanguage.class.getSimpleName();
+    return Paths.get(configProvider.getS3OutputBasePath(), className, relativePath).toString();
+  }
+
+  /**
+   * Starts the actual encoding process and periodically polls its status until it reaches a final
+   * state
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/all#/Encoding/PostEncodingEncodingsStartByEncodingId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/GetEncodingEncodingsStatusByEncodingId
+   *
+   * <p>Please note that you can also use our webhooks API instead of polling the status. For more
+   * information consult the API spec:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/notifications-webhooks
+   *
+   * @param encoding The encoding to be started
+   */
+  private static void executeEncoding(Encoding encoding)
+      throws InterruptedException, BitmovinException {
+    bitmovinApi.encoding.encodings.start(encoding.getId(), new StartEncodingRequest());
+
+    Task task;
+    do {
+      Thread.sleep(5000);
+      task = bitmovinApi.encoding.encodings.status(encoding.getId());
+      logger.info("encoding status is {} (progress: {} %)", task.getStatus(), task.getProgress());
+    } while (task.getStatus() != Status.FINISHED
+        && task.getStatus() != Status.ERROR
+        && task.getStatus() != Status.CANCELED);
+
+    if (task.getStatus() != Status.FINISHED) {
+      logTaskErrors(task);
+      throw new EncodingFailedException(task.getStatus());
+    }
+    logger.info("encoding finished successfully");
+  }
+
+  /** Creates the HLS master manifest. */
+  private static HlsManifest createHlsMasterManifest(String name, Output output, String outputPath)
+      throws BitmovinException {
+
+    HlsManifest hlsManifest = new HlsManifest();
+    hlsManifest.setName(name);
+    hlsManifest.addOutputsItem(buildEncodingOutput(output, outputPath));
+
+    return bitmovinApi.encoding.manifests.hls.create(hlsManifest);
+  }
+
+  /**
+   * Creates a DASH manifest
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsDash
+   *
+   * @param name the resource name
+   * @param dashProfile the DASH profile of the manifest (ON_DEMAND, LIVE)
+   * @param output the output of the manifest
+   * @param outputPath the output path where the manifest is written to
+   * @return the created manifest
+   */
+  private static DashManifest createDashManifest(
+      String name, DashProfile dashProfile, Output output, String outputPath)
+      throws BitmovinException {
+    DashManifest dashManifest = new DashManifest();
+    dashManifest.setName(name);
+    dashManifest.setProfile(dashProfile);
+    dashManifest.addOutputsItem(buildEncodingOutput(output, outputPath));
+
+    return bitmovinApi.encoding.manifests.dash.create(dashManifest);
+  }
+
+  /**
+   * Starts the HLS manifest creation and periodically polls its status until it reaches a final
+   * state
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsHlsStartByManifestId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/GetEncodingManifestsHlsStatusByManifestId
+   *
+   * @param hlsManifest The HLS manifest to be created
+   */
+  private static void executeHlsManifestCreation(HlsManifest hlsManifest)
+      throws BitmovinException, InterruptedException {
+
+    bitmovinApi.encoding.manifests.hls.start(hlsManifest.getId());
+
+    Task task;
+    do {
+      Thread.sleep(1000);
+      task = bitmovinApi.encoding.manifests.hls.status(hlsManifest.getId());
+    } while (task.getStatus() != Status.FINISHED && task.getStatus() != Status.ERROR);
+
+    if (task.getStatus() == Status.ERROR) {
+      logTaskErrors(task);
+      throw new RuntimeException("HLS manifest creation failed");
+    }
+    logger.info("HLS manifest creation finished successfully");
+  }
+
+  /**
+   * Starts the DASH manifest creation and periodically polls its status until it reaches a final
+   * state
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsDashStartByManifestId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/GetEncodingManifestsDashStatusByManifestId
+   *
+   * @param dashManifest The DASH manifest to be created
+   */
+  private static void executeDashManifestCreation(DashManifest dashManifest)
+      throws BitmovinException, InterruptedException {
+    bitmovinApi.encoding.manifests.dash.start(dashManifest.getId());
+
+    Task task;
+    do {
+      Thread.sleep(1000);
+      task = bitmovinApi.encoding.manifests.dash.status(dashManifest.getId());
+    } while (task.getStatus() != Status.FINISHED && task.getStatus() != Status.ERROR);
+
+    if (task.getStatus() == Status.ERROR) {
+      logTaskErrors(task);
+      throw new RuntimeException("DASH manifest creation failed");
+    }
+    logger.info("DASH manifest creation finished successfully");
+  }
+
+  private static void logTaskErrors(Task task) {
+    task.getMessages().stream()
+        .filter(msg -> msg.getType() == MessageType.ERROR)
+        .forEach(msg -> logger.error(msg.getText()));
+  }
+}
@@ -26,11 +26,13 @@ import com.bitmovin.api.sdk.model.Status;
 import com.bitmovin.api.sdk.model.Stream;
 import com.bitmovin.api.sdk.model.StreamInput;
 import com.bitmovin.api.sdk.model.StreamMode;
-import com.bitmovin.api.sdk.model.StreamSelectionMode;
 import com.bitmovin.api.sdk.model.Task;
+import common.ApiClientFactory;
+import common.BudgetTag;
 import common.ConfigProvider;
-import feign.Logger.Level;
-import feign.slf4j.Slf4jLogger;
+import common.EncodingFailedException;
+import common.PreviewTrimming;
+import common.SegmentSharding;
 import java.nio.file.Paths;
 import org.slf4j.Logger;
 import org.slf4j.LoggerFactory;
@@ -44,6 +46,8 @@ import org.slf4j.LoggerFactory;
  *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin API
  *   <li>BITMOVIN_TENANT_ORG_ID - (optional) The ID of the Organisation in which you want to perform
  *       the encoding.
+ *   <li>BITMOVIN_API_BASE_URL - (optional) The base URL of the Bitmovin API, e.g. of a mock server
+ *       or an API gateway. Default: https://api.bitmovin.com/v1
  *   <li>HTTP_INPUT_HOST - The Hostname or IP address of the HTTP server hosting your input files,
  *       e.g.: my-storage.biz
  *   <li>HTTP_INPUT_FILE_PATH - The path to your input file on the provided HTTP server Example:
@@ -53,6 +57,8 @@ import org.slf4j.LoggerFactory;
  *   <li>S3_OUTPUT_SECRET_KEY - The secret key of your S3 output bucket
  *   <li>S3_OUTPUT_BASE_PATH - The base path on your S3 output bucket where content will be written.
  *       Example: /outputs
+ *   <li>SEGMENT_SHARDING - (optional) Set to true to start the name of every segment with random
+ *       characters, see {@link SegmentSharding}. Default: false
  * </ul>
  *
  * <p>Configuration parameters will be retrieved from these sources in the listed order:
@@ -76,12 +82,8 @@ public class DefaultManifests {
   public static void main(String[] args) throws Exception {
     configProvider = new ConfigProvider(args);
     bitmovinApi =
-        BitmovinApi.builder()
+        ApiClientFactory.builder(configProvider)
             .withApiKey(configProvider.getBitmovinApiKey())
-            // uncomment the following line if you are working with a multi-tenant account
-            // .withTenantOrgId(configProvider.getBitmovinTenantOrgId())
-            .withLogger(
-                new Slf4jLogger(), Level.BASIC) // set the logger and log level for the API client
             .build();
 
     Encoding encoding =
@@ -183,6 +185,7 @@ public class DefaultManifests {
     Encoding encoding = new Encoding();
     encoding.setName(name);
     encoding.setDescription(description);
+    new BudgetTag(configProvider).applyTo(encoding);
 
     return bitmovinApi.encoding.encodings.create(encoding);
   }
@@ -202,10 +205,8 @@ public class DefaultManifests {
   private static Stream createStream(
       Encoding encoding, Input input, String inputPath, CodecConfiguration codecConfiguration)
       throws BitmovinException {
-    StreamInput streamInput = new StreamInput();
-    streamInput.setInputId(input.getId());
-    streamInput.setInputPath(inputPath);
-    streamInput.setSelectionMode(StreamSelectionMode.AUTO);
+    StreamInput streamInput =
+        PreviewTrimming.createStreamInput(bitmovinApi, configProvider, encoding, input, inputPath);
 
     Stream stream = new Stream();
     stream.addInputStreamsItem(streamInput);
@@ -257,6 +258,10 @@ public class DefaultManifests {
    * Creates a fragmented MP4 muxing. This will generate segments with a given segment length for
    * adaptive streaming.
    *
+   * <p>If segment sharding is enabled, the name of every segment starts with random characters,
+   * see {@link SegmentSharding}. The default manifests reference the segment names that have
+   * actually been written.
+   *
    * <p>API endpoint:
    * https://bitmovin.com/docs/encoding/api-reference/all#/Encoding/PostEncodingEncodingsMuxingsFmp4ByEncodingId
    *
@@ -274,6 +279,7 @@ public class DefaultManifests {
     muxing.addOutputsItem(buildEncodingOutput(output, outputPath));
     muxing.addStreamsItem(muxingStream);
     muxing.setSegmentLength(4.0);
+    SegmentSharding.apply(configProvider, muxing);
 
     return bitmovinApi.encoding.encodings.muxings.fmp4.create(encoding.getId(), muxing);
   }
@@ -334,11 +340,13 @@ public class DefaultManifests {
       Thread.sleep(5000);
       task = bitmovinApi.encoding.encodings.status(encoding.getId());
       logger.info("encoding status is {} (progress: {} %)", task.getStatus(), task.getProgress());
-    } while (task.getStatus() != Status.FINISHED && task.getStatus() != Status.ERROR);
+    } while (task.getStatus() != Status.FINISHED
+        && task.getStatus() != Status.ERROR
+        && task.getStatus() != Status.CANCELED);
 
-    if (task.getStatus() == Status.ERROR) {
+    if (task.getStatus() != Status.FINISHED) {
       logTaskErrors(task);
-      throw new RuntimeException("Encoding failed");
+      throw new EncodingFailedException(task.getStatus());
     }
     logger.info("encoding finished successfully");
   }
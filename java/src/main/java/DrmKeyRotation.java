@@ -0,0 +1,553 @@
+import com.bitmovin.api.sdk.BitmovinApi;
+import com.bitmovin.api.sdk.common.BitmovinException;
+import com.bitmovin.api.sdk.encoding.encodings.muxings.fmp4.Fmp4MuxingListQueryParams;
+import com.bitmovin.api.sdk.encoding.encodings.streams.StreamListQueryParams;
+import com.bitmovin.api.sdk.model.CencDrm;
+import com.bitmovin.api.sdk.model.CencFairPlay;
+import com.bitmovin.api.sdk.model.CencPlayReady;
+import com.bitmovin.api.sdk.model.CencWidevine;
+import com.bitmovin.api.sdk.model.DashManifest;
+import com.bitmovin.api.sdk.model.DashManifestDefault;
+import com.bitmovin.api.sdk.model.DashManifestDefaultVersion;
+import com.bitmovin.api.sdk.model.Encoding;
+import com.bitmovin.api.sdk.model.EncodingOutput;
+import com.bitmovin.api.sdk.model.Fmp4Muxing;
+import com.bitmovin.api.sdk.model.HlsManifest;
+import com.bitmovin.api.sdk.model.HlsManifestDefault;
+import com.bitmovin.api.sdk.model.HlsManifestDefaultVersion;
+import com.bitmovin.api.sdk.model.MessageType;
+import com.bitmovin.api.sdk.model.MuxingStream;
+import com.bitmovin.api.sdk.model.StartEncodingRequest;
+import com.bitmovin.api.sdk.model.Status;
+import com.bitmovin.api.sdk.model.Stream;
+import com.bitmovin.api.sdk.model.StreamFilter;
+import com.bitmovin.api.sdk.model.StreamInput;
+import com.bitmovin.api.sdk.model.StreamMode;
+import com.bitmovin.api.sdk.model.Task;
+import common.ApiClientFactory;
+import common.BudgetTag;
+import common.ConfigProvider;
+import common.EncodingFailedException;
+import common.PreviewTrimming;
+import common.SegmentSharding;
+import java.io.IOException;
+import java.nio.charset.StandardCharsets;
+import java.nio.file.Files;
+import java.nio.file.Path;
+import java.nio.file.Paths;
+import java.time.LocalDate;
+import java.util.HashMap;
+import java.util.LinkedHashMap;
+import java.util.List;
+import java.util.Map;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+
+/**
+ * This tool demonstrates how to re-package existing assets with new DRM keys, e.g. for key rotation
+ * events mandated by content owners.
+ *
+ * <p>DRM is applied to the segments while they are muxed, so it cannot be added to or exchanged on
+ * the muxings of a finished encoding. Instead, the tool performs a minimal re-encode of each asset:
+ * it reads the streams and fMP4 muxings with CENC DRM of the original encoding, and creates a new
+ * encoding with the same inputs, codec configurations and stream filters. Only the encrypted
+ * muxings are created again, with the new key, and written to the same outputs as before, below a
+ * separate folder. Default DASH and HLS manifests are created for the re-packaged segments. This
+ * allows to switch to the re-packaged assets at once, e.g. by changing the origin path of the CDN,
+ * instead of replacing segments that are being played.
+ *
+ * <p>The new keys are read from a CSV file with one line per asset, in the format
+ * "encodingId,key,kid,widevinePssh". The Widevine PSSH is optional. If no file is configured, the
+ * key set from DRM_KEY, DRM_WIDEVINE_KID and DRM_WIDEVINE_PSSH is applied to all assets. PlayReady
+ * and FairPlay settings of the original DRM are carried over, using DRM_FAIRPLAY_IV as new
+ * initialization vector for FairPlay.
+ *
+ * <p>Only encodings with standard streams and fMP4 muxings protected with CENC are supported.
+ * Encodings using per-title or other muxing types are skipped with a warning and have to be
+ * re-encoded with their original workflow.
+ *
+ * <p>The following configuration parameters are expected:
+ *
+ * <ul>
+ *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin API
+ *   <li>BITMOVIN_TENANT_ORG_ID - (optional) The ID of the Organisation in which you want to perform
+ *       the encoding.
+ *   <li>BITMOVIN_API_BASE_URL - (optional) The base URL of the Bitmovin API, e.g. of a mock server
+ *       or an API gateway. Default: https://api.bitmovin.com/v1
+ *   <li>DRM_ROTATION_ENCODING_IDS - A comma separated list of the IDs of the encodings to be
+ *       re-packaged
+ *   <li>DRM_ROTATION_KEYS_FILE - (optional) The path to a CSV file with the new key set per
+ *       encoding
+ *   <li>DRM_KEY - (optional) The new 16 byte encryption key, represented as 32 hexadecimal
+ *       characters, if no keys file is used
+ *   <li>DRM_WIDEVINE_KID - (optional) The new 16 byte encryption key id, represented as 32
+ *       hexadecimal characters, if no keys file is used
+ *   <li>DRM_WIDEVINE_PSSH - (optional) The new base64 encoded Widevine PSSH payload, if no keys
+ *       file is used
+ *   <li>DRM_FAIRPLAY_IV - (optional) The new FairPlay initialization vector, required if the
+ *       original DRM includes FairPlay
+ *   <li>DRM_ROTATION_OUTPUT_FOLDER - (optional) The name of the folder the re-packaged assets are
+ *       written to, next to the original segments. Default: key-rotation-{current date}
+ * </ul>
+ *
+ * <p>Configuration parameters will be retrieved from these sources in the listed order:
+ *
+ * <ol>
+ *   <li>command line arguments (eg BITMOVIN_API_KEY=xyz)
+ *   <li>properties file located in the root folder of the JAVA examples at ./examples.properties
+ *       (see examples.properties.template as reference)
+ *   <li>environment variables
+ *   <li>properties file located in the home folder at ~/.bitmovin/examples.properties (see
+ *       examples.properties.template as reference)
+ * </ol>
+ */
+public class DrmKeyRotation {
+  private static final Logger logger = LoggerFactory.getLogger(DrmKeyRotation.class);
+
+  private static BitmovinApi bitmovinApi;
+  private static ConfigProvider configProvider;
+
+  private static final int PAGE_SIZE = 100;
+
+  public static void main(String[] args) throws Exception {
+    configProvider = new ConfigProvider(args);
+    bitmovinApi =
+        ApiClientFactory.builder(configProvider)
+            .withApiKey(configProvider.getBitmovinApiKey())
+            .build();
+
+    Map<String, KeySet> keySets = readKeySets();
+    String outputFolder =
+        configProvider.getParameterByKey(
+            "DRM_ROTATION_OUTPUT_FOLDER", "key-rotation-" + LocalDate.now());
+
+    for (String encodingId :
+        configProvider.getParameterByKey("DRM_ROTATION_ENCODING_IDS").split(",")) {
+      encodingId = encodingId.trim();
+      KeySet keySet = keySets.containsKey(encodingId) ? keySets.get(encodingId) : keySets.get(null);
+      if (keySet == null) {
+        logger.warn("No key set configured for encoding {}, skipping it", encodingId);
+        continue;
+      }
+
+      try {
+        repackage(encodingId, keySet, outputFolder);
+      } catch (UnsupportedOperationException e) {
+        logger.warn("Encoding {} cannot be re-packaged: {}", encodingId, e.getMessage());
+      }
+    }
+  }
+
+  /**
+   * Reads the key sets from the configured CSV file, or returns the key set from the DRM
+   * configuration parameters, stored with a null key, to be applied to all encodings
+   */
+  private static Map<String, KeySet> readKeySets() throws IOException {
+    Map<String, KeySet> keySets = new HashMap<>();
+    String keysFile = configProvider.getParameterByKey("DRM_ROTATION_KEYS_FILE", null);
+
+    if (keysFile == null) {
+      keySets.put(
+          null,
+          new KeySet(
+              configProvider.getDrmKey(),
+              configProvider.getDrmWidevineKid(),
+              configProvider.getParameterByKey("DRM_WIDEVINE_PSSH", null)));
+      return keySets;
+    }
+
+    for (String line : Files.readAllLines(Paths.get(keysFile), StandardCharsets.UTF_8)) {
+      if (line.trim().isEmpty()) {
+        continue;
+      }
+      String[] fields = line.split(",");
+      String widevinePssh = fields.length > 3 ? fields[3].trim() : null;
+      keySets.put(fields[0].trim(), new KeySet(fields[1].trim(), fields[2].trim(), widevinePssh));
+    }
+
+    return keySets;
+  }
+
+  /**
+   * Re-packages the encrypted muxings of an encoding with a new key set, and creates new default
+   * manifests for them
+   *
+   * @param encodingId The ID of the original encoding
+   * @param keySet The new key set
+   * @param outputFolder The folder the re-packaged assets are written to
+   */
+  private static void repackage(String encodingId, KeySet keySet, String outputFolder)
+      throws Exception {
+    Encoding sourceEncoding = bitmovinApi.encoding.encodings.get(encodingId);
+    List<Stream> sourceStreams = listStreams(encodingId);
+    for (Stream sourceStream : sourceStreams) {
+      if (sourceStream.getMode() != null && sourceStream.getMode() != StreamMode.STANDARD) {
+        throw new UnsupportedOperationException("stream mode " + sourceStream.getMode());
+      }
+    }
+
+    Map<Fmp4Muxing, CencDrm> sourceMuxings = new LinkedHashMap<>();
+    for (Fmp4Muxing sourceMuxing : listFmp4Muxings(encodingId)) {
+      List<CencDrm> drms =
+          bitmovinApi
+              .encoding
+              .encodings
+              .muxings
+              .fmp4
+              .drm
+              .cenc
+              .list(encodingId, sourceMuxing.getId())
+              .getItems();
+      if (!drms.isEmpty()) {
+        sourceMuxings.put(sourceMuxing, drms.get(0));
+      }
+    }
+    if (sourceMuxings.isEmpty()) {
+      throw new UnsupportedOperationException("no fMP4 muxings with CENC DRM found");
+    }
+
+    Encoding encoding = new Encoding();
+    encoding.setName(sourceEncoding.getName() + " (" + outputFolder + ")");
+    encoding.setDescription("Re-packaging of encoding " + encodingId + " with a new DRM key");
+    encoding.setCloudRegion(sourceEncoding.getCloudRegion());
+    encoding.setEncoderVersion(sourceEncoding.getEncoderVersion());
+    new BudgetTag(configProvider).applyTo(encoding);
+    encoding = bitmovinApi.encoding.encodings.create(encoding);
+
+    Map<String, Stream> streams = new HashMap<>();
+    for (Stream sourceStream : sourceStreams) {
+      streams.put(sourceStream.getId(), copyStream(sourceEncoding, sourceStream, encoding));
+    }
+
+    EncodingOutput manifestOutput = null;
+    for (Map.Entry<Fmp4Muxing, CencDrm> sourceMuxing : sourceMuxings.entrySet()) {
+      Fmp4Muxing muxing = new Fmp4Muxing();
+      muxing.setSegmentLength(sourceMuxing.getKey().getSegmentLength());
+      SegmentSharding.apply(configProvider, muxing);
+      for (MuxingStream sourceMuxingStream : sourceMuxing.getKey().getStreams()) {
+        MuxingStream muxingStream = new MuxingStream();
+        muxingStream.setStreamId(streams.get(sourceMuxingStream.getStreamId()).getId());
+        muxing.addStreamsItem(muxingStream);
+      }
+      muxing = bitmovinApi.encoding.encodings.muxings.fmp4.create(encoding.getId(), muxing);
+
+      EncodingOutput drmOutput =
+          buildRotatedOutput(sourceMuxing.getValue().getOutputs().get(0), outputFolder);
+      createDrm(encoding, muxing, sourceMuxing.getValue(), keySet, drmOutput);
+
+      if (manifestOutput == null) {
+        manifestOutput = new EncodingOutput();
+        manifestOutput.setOutputId(drmOutput.getOutputId());
+        manifestOutput.setOutputPath(drmOutput.getOutputPath());
+      } else {
+        manifestOutput.setOutputPath(
+            commonParent(manifestOutput.getOutputPath(), drmOutput.getOutputPath()));
+      }
+    }
+
+    executeEncoding(encoding);
+
+    generateDashManifest(encoding, manifestOutput);
+    generateHlsManifest(encoding, manifestOutput);
+    logger.info(
+        "Encoding {} has been re-packaged by encoding {} to {}",
+        encodingId,
+        encoding.getId(),
+        manifestOutput.getOutputPath());
+  }
+
+  /**
+   * Creates a stream in the new encoding with the same inputs, codec configuration and filters as
+   * a stream of the original encoding
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsStreamsByEncodingId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/GetEncodingEncodingsStreamsFiltersByEncodingIdAndStreamId
+   *
+   * @param sourceEncoding The original encoding
+   * @param sourceStream The stream of the original encoding
+   * @param encoding The new encoding
+   */
+  private static Stream copyStream(Encoding sourceEncoding, Stream sourceStream, Encoding encoding)
+      throws BitmovinException {
+    Stream stream = new Stream();
+    stream.setName(sourceStream.getName());
+    for (StreamInput sourceStreamInput : sourceStream.getInputStreams()) {
+      StreamInput streamInput =
+          PreviewTrimming.copyStreamInput(bitmovinApi, configProvider, encoding, sourceStreamInput);
+      stream.addInputStreamsItem(streamInput);
+    }
+    stream.setCodecConfigId(sourceStream.getCodecConfigId());
+    stream.setMode(StreamMode.STANDARD);
+    stream = bitmovinApi.encoding.encodings.streams.create(encoding.getId(), stream);
+
+    List<StreamFilter> filters =
+        bitmovinApi.encoding.encodings.streams.filters
+            .list(sourceEncoding.getId(), sourceStream.getId())
+            .getFilters();
+    if (filters != null && !filters.isEmpty()) {
+      bitmovinApi.encoding.encodings.streams.filters.create(
+          encoding.getId(), stream.getId(), filters);
+    }
+
+    return stream;
+  }
+
+  /**
+   * Adds a CENC DRM with the new key set to a muxing. PlayReady and FairPlay are applied if they
+   * have been used by the original DRM.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsMuxingsFmp4DrmCencByEncodingIdAndMuxingId
+   *
+   * @param encoding The new encoding
+   * @param muxing The muxing to add the DRM to
+   * @param sourceDrm The DRM of the original muxing
+   * @param keySet The new key set
+   * @param output The output the encrypted segments are written to
+   */
+  private static CencDrm createDrm(
+      Encoding encoding, Fmp4Muxing muxing, CencDrm sourceDrm, KeySet keySet, EncodingOutput output)
+      throws BitmovinException {
+    CencDrm cencDrm = new CencDrm();
+    cencDrm.addOutputsItem(output);
+    cencDrm.setKey(keySet.key);
+    cencDrm.setKid(keySet.kid);
+    cencDrm.setEncryptionMode(sourceDrm.getEncryptionMode());
+
+    if (keySet.widevinePssh != null) {
+      CencWidevine widevine = new CencWidevine();
+      widevine.setPssh(keySet.widevinePssh);
+      cencDrm.setWidevine(widevine);
+    }
+
+    if (sourceDrm.getPlayReady() != null) {
+      CencPlayReady playReady = new CencPlayReady();
+      playReady.setLaUrl(sourceDrm.getPlayReady().getLaUrl());
+      cencDrm.setPlayReady(playReady);
+    }
+
+    if (sourceDrm.getFairPlay() != null) {
+      CencFairPlay fairPlay = new CencFairPlay();
+      fairPlay.setIv(configProvider.getDrmFairplayIv());
+      fairPlay.setUri(sourceDrm.getFairPlay().getUri());
+      cencDrm.setFairPlay(fairPlay);
+    }
+
+    return bitmovinApi.encoding.encodings.muxings.fmp4.drm.cenc.create(
+        encoding.getId(), muxing.getId(), cencDrm);
+  }
+
+  /**
+   * Builds an output writing to the same output resource as the original one, with the output
+   * folder inserted before the last path element. E.g. "/assets/movie/video/1080" is written to
+   * "/assets/movie/video/key-rotation-2024-01-31/1080".
+   *
+   * @param sourceOutput The output of the original DRM
+   * @param outputFolder The folder the re-packaged assets are written to
+   */
+  private static EncodingOutput buildRotatedOutput(
+      EncodingOutput sourceOutput, String outputFolder) {
+    Path sourcePath = Paths.get(sourceOutput.getOutputPath());
+    Path parent = sourcePath.getParent() != null ? sourcePath.getParent() : Paths.get("/");
+
+    EncodingOutput encodingOutput = new EncodingOutput();
+    encodingOutput.setOutputId(sourceOutput.getOutputId());
+    encodingOutput.setOutputPath(
+        parent.resolve(outputFolder).resolve(sourcePath.getFileName()).toString());
+    encodingOutput.setAcl(sourceOutput.getAcl());
+    return encodingOutput;
+  }
+
+  /** Returns the deepest common parent folder of the two given paths */
+  private static String commonParent(String path1, String path2) {
+    Path parent = Paths.get(path1);
+    while (parent != null && !Paths.get(path2).startsWith(parent)) {
+      parent = parent.getParent();
+    }
+    return parent != null ? parent.toString() : "/";
+  }
+
+  /**
+   * Lists all streams of an encoding
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/GetEncodingEncodingsStreamsByEncodingId
+   *
+   * @param encodingId The ID of the encoding
+   */
+  private static List<Stream> listStreams(String encodingId) throws BitmovinException {
+    StreamListQueryParams queryParams = new StreamListQueryParams();
+    queryParams.setLimit(PAGE_SIZE);
+    return bitmovinApi.encoding.encodings.streams.list(encodingId, queryParams).getItems();
+  }
+
+  /**
+   * Lists all fMP4 muxings of an encoding
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/GetEncodingEncodingsMuxingsFmp4ByEncodingId
+   *
+   * @param encodingId The ID of the encoding
+   */
+  private static List<Fmp4Muxing> listFmp4Muxings(String encodingId) throws BitmovinException {
+    Fmp4MuxingListQueryParams queryParams = new Fmp4MuxingListQueryParams();
+    queryParams.setLimit(PAGE_SIZE);
+    return bitmovinApi.encoding.encodings.muxings.fmp4.list(encodingId, queryParams).getItems();
+  }
+
+  /**
+   * Creates a DASH default manifest for the re-packaged segments
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsDash
+   *
+   * @param encoding The encoding for which the manifest should be generated
+   * @param output The output the manifest is written to
+   */
+  private static void generateDashManifest(Encoding encoding, EncodingOutput output)
+      throws Exception {
+    DashManifestDefault dashManifestDefault = new DashManifestDefault();
+    dashManifestDefault.setEncodingId(encoding.getId());
+    dashManifestDefault.setManifestName("stream.mpd");
+    dashManifestDefault.setVersion(DashManifestDefaultVersion.V1);
+    dashManifestDefault.addOutputsItem(output);
+    dashManifestDefault =
+        bitmovinApi.encoding.manifests.dash.defaultapi.create(dashManifestDefault);
+    executeDashManifestCreation(dashManifestDefault);
+  }
+
+  /**
+   * Creates an HLS default manifest for the re-packaged segments
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsHlsDefault
+   *
+   * @param encoding The encoding for which the manifest should be generated
+   * @param output The output the manifest is written to
+   */
+  private static void generateHlsManifest(Encoding encoding, EncodingOutput output)
+      throws Exception {
+    HlsManifestDefault hlsManifestDefault = new HlsManifestDefault();
+    hlsManifestDefault.setEncodingId(encoding.getId());
+    hlsManifestDefault.addOutputsItem(output);
+    hlsManifestDefault.setName("master.m3u8");
+    hlsManifestDefault.setVersion(HlsManifestDefaultVersion.V1);
+
+    hlsManifestDefault = bitmovinApi.encoding.manifests.hls.defaultapi.create(hlsManifestDefault);
+    executeHlsManifestCreation(hlsManifestDefault);
+  }
+
+  private static class KeySet {
+
+    private String key;
+    private String kid;
+    private String widevinePssh;
+
+    /**
+     * @param key The 16 byte encryption key, represented as 32 hexadecimal characters
+     * @param kid The 16 byte key ID, represented as 32 hexadecimal characters
+     * @param widevinePssh The base64 encoded Widevine PSSH payload, or null
+     */
+    private KeySet(String key, String kid, String widevinePssh) {
+      this.key = key;
+      this.kid = kid;
+      this.widevinePssh = widevinePssh;
+    }
+  }
+
+  /**
+   * Starts the actual encoding process and periodically polls its status until it reaches a final
+   * state
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/all#/Encoding/PostEncodingEncodingsStartByEncodingId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/GetEncodingEncodingsStatusByEncodingId
+   *
+   * <p>Please note that you can also use our webhooks API instead of polling the status. For more
+   * information consult the API spec:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/notifications-webhooks
+   *
+   * @param encoding The encoding to be started
+   */
+  private static void executeEncoding(Encoding encoding)
+      throws InterruptedException, BitmovinException {
+    bitmovinApi.encoding.encodings.start(encoding.getId(), new StartEncodingRequest());
+
+    Task task;
+    do {
+      Thread.sleep(5000);
+      task = bitmovinApi.encoding.encodings.status(encoding.getId());
+      logger.info("encoding status is {} (progress: {} %)", task.getStatus(), task.getProgress());
+    } while (task.getStatus() != Status.FINISHED
+        && task.getStatus() != Status.ERROR
+        && task.getStatus() != Status.CANCELED);
+
+    if (task.getStatus() != Status.FINISHED) {
+      logTaskErrors(task);
+      throw new EncodingFailedException(task.getStatus());
+    }
+    logger.info("encoding finished successfully");
+  }
+
+  /**
+   * Starts the DASH manifest creation and periodically polls its status until it reaches a final
+   * state
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsDashStartByManifestId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/GetEncodingManifestsDashStatusByManifestId
+   *
+   * @param dashManifest The DASH manifest to be created
+   */
+  private static void executeDashManifestCreation(DashManifest dashManifest)
+      throws BitmovinException, InterruptedException {
+    bitmovinApi.encoding.manifests.dash.start(dashManifest.getId());
+
+    Task task;
+    do {
+      Thread.sleep(1000);
+      task = bitmovinApi.encoding.manifests.dash.status(dashManifest.getId());
+    } while (task.getStatus() != Status.FINISHED && task.getStatus() != Status.ERROR);
+
+    if (task.getStatus() == Status.ERROR) {
+      logTaskErrors(task);
+      throw new RuntimeException("DASH manifest creation failed");
+    }
+    logger.info("DASH manifest creation finished successfully");
+  }
+
+  /**
+   * Starts the HLS manifest creation and periodically polls its status until it reaches a final
+   * state
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsHlsStartByManifestId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/GetEncodingManifestsHlsStatusByManifestId
+   *
+   * @param hlsManifest The HLS manifest to be created
+   */
+  private static void executeHlsManifestCreation(HlsManifest hlsManifest)
+      throws BitmovinException, InterruptedException {
+
+    bitmovinApi.encoding.manifests.hls.start(hlsManifest.getId());
+
+    Task task;
+    do {
+      Thread.sleep(1000);
+      task = bitmovinApi.encoding.manifests.hls.status(hlsManifest.getId());
+    } while (task.getStatus() != Status.FINISHED && task.getStatus() != Status.ERROR);
+
+    if (task.getStatus() == Status.ERROR) {
+      logTaskErrors(task);
+      throw new RuntimeException("HLS manifest creation failed");
+    }
+    logger.info("HLS manifest creation finished successfully");
+  }
+
+  private static void logTaskErrors(Task task) {
+    task.getMessages().stream()
+        .filter(msg -> msg.getType() == MessageType.ERROR)
+        .forEach(msg -> logger.error(msg.getText()));
+  }
+}
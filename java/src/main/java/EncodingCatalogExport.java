@@ -0,0 +1,377 @@
+import com.bitmovin.api.sdk.BitmovinApi;
+import com.bitmovin.api.sdk.common.BitmovinException;
+import com.bitmovin.api.sdk.encoding.encodings.EncodingListQueryParams;
+import com.bitmovin.api.sdk.encoding.encodings.muxings.MuxingListQueryParams;
+import com.bitmovin.api.sdk.encoding.manifests.dash.DashManifestListQueryParams;
+import com.bitmovin.api.sdk.encoding.manifests.hls.HlsManifestListQueryParams;
+import com.bitmovin.api.sdk.model.DashManifest;
+import com.bitmovin.api.sdk.model.Encoding;
+import com.bitmovin.api.sdk.model.EncodingOutput;
+import com.bitmovin.api.sdk.model.HlsManifest;
+import com.bitmovin.api.sdk.model.Manifest;
+import com.bitmovin.api.sdk.model.Muxing;
+import com.bitmovin.api.sdk.model.Status;
+import common.ApiClientFactory;
+import common.ConfigProvider;
+import java.sql.Connection;
+import java.sql.DriverManager;
+import java.sql.PreparedStatement;
+import java.sql.ResultSet;
+import java.sql.SQLException;
+import java.sql.Statement;
+import java.sql.Types;
+import java.time.Duration;
+import java.time.Instant;
+import java.util.ArrayList;
+import java.util.Arrays;
+import java.util.Date;
+import java.util.HashMap;
+import java.util.List;
+import java.util.Map;
+import java.util.Objects;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+
+/**
+ * This tool synchronizes the metadata of all encodings of your account, their muxings and their
+ * DASH and HLS manifests into a relational database. This allows to answer questions about your
+ * asset library with SQL that cannot be expressed with the filters of the REST API, e.g. which
+ * titles have no HLS manifest, or how the average bitrate of the 1080p renditions developed over
+ * time.
+ *
+ * <p>By default, the catalog is a local SQLite file. A PostgreSQL database can be used instead by
+ * configuring a JDBC URL like jdbc:postgresql://db.example.com/catalog?user=x&password=y. The
+ * following tables are created if they do not exist:
+ *
+ * <ul>
+ *   <li>encodings - id, name, status, labels, encoder_version, cloud_region, created_at,
+ *       modified_at and synced_at of every encoding
+ *   <li>muxings - id, encoding_id, type, name, avg_bitrate, output_id and output_path of every
+ *       muxing. If a muxing writes to multiple outputs, the first one is stored.
+ *   <li>manifests - id, encoding_id, type (DASH or HLS), name, manifest_name, output_id and
+ *       output_path of every manifest. A manifest referencing multiple encodings is stored once
+ *       for each of them.
+ * </ul>
+ *
+ * <p>Timestamps are stored as ISO-8601 strings in UTC, which sort chronologically and can be
+ * compared with string literals in both databases, e.g. WHERE created_at &gt;= '2021-12-01'.
+ *
+ * <p>The synchronization is incremental: the list of encodings is fetched on every run, but the
+ * muxings and manifests of an encoding are only fetched again if the encoding has been modified or
+ * has not reached a final state since the previous run. Without CATALOG_SYNC_INTERVAL_MINUTES, the
+ * tool synchronizes once and terminates, so it can be scheduled by cron or a CI pipeline. With it,
+ * the tool keeps running and synchronizes in the given interval.
+ *
+ * <p>The following configuration parameters are expected:
+ *
+ * <ul>
+ *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin API
+ *   <li>BITMOVIN_TENANT_ORG_ID - (optional) The ID of the Organisation in which you want to perform
+ *       the encoding.
+ *   <li>BITMOVIN_API_BASE_URL - (optional) The base URL of the Bitmovin API, e.g. of a mock server
+ *       or an API gateway. Default: https://api.bitmovin.com/v1
+ *   <li>CATALOG_JDBC_URL - (optional) The JDBC URL of the catalog database. Default:
+ *       jdbc:sqlite:encoding-catalog.db
+ *   <li>CATALOG_SYNC_INTERVAL_MINUTES - (optional) The interval in which the catalog is
+ *       synchronized. If not set, the catalog is synchronized once.
+ * </ul>
+ *
+ * <p>Configuration parameters will be retrieved from these sources in the listed order:
+ *
+ * <ol>
+ *   <li>command line arguments (eg BITMOVIN_API_KEY=xyz)
+ *   <li>properties file located in the root folder of the JAVA examples at ./examples.properties
+ *       (see examples.properties.template as reference)
+ *   <li>environment variables
+ *   <li>properties file located in the home folder at ~/.bitmovin/examples.properties (see
+ *       examples.properties.template as reference)
+ * </ol>
+ */
+public class EncodingCatalogExport {
+  private static final Logger logger = LoggerFactory.getLogger(EncodingCatalogExport.class);
+
+  private static final int PAGE_SIZE = 100;
+
+  /** The states after which an encoding is not expected to change any more */
+  private static final List<Status> FINAL_STATES =
+      Arrays.asList(Status.FINISHED, Status.ERROR, Status.CANCELED);
+
+  private static final String[] SCHEMA = {
+    "CREATE TABLE IF NOT EXISTS encodings ("
+        + "id VARCHAR(64) PRIMARY KEY, name TEXT, status VARCHAR(32), labels TEXT,"
+        + " encoder_version VARCHAR(32), cloud_region VARCHAR(64), created_at VARCHAR(32),"
+        + " modified_at VARCHAR(32), synced_at VARCHAR(32))",
+    "CREATE TABLE IF NOT EXISTS muxings ("
+        + "id VARCHAR(64) PRIMARY KEY, encoding_id VARCHAR(64), type VARCHAR(32), name TEXT,"
+        + " avg_bitrate BIGINT, output_id VARCHAR(64), output_path TEXT)",
+    "CREATE TABLE IF NOT EXISTS manifests ("
+        + "id VARCHAR(64), encoding_id VARCHAR(64), type VARCHAR(16), name TEXT,"
+        + " manifest_name TEXT, output_id VARCHAR(64), output_path TEXT,"
+        + " PRIMARY KEY (id, encoding_id))",
+    "CREATE INDEX IF NOT EXISTS muxings_encoding_id ON muxings (encoding_id)",
+    "CREATE INDEX IF NOT EXISTS manifests_encoding_id ON manifests (encoding_id)"
+  };
+
+  private static BitmovinApi bitmovinApi;
+  private static ConfigProvider configProvider;
+
+  public static void main(String[] args) throws Exception {
+    configProvider = new ConfigProvider(args);
+    bitmovinApi =
+        ApiClientFactory.builder(configProvider)
+            .withApiKey(configProvider.getBitmovinApiKey())
+            .build();
+
+    String jdbcUrl =
+        configProvider.getParameterByKey("CATALOG_JDBC_URL", "jdbc:sqlite:encoding-catalog.db");
+    String interval = configProvider.getParameterByKey("CATALOG_SYNC_INTERVAL_MINUTES", null);
+
+    try (Connection connection = DriverManager.getConnection(jdbcUrl)) {
+      createSchema(connection);
+
+      while (true) {
+        syncCatalog(connection);
+        if (interval == null) {
+          return;
+        }
+        logger.info("Next synchronization in {} minutes", interval);
+        Thread.sleep(Duration.ofMinutes(Long.parseLong(interval)).toMillis());
+      }
+    }
+  }
+
+  /**
+   * Synchronizes all encodings of the account into the catalog. Each encoding is written in its own
+   * transaction, so an interrupted run leaves a consistent catalog behind.
+   *
+   * @param connection The connection to the catalog database
+   */
+  private static void syncCatalog(Connection connection) throws BitmovinException, SQLException {
+    Map<String, String> syncedEncodings = readSyncedEncodings(connection);
+    List<Encoding> encodings = listAllEncodings();
+    logger.info("Synchronizing {} encodings into the catalog", encodings.size());
+
+    int updated = 0;
+    connection.setAutoCommit(false);
+    try {
+      for (Encoding encoding : encodings) {
+        String modifiedAt = toIsoString(encoding.getModifiedAt());
+        boolean unchanged =
+            FINAL_STATES.contains(encoding.getStatus())
+                && modifiedAt != null
+                && modifiedAt.equals(syncedEncodings.get(encoding.getId()));
+        if (unchanged) {
+          continue;
+        }
+
+        writeEncoding(connection, encoding);
+        writeMuxings(connection, encoding.getId(), listMuxings(encoding.getId()));
+        writeManifests(connection, encoding.getId());
+        connection.commit();
+        updated++;
+      }
+    } catch (BitmovinException | SQLException e) {
+      connection.rollback();
+      throw e;
+    } finally {
+      connection.setAutoCommit(true);
+    }
+
+    logger.info(
+        "Catalog synchronized: {} encodings updated, {} unchanged",
+        updated,
+        encodings.size() - updated);
+  }
+
+  private static void createSchema(Connection connection) throws SQLException {
+    try (Statement statement = connection.createStatement()) {
+      for (String sql : SCHEMA) {
+        statement.execute(sql);
+      }
+    }
+  }
+
+  /**
+   * Reads the modification timestamps of the encodings already in the catalog, which are used to
+   * skip unchanged encodings
+   *
+   * @param connection The connection to the catalog database
+   */
+  private static Map<String, String> readSyncedEncodings(Connection connection)
+      throws SQLException {
+    Map<String, String> modifiedAtById = new HashMap<>();
+    try (Statement statement = connection.createStatement();
+        ResultSet resultSet = statement.executeQuery("SELECT id, modified_at FROM encodings")) {
+      while (resultSet.next()) {
+        modifiedAtById.put(resultSet.getString("id"), resultSet.getString("modified_at"));
+      }
+    }
+    return modifiedAtById;
+  }
+
+  private static void writeEncoding(Connection connection, Encoding encoding)
+      throws SQLException {
+    delete(connection, "DELETE FROM encodings WHERE id = ?", encoding.getId());
+
+    try (PreparedStatement statement =
+        connection.prepareStatement(
+            "INSERT INTO encodings (id, name, status, labels, encoder_version, cloud_region,"
+                + " created_at, modified_at, synced_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)")) {
+      statement.setString(1, encoding.getId());
+      statement.setString(2, encoding.getName());
+      statement.setString(3, Objects.toString(encoding.getStatus(), null));
+      statement.setString(
+          4, encoding.getLabels() != null ? String.join(",", encoding.getLabels()) : null);
+      statement.setString(5, encoding.getSelectedEncoderVersion());
+      statement.setString(6, Objects.toString(encoding.getSelectedCloudRegion(), null));
+      statement.setString(7, toIsoString(encoding.getCreatedAt()));
+      statement.setString(8, toIsoString(encoding.getModifiedAt()));
+      statement.setString(9, Instant.now().toString());
+      statement.executeUpdate();
+    }
+  }
+
+  private static void writeMuxings(Connection connection, String encodingId, List<Muxing> muxings)
+      throws SQLException {
+    delete(connection, "DELETE FROM muxings WHERE encoding_id = ?", encodingId);
+
+    try (PreparedStatement statement =
+        connection.prepareStatement(
+            "INSERT INTO muxings (id, encoding_id, type, name, avg_bitrate, output_id,"
+                + " output_path) VALUES (?, ?, ?, ?, ?, ?, ?)")) {
+      for (Muxing muxing : muxings) {
+        EncodingOutput output = firstOutput(muxing.getOutputs());
+        statement.setString(1, muxing.getId());
+        statement.setString(2, encodingId);
+        statement.setString(3, Objects.toString(muxing.getType(), null));
+        statement.setString(4, muxing.getName());
+        if (muxing.getAvgBitrate() != null) {
+          statement.setLong(5, muxing.getAvgBitrate());
+        } else {
+          statement.setNull(5, Types.BIGINT);
+        }
+        statement.setString(6, output != null ? output.getOutputId() : null);
+        statement.setString(7, output != null ? output.getOutputPath() : null);
+        statement.addBatch();
+      }
+      statement.executeBatch();
+    }
+  }
+
+  private static void writeManifests(Connection connection, String encodingId)
+      throws BitmovinException, SQLException {
+    delete(connection, "DELETE FROM manifests WHERE encoding_id = ?", encodingId);
+
+    try (PreparedStatement statement =
+        connection.prepareStatement(
+            "INSERT INTO manifests (id, encoding_id, type, name, manifest_name, output_id,"
+                + " output_path) VALUES (?, ?, ?, ?, ?, ?, ?)")) {
+      for (DashManifest dashManifest : listDashManifests(encodingId)) {
+        addManifest(statement, encodingId, "DASH", dashManifest, dashManifest.getManifestName());
+      }
+      for (HlsManifest hlsManifest : listHlsManifests(encodingId)) {
+        addManifest(statement, encodingId, "HLS", hlsManifest, hlsManifest.getManifestName());
+      }
+      statement.executeBatch();
+    }
+  }
+
+  private static void addManifest(
+      PreparedStatement statement,
+      String encodingId,
+      String type,
+      Manifest manifest,
+      String manifestName)
+      throws SQLException {
+    EncodingOutput output = firstOutput(manifest.getOutputs());
+    statement.setString(1, manifest.getId());
+    statement.setString(2, encodingId);
+    statement.setString(3, type);
+    statement.setString(4, manifest.getName());
+    statement.setString(5, manifestName);
+    statement.setString(6, output != null ? output.getOutputId() : null);
+    statement.setString(7, output != null ? output.getOutputPath() : null);
+    statement.addBatch();
+  }
+
+  private static void delete(Connection connection, String sql, String id) throws SQLException {
+    try (PreparedStatement statement = connection.prepareStatement(sql)) {
+      statement.setString(1, id);
+      statement.executeUpdate();
+    }
+  }
+
+  /**
+   * Lists all encodings of the account, requesting as many pages as needed.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/GetEncodingEncodings
+   */
+  private static List<Encoding> listAllEncodings() throws BitmovinException {
+    EncodingListQueryParams queryParams = new EncodingListQueryParams();
+    queryParams.setLimit(PAGE_SIZE);
+
+    List<Encoding> encodings = new ArrayList<>();
+    List<Encoding> page;
+    do {
+      queryParams.setOffset(encodings.size());
+      page = bitmovinApi.encoding.encodings.list(queryParams).getItems();
+      encodings.addAll(page);
+    } while (page.size() == PAGE_SIZE);
+
+    return encodings;
+  }
+
+  /**
+   * Lists the muxings of all types of an encoding
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/GetEncodingEncodingsMuxingsByEncodingId
+   *
+   * @param encodingId The ID of the encoding
+   */
+  private static List<Muxing> listMuxings(String encodingId) throws BitmovinException {
+    MuxingListQueryParams queryParams = new MuxingListQueryParams();
+    queryParams.setLimit(PAGE_SIZE);
+    return bitmovinApi.encoding.encodings.muxings.list(encodingId, queryParams).getItems();
+  }
+
+  /**
+   * Lists the DASH manifests created for an encoding
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/GetEncodingManifestsDash
+   *
+   * @param encodingId The ID of the encoding
+   */
+  private static List<DashManifest> listDashManifests(String encodingId)
+      throws BitmovinException {
+    DashManifestListQueryParams queryParams = new DashManifestListQueryParams();
+    queryParams.setEncodingId(encodingId);
+    queryParams.setLimit(PAGE_SIZE);
+    return bitmovinApi.encoding.manifests.dash.list(queryParams).getItems();
+  }
+
+  /**
+   * Lists the HLS manifests created for an encoding
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/GetEncodingManifestsHls
+   *
+   * @param encodingId The ID of the encoding
+   */
+  private static List<HlsManifest> listHlsManifests(String encodingId) throws BitmovinException {
+    HlsManifestListQueryParams queryParams = new HlsManifestListQueryParams();
+    queryParams.setEncodingId(encodingId);
+    queryParams.setLimit(PAGE_SIZE);
+    return bitmovinApi.encoding.manifests.hls.list(queryParams).getItems();
+  }
+
+  private static EncodingOutput firstOutput(List<EncodingOutput> outputs) {
+    return outputs != null && !outputs.isEmpty() ? outputs.get(0) : null;
+  }
+
+  private static String toIsoString(Date date) {
+    return date != null ? date.toInstant().toString() : null;
+  }
+}
@@ -0,0 +1,511 @@
+import com.bitmovin.api.sdk.BitmovinApi;
+import com.bitmovin.api.sdk.common.BitmovinException;
+import com.bitmovin.api.sdk.model.AacAudioConfiguration;
+import com.bitmovin.api.sdk.model.AclEntry;
+import com.bitmovin.api.sdk.model.AclPermission;
+import com.bitmovin.api.sdk.model.CodecConfiguration;
+import com.bitmovin.api.sdk.model.Encoding;
+import com.bitmovin.api.sdk.model.EncodingOutput;
+import com.bitmovin.api.sdk.model.H264VideoConfiguration;
+import com.bitmovin.api.sdk.model.HttpInput;
+import com.bitmovin.api.sdk.model.Input;
+import com.bitmovin.api.sdk.model.Mp4Muxing;
+import com.bitmovin.api.sdk.model.MuxingStream;
+import com.bitmovin.api.sdk.model.Output;
+import com.bitmovin.api.sdk.model.PresetConfiguration;
+import com.bitmovin.api.sdk.model.S3Output;
+import com.bitmovin.api.sdk.model.StartEncodingRequest;
+import com.bitmovin.api.sdk.model.Stream;
+import com.bitmovin.api.sdk.model.StreamInput;
+import com.bitmovin.api.sdk.model.Task;
+import com.bitmovin.api.sdk.model.Webhook;
+import com.bitmovin.api.sdk.model.WebhookHttpMethod;
+import com.fasterxml.jackson.databind.JsonNode;
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.sun.net.httpserver.HttpExchange;
+import com.sun.net.httpserver.HttpServer;
+import common.ApiClientFactory;
+import common.BudgetTag;
+import common.ConfigProvider;
+import common.PreviewTrimming;
+import java.io.IOException;
+import java.io.InputStream;
+import java.net.InetSocketAddress;
+import java.nio.file.Paths;
+import java.time.Instant;
+import java.util.Arrays;
+import java.util.LinkedHashMap;
+import java.util.List;
+import java.util.Map;
+import java.util.concurrent.CountDownLatch;
+import org.apache.commons.lang3.StringUtils;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import software.amazon.awssdk.auth.credentials.AwsBasicCredentials;
+import software.amazon.awssdk.auth.credentials.StaticCredentialsProvider;
+import software.amazon.awssdk.core.sync.RequestBody;
+import software.amazon.awssdk.regions.Region;
+import software.amazon.awssdk.services.eventbridge.EventBridgeClient;
+import software.amazon.awssdk.services.eventbridge.model.PutEventsRequest;
+import software.amazon.awssdk.services.eventbridge.model.PutEventsRequestEntry;
+import software.amazon.awssdk.services.s3.S3Client;
+import software.amazon.awssdk.services.s3.model.PutObjectRequest;
+
+/**
+ * This example demonstrates how to react to the completion of an encoding with webhooks instead of
+ * polling its status, and how to hand the result over to downstream systems (e.g. a CMS or a QC
+ * pipeline) in a loosely-coupled way.
+ *
+ * <p>A small HTTP server is started, which receives the webhooks sent by Bitmovin when the encoding
+ * has finished or failed. For every received webhook, a JSON summary of the encoding is written to
+ * the "events" prefix of the S3 output bucket. Downstream systems can subscribe to the S3 event
+ * notifications of that prefix. Optionally, the summary is also published to an Amazon EventBridge
+ * event bus.
+ *
+ * <p>The webhook URL has to be reachable from the internet and forward requests to the port of the
+ * local HTTP server. For local testing, a tunnel service can be used.
+ *
+ * <p>The following configuration parameters are expected:
+ *
+ * <ul>
+ *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin API
+ *   <li>BITMOVIN_TENANT_ORG_ID - (optional) The ID of the Organisation in which you want to perform
+ *       the encoding.
+ *   <li>BITMOVIN_API_BASE_URL - (optional) The base URL of the Bitmovin API, e.g. of a mock server
+ *       or an API gateway. Default: https://api.bitmovin.com/v1
+ *   <li>HTTP_INPUT_HOST - The Hostname or IP address of the HTTP server hosting your input files,
+ *       e.g.: my-storage.biz
+ *   <li>HTTP_INPUT_FILE_PATH - The path to your input file on the provided HTTP server Example:
+ *       videos/1080p_Sintel.mp4
+ *   <li>S3_OUTPUT_BUCKET_NAME - The name of your S3 output bucket. Example: my-bucket-name
+ *   <li>S3_OUTPUT_ACCESS_KEY - The access key of your S3 output bucket
+ *   <li>S3_OUTPUT_SECRET_KEY - The secret key of your S3 output bucket
+ *   <li>S3_OUTPUT_BASE_PATH - The base path on your S3 output bucket where content will be written.
+ *       Example: /outputs
+ *   <li>WEBHOOK_URL - The public URL forwarding requests to the local HTTP server. Example:
+ *       https://my-tunnel.example.com
+ *   <li>WEBHOOK_SERVER_PORT - (optional) The port of the local HTTP server. Default: 8080
+ *   <li>EVENTS_AWS_REGION - (optional) The AWS region of the S3 output bucket and the event bus.
+ *       Default: us-east-1
+ *   <li>EVENTS_EVENT_BUS_NAME - (optional) The name of the EventBridge event bus the summaries are
+ *       published to
+ * </ul>
+ *
+ * <p>Configuration parameters will be retrieved from these sources in the listed order:
+ *
+ * <ol>
+ *   <li>command line arguments (eg BITMOVIN_API_KEY=xyz)
+ *   <li>properties file located in the root folder of the JAVA examples at ./examples.properties
+ *       (see examples.properties.template as reference)
+ *   <li>environment variables
+ *   <li>properties file located in the home folder at ~/.bitmovin/examples.properties (see
+ *       examples.properties.template as reference)
+ * </ol>
+ */
+public class EncodingEventPublisher {
+  private static final Logger logger = LoggerFactory.getLogger(EncodingEventPublisher.class);
+
+  private static final ObjectMapper objectMapper = new ObjectMapper();
+  private static final CountDownLatch webhookReceived = new CountDownLatch(1);
+
+  private static BitmovinApi bitmovinApi;
+  private static ConfigProvider configProvider;
+
+  public static void main(String[] args) throws Exception {
+    configProvider = new ConfigProvider(args);
+    bitmovinApi =
+        ApiClientFactory.builder(configProvider)
+            .withApiKey(configProvider.getBitmovinApiKey())
+            .build();
+
+    int serverPort =
+        Integer.parseInt(configProvider.getParameterByKey("WEBHOOK_SERVER_PORT", "8080"));
+    String webhookUrl = StringUtils.removeEnd(configProvider.getParameterByKey("WEBHOOK_URL"), "/");
+
+    HttpServer server = HttpServer.create(new InetSocketAddress(serverPort), 0);
+    server.createContext("/", EncodingEventPublisher::handleWebhook);
+    server.start();
+    logger.info("Listening for webhooks on port {}", serverPort);
+
+    Encoding encoding =
+        createEncoding(
+            "Encoding event publisher", "Encoding with a webhook publishing its summary");
+
+    HttpInput input = createHttpInput(configProvider.getHttpInputHost());
+    Output output =
+        createS3Output(
+            configProvider.getS3OutputBucketName(),
+            configProvider.getS3OutputAccessKey(),
+            configProvider.getS3OutputSecretKey());
+
+    String inputFilePath = configProvider.getHttpInputFilePath();
+
+    H264VideoConfiguration h264Config = createH264VideoConfig(1080, 4_800_000L);
+    AacAudioConfiguration aacConfig = createAacAudioConfig();
+
+    Stream videoStream = createStream(encoding, input, inputFilePath, h264Config);
+    Stream audioStream = createStream(encoding, input, inputFilePath, aacConfig);
+
+    createMp4Muxing(
+        encoding, output, "/", Arrays.asList(videoStream, audioStream), "video_1080p.mp4");
+
+    createWebhooks(encoding, webhookUrl);
+
+    bitmovinApi.encoding.encodings.start(encoding.getId(), new StartEncodingRequest());
+    logger.info("Encoding {} started, waiting for the webhook to be received", encoding.getId());
+
+    webhookReceived.await();
+    server.stop(0);
+  }
+
+  /**
+   * Creates webhooks which will be sent to the local HTTP server when the encoding has finished or
+   * failed. The event type is encoded into the path of the webhook URL.
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/notifications#/Encoding/PostNotificationsWebhooksEncodingEncodingsFinishedByEncodingId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/notifications#/Encoding/PostNotificationsWebhooksEncodingEncodingsErrorByEncodingId
+   *
+   * @param encoding The encoding for which the webhooks should be sent
+   * @param webhookUrl The public URL forwarding requests to the local HTTP server
+   */
+  private static void createWebhooks(Encoding encoding, String webhookUrl)
+      throws BitmovinException {
+    Webhook finishedWebhook = new Webhook();
+    finishedWebhook.setUrl(webhookUrl + "/finished");
+    finishedWebhook.setMethod(WebhookHttpMethod.POST);
+    bitmovinApi.notifications.webhooks.encoding.encodings.finished.createByEncodingId(
+        encoding.getId(), finishedWebhook);
+
+    Webhook errorWebhook = new Webhook();
+    errorWebhook.setUrl(webhookUrl + "/error");
+    errorWebhook.setMethod(WebhookHttpMethod.POST);
+    bitmovinApi.notifications.webhooks.encoding.encodings.error.createByEncodingId(
+        encoding.getId(), errorWebhook);
+  }
+
+  /**
+   * Handles a webhook request. The request is acknowledged immediately, before the summary is
+   * published, so Bitmovin does not run into a timeout and resend the webhook.
+   *
+   * @param exchange The HTTP request received by the local HTTP server
+   */
+  private static void handleWebhook(HttpExchange exchange) throws IOException {
+    if (!"POST".equals(exchange.getRequestMethod())) {
+      exchange.sendResponseHeaders(405, -1);
+      exchange.close();
+      return;
+    }
+
+    JsonNode payload;
+    try (InputStream requestBody = exchange.getRequestBody()) {
+      payload = objectMapper.readTree(requestBody);
+    }
+
+    exchange.sendResponseHeaders(204, -1);
+    exchange.close();
+
+    String eventType =
+        exchange.getRequestURI().getPath().endsWith("/error")
+            ? "ENCODING_ERROR"
+            : "ENCODING_FINISHED";
+    logger.info("Received {} webhook", eventType);
+
+    try {
+      publishEncodingSummary(eventType, getEncodingId(payload));
+    } catch (Exception e) {
+      logger.error("Failed to publish the encoding summary", e);
+    } finally {
+      webhookReceived.countDown();
+    }
+  }
+
+  /**
+   * Extracts the ID of the encoding the webhook was sent for. Depending on the webhook payload
+   * format, the ID is either contained in a nested encoding object or in the resource ID field.
+   *
+   * @param payload The JSON payload of the webhook
+   */
+  private static String getEncodingId(JsonNode payload) {
+    String encodingId = payload.path("encoding").path("id").asText(null);
+    if (encodingId == null) {
+      encodingId = payload.path("resourceId").asText(null);
+    }
+
+    if (encodingId == null) {
+      throw new IllegalArgumentException("Webhook payload does not contain an encoding ID");
+    }
+
+    return encodingId;
+  }
+
+  /**
+   * Builds a JSON summary of the encoding and writes it to the "events" prefix of the S3 output
+   * bucket. If an EventBridge event bus is configured, the summary is also published as an event.
+   *
+   * @param eventType The type of the received webhook event
+   * @param encodingId The ID of the encoding the webhook was sent for
+   */
+  private static void publishEncodingSummary(String eventType, String encodingId)
+      throws Exception {
+    Encoding encoding = bitmovinApi.encoding.encodings.get(encodingId);
+    Task task = bitmovinApi.encoding.encodings.status(encodingId);
+
+    Map<String, Object> summary = new LinkedHashMap<>();
+    summary.put("eventType", eventType);
+    summary.put("encodingId", encodingId);
+    summary.put("encodingName", encoding.getName());
+    summary.put("status", String.valueOf(task.getStatus()));
+    summary.put("outputBucket", configProvider.getS3OutputBucketName());
+    summary.put("outputPath", buildAbsolutePath("/"));
+    summary.put("publishedAt", Instant.now().toString());
+
+    String summaryJson = objectMapper.writerWithDefaultPrettyPrinter().writeValueAsString(summary);
+
+    StaticCredentialsProvider credentialsProvider =
+        StaticCredentialsProvider.create(
+            AwsBasicCredentials.create(
+                configProvider.getS3OutputAccessKey(), configProvider.getS3OutputSecretKey()));
+    Region region = Region.of(configProvider.getParameterByKey("EVENTS_AWS_REGION", "us-east-1"));
+
+    String objectKey =
+        StringUtils.removeStart(buildAbsolutePath("events/" + encodingId + ".json"), "/");
+
+    try (S3Client s3Client =
+        S3Client.builder().credentialsProvider(credentialsProvider).region(region).build()) {
+      PutObjectRequest putObjectRequest =
+          PutObjectRequest.builder()
+              .bucket(configProvider.getS3OutputBucketName())
+              .key(objectKey)
+              .contentType("application/json")
+              .build();
+      s3Client.putObject(putObjectRequest, RequestBody.fromString(summaryJson));
+    }
+    logger.info(
+        "Wrote encoding summary to s3://{}/{}", configProvider.getS3OutputBucketName(), objectKey);
+
+    String eventBusName = configProvider.getParameterByKey("EVENTS_EVENT_BUS_NAME", null);
+    if (eventBusName == null) {
+      return;
+    }
+
+    try (EventBridgeClient eventBridgeClient =
+        EventBridgeClient.builder()
+            .credentialsProvider(credentialsProvider)
+            .region(region)
+            .build()) {
+      PutEventsRequestEntry entry =
+          PutEventsRequestEntry.builder()
+              .eventBusName(eventBusName)
+              .source("bitmovin.encoding")
+              .detailType(eventType)
+              .detail(summaryJson)
+              .build();
+      eventBridgeClient.putEvents(PutEventsRequest.builder().entries(entry).build());
+    }
+    logger.info("Published encoding summary to event bus {}", eventBusName);
+  }
+
+  /**
+   * Creates a resource representing an HTTP server providing the input files. For alternative input
+   * methods see <a
+   * href="https://bitmovin.com/docs/encoding/articles/supported-input-output-storages">list of
+   * supported input and output storages</a>
+   *
+   * <p>For reasons of simplicity, a new input resource is created on each execution of this
+   * example. In production use, this method should be replaced by a <a
+   * href="https://bitmovin.com/docs/encoding/api-reference/sections/inputs#/Encoding/GetEncodingInputsHttpByInputId">get
+   * call</a> to retrieve an existing resource.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/inputs#/Encoding/PostEncodingInputsHttp
+   *
+   * @param host The hostname or IP address of the HTTP server e.g.: my-storage.biz
+   */
+  private static HttpInput createHttpInput(String host) throws BitmovinException {
+    HttpInput input = new HttpInput();
+    input.setHost(host);
+
+    return bitmovinApi.encoding.inputs.http.create(input);
+  }
+
+  /**
+   * Creates a resource representing an AWS S3 cloud storage bucket to which generated content will
+   * be transferred. For alternative output methods see <a
+   * href="https://bitmovin.com/docs/encoding/articles/supported-input-output-storages">list of
+   * supported input and output storages</a>
+   *
+   * <p>The provided credentials need to allow <i>read</i>, <i>write</i> and <i>list</i> operations.
+   * <i>delete</i> should also be granted to allow overwriting of existings files. See <a
+   * href="https://bitmovin.com/docs/encoding/faqs/how-do-i-create-a-aws-s3-bucket-which-can-be-used-as-output-location">creating
+   * an S3 bucket and setting permissions</a> for further information
+   *
+   * <p>For reasons of simplicity, a new output resource is created on each execution of this
+   * example. In production use, this method should be replaced by a <a
+   * href="https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/GetEncodingOutputsS3">get
+   * call</a> retrieving an existing resource.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/PostEncodingOutputsS3
+   *
+   * @param bucketName The name of the S3 bucket
+   * @param accessKey The access key of your S3 account
+   * @param secretKey The secret key of your S3 account
+   */
+  private static S3Output createS3Output(String bucketName, String accessKey, String secretKey)
+      throws BitmovinException {
+
+    S3Output s3Output = new S3Output();
+    s3Output.setBucketName(bucketName);
+    s3Output.setAccessKey(accessKey);
+    s3Output.setSecretKey(secretKey);
+
+    return bitmovinApi.encoding.outputs.s3.create(s3Output);
+  }
+
+  /**
+   * Creates an encoding object. This is the base object to configure your encoding.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodings
+   *
+   * @param name This is the name of the encoding
+   * @param description This is the description of the encoding
+   */
+  private static Encoding createEncoding(String name, String description) throws BitmovinException {
+    Encoding encoding = new Encoding();
+    encoding.setName(name);
+    encoding.setDescription(description);
+    new BudgetTag(configProvider).applyTo(encoding);
+
+    return bitmovinApi.encoding.encodings.create(encoding);
+  }
+
+  /**
+   * Creates a stream which binds an input file to a codec configuration. The stream is used for
+   * muxings later on.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsStreamsByEncodingId
+   *
+   * @param encoding The encoding to add the stream onto
+   * @param input The input that should be used
+   * @param inputPath The path to the input file
+   * @param codecConfiguration The codec configuration to be applied to the stream
+   */
+  private static Stream createStream(
+      Encoding encoding, Input input, String inputPath, CodecConfiguration codecConfiguration)
+      throws BitmovinException {
+    StreamInput streamInput =
+        PreviewTrimming.createStreamInput(bitmovinApi, configProvider, encoding, input, inputPath);
+
+    Stream stream = new Stream();
+    stream.addInputStreamsItem(streamInput);
+    stream.setCodecConfigId(codecConfiguration.getId());
+
+    return bitmovinApi.encoding.encodings.streams.create(encoding.getId(), stream);
+  }
+
+  /**
+   * Creates a configuration for the H.264 video codec to be applied to video streams.
+   *
+   * <p>The output resolution is defined by setting only the height. Width will be determined
+   * automatically to maintain the aspect ratio of your input video.
+   *
+   * <p>To keep things simple, we use a quality-optimized VoD preset configuration, which will apply
+   * proven settings for the codec. See <a
+   * href="https://bitmovin.com/docs/encoding/tutorials/how-to-optimize-your-h264-codec-configuration-for-different-use-cases">How
+   * to optimize your H264 codec configuration for different use-cases</a> for alternative presets.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsVideoH264
+   *
+   * @param height The height of the output video
+   * @param bitrate The target bitrate of the output video
+   */
+  private static H264VideoConfiguration createH264VideoConfig(int height, long bitrate)
+      throws BitmovinException {
+    H264VideoConfiguration config = new H264VideoConfiguration();
+    config.setName(String.format("H.264 %dp", height));
+    config.setPresetConfiguration(PresetConfiguration.VOD_STANDARD);
+    config.setHeight(height);
+    config.setBitrate(bitrate);
+
+    return bitmovinApi.encoding.configurations.video.h264.create(config);
+  }
+
+  /**
+   * Creates a configuration for the AAC audio codec to be applied to audio streams.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsAudioAac
+   */
+  private static AacAudioConfiguration createAacAudioConfig() throws BitmovinException {
+    AacAudioConfiguration config = new AacAudioConfiguration();
+    config.setName("AAC 128 kbit/s");
+    config.setBitrate(128_000L);
+
+    return bitmovinApi.encoding.configurations.audio.aac.create(config);
+  }
+
+  /**
+   * Creates an MP4 muxing.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsMuxingsMp4ByEncodingId
+   *
+   * @param encoding The encoding to add the MP4 muxing to
+   * @param output The output that should be used for the muxing to write the segments to
+   * @param outputPath The output path where the fragments will be written to
+   * @param streams A list of streams to be added to the muxing
+   * @param fileName The name of the file that will be written to the output
+   */
+  private static Mp4Muxing createMp4Muxing(
+      Encoding encoding, Output output, String outputPath, List<Stream> streams, String fileName)
+      throws BitmovinException {
+    Mp4Muxing muxing = new Mp4Muxing();
+    muxing.addOutputsItem(buildEncodingOutput(output, outputPath));
+    muxing.setFilename(fileName);
+
+    for (Stream stream : streams) {
+      MuxingStream muxingStream = new MuxingStream();
+      muxingStream.setStreamId(stream.getId());
+      muxing.addStreamsItem(muxingStream);
+    }
+
+    return bitmovinApi.encoding.encodings.muxings.mp4.create(encoding.getId(), muxing);
+  }
+
+  /**
+   * Builds an EncodingOutput object which defines where the output content (e.g. of a muxing) will
+   * be written to. Public read permissions will be set for the files written, so they can be
+   * accessed easily via HTTP.
+   *
+   * @param output The output resource to be used by the EncodingOutput
+   * @param outputPath The path where the content will be written to
+   */
+  private static EncodingOutput buildEncodingOutput(Output output, String outputPath) {
+    AclEntry aclEntry = new AclEntry();
+    aclEntry.setPermission(AclPermission.PUBLIC_READ);
+
+    EncodingOutput encodingOutput = new EncodingOutput();
+    encodingOutput.setOutputPath(buildAbsolutePath(outputPath));
+    encodingOutput.setOutputId(output.getId());
+    encodingOutput.addAclItem(aclEntry);
+    return encodingOutput;
+  }
+
+  /**
+   * Builds an absolute path by concatenating the S3_OUTPUT_BASE_PATH configuration parameter, the
+   * name of this example class and the given relative path
+   *
+   * <p>e.g.: /s3/base/path/ClassName/relative/path
+   *
+   * @param relativePath The relative path that is concatenated
+   * @return The absolute path
+   */
+  public static String buildAbsolutePath(String relativePath) {
+    String className = EncodingEventPublisher.class.getSimpleName();
+    return Paths.get(configProvider.getS3OutputBasePath(), className, relativePath).toString();
+  }
+}
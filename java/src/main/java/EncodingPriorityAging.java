@@ -0,0 +1,251 @@
+import com.bitmovin.api.sdk.BitmovinApi;
+import com.bitmovin.api.sdk.common.BitmovinException;
+import com.bitmovin.api.sdk.encoding.encodings.EncodingListQueryParams;
+import com.bitmovin.api.sdk.model.Encoding;
+import com.bitmovin.api.sdk.model.ReprioritizeEncodingRequest;
+import com.bitmovin.api.sdk.model.StartEncodingRequest;
+import com.bitmovin.api.sdk.model.Status;
+import common.ApiClientFactory;
+import common.ConfigProvider;
+import java.time.Duration;
+import java.time.Instant;
+import java.util.ArrayList;
+import java.util.Date;
+import java.util.HashMap;
+import java.util.List;
+import java.util.Map;
+import java.util.Set;
+import java.util.concurrent.TimeUnit;
+import java.util.stream.Collectors;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+
+/**
+ * This tool demonstrates how to implement priority aging for encodings, which provides fairness
+ * when interactive and batch workloads share one organisation. Interactive encodings are usually
+ * started with a high priority, so they overtake queued batch encodings. To prevent batch encodings
+ * from waiting indefinitely while interactive encodings keep coming in, the priority of encodings
+ * that are queued for a long time is raised step by step.
+ *
+ * <p>For every full threshold an encoding has been waiting in the queue, its priority is raised by
+ * one step above the base priority, up to the configured maximum. Encodings are reprioritized only
+ * if this results in a higher priority than their current one, which is read from the request the
+ * encoding has been started with, so the priority of an encoding is never lowered. Encodings
+ * started with a high priority are not affected until their aged priority exceeds it.
+ *
+ * <p>By default, the aging is executed once. If an interval is configured, it is executed
+ * periodically until the process is terminated.
+ *
+ * <p>The following configuration parameters are expected:
+ *
+ * <ul>
+ *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin API
+ *   <li>BITMOVIN_TENANT_ORG_ID - (optional) The ID of the Organisation in which you want to perform
+ *       the encoding.
+ *   <li>BITMOVIN_API_BASE_URL - (optional) The base URL of the Bitmovin API, e.g. of a mock server
+ *       or an API gateway. Default: https://api.bitmovin.com/v1
+ *   <li>PRIORITY_AGING_THRESHOLD_MINUTES - (optional) The time an encoding has to wait in the queue
+ *       for each raise of its priority. Default: 30
+ *   <li>PRIORITY_AGING_BASE - (optional) The priority aging starts from, which is usually the
+ *       priority batch encodings are started with. Default: 50
+ *   <li>PRIORITY_AGING_STEP - (optional) The amount the priority is raised by per threshold.
+ *       Default: 10
+ *   <li>PRIORITY_AGING_MAX - (optional) The highest priority assigned by aging, which should be
+ *       below the priority of interactive encodings. Default: 80
+ *   <li>PRIORITY_AGING_INTERVAL_MINUTES - (optional) The interval in which the aging is repeated.
+ *       If not set, it is executed once
+ * </ul>
+ *
+ * <p>Configuration parameters will be retrieved from these sources in the listed order:
+ *
+ * <ol>
+ *   <li>command line arguments (eg BITMOVIN_API_KEY=xyz)
+ *   <li>properties file located in the root folder of the JAVA examples at ./examples.properties
+ *       (see examples.properties.template as reference)
+ *   <li>environment variables
+ *   <li>properties file located in the home folder at ~/.bitmovin/examples.properties (see
+ *       examples.properties.template as reference)
+ * </ol>
+ */
+public class EncodingPriorityAging {
+  private static final Logger logger = LoggerFactory.getLogger(EncodingPriorityAging.class);
+
+  private static BitmovinApi bitmovinApi;
+  private static ConfigProvider configProvider;
+
+  private static final int PAGE_SIZE = 100;
+
+  /**
+   * The current priorities per encoding ID, so the start request of an encoding is only requested
+   * once. After a restart of the tool, the priorities are read from the start requests again. As
+   * the aged priority only grows with the waiting time, the priorities assigned before are not
+   * lowered.
+   */
+  private static Map<String, Integer> currentPriorities = new HashMap<>();
+
+  public static void main(String[] args) throws Exception {
+    configProvider = new ConfigProvider(args);
+    bitmovinApi =
+        ApiClientFactory.builder(configProvider)
+            .withApiKey(configProvider.getBitmovinApiKey())
+            .build();
+
+    AgingPolicy policy =
+        new AgingPolicy(
+            Long.parseLong(
+                configProvider.getParameterByKey("PRIORITY_AGING_THRESHOLD_MINUTES", "30")),
+            Integer.parseInt(configProvider.getParameterByKey("PRIORITY_AGING_BASE", "50")),
+            Integer.parseInt(configProvider.getParameterByKey("PRIORITY_AGING_STEP", "10")),
+            Integer.parseInt(configProvider.getParameterByKey("PRIORITY_AGING_MAX", "80")));
+    String interval = configProvider.getParameterByKey("PRIORITY_AGING_INTERVAL_MINUTES", null);
+
+    if (interval == null) {
+      ageQueuedEncodings(policy);
+      return;
+    }
+
+    while (true) {
+      try {
+        ageQueuedEncodings(policy);
+      } catch (BitmovinException e) {
+        logger.error("Priority aging failed, retrying in the next interval", e);
+      }
+      Thread.sleep(TimeUnit.MINUTES.toMillis(Long.parseLong(interval)));
+    }
+  }
+
+  /**
+   * Raises the priority of all queued encodings according to the time they have been waiting.
+   *
+   * @param policy The policy defining how the priority is raised
+   */
+  private static void ageQueuedEncodings(AgingPolicy policy) throws BitmovinException {
+    Instant now = Instant.now();
+    List<Encoding> queuedEncodings = listEncodings(Status.QUEUED);
+    int reprioritized = 0;
+
+    for (Encoding encoding : queuedEncodings) {
+      Date queuedAt =
+          encoding.getQueuedAt() != null ? encoding.getQueuedAt() : encoding.getCreatedAt();
+      if (queuedAt == null) {
+        continue;
+      }
+
+      Duration waitingTime = Duration.between(queuedAt.toInstant(), now);
+      int priority = policy.getPriority(waitingTime);
+      int currentPriority = getCurrentPriority(encoding, policy);
+      if (priority <= currentPriority) {
+        continue;
+      }
+
+      logger.info(
+          "Encoding {} ({}) is queued for {} minutes, raising its priority to {}",
+          encoding.getId(),
+          encoding.getName(),
+          waitingTime.toMinutes(),
+          priority);
+      reprioritize(encoding, priority);
+      currentPriorities.put(encoding.getId(), priority);
+      reprioritized++;
+    }
+
+    currentPriorities.keySet().retainAll(getIds(queuedEncodings));
+    logger.info(
+        "Priority aging done: {} of {} queued encodings reprioritized",
+        reprioritized,
+        queuedEncodings.size());
+  }
+
+  /**
+   * Returns the current priority of a queued encoding. Unless it has been reprioritized by this
+   * tool, it is the priority the encoding has been started with.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/GetEncodingEncodingsStartByEncodingId
+   *
+   * @param encoding The queued encoding
+   * @param policy The policy providing the priority of encodings started without one
+   */
+  private static int getCurrentPriority(Encoding encoding, AgingPolicy policy)
+      throws BitmovinException {
+    Integer priority = currentPriorities.get(encoding.getId());
+    if (priority == null) {
+      StartEncodingRequest startRequest =
+          bitmovinApi.encoding.encodings.getStartRequest(encoding.getId());
+      priority = startRequest.getPriority() != null ? startRequest.getPriority() : policy.base;
+      currentPriorities.put(encoding.getId(), priority);
+    }
+    return priority;
+  }
+
+  private static Set<String> getIds(List<Encoding> encodings) {
+    return encodings.stream().map(Encoding::getId).collect(Collectors.toSet());
+  }
+
+  /**
+   * Changes the priority of a queued encoding.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PutEncodingEncodingsReprioritizeByEncodingId
+   *
+   * @param encoding The encoding to be reprioritized
+   * @param priority The new priority of the encoding
+   */
+  private static void reprioritize(Encoding encoding, int priority) throws BitmovinException {
+    ReprioritizeEncodingRequest request = new ReprioritizeEncodingRequest();
+    request.setPriority(priority);
+
+    bitmovinApi.encoding.encodings.reprioritize(encoding.getId(), request);
+  }
+
+  /**
+   * Lists all encodings with the given status, requesting as many pages as needed.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/GetEncodingEncodings
+   *
+   * @param status The status of the encodings to be listed
+   */
+  private static List<Encoding> listEncodings(Status status) throws BitmovinException {
+    List<Encoding> encodings = new ArrayList<>();
+    EncodingListQueryParams queryParams = new EncodingListQueryParams();
+    queryParams.setStatus(status.toString());
+    queryParams.setLimit(PAGE_SIZE);
+
+    List<Encoding> page;
+    do {
+      queryParams.setOffset(encodings.size());
+      page = bitmovinApi.encoding.encodings.list(queryParams).getItems();
+      encodings.addAll(page);
+    } while (page.size() == PAGE_SIZE);
+
+    return encodings;
+  }
+
+  /** Helper class defining how the priority of an encoding is raised while it is queued */
+  private static class AgingPolicy {
+
+    private long thresholdMinutes;
+    private int base;
+    private int step;
+    private int max;
+
+    /**
+     * @param thresholdMinutes The time an encoding has to wait for each raise of its priority
+     * @param base The priority the encodings have been started with
+     * @param step The amount the priority is raised by per threshold
+     * @param max The highest priority assigned by aging
+     */
+    private AgingPolicy(long thresholdMinutes, int base, int step, int max) {
+      this.thresholdMinutes = thresholdMinutes;
+      this.base = base;
+      this.step = step;
+      this.max = max;
+    }
+
+    private int getPriority(Duration waitingTime) {
+      long steps = waitingTime.toMinutes() / thresholdMinutes;
+      return (int) Math.min(max, base + steps * step);
+    }
+  }
+}
@@ -0,0 +1,821 @@
+import com.bitmovin.api.sdk.BitmovinApi;
+import com.bitmovin.api.sdk.common.BitmovinException;
+import com.bitmovin.api.sdk.model.AacAudioConfiguration;
+import com.bitmovin.api.sdk.model.AclEntry;
+import com.bitmovin.api.sdk.model.AclPermission;
+import com.bitmovin.api.sdk.model.CencDrm;
+import com.bitmovin.api.sdk.model.CencFairPlay;
+import com.bitmovin.api.sdk.model.CencWidevine;
+import com.bitmovin.api.sdk.model.CodecConfiguration;
+import com.bitmovin.api.sdk.model.DashManifest;
+import com.bitmovin.api.sdk.model.DashManifestDefault;
+import com.bitmovin.api.sdk.model.DashManifestDefaultVersion;
+import com.bitmovin.api.sdk.model.Encoding;
+import com.bitmovin.api.sdk.model.EncodingOutput;
+import com.bitmovin.api.sdk.model.Fmp4Muxing;
+import com.bitmovin.api.sdk.model.H264VideoConfiguration;
+import com.bitmovin.api.sdk.model.H265VideoConfiguration;
+import com.bitmovin.api.sdk.model.HlsManifest;
+import com.bitmovin.api.sdk.model.HlsManifestDefault;
+import com.bitmovin.api.sdk.model.HlsManifestDefaultVersion;
+import com.bitmovin.api.sdk.model.HttpInput;
+import com.bitmovin.api.sdk.model.Input;
+import com.bitmovin.api.sdk.model.MessageType;
+import com.bitmovin.api.sdk.model.MuxingStream;
+import com.bitmovin.api.sdk.model.Output;
+import com.bitmovin.api.sdk.model.PresetConfiguration;
+import com.bitmovin.api.sdk.model.S3Output;
+import com.bitmovin.api.sdk.model.StartEncodingRequest;
+import com.bitmovin.api.sdk.model.Status;
+import com.bitmovin.api.sdk.model.Stream;
+import com.bitmovin.api.sdk.model.StreamInput;
+import com.bitmovin.api.sdk.model.Task;
+import com.bitmovin.api.sdk.model.TsMuxing;
+import com.fasterxml.jackson.databind.ObjectMapper;
+import common.ApiClientFactory;
+import common.BudgetTag;
+import common.ConfigProvider;
+import common.EncodingFailedException;
+import common.PreviewTrimming;
+import common.ResourceGraph;
+import common.SegmentSharding;
+import java.io.File;
+import java.nio.file.Paths;
+import java.util.ArrayList;
+import java.util.Arrays;
+import java.util.List;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+
+/**
+ * This example runs an encoding workflow that is defined by an encoding profile in a JSON document
+ * instead of code. It allows people who do not develop in Java, e.g. operations or content teams,
+ * to define and adjust bitrate ladders, codecs, packaging, DRM and manifests by editing a file. An
+ * example profile is provided in profiles/fixed-ladder.json. All properties except name,
+ * video.renditions and audio are optional.
+ *
+ * <p>The following values are supported:
+ *
+ * <ul>
+ *   <li>video.codec - H264 or H265
+ *   <li>audio.codec - AAC
+ *   <li>muxing - FMP4 (fragmented MP4, for DASH and HLS) or TS (MPEG transport stream, for HLS
+ *       only)
+ *   <li>drm - If true, the segments are protected with CENC DRM for Widevine and FairPlay, using
+ *       the DRM_* configuration parameters. Only supported for FMP4.
+ *   <li>manifests - Whether default DASH and HLS manifests are created. DASH requires FMP4.
+ * </ul>
+ *
+ * <p>Unknown properties and unsupported combinations are rejected before any resource is created,
+ * so a typo in the profile does not result in an unexpected encoding.
+ *
+ * <p>When run with the --preview command line argument, the example only validates the profile and
+ * prints an approximation of the HLS master playlist and DASH MPD that the encoding would produce,
+ * with the folder names, bitrates, codecs and groups of all renditions. No resources are created
+ * and no API key is required, so the packaging layout can be reviewed before spending encoding
+ * minutes. Details like resolutions, exact codec strings and segment names are only known after
+ * the encoding and are therefore omitted.
+ *
+ * <p>If WORKFLOW_GRAPH_FILE is configured, the resources created by the run are written to it as a
+ * diagram, from the input over the streams, muxings and DRM configurations to the manifests and
+ * the output, together with their IDs. The diagram is written even if the encoding fails, so it
+ * documents what each run actually created. Files ending with .dot are written as Graphviz diagram,
+ * all others as Mermaid diagram, see {@link ResourceGraph}.
+ *
+ * <p>The following configuration parameters are expected:
+ *
+ * <ul>
+ *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin API
+ *   <li>BITMOVIN_TENANT_ORG_ID - (optional) The ID of the Organisation in which you want to perform
+ *       the encoding.
+ *   <li>BITMOVIN_API_BASE_URL - (optional) The base URL of the Bitmovin API, e.g. of a mock server
+ *       or an API gateway. Default: https://api.bitmovin.com/v1
+ *   <li>HTTP_INPUT_HOST - The Hostname or IP address of the HTTP server hosting your input files,
+ *       e.g.: my-storage.biz
+ *   <li>HTTP_INPUT_FILE_PATH - The path to your input file on the provided HTTP server Example:
+ *       videos/1080p_Sintel.mp4
+ *   <li>S3_OUTPUT_BUCKET_NAME - The name of your S3 output bucket. Example: my-bucket-name
+ *   <li>S3_OUTPUT_ACCESS_KEY - The access key of your S3 output bucket
+ *   <li>S3_OUTPUT_SECRET_KEY - The secret key of your S3 output bucket
+ *   <li>S3_OUTPUT_BASE_PATH - The base path on your S3 output bucket where content will be written.
+ *       Example: /outputs
+ *   <li>ENCODING_PROFILE_FILE - The path to the encoding profile JSON document. Example:
+ *       profiles/fixed-ladder.json
+ *   <li>DRM_KEY - (optional) 16 byte encryption key, represented as 32 hexadecimal characters,
+ *       required if DRM is enabled
+ *   <li>DRM_FAIRPLAY_IV - (optional) 16 byte initialization vector, represented as 32 hexadecimal
+ *       characters, required if DRM is enabled
+ *   <li>DRM_FAIRPLAY_URI - (optional) URI of the licensing server, required if DRM is enabled
+ *   <li>DRM_WIDEVINE_KID - (optional) 16 byte encryption key id, represented as 32 hexadecimal
+ *       characters, required if DRM is enabled
+ *   <li>DRM_WIDEVINE_PSSH - (optional) Base64 encoded PSSH payload, required if DRM is enabled
+ *   <li>WORKFLOW_GRAPH_FILE - (optional) The file the diagram of the created resources is written
+ *       to. Example: workflow.mmd
+ * </ul>
+ *
+ * <p>Configuration parameters will be retrieved from these sources in the listed order:
+ *
+ * <ol>
+ *   <li>command line arguments (eg BITMOVIN_API_KEY=xyz)
+ *   <li>properties file located in the root folder of the JAVA examples at ./examples.properties
+ *       (see examples.properties.template as reference)
+ *   <li>environment variables
+ *   <li>properties file located in the home folder at ~/.bitmovin/examples.properties (see
+ *       examples.properties.template as reference)
+ * </ol>
+ */
+public class EncodingProfileRunner {
+  private static final Logger logger = LoggerFactory.getLogger(EncodingProfileRunner.class);
+
+  /** The codec string of AAC-LC, as signaled in the manifests */
+  private static final String AUDIO_CODEC_STRING = "mp4a.40.2";
+
+  private static BitmovinApi bitmovinApi;
+  private static ConfigProvider configProvider;
+  private static final ResourceGraph resourceGraph = new ResourceGraph();
+
+  public static void main(String[] args) throws Exception {
+    configProvider = new ConfigProvider(args);
+
+    EncodingProfile profile =
+        new ObjectMapper()
+            .readValue(
+                new File(configProvider.getParameterByKey("ENCODING_PROFILE_FILE")),
+                EncodingProfile.class);
+    profile.validate();
+
+    if (Arrays.asList(args).contains("--preview")) {
+      printManifestPreview(profile);
+      return;
+    }
+
+    bitmovinApi =
+        ApiClientFactory.builder(configProvider)
+            .withApiKey(configProvider.getBitmovinApiKey())
+            .build();
+
+    String workflowGraphFile = configProvider.getParameterByKey("WORKFLOW_GRAPH_FILE", null);
+    try {
+      runProfile(profile);
+    } finally {
+      if (workflowGraphFile != null) {
+        resourceGraph.write(Paths.get(workflowGraphFile));
+        logger.info("Diagram of the created resources written to {}", workflowGraphFile);
+      }
+    }
+  }
+
+  /**
+   * Creates the resources defined by the profile, runs the encoding and creates the manifests. All
+   * created resources are recorded in the resource graph.
+   *
+   * @param profile The validated encoding profile
+   */
+  private static void runProfile(EncodingProfile profile) throws Exception {
+    Encoding encoding =
+        createEncoding(profile.name, "Encoding defined by the encoding profile " + profile.name);
+    resourceGraph.setTitle(String.format("Encoding %s (%s)", encoding.getName(), encoding.getId()));
+
+    HttpInput input = createHttpInput(configProvider.getHttpInputHost());
+    Output output =
+        createS3Output(
+            configProvider.getS3OutputBucketName(),
+            configProvider.getS3OutputAccessKey(),
+            configProvider.getS3OutputSecretKey());
+    resourceGraph.addResource(input.getId(), "HTTP input", input.getHost());
+    resourceGraph.addResource(output.getId(), "S3 output", configProvider.getS3OutputBucketName());
+
+    String inputFilePath = configProvider.getHttpInputFilePath();
+    List<String> segmentResourceIds = new ArrayList<>();
+
+    for (VideoRendition rendition : profile.video.renditions) {
+      CodecConfiguration videoConfiguration =
+          createVideoConfig(profile.video.codec, rendition.height, rendition.bitrate);
+      Stream videoStream = createStream(encoding, input, inputFilePath, videoConfiguration);
+      addStreamToGraph(input, videoStream, videoConfiguration);
+      segmentResourceIds.add(
+          createMuxing(encoding, profile, output, "video/" + rendition.height, videoStream));
+    }
+
+    AacAudioConfiguration audioConfiguration = createAacAudioConfig(profile.audio.bitrate);
+    Stream audioStream = createStream(encoding, input, inputFilePath, audioConfiguration);
+    addStreamToGraph(input, audioStream, audioConfiguration);
+    segmentResourceIds.add(createMuxing(encoding, profile, output, "audio", audioStream));
+
+    executeEncoding(encoding);
+
+    if (profile.manifests.dash) {
+      DashManifestDefault dashManifest = generateDashManifest(encoding, output, "/");
+      addManifestToGraph(dashManifest.getId(), "DASH manifest", segmentResourceIds, output);
+    }
+    if (profile.manifests.hls) {
+      HlsManifestDefault hlsManifest = generateHlsManifest(encoding, output, "/");
+      addManifestToGraph(hlsManifest.getId(), "HLS manifest", segmentResourceIds, output);
+    }
+  }
+
+  /**
+   * Adds a stream to the resource graph, connected to the input it reads from
+   *
+   * @param input The input the stream reads from
+   * @param stream The created stream
+   * @param codecConfiguration The codec configuration of the stream
+   */
+  private static void addStreamToGraph(
+      Input input, Stream stream, CodecConfiguration codecConfiguration) {
+    resourceGraph.addResource(stream.getId(), "Stream", codecConfiguration.getName());
+    resourceGraph.connect(input.getId(), stream.getId());
+  }
+
+  /**
+   * Adds a manifest to the resource graph, connected to the resources writing the segments it
+   * references and to the output it is written to
+   *
+   * @param manifestId The ID of the created manifest
+   * @param type The type of the manifest
+   * @param segmentResourceIds The IDs of the muxings or DRM configurations writing the segments
+   * @param output The output the manifest is written to
+   */
+  private static void addManifestToGraph(
+      String manifestId, String type, List<String> segmentResourceIds, Output output) {
+    resourceGraph.addResource(manifestId, type, null);
+    for (String segmentResourceId : segmentResourceIds) {
+      resourceGraph.connect(segmentResourceId, manifestId);
+    }
+    resourceGraph.connect(manifestId, output.getId());
+  }
+
+  /**
+   * Creates a configuration for the video codec of the profile. The output resolution is defined by
+   * setting only the height. Width will be determined automatically to maintain the aspect ratio of
+   * your input video.
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsVideoH264
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsVideoH265
+   *
+   * @param codec The video codec of the profile
+   * @param height The height of the output video
+   * @param bitrate The target bitrate of the output video
+   */
+  private static CodecConfiguration createVideoConfig(VideoCodec codec, int height, long bitrate)
+      throws BitmovinException {
+    if (codec == VideoCodec.H265) {
+      H265VideoConfiguration config = new H265VideoConfiguration();
+      config.setName(String.format("H.265 %dp", height));
+      config.setPresetConfiguration(PresetConfiguration.VOD_STANDARD);
+      config.setHeight(height);
+      config.setBitrate(bitrate);
+
+      return bitmovinApi.encoding.configurations.video.h265.create(config);
+    }
+
+    H264VideoConfiguration config = new H264VideoConfiguration();
+    config.setName(String.format("H.264 %dp", height));
+    config.setPresetConfiguration(PresetConfiguration.VOD_STANDARD);
+    config.setHeight(height);
+    config.setBitrate(bitrate);
+
+    return bitmovinApi.encoding.configurations.video.h264.create(config);
+  }
+
+  /**
+   * Creates a configuration for the AAC audio codec to be applied to audio streams.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsAudioAac
+   *
+   * @param bitrate The target bitrate of the output audio
+   */
+  private static AacAudioConfiguration createAacAudioConfig(long bitrate)
+      throws BitmovinException {
+    AacAudioConfiguration config = new AacAudioConfiguration();
+    config.setName(String.format("AAC %d kbit/s", bitrate / 1000));
+    config.setBitrate(bitrate);
+
+    return bitmovinApi.encoding.configurations.audio.aac.create(config);
+  }
+
+  /**
+   * Creates a muxing of the type defined by the profile. If DRM is enabled, the muxing has no
+   * output, and the encrypted segments are written by a CENC DRM configuration added to it instead.
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsMuxingsFmp4ByEncodingId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsMuxingsTsByEncodingId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsMuxingsFmp4DrmCencByEncodingIdAndMuxingId
+   *
+   * @param encoding The encoding where to add the muxing to
+   * @param profile The encoding profile defining the muxing
+   * @param output The output that should be used to write the segments to
+   * @param outputPath The output path where the segments will be written to
+   * @param stream The stream that is associated with the muxing
+   * @return The ID of the resource writing the segments, which is the DRM configuration if DRM is
+   *     enabled, and the muxing otherwise
+   */
+  private static String createMuxing(
+      Encoding encoding, EncodingProfile profile, Output output, String outputPath, Stream stream)
+      throws BitmovinException {
+    MuxingStream muxingStream = new MuxingStream();
+    muxingStream.setStreamId(stream.getId());
+
+    if (profile.muxing == MuxingType.TS) {
+      TsMuxing muxing = new TsMuxing();
+      muxing.addOutputsItem(buildEncodingOutput(output, outputPath));
+      muxing.addStreamsItem(muxingStream);
+      muxing.setSegmentLength(profile.segmentLength);
+      SegmentSharding.apply(configProvider, muxing);
+
+      muxing = bitmovinApi.encoding.encodings.muxings.ts.create(encoding.getId(), muxing);
+      addMuxingToGraph(muxing.getId(), "TS muxing", outputPath, stream);
+      resourceGraph.connect(muxing.getId(), output.getId());
+      return muxing.getId();
+    }
+
+    Fmp4Muxing muxing = new Fmp4Muxing();
+    muxing.addStreamsItem(muxingStream);
+    muxing.setSegmentLength(profile.segmentLength);
+    SegmentSharding.apply(configProvider, muxing);
+    if (!profile.drm) {
+      muxing.addOutputsItem(buildEncodingOutput(output, outputPath));
+    }
+    muxing = bitmovinApi.encoding.encodings.muxings.fmp4.create(encoding.getId(), muxing);
+    addMuxingToGraph(muxing.getId(), "FMP4 muxing", outputPath, stream);
+
+    if (!profile.drm) {
+      resourceGraph.connect(muxing.getId(), output.getId());
+      return muxing.getId();
+    }
+
+    CencDrm cencDrm = new CencDrm();
+    cencDrm.addOutputsItem(buildEncodingOutput(output, outputPath));
+    cencDrm.setKey(configProvider.getDrmKey());
+    cencDrm.setKid(configProvider.getDrmWidevineKid());
+
+    CencWidevine widevineDrm = new CencWidevine();
+    widevineDrm.setPssh(configProvider.getDrmWidevinePssh());
+    cencDrm.setWidevine(widevineDrm);
+
+    CencFairPlay cencFairPlay = new CencFairPlay();
+    cencFairPlay.setIv(configProvider.getDrmFairplayIv());
+    cencFairPlay.setUri(configProvider.getDrmFairplayUri());
+    cencDrm.setFairPlay(cencFairPlay);
+
+    cencDrm =
+        bitmovinApi.encoding.encodings.muxings.fmp4.drm.cenc.create(
+            encoding.getId(), muxing.getId(), cencDrm);
+    resourceGraph.addResource(cencDrm.getId(), "CENC DRM", "Widevine, FairPlay");
+    resourceGraph.connect(muxing.getId(), cencDrm.getId());
+    resourceGraph.connect(cencDrm.getId(), output.getId());
+    return cencDrm.getId();
+  }
+
+  /**
+   * Adds a muxing to the resource graph, connected to the stream it packages
+   *
+   * @param muxingId The ID of the created muxing
+   * @param type The type of the muxing
+   * @param outputPath The output path the muxing is written to
+   * @param stream The stream that is associated with the muxing
+   */
+  private static void addMuxingToGraph(
+      String muxingId, String type, String outputPath, Stream stream) {
+    resourceGraph.addResource(muxingId, type, outputPath);
+    resourceGraph.connect(stream.getId(), muxingId);
+  }
+
+  /**
+   * Prints an approximation of the manifests the encoding would produce for the profile, based on
+   * the planned renditions and the output folders their muxings are written to
+   *
+   * @param profile The validated encoding profile
+   */
+  private static void printManifestPreview(EncodingProfile profile) {
+    logger.info("Manifest preview of the encoding profile '{}'", profile.name);
+    if (profile.manifests.hls) {
+      System.out.println("--- master.m3u8 ---");
+      System.out.println(renderHlsPreview(profile));
+    }
+    if (profile.manifests.dash) {
+      System.out.println("--- stream.mpd ---");
+      System.out.println(renderDashPreview(profile));
+    }
+    if (!profile.manifests.hls && !profile.manifests.dash) {
+      logger.info("The profile does not create any manifests");
+    }
+  }
+
+  /**
+   * Renders the structure of the HLS master playlist: one audio rendition group, and one variant
+   * stream per video rendition referencing it. The bandwidth of a variant is the sum of the video
+   * and audio bitrates.
+   */
+  private static String renderHlsPreview(EncodingProfile profile) {
+    StringBuilder playlist = new StringBuilder();
+    playlist.append("#EXTM3U\n");
+    playlist.append(
+        String.format(
+            "#EXT-X-MEDIA:TYPE=AUDIO,GROUP-ID=\"audio\",NAME=\"%s\",DEFAULT=YES,URI=\"%s\"\n",
+            audioName(profile), "audio/playlist.m3u8"));
+
+    for (VideoRendition rendition : profile.video.renditions) {
+      playlist.append(
+          String.format(
+              "#EXT-X-STREAM-INF:BANDWIDTH=%d,CODECS=\"%s,%s\",AUDIO=\"audio\"\n",
+              rendition.bitrate + profile.audio.bitrate,
+              videoCodecString(profile.video.codec),
+              AUDIO_CODEC_STRING));
+      playlist.append(String.format("video/%d/playlist.m3u8\n", rendition.height));
+    }
+    return playlist.toString();
+  }
+
+  /**
+   * Renders the structure of the DASH MPD: one adaptation set for all video renditions and one for
+   * the audio rendition, with the content protection signaling if DRM is enabled
+   */
+  private static String renderDashPreview(EncodingProfile profile) {
+    String contentProtection =
+        profile.drm
+            ? "      <ContentProtection schemeIdUri=\"urn:mpeg:dash:mp4protection:2011\""
+                + " value=\"cenc\"/>\n"
+            : "";
+
+    StringBuilder mpd = new StringBuilder();
+    mpd.append("<MPD type=\"static\" profiles=\"urn:mpeg:dash:profile:isoff-live:2011\">\n");
+    mpd.append("  <Period>\n");
+    mpd.append("    <AdaptationSet mimeType=\"video/mp4\" segmentAlignment=\"true\">\n");
+    mpd.append(contentProtection);
+    for (VideoRendition rendition : profile.video.renditions) {
+      mpd.append(
+          String.format(
+              "      <Representation bandwidth=\"%d\" height=\"%d\" codecs=\"%s\">\n",
+              rendition.bitrate, rendition.height, videoCodecString(profile.video.codec)));
+      mpd.append(String.format("        <BaseURL>video/%d/</BaseURL>\n", rendition.height));
+      mpd.append("      </Representation>\n");
+    }
+    mpd.append("    </AdaptationSet>\n");
+    mpd.append("    <AdaptationSet mimeType=\"audio/mp4\">\n");
+    mpd.append(contentProtection);
+    mpd.append(
+        String.format(
+            "      <Representation bandwidth=\"%d\" codecs=\"%s\">\n",
+            profile.audio.bitrate, AUDIO_CODEC_STRING));
+    mpd.append("        <BaseURL>audio/</BaseURL>\n");
+    mpd.append("      </Representation>\n");
+    mpd.append("    </AdaptationSet>\n");
+    mpd.append("  </Period>\n");
+    mpd.append("</MPD>");
+    return mpd.toString();
+  }
+
+  private static String videoCodecString(VideoCodec codec) {
+    return codec == VideoCodec.H265 ? "hvc1" : "avc1";
+  }
+
+  private static String audioName(EncodingProfile profile) {
+    return String.format("AAC %d kbit/s", profile.audio.bitrate / 1000);
+  }
+
+  private enum VideoCodec {
+    H264,
+    H265
+  }
+
+  private enum AudioCodec {
+    AAC
+  }
+
+  private enum MuxingType {
+    FMP4,
+    TS
+  }
+
+  /** The encoding profile, as defined by the JSON document */
+  private static class EncodingProfile {
+
+    public String name;
+    public double segmentLength = 4.0;
+    public VideoProfile video;
+    public AudioProfile audio;
+    public MuxingType muxing = MuxingType.FMP4;
+    public boolean drm;
+    public ManifestProfile manifests = new ManifestProfile();
+
+    /** Rejects profiles with missing values or unsupported combinations */
+    private void validate() {
+      if (name == null || video == null || audio == null) {
+        throw new IllegalArgumentException("The profile has to define name, video and audio");
+      }
+      if (video.renditions == null || video.renditions.isEmpty()) {
+        throw new IllegalArgumentException("The profile has to define at least one rendition");
+      }
+      if (drm && muxing != MuxingType.FMP4) {
+        throw new IllegalArgumentException("DRM is only supported for the muxing FMP4");
+      }
+      if (manifests.dash && muxing != MuxingType.FMP4) {
+        throw new IllegalArgumentException("DASH manifests require the muxing FMP4");
+      }
+    }
+  }
+
+  private static class VideoProfile {
+
+    public VideoCodec codec = VideoCodec.H264;
+    public List<VideoRendition> renditions;
+  }
+
+  private static class VideoRendition {
+
+    public int height;
+    public long bitrate;
+  }
+
+  private static class AudioProfile {
+
+    public AudioCodec codec = AudioCodec.AAC;
+    public long bitrate = 128_000L;
+  }
+
+  private static class ManifestProfile {
+
+    public boolean dash = true;
+    public boolean hls = true;
+  }
+
+  /**
+   * Creates a resource representing an HTTP server providing the input files. For alternative input
+   * methods see <a
+   * href="https://bitmovin.com/docs/encoding/articles/supported-input-output-storages">list of
+   * supported input and output storages</a>
+   *
+   * <p>For reasons of simplicity, a new input resource is created on each execution of this
+   * example. In production use, this method should be replaced by a <a
+   * href="https://bitmovin.com/docs/encoding/api-reference/sections/inputs#/Encoding/GetEncodingInputsHttpByInputId">get
+   * call</a> to retrieve an existing resource.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/inputs#/Encoding/PostEncodingInputsHttp
+   *
+   * @param host The hostname or IP address of the HTTP server e.g.: my-storage.biz
+   */
+  private static HttpInput createHttpInput(String host) throws BitmovinException {
+    HttpInput input = new HttpInput();
+    input.setHost(host);
+
+    return bitmovinApi.encoding.inputs.http.create(input);
+  }
+
+  /**
+   * Creates a resource representing an AWS S3 cloud storage bucket to which generated content will
+   * be transferred. For alternative output methods see <a
+   * href="https://bitmovin.com/docs/encoding/articles/supported-input-output-storages">list of
+   * supported input and output storages</a>
+   *
+   * <p>The provided credentials need to allow <i>read</i>, <i>write</i> and <i>list</i> operations.
+   * <i>delete</i> should also be granted to allow overwriting of existings files. See <a
+   * href="https://bitmovin.com/docs/encoding/faqs/how-do-i-create-a-aws-s3-bucket-which-can-be-used-as-output-location">creating
+   * an S3 bucket and setting permissions</a> for further information
+   *
+   * <p>For reasons of simplicity, a new output resource is created on each execution of this
+   * example. In production use, this method should be replaced by a <a
+   * href="https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/GetEncodingOutputsS3">get
+   * call</a> retrieving an existing resource.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/PostEncodingOutputsS3
+   *
+   * @param bucketName The name of the S3 bucket
+   * @param accessKey The access key of your S3 account
+   * @param secretKey The secret key of your S3 account
+   */
+  private static S3Output createS3Output(String bucketName, String accessKey, String secretKey)
+      throws BitmovinException {
+
+    S3Output s3Output = new S3Output();
+    s3Output.setBucketName(bucketName);
+    s3Output.setAccessKey(accessKey);
+    s3Output.setSecretKey(secretKey);
+
+    return bitmovinApi.encoding.outputs.s3.create(s3Output);
+  }
+
+  /**
+   * Creates an encoding object. This is the base object to configure your encoding.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodings
+   *
+   * @param name This is the name of the encoding
+   * @param description This is the description of the encoding
+   */
+  private static Encoding createEncoding(String name, String description) throws BitmovinException {
+    Encoding encoding = new Encoding();
+    encoding.setName(name);
+    encoding.setDescription(description);
+    new BudgetTag(configProvider).applyTo(encoding);
+
+    return bitmovinApi.encoding.encodings.create(encoding);
+  }
+
+  /**
+   * Creates a stream which binds an input file to a codec configuration. The stream is used for
+   * muxings later on.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsStreamsByEncodingId
+   *
+   * @param encoding The encoding to add the stream onto
+   * @param input The input that should be used
+   * @param inputPath The path to the input file
+   * @param codecConfiguration The codec configuration to be applied to the stream
+   */
+  private static Stream createStream(
+      Encoding encoding, Input input, String inputPath, CodecConfiguration codecConfiguration)
+      throws BitmovinException {
+    StreamInput streamInput =
+        PreviewTrimming.createStreamInput(bitmovinApi, configProvider, encoding, input, inputPath);
+
+    Stream stream = new Stream();
+    stream.addInputStreamsItem(streamInput);
+    stream.setCodecConfigId(codecConfiguration.getId());
+
+    return bitmovinApi.encoding.encodings.streams.create(encoding.getId(), stream);
+  }
+
+  /**
+   * Builds an EncodingOutput object which defines where the output content (e.g. of a muxing) will
+   * be written to. Public read permissions will be set for the files written, so they can be
+   * accessed easily via HTTP.
+   *
+   * @param output The output resource to be used by the EncodingOutput
+   * @param outputPath The path where the content will be written to
+   */
+  private static EncodingOutput buildEncodingOutput(Output output, String outputPath) {
+    AclEntry aclEntry = new AclEntry();
+    aclEntry.setPermission(AclPermission.PUBLIC_READ);
+
+    EncodingOutput encodingOutput = new EncodingOutput();
+    encodingOutput.setOutputPath(buildAbsolutePath(outputPath));
+    encodingOutput.setOutputId(output.getId());
+    encodingOutput.addAclItem(aclEntry);
+    return encodingOutput;
+  }
+
+  /**
+   * Builds an absolute path by concatenating the S3_OUTPUT_BASE_PATH configuration parameter, the
+   * name of this example class and the given relative path
+   *
+   * <p>e.g.: /s3/base/path/ClassName/relative/path
+   *
+   * @param relativePath The relative path that is concatenated
+   * @return The absolute path
+   */
+  public static String buildAbsolutePath(String relativePath) {
+    String className = EncodingProfileRunner.class.getSimpleName();
+    return Paths.get(configProvider.getS3OutputBasePath(), className, relativePath).toString();
+  }
+
+  /**
+   * Starts the actual encoding process and periodically polls its status until it reaches a final
+   * state
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/all#/Encoding/PostEncodingEncodingsStartByEncodingId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/GetEncodingEncodingsStatusByEncodingId
+   *
+   * <p>Please note that you can also use our webhooks API instead of polling the status. For more
+   * information consult the API spec:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/notifications-webhooks
+   *
+   * @param encoding The encoding to be started
+   */
+  private static void executeEncoding(Encoding encoding)
+      throws InterruptedException, BitmovinException {
+    bitmovinApi.encoding.encodings.start(encoding.getId(), new StartEncodingRequest());
+
+    Task task;
+    do {
+      Thread.sleep(5000);
+      task = bitmovinApi.encoding.encodings.status(encoding.getId());
+      logger.info("encoding status is {} (progress: {} %)", task.getStatus(), task.getProgress());
+    } while (task.getStatus() != Status.FINISHED
+        && task.getStatus() != Status.ERROR
+        && task.getStatus() != Status.CANCELED);
+
+    if (task.getStatus() != Status.FINISHED) {
+      logTaskErrors(task);
+      throw new EncodingFailedException(task.getStatus());
+    }
+    logger.info("encoding finished successfully");
+  }
+
+  /**
+   * Creates an HLS default manifest that automatically includes all representations configured in
+   * the encoding.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsHlsDefault
+   *
+   * @param encoding The encoding for which the manifest should be generated
+   * @param output The output to which the manifest should be written
+   * @param outputPath The path to which the manifest should be written
+   */
+  private static HlsManifestDefault generateHlsManifest(
+      Encoding encoding, Output output, String outputPath) throws Exception {
+    HlsManifestDefault hlsManifestDefault = new HlsManifestDefault();
+    hlsManifestDefault.setEncodingId(encoding.getId());
+    hlsManifestDefault.addOutputsItem(buildEncodingOutput(output, outputPath));
+    hlsManifestDefault.setName("master.m3u8");
+    hlsManifestDefault.setVersion(HlsManifestDefaultVersion.V1);
+
+    hlsManifestDefault = bitmovinApi.encoding.manifests.hls.defaultapi.create(hlsManifestDefault);
+    executeHlsManifestCreation(hlsManifestDefault);
+    return hlsManifestDefault;
+  }
+
+  /**
+   * Creates a DASH default manifest that automatically includes all representations configured in
+   * the encoding.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsDash
+   *
+   * @param encoding The encoding for which the manifest should be generated
+   * @param output The output to which the manifest should be written
+   * @param outputPath The path to which the manifest should be written
+   */
+  private static DashManifestDefault generateDashManifest(
+      Encoding encoding, Output output, String outputPath) throws Exception {
+    DashManifestDefault dashManifestDefault = new DashManifestDefault();
+    dashManifestDefault.setEncodingId(encoding.getId());
+    dashManifestDefault.setManifestName("stream.mpd");
+    dashManifestDefault.setVersion(DashManifestDefaultVersion.V1);
+    dashManifestDefault.addOutputsItem(buildEncodingOutput(output, outputPath));
+    dashManifestDefault =
+        bitmovinApi.encoding.manifests.dash.defaultapi.create(dashManifestDefault);
+    executeDashManifestCreation(dashManifestDefault);
+    return dashManifestDefault;
+  }
+
+  /**
+   * Starts the DASH manifest creation and periodically polls its status until it reaches a final
+   * state
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsDashStartByManifestId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/GetEncodingManifestsDashStatusByManifestId
+   *
+   * @param dashManifest The DASH manifest to be created
+   */
+  private static void executeDashManifestCreation(DashManifest dashManifest)
+      throws BitmovinException, InterruptedException {
+    bitmovinApi.encoding.manifests.dash.start(dashManifest.getId());
+
+    Task task;
+    do {
+      Thread.sleep(1000);
+      task = bitmovinApi.encoding.manifests.dash.status(dashManifest.getId());
+    } while (task.getStatus() != Status.FINISHED && task.getStatus() != Status.ERROR);
+
+    if (task.getStatus() == Status.ERROR) {
+      logTaskErrors(task);
+      throw new RuntimeException("DASH manifest creation failed");
+    }
+    logger.info("DASH manifest creation finished successfully");
+  }
+
+  /**
+   * Starts the HLS manifest creation and periodically polls its status until it reaches a final
+   * state
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsHlsStartByManifestId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/GetEncodingManifestsHlsStatusByManifestId
+   *
+   * @param hlsManifest The HLS manifest to be created
+   */
+  private static void executeHlsManifestCreation(HlsManifest hlsManifest)
+      throws BitmovinException, InterruptedException {
+
+    bitmovinApi.encoding.manifests.hls.start(hlsManifest.getId());
+
+    Task task;
+    do {
+      Thread.sleep(1000);
+      task = bitmovinApi.encoding.manifests.hls.status(hlsManifest.getId());
+    } while (task.getStatus() != Status.FINISHED && task.getStatus() != Status.ERROR);
+
+    if (task.getStatus() == Status.ERROR) {
+      logTaskErrors(task);
+      throw new RuntimeException("HLS manifest creation failed");
+    }
+    logger.info("HLS manifest creation finished successfully");
+  }
+
+  private static void logTaskErrors(Task task) {
+    task.getMessages().stream()
+        .filter(msg -> msg.getType() == MessageType.ERROR)
+        .forEach(msg -> logger.error(msg.getText()));
+  }
+}
@@ -0,0 +1,279 @@
+import com.bitmovin.api.sdk.BitmovinApi;
+import com.bitmovin.api.sdk.common.BitmovinException;
+import com.bitmovin.api.sdk.encoding.encodings.EncodingListQueryParams;
+import com.bitmovin.api.sdk.model.Encoding;
+import com.bitmovin.api.sdk.model.MessageType;
+import com.bitmovin.api.sdk.model.Status;
+import com.bitmovin.api.sdk.model.Task;
+import common.ApiClientFactory;
+import common.ConfigProvider;
+import java.io.IOException;
+import java.io.Reader;
+import java.io.UncheckedIOException;
+import java.io.Writer;
+import java.nio.charset.StandardCharsets;
+import java.nio.file.Files;
+import java.nio.file.Path;
+import java.nio.file.Paths;
+import java.time.Duration;
+import java.time.Instant;
+import java.util.ArrayList;
+import java.util.Arrays;
+import java.util.Date;
+import java.util.List;
+import java.util.Properties;
+import java.util.concurrent.TimeUnit;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+
+/**
+ * This tool demonstrates how to reconcile the status of encodings, covering the case where a
+ * completion webhook was missed by the receiver, e.g. because it was unavailable at that time.
+ *
+ * <p>All encodings that have finished, failed or been canceled since the last run are handed over
+ * to the same handling a completion webhook would trigger. In this example the result is only
+ * logged, replace {@link #onEncodingResolved} with your own logic. As the completion webhooks of
+ * most of these encodings have been received already, the handling has to be idempotent, e.g. by
+ * skipping encodings which are already marked as done in your content management system. The time
+ * of the last run is stored in a state file, so no encoding is missed between two runs.
+ *
+ * <p>In addition, all encodings that are queued or running for longer than a configurable threshold
+ * are listed, and their status is re-checked individually. Encodings which are still not finished
+ * can optionally be stopped, so they end up in a final state and are resolved by the next run.
+ *
+ * <p>By default, the reconciliation is executed once. If an interval is configured, it is executed
+ * periodically until the process is terminated.
+ *
+ * <p>The following configuration parameters are expected:
+ *
+ * <ul>
+ *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin API
+ *   <li>BITMOVIN_TENANT_ORG_ID - (optional) The ID of the Organisation in which you want to perform
+ *       the encoding.
+ *   <li>BITMOVIN_API_BASE_URL - (optional) The base URL of the Bitmovin API, e.g. of a mock server
+ *       or an API gateway. Default: https://api.bitmovin.com/v1
+ *   <li>RECONCILE_MIN_AGE_MINUTES - (optional) Only encodings created longer ago than this are
+ *       checked. Default: 60
+ *   <li>RECONCILE_INTERVAL_MINUTES - (optional) The interval in which the reconciliation is
+ *       repeated. If not set, it is executed once
+ *   <li>RECONCILE_STOP_STALE - (optional) If set to true, encodings which are still queued or
+ *       running are stopped. Default: false
+ *   <li>RECONCILE_STATE_FILE - (optional) The file the time of the last run is stored in. Default:
+ *       reconciliation.properties
+ *   <li>RECONCILE_LOOKBACK_HOURS - (optional) How far back encodings are resolved if there is no
+ *       previous run in the state file. Default: 24
+ * </ul>
+ *
+ * <p>Configuration parameters will be retrieved from these sources in the listed order:
+ *
+ * <ol>
+ *   <li>command line arguments (eg BITMOVIN_API_KEY=xyz)
+ *   <li>properties file located in the root folder of the JAVA examples at ./examples.properties
+ *       (see examples.properties.template as reference)
+ *   <li>environment variables
+ *   <li>properties file located in the home folder at ~/.bitmovin/examples.properties (see
+ *       examples.properties.template as reference)
+ * </ol>
+ */
+public class EncodingReconciliation {
+  private static final Logger logger = LoggerFactory.getLogger(EncodingReconciliation.class);
+
+  private static BitmovinApi bitmovinApi;
+  private static ConfigProvider configProvider;
+
+  private static final List<Status> NON_FINAL_STATES = Arrays.asList(Status.QUEUED, Status.RUNNING);
+  private static final List<Status> FINAL_STATES =
+      Arrays.asList(Status.FINISHED, Status.ERROR, Status.CANCELED);
+  private static final String LAST_RUN_KEY = "lastRunAt";
+  private static final int PAGE_SIZE = 100;
+
+  public static void main(String[] args) throws Exception {
+    configProvider = new ConfigProvider(args);
+    bitmovinApi =
+        ApiClientFactory.builder(configProvider)
+            .withApiKey(configProvider.getBitmovinApiKey())
+            .build();
+
+    long minAgeMinutes =
+        Long.parseLong(configProvider.getParameterByKey("RECONCILE_MIN_AGE_MINUTES", "60"));
+    String interval = configProvider.getParameterByKey("RECONCILE_INTERVAL_MINUTES", null);
+    boolean stopStale =
+        Boolean.parseBoolean(configProvider.getParameterByKey("RECONCILE_STOP_STALE", "false"));
+    Path stateFile =
+        Paths.get(
+            configProvider.getParameterByKey("RECONCILE_STATE_FILE", "reconciliation.properties"));
+    long lookbackHours =
+        Long.parseLong(configProvider.getParameterByKey("RECONCILE_LOOKBACK_HOURS", "24"));
+
+    if (interval == null) {
+      reconcile(stateFile, lookbackHours, minAgeMinutes, stopStale);
+      return;
+    }
+
+    while (true) {
+      try {
+        reconcile(stateFile, lookbackHours, minAgeMinutes, stopStale);
+      } catch (BitmovinException e) {
+        logger.error("Reconciliation failed, retrying in the next interval", e);
+      }
+      Thread.sleep(TimeUnit.MINUTES.toMillis(Long.parseLong(interval)));
+    }
+  }
+
+  /**
+   * Resolves all encodings which have reached a final state since the last run, and checks all
+   * encodings which are in a non-final state for longer than the given age. The time of the run is
+   * only stored once all encodings have been handled, so a failed run is repeated completely.
+   *
+   * @param stateFile The file the time of the last run is stored in
+   * @param lookbackHours How far back encodings are resolved if there is no previous run
+   * @param minAgeMinutes Only encodings created longer ago than this are checked
+   * @param stopStale Whether encodings which are still not finished should be stopped
+   */
+  private static void reconcile(
+      Path stateFile, long lookbackHours, long minAgeMinutes, boolean stopStale)
+      throws BitmovinException {
+    Instant runStartedAt = Instant.now();
+    Instant lastRunAt = loadLastRun(stateFile);
+    if (lastRunAt == null) {
+      lastRunAt = runStartedAt.minus(Duration.ofHours(lookbackHours));
+    }
+    Date threshold = Date.from(runStartedAt.minus(Duration.ofMinutes(minAgeMinutes)));
+    int resolved = 0;
+    int stale = 0;
+
+    for (Status status : FINAL_STATES) {
+      for (Encoding encoding : listEncodings(status, Date.from(lastRunAt))) {
+        onEncodingResolved(encoding, bitmovinApi.encoding.encodings.status(encoding.getId()));
+        resolved++;
+      }
+    }
+
+    for (Status status : NON_FINAL_STATES) {
+      for (Encoding encoding : listEncodings(status)) {
+        if (encoding.getCreatedAt() == null || encoding.getCreatedAt().after(threshold)) {
+          continue;
+        }
+
+        Task task = bitmovinApi.encoding.encodings.status(encoding.getId());
+        if (FINAL_STATES.contains(task.getStatus())) {
+          // the encoding has reached a final state after it was listed and is resolved by the next
+          // run, as its finish time is newer than the time of this run
+          continue;
+        }
+
+        stale++;
+        logger.warn(
+            "Encoding {} ({}) is {} since {}",
+            encoding.getId(),
+            encoding.getName(),
+            task.getStatus(),
+            encoding.getCreatedAt());
+
+        if (stopStale) {
+          logger.info("Stopping encoding {}", encoding.getId());
+          bitmovinApi.encoding.encodings.stop(encoding.getId());
+        }
+      }
+    }
+
+    saveLastRun(stateFile, runStartedAt);
+    logger.info("Reconciliation done: {} encodings resolved, {} still pending", resolved, stale);
+  }
+
+  /**
+   * Lists all encodings with the given status, requesting as many pages as needed.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/GetEncodingEncodings
+   *
+   * @param status The status of the encodings to be listed
+   */
+  private static List<Encoding> listEncodings(Status status) throws BitmovinException {
+    return listEncodings(status, null);
+  }
+
+  /**
+   * Lists all encodings with the given status, requesting as many pages as needed.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/GetEncodingEncodings
+   *
+   * @param status The status of the encodings to be listed
+   * @param finishedAfter If set, only encodings which have ended after this time are listed
+   */
+  private static List<Encoding> listEncodings(Status status, Date finishedAfter)
+      throws BitmovinException {
+    List<Encoding> encodings = new ArrayList<>();
+    EncodingListQueryParams queryParams = new EncodingListQueryParams();
+    queryParams.setStatus(status.toString());
+    queryParams.setFinishedAtNewerThan(finishedAfter);
+    queryParams.setLimit(PAGE_SIZE);
+
+    List<Encoding> page;
+    do {
+      queryParams.setOffset(encodings.size());
+      page = bitmovinApi.encoding.encodings.list(queryParams).getItems();
+      encodings.addAll(page);
+    } while (page.size() == PAGE_SIZE);
+
+    return encodings;
+  }
+
+  /**
+   * Handles an encoding which has reached a final state without the completion being noticed, in
+   * the same way a received webhook would be handled. Replace this with your own logic, e.g.
+   * creating manifests or updating your content management system.
+   *
+   * @param encoding The encoding which has reached a final state
+   * @param task The current status of the encoding
+   */
+  private static void onEncodingResolved(Encoding encoding, Task task) {
+    logger.info(
+        "Encoding {} ({}) has reached status {}",
+        encoding.getId(),
+        encoding.getName(),
+        task.getStatus());
+
+    if (task.getStatus() == Status.ERROR) {
+      logTaskErrors(task);
+    }
+  }
+
+  /**
+   * Returns the time of the last run stored in the given state file, or null if there is none
+   *
+   * @param stateFile The file the time of the last run is stored in
+   */
+  private static Instant loadLastRun(Path stateFile) {
+    if (!Files.exists(stateFile)) {
+      return null;
+    }
+
+    Properties properties = new Properties();
+    try (Reader reader = Files.newBufferedReader(stateFile, StandardCharsets.UTF_8)) {
+      properties.load(reader);
+    } catch (IOException e) {
+      throw new UncheckedIOException("Error reading state file: " + stateFile, e);
+    }
+
+    String lastRunAt = properties.getProperty(LAST_RUN_KEY);
+    return lastRunAt != null ? Instant.parse(lastRunAt) : null;
+  }
+
+  private static void saveLastRun(Path stateFile, Instant lastRunAt) {
+    Properties properties = new Properties();
+    properties.setProperty(LAST_RUN_KEY, lastRunAt.toString());
+    try (Writer writer = Files.newBufferedWriter(stateFile, StandardCharsets.UTF_8)) {
+      properties.store(writer, "State of the EncodingReconciliation example");
+    } catch (IOException e) {
+      throw new UncheckedIOException("Error writing state file: " + stateFile, e);
+    }
+  }
+
+  private static void logTaskErrors(Task task) {
+    task.getMessages().stream()
+        .filter(msg -> msg.getType() == MessageType.ERROR)
+        .forEach(msg -> logger.error(msg.getText()));
+  }
+}
@@ -0,0 +1,267 @@
+import com.bitmovin.api.sdk.BitmovinApi;
+import com.bitmovin.api.sdk.model.Encoding;
+import com.bitmovin.api.sdk.model.Message;
+import com.bitmovin.api.sdk.model.Subtask;
+import com.bitmovin.api.sdk.model.Task;
+import com.fasterxml.jackson.databind.ObjectMapper;
+import common.ApiClientFactory;
+import common.ConfigProvider;
+import java.io.IOException;
+import java.nio.charset.StandardCharsets;
+import java.nio.file.Files;
+import java.nio.file.Path;
+import java.nio.file.Paths;
+import java.time.Duration;
+import java.util.ArrayList;
+import java.util.Comparator;
+import java.util.Date;
+import java.util.LinkedHashMap;
+import java.util.List;
+import java.util.Map;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+
+/**
+ * This tool exports the timeline of an existing encoding, to help diagnosing where time is spent in
+ * an encoding workflow, e.g. waiting in the queue, analyzing the input or encoding the individual
+ * streams.
+ *
+ * <p>The timestamps of the encoding itself (created, queued, running, finished or error), the
+ * timestamps of all subtasks and all messages of the encoding are collected, sorted chronologically
+ * and written to a CSV or JSON file. Each event contains the offset in seconds since the encoding
+ * was created. Additionally, the time spent in the queue and in each subtask is logged as a
+ * summary.
+ *
+ * <p>The following configuration parameters are expected:
+ *
+ * <ul>
+ *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin API
+ *   <li>BITMOVIN_TENANT_ORG_ID - (optional) The ID of the Organisation in which you want to perform
+ *       the encoding.
+ *   <li>BITMOVIN_API_BASE_URL - (optional) The base URL of the Bitmovin API, e.g. of a mock server
+ *       or an API gateway. Default: https://api.bitmovin.com/v1
+ *   <li>ENCODING_ID - The ID of the encoding to export the timeline of
+ *   <li>TIMELINE_FORMAT - (optional) The format of the exported timeline, either CSV or JSON.
+ *       Default: CSV
+ *   <li>TIMELINE_OUTPUT_FILE - (optional) The file the timeline is written to. Default:
+ *       timeline_{encodingId}.csv or timeline_{encodingId}.json
+ * </ul>
+ *
+ * <p>Configuration parameters will be retrieved from these sources in the listed order:
+ *
+ * <ol>
+ *   <li>command line arguments (eg BITMOVIN_API_KEY=xyz)
+ *   <li>properties file located in the root folder of the JAVA examples at ./examples.properties
+ *       (see examples.properties.template as reference)
+ *   <li>environment variables
+ *   <li>properties file located in the home folder at ~/.bitmovin/examples.properties (see
+ *       examples.properties.template as reference)
+ * </ol>
+ */
+public class EncodingTimelineExport {
+  private static final Logger logger = LoggerFactory.getLogger(EncodingTimelineExport.class);
+
+  private static final ObjectMapper objectMapper = new ObjectMapper();
+
+  private static BitmovinApi bitmovinApi;
+  private static ConfigProvider configProvider;
+
+  public static void main(String[] args) throws Exception {
+    configProvider = new ConfigProvider(args);
+    bitmovinApi =
+        ApiClientFactory.builder(configProvider)
+            .withApiKey(configProvider.getBitmovinApiKey())
+            .build();
+
+    String encodingId = configProvider.getParameterByKey("ENCODING_ID");
+    String format = configProvider.getParameterByKey("TIMELINE_FORMAT", "CSV").toUpperCase();
+    if (!"CSV".equals(format) && !"JSON".equals(format)) {
+      throw new IllegalArgumentException("Unsupported timeline format: " + format);
+    }
+    Path outputFile =
+        Paths.get(
+            configProvider.getParameterByKey(
+                "TIMELINE_OUTPUT_FILE",
+                String.format("timeline_%s.%s", encodingId, format.toLowerCase())));
+
+    Encoding encoding = bitmovinApi.encoding.encodings.get(encodingId);
+    Task task = bitmovinApi.encoding.encodings.status(encodingId);
+
+    List<TimelineEvent> timeline = collectTimeline(encoding, task);
+    logSummary(encoding, task);
+
+    if ("JSON".equals(format)) {
+      writeJson(timeline, outputFile);
+    } else {
+      writeCsv(timeline, outputFile);
+    }
+    logger.info("Exported {} timeline events to {}", timeline.size(), outputFile.toAbsolutePath());
+  }
+
+  /**
+   * Collects the status changes of the encoding and its subtasks as well as all messages of the
+   * encoding, and returns them sorted chronologically.
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/GetEncodingEncodingsByEncodingId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/GetEncodingEncodingsStatusByEncodingId
+   *
+   * @param encoding The encoding to export the timeline of
+   * @param task The status of the encoding
+   */
+  private static List<TimelineEvent> collectTimeline(Encoding encoding, Task task) {
+    Date createdAt = encoding.getCreatedAt();
+    List<TimelineEvent> timeline = new ArrayList<>();
+
+    addEvent(timeline, createdAt, encoding.getCreatedAt(), "encoding", "CREATED", null);
+    addEvent(timeline, createdAt, encoding.getQueuedAt(), "encoding", "QUEUED", null);
+    addEvent(timeline, createdAt, encoding.getRunningAt(), "encoding", "RUNNING", null);
+    addEvent(timeline, createdAt, encoding.getFinishedAt(), "encoding", "FINISHED", null);
+    addEvent(timeline, createdAt, encoding.getErrorAt(), "encoding", "ERROR", null);
+
+    if (task.getSubtasks() != null) {
+      for (Subtask subtask : task.getSubtasks()) {
+        String source = "subtask:" + subtask.getName();
+        addEvent(timeline, createdAt, subtask.getQueuedAt(), source, "QUEUED", null);
+        addEvent(timeline, createdAt, subtask.getRunningAt(), source, "RUNNING", null);
+        addEvent(timeline, createdAt, subtask.getFinishedAt(), source, "FINISHED", null);
+        addEvent(timeline, createdAt, subtask.getErrorAt(), source, "ERROR", null);
+      }
+    }
+
+    if (task.getMessages() != null) {
+      for (Message message : task.getMessages()) {
+        addEvent(
+            timeline,
+            createdAt,
+            message.getDate(),
+            "message",
+            String.valueOf(message.getType()),
+            message.getText());
+      }
+    }
+
+    timeline.sort(Comparator.comparing(event -> event.timestamp));
+    return timeline;
+  }
+
+  private static void addEvent(
+      List<TimelineEvent> timeline,
+      Date createdAt,
+      Date timestamp,
+      String source,
+      String event,
+      String details) {
+    if (timestamp == null) {
+      return;
+    }
+
+    long offsetSeconds = secondsBetween(createdAt, timestamp);
+    timeline.add(new TimelineEvent(timestamp, offsetSeconds, source, event, details));
+  }
+
+  /**
+   * Logs how long the encoding waited in the queue, how long it was running and how long each of
+   * its subtasks took.
+   */
+  private static void logSummary(Encoding encoding, Task task) {
+    Date endedAt =
+        encoding.getFinishedAt() != null ? encoding.getFinishedAt() : encoding.getErrorAt();
+
+    logger.info("Timeline summary of encoding {} ({})", encoding.getId(), task.getStatus());
+    logDuration("Waiting in queue", encoding.getQueuedAt(), encoding.getRunningAt());
+    logDuration("Running", encoding.getRunningAt(), endedAt);
+
+    if (task.getSubtasks() != null) {
+      for (Subtask subtask : task.getSubtasks()) {
+        Date subtaskEndedAt =
+            subtask.getFinishedAt() != null ? subtask.getFinishedAt() : subtask.getErrorAt();
+        logDuration("Subtask " + subtask.getName(), subtask.getRunningAt(), subtaskEndedAt);
+      }
+    }
+  }
+
+  private static void logDuration(String phase, Date from, Date to) {
+    if (from == null || to == null) {
+      logger.info("  {}: n/a", phase);
+      return;
+    }
+
+    logger.info("  {}: {} s", phase, secondsBetween(from, to));
+  }
+
+  private static long secondsBetween(Date from, Date to) {
+    if (from == null) {
+      return 0;
+    }
+
+    return Duration.between(from.toInstant(), to.toInstant()).getSeconds();
+  }
+
+  private static void writeCsv(List<TimelineEvent> timeline, Path outputFile) throws IOException {
+    List<String> lines = new ArrayList<>();
+    lines.add("timestamp,offset_seconds,source,event,details");
+
+    for (TimelineEvent event : timeline) {
+      lines.add(
+          String.join(
+              ",",
+              event.timestamp.toInstant().toString(),
+              String.valueOf(event.offsetSeconds),
+              escapeCsv(event.source),
+              escapeCsv(event.event),
+              escapeCsv(event.details)));
+    }
+
+    Files.write(outputFile, lines, StandardCharsets.UTF_8);
+  }
+
+  private static String escapeCsv(String value) {
+    if (value == null) {
+      return "";
+    }
+
+    return "\"" + value.replace("\"", "\"\"") + "\"";
+  }
+
+  private static void writeJson(List<TimelineEvent> timeline, Path outputFile) throws IOException {
+    List<Map<String, Object>> events = new ArrayList<>();
+
+    for (TimelineEvent event : timeline) {
+      Map<String, Object> entry = new LinkedHashMap<>();
+      entry.put("timestamp", event.timestamp.toInstant().toString());
+      entry.put("offsetSeconds", event.offsetSeconds);
+      entry.put("source", event.source);
+      entry.put("event", event.event);
+      entry.put("details", event.details);
+      events.add(entry);
+    }
+
+    objectMapper.writerWithDefaultPrettyPrinter().writeValue(outputFile.toFile(), events);
+  }
+
+  private static class TimelineEvent {
+
+    private Date timestamp;
+    private long offsetSeconds;
+    private String source;
+    private String event;
+    private String details;
+
+    /**
+     * @param timestamp The point in time the event occurred
+     * @param offsetSeconds The seconds passed between the creation of the encoding and the event
+     * @param source The encoding, subtask or message the event originates from
+     * @param event The status or message type
+     * @param details The message text, if any
+     */
+    private TimelineEvent(
+        Date timestamp, long offsetSeconds, String source, String event, String details) {
+      this.timestamp = timestamp;
+      this.offsetSeconds = offsetSeconds;
+      this.source = source;
+      this.event = event;
+      this.details = details;
+    }
+  }
+}
@@ -0,0 +1,198 @@
+import common.ConfigProvider;
+import java.io.File;
+import java.lang.reflect.InvocationTargetException;
+import java.lang.reflect.Method;
+import java.util.ArrayList;
+import java.util.Arrays;
+import java.util.List;
+import java.util.stream.Collectors;
+import java.util.stream.Stream;
+import javax.xml.parsers.DocumentBuilderFactory;
+import javax.xml.transform.OutputKeys;
+import javax.xml.transform.Transformer;
+import javax.xml.transform.TransformerFactory;
+import javax.xml.transform.dom.DOMSource;
+import javax.xml.transform.stream.StreamResult;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import org.w3c.dom.Document;
+import org.w3c.dom.Element;
+
+/**
+ * This tool executes a configurable selection of examples one after the other and records whether
+ * each of them succeeded and how long it took. The results are written to a JUnit-style XML report,
+ * which can be displayed by most CI systems. This provides a one-command check whether your account
+ * and storage setup is compatible with the examples.
+ *
+ * <p>All examples are run with the same configuration. To keep the run short and cheap, it is
+ * advisable to use a tiny input file (e.g. a few seconds of video), which can be set with
+ * SMOKE_MATRIX_INPUT_FILE_PATH without changing the configuration used for regular runs.
+ *
+ * <p>The following configuration parameters are expected:
+ *
+ * <ul>
+ *   <li>SMOKE_MATRIX_EXAMPLES - (optional) A comma-separated list of the class names of the
+ *       examples to run. Default: FixedBitrateLadder,DefaultManifests
+ *   <li>SMOKE_MATRIX_INPUT_FILE_PATH - (optional) The path to the input file used by all examples,
+ *       overriding HTTP_INPUT_FILE_PATH. Example: videos/5s_test_clip.mp4
+ *   <li>SMOKE_MATRIX_REPORT_FILE - (optional) The file the JUnit XML report is written to.
+ *       Default: smoke-matrix.xml
+ * </ul>
+ *
+ * <p>Additionally, all configuration parameters required by the selected examples have to be
+ * provided.
+ *
+ * <p>Configuration parameters will be retrieved from these sources in the listed order:
+ *
+ * <ol>
+ *   <li>command line arguments (eg BITMOVIN_API_KEY=xyz)
+ *   <li>properties file located in the root folder of the JAVA examples at ./examples.properties
+ *       (see examples.properties.template as reference)
+ *   <li>environment variables
+ *   <li>properties file located in the home folder at ~/.bitmovin/examples.properties (see
+ *       examples.properties.template as reference)
+ * </ol>
+ */
+public class ExampleSmokeMatrix {
+  private static final Logger logger = LoggerFactory.getLogger(ExampleSmokeMatrix.class);
+
+  public static void main(String[] args) throws Exception {
+    ConfigProvider configProvider = new ConfigProvider(args);
+
+    String examples =
+        configProvider.getParameterByKey(
+            "SMOKE_MATRIX_EXAMPLES", "FixedBitrateLadder,DefaultManifests");
+    List<String> exampleNames =
+        Arrays.stream(examples.split(","))
+            .map(String::trim)
+            .filter(name -> !name.isEmpty())
+            .collect(Collectors.toList());
+    String reportFile =
+        configProvider.getParameterByKey("SMOKE_MATRIX_REPORT_FILE", "smoke-matrix.xml");
+
+    String[] exampleArgs = buildExampleArgs(args, configProvider);
+
+    List<ExampleResult> results = new ArrayList<>();
+    for (String exampleName : exampleNames) {
+      results.add(runExample(exampleName, exampleArgs));
+    }
+
+    writeJUnitReport(results, new File(reportFile));
+
+    long failures = results.stream().filter(result -> result.failure != null).count();
+    logger.info(
+        "{} of {} examples passed. Report written to {}",
+        results.size() - failures,
+        results.size(),
+        reportFile);
+
+    if (failures > 0) {
+      throw new RuntimeException(String.format("%d examples failed", failures));
+    }
+  }
+
+  /**
+   * Builds the command line arguments passed to each example. If a dedicated input file is
+   * configured, it is passed as HTTP_INPUT_FILE_PATH, which takes precedence over all other config
+   * sources, as command line arguments are evaluated first.
+   *
+   * @param args The command line arguments of this tool
+   * @param configProvider The config provider of this tool
+   */
+  private static String[] buildExampleArgs(String[] args, ConfigProvider configProvider) {
+    String inputFilePath = configProvider.getParameterByKey("SMOKE_MATRIX_INPUT_FILE_PATH", null);
+    if (inputFilePath == null) {
+      return args;
+    }
+
+    return Stream.concat(
+            Stream.of("HTTP_INPUT_FILE_PATH=" + inputFilePath),
+            Arrays.stream(args).filter(arg -> !arg.startsWith("HTTP_INPUT_FILE_PATH=")))
+        .toArray(String[]::new);
+  }
+
+  /**
+   * Runs the main method of the given example and records the outcome. An example is considered
+   * failed if its main method throws an exception.
+   *
+   * @param exampleName The class name of the example, e.g. FixedBitrateLadder
+   * @param exampleArgs The command line arguments passed to the example
+   */
+  private static ExampleResult runExample(String exampleName, String[] exampleArgs) {
+    logger.info("Running example {}", exampleName);
+    ExampleResult result = new ExampleResult(exampleName);
+    long start = System.currentTimeMillis();
+
+    try {
+      Method mainMethod = Class.forName(exampleName).getMethod("main", String[].class);
+      mainMethod.invoke(null, (Object) exampleArgs);
+    } catch (InvocationTargetException e) {
+      result.failure = e.getCause();
+    } catch (Exception e) {
+      result.failure = e;
+    }
+
+    result.durationSeconds = (System.currentTimeMillis() - start) / 1000.0;
+    if (result.failure != null) {
+      logger.error("Example {} failed: {}", exampleName, result.failure.getMessage());
+    } else {
+      logger.info("Example {} passed in {} seconds", exampleName, result.durationSeconds);
+    }
+
+    return result;
+  }
+
+  /**
+   * Writes the results to a JUnit-style XML report, with one test case per example
+   *
+   * @param results The results of the executed examples
+   * @param reportFile The file to write the report to
+   */
+  private static void writeJUnitReport(List<ExampleResult> results, File reportFile)
+      throws Exception {
+    Document document = DocumentBuilderFactory.newInstance().newDocumentBuilder().newDocument();
+
+    Element testSuite = document.createElement("testsuite");
+    testSuite.setAttribute("name", ExampleSmokeMatrix.class.getSimpleName());
+    testSuite.setAttribute("tests", String.valueOf(results.size()));
+    testSuite.setAttribute(
+        "failures",
+        String.valueOf(results.stream().filter(result -> result.failure != null).count()));
+    testSuite.setAttribute(
+        "time",
+        String.valueOf(results.stream().mapToDouble(result -> result.durationSeconds).sum()));
+    document.appendChild(testSuite);
+
+    for (ExampleResult result : results) {
+      Element testCase = document.createElement("testcase");
+      testCase.setAttribute("classname", "examples");
+      testCase.setAttribute("name", result.exampleName);
+      testCase.setAttribute("time", String.valueOf(result.durationSeconds));
+
+      if (result.failure != null) {
+        Element failure = document.createElement("failure");
+        failure.setAttribute("type", result.failure.getClass().getName());
+        failure.setAttribute("message", String.valueOf(result.failure.getMessage()));
+        failure.setTextContent(result.failure.toString());
+        testCase.appendChild(failure);
+      }
+      testSuite.appendChild(testCase);
+    }
+
+    Transformer transformer = TransformerFactory.newInstance().newTransformer();
+    transformer.setOutputProperty(OutputKeys.INDENT, "yes");
+    transformer.transform(new DOMSource(document), new StreamResult(reportFile));
+  }
+
+  /** Helper class holding the outcome of a single example run */
+  private static class ExampleResult {
+
+    private String exampleName;
+    private double durationSeconds;
+    private Throwable failure;
+
+    private ExampleResult(String exampleName) {
+      this.exampleName = exampleName;
+    }
+  }
+}
@@ -24,13 +24,14 @@ import com.bitmovin.api.sdk.model.StreamFilter;
 import com.bitmovin.api.sdk.model.StreamFilterList;
 import com.bitmovin.api.sdk.model.StreamInput;
 import com.bitmovin.api.sdk.model.StreamMode;
-import com.bitmovin.api.sdk.model.StreamSelectionMode;
 import com.bitmovin.api.sdk.model.Task;
 import com.bitmovin.api.sdk.model.TextFilter;
 import com.bitmovin.api.sdk.model.WatermarkFilter;
+import common.ApiClientFactory;
+import common.BudgetTag;
 import common.ConfigProvider;
-import feign.Logger.Level;
-import feign.slf4j.Slf4jLogger;
+import common.EncodingFailedException;
+import common.PreviewTrimming;
 import java.nio.file.Paths;
 import java.util.ArrayList;
 import java.util.Arrays;
@@ -47,6 +48,8 @@ import org.slf4j.LoggerFactory;
  *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin API
  *   <li>BITMOVIN_TENANT_ORG_ID - (optional) The ID of the Organisation in which you want to perform
  *       the encoding.
+ *   <li>BITMOVIN_API_BASE_URL - (optional) The base URL of the Bitmovin API, e.g. of a mock server
+ *       or an API gateway. Default: https://api.bitmovin.com/v1
  *   <li>HTTP_INPUT_HOST - The Hostname or IP address of the HTTP server hosting your input files,
  *       e.g.: my-storage.biz
  *   <li>HTTP_INPUT_FILE_PATH - The path to your input file on the provided HTTP server Example:
@@ -82,12 +85,8 @@ public class Filters {
   public static void main(String[] args) throws Exception {
     configProvider = new ConfigProvider(args);
     bitmovinApi =
-        BitmovinApi.builder()
+        ApiClientFactory.builder(configProvider)
             .withApiKey(configProvider.getBitmovinApiKey())
-            // uncomment the following line if you are working with a multi-tenant account
-            // .withTenantOrgId(configProvider.getBitmovinTenantOrgId())
-            .withLogger(
-                new Slf4jLogger(), Level.BASIC) // set the logger and log level for the API client
             .build();
 
     Encoding encoding =
@@ -161,6 +160,7 @@ public class Filters {
     Encoding encoding = new Encoding();
     encoding.setName(name);
     encoding.setDescription(description);
+    new BudgetTag(configProvider).applyTo(encoding);
 
     return bitmovinApi.encoding.encodings.create(encoding);
   }
@@ -283,10 +283,8 @@ public class Filters {
   private static Stream createStream(
       Encoding encoding, Input input, String inputPath, CodecConfiguration codecConfiguration)
       throws BitmovinException {
-    StreamInput streamInput = new StreamInput();
-    streamInput.setInputId(input.getId());
-    streamInput.setInputPath(inputPath);
-    streamInput.setSelectionMode(StreamSelectionMode.AUTO);
+    StreamInput streamInput =
+        PreviewTrimming.createStreamInput(bitmovinApi, configProvider, encoding, input, inputPath);
 
     Stream stream = new Stream();
     stream.addInputStreamsItem(streamInput);
@@ -418,11 +416,13 @@ public class Filters {
       Thread.sleep(5000);
       task = bitmovinApi.encoding.encodings.status(encoding.getId());
       logger.info("Encoding status is {} (progress: {} %)", task.getStatus(), task.getProgress());
-    } while (task.getStatus() != Status.FINISHED && task.getStatus() != Status.ERROR);
+    } while (task.getStatus() != Status.FINISHED
+        && task.getStatus() != Status.ERROR
+        && task.getStatus() != Status.CANCELED);
 
-    if (task.getStatus() == Status.ERROR) {
+    if (task.getStatus() != Status.FINISHED) {
       logTaskErrors(task);
-      throw new RuntimeException("Encoding failed");
+      throw new EncodingFailedException(task.getStatus());
     }
     logger.info("Encoding finished successfully");
   }
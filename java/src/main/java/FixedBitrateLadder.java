@@ -19,11 +19,12 @@ import com.bitmovin.api.sdk.model.StartEncodingRequest;
 import com.bitmovin.api.sdk.model.Status;
 import com.bitmovin.api.sdk.model.Stream;
 import com.bitmovin.api.sdk.model.StreamInput;
-import com.bitmovin.api.sdk.model.StreamSelectionMode;
 import com.bitmovin.api.sdk.model.Task;
+import common.ApiClientFactory;
+import common.BudgetTag;
 import common.ConfigProvider;
-import feign.Logger.Level;
-import feign.slf4j.Slf4jLogger;
+import common.EncodingFailedException;
+import common.PreviewTrimming;
 import java.nio.file.Paths;
 import java.util.Arrays;
 import java.util.List;
@@ -40,6 +41,8 @@ import org.slf4j.LoggerFactory;
  *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin API
  *   <li>BITMOVIN_TENANT_ORG_ID - (optional) The ID of the Organisation in which you want to perform
  *       the encoding.
+ *   <li>BITMOVIN_API_BASE_URL - (optional) The base URL of the Bitmovin API, e.g. of a mock server
+ *       or an API gateway. Default: https://api.bitmovin.com/v1
  *   <li>HTTP_INPUT_HOST - The Hostname or IP address of the HTTP server hosting your input files,
  *       e.g.: my-storage.biz
  *   <li>HTTP_INPUT_FILE_PATH - The path to your input file on the provided HTTP server Example:
@@ -71,12 +74,8 @@ public class FixedBitrateLadder {
   public static void main(String[] args) throws Exception {
     configProvider = new ConfigProvider(args);
     bitmovinApi =
-        BitmovinApi.builder()
+        ApiClientFactory.builder(configProvider)
             .withApiKey(configProvider.getBitmovinApiKey())
-            // uncomment the following line if you are working with a multi-tenant account
-            // .withTenantOrgId(configProvider.getBitmovinTenantOrgId())
-            .withLogger(
-                new Slf4jLogger(), Level.BASIC) // set the logger and log level for the API client
             .build();
 
     Encoding encoding =
@@ -191,6 +190,7 @@ public class FixedBitrateLadder {
     Encoding encoding = new Encoding();
     encoding.setName(name);
     encoding.setDescription(description);
+    new BudgetTag(configProvider).applyTo(encoding);
 
     return bitmovinApi.encoding.encodings.create(encoding);
   }
@@ -309,10 +309,8 @@ public class FixedBitrateLadder {
   private static Stream createStream(
       Encoding encoding, Input input, String inputPath, CodecConfiguration codecConfiguration)
       throws BitmovinException {
-    StreamInput streamInput = new StreamInput();
-    streamInput.setInputId(input.getId());
-    streamInput.setInputPath(inputPath);
-    streamInput.setSelectionMode(StreamSelectionMode.AUTO);
+    StreamInput streamInput =
+        PreviewTrimming.createStreamInput(bitmovinApi, configProvider, encoding, input, inputPath);
 
     Stream stream = new Stream();
     stream.addInputStreamsItem(streamInput);
@@ -344,11 +342,13 @@ public class FixedBitrateLadder {
       Thread.sleep(5000);
       task = bitmovinApi.encoding.encodings.status(encoding.getId());
       logger.info("encoding status is {} (progress: {} %)", task.getStatus(), task.getProgress());
-    } while (task.getStatus() != Status.FINISHED && task.getStatus() != Status.ERROR);
+    } while (task.getStatus() != Status.FINISHED
+        && task.getStatus() != Status.ERROR
+        && task.getStatus() != Status.CANCELED);
 
-    if (task.getStatus() == Status.ERROR) {
+    if (task.getStatus() != Status.FINISHED) {
       logTaskErrors(task);
-      throw new RuntimeException("Encoding failed");
+      throw new EncodingFailedException(task.getStatus());
     }
     logger.info("encoding finished successfully");
   }
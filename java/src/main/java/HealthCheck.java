@@ -0,0 +1,275 @@
+import com.bitmovin.api.sdk.BitmovinApi;
+import com.bitmovin.api.sdk.encoding.encodings.EncodingListQueryParams;
+import com.bitmovin.api.sdk.model.AccountInformation;
+import com.bitmovin.api.sdk.model.AwsCloudRegion;
+import com.bitmovin.api.sdk.model.Organization;
+import common.ApiClientFactory;
+import common.ConfigProvider;
+import feign.Logger.Level;
+import java.net.HttpURLConnection;
+import java.net.URL;
+import java.nio.file.Paths;
+import java.util.ArrayList;
+import java.util.List;
+import java.util.Locale;
+import java.util.UUID;
+import org.apache.commons.lang3.StringUtils;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import software.amazon.awssdk.auth.credentials.AwsBasicCredentials;
+import software.amazon.awssdk.auth.credentials.StaticCredentialsProvider;
+import software.amazon.awssdk.core.sync.RequestBody;
+import software.amazon.awssdk.regions.Region;
+import software.amazon.awssdk.services.s3.S3Client;
+import software.amazon.awssdk.services.s3.model.DeleteObjectRequest;
+import software.amazon.awssdk.services.s3.model.PutObjectRequest;
+
+/**
+ * This tool verifies the configuration shared by most examples and prints a checklist of the
+ * results. It is the first thing to run when an example fails for no obvious reason, and the
+ * checklist is what our support will ask for:
+ *
+ * <ul>
+ *   <li>API key - The API key is valid, i.e. the account information can be retrieved. If
+ *       BITMOVIN_API_BASE_URL is configured, the request is sent to it instead of the Bitmovin API
+ *   <li>Tenant organization - If BITMOVIN_TENANT_ORG_ID is configured, the organization exists and
+ *       encodings can be listed in it
+ *   <li>Input host - HTTP_INPUT_HOST responds to HTTP requests, and HTTP_INPUT_FILE_PATH exists if
+ *       configured
+ *   <li>Output bucket - A small file can be written to and deleted from S3_OUTPUT_BASE_PATH in
+ *       S3_OUTPUT_BUCKET_NAME with the configured access key
+ * </ul>
+ *
+ * <p>All checks are executed even if one of them fails, and missing configuration parameters are
+ * reported as failures of the affected check. Note that the input host and output bucket are
+ * checked from the machine running this tool. A host that is only reachable from your own network
+ * will pass the check, but is not reachable by the Bitmovin encoders.
+ *
+ * <p>No Bitmovin resources are created, so the tool can be run at any time.
+ *
+ * <p>The following configuration parameters are evaluated:
+ *
+ * <ul>
+ *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin API
+ *   <li>BITMOVIN_TENANT_ORG_ID - (optional) The ID of the Organisation in which you want to perform
+ *       the encoding.
+ *   <li>BITMOVIN_API_BASE_URL - (optional) The base URL of the Bitmovin API, e.g. of a mock server
+ *       or an API gateway. Default: https://api.bitmovin.com/v1
+ *   <li>HTTP_INPUT_HOST - The Hostname or IP address of the HTTP server hosting your input files,
+ *       e.g.: my-storage.biz
+ *   <li>HTTP_INPUT_FILE_PATH - (optional) The path to your input file on the provided HTTP server
+ *       Example: videos/1080p_Sintel.mp4
+ *   <li>S3_OUTPUT_BUCKET_NAME - The name of your S3 output bucket. Example: my-bucket-name
+ *   <li>S3_OUTPUT_ACCESS_KEY - The access key of your S3 output bucket
+ *   <li>S3_OUTPUT_SECRET_KEY - The secret key of your S3 output bucket
+ *   <li>S3_OUTPUT_BASE_PATH - The base path on your S3 output bucket where content will be written.
+ *       Example: /outputs
+ *   <li>S3_OUTPUT_CLOUD_REGION - (optional) The AWS region of your S3 output bucket. Default:
+ *       US_EAST_1
+ * </ul>
+ *
+ * <p>Configuration parameters will be retrieved from these sources in the listed order:
+ *
+ * <ol>
+ *   <li>command line arguments (eg BITMOVIN_API_KEY=xyz)
+ *   <li>properties file located in the root folder of the JAVA examples at ./examples.properties
+ *       (see examples.properties.template as reference)
+ *   <li>environment variables
+ *   <li>properties file located in the home folder at ~/.bitmovin/examples.properties (see
+ *       examples.properties.template as reference)
+ * </ol>
+ */
+public class HealthCheck {
+  private static final Logger logger = LoggerFactory.getLogger(HealthCheck.class);
+
+  private static final int HTTP_TIMEOUT_MILLIS = 10_000;
+
+  private static ConfigProvider configProvider;
+
+  public static void main(String[] args) throws Exception {
+    configProvider = new ConfigProvider(args);
+
+    List<CheckResult> results = new ArrayList<>();
+    results.add(runCheck("API key", HealthCheck::checkApiKey));
+    results.add(runCheck("Tenant organization", HealthCheck::checkTenantOrganization));
+    results.add(runCheck("Input host", HealthCheck::checkInputHost));
+    results.add(runCheck("Output bucket", HealthCheck::checkOutputBucket));
+
+    System.out.println();
+    for (CheckResult result : results) {
+      System.out.println(String.format("[%s] %s - %s", result.status, result.name, result.detail));
+    }
+
+    long failures = results.stream().filter(result -> result.status == CheckStatus.FAIL).count();
+    if (failures > 0) {
+      throw new RuntimeException(String.format("%d checks failed", failures));
+    }
+  }
+
+  /**
+   * Executes a single check. A check fails if it throws an exception, e.g. because of an API error
+   * or a missing configuration parameter.
+   *
+   * @param name The name of the check, as printed in the checklist
+   * @param check The check to execute
+   */
+  private static CheckResult runCheck(String name, Check check) {
+    logger.info("Checking {}", name);
+    try {
+      return check.run(name);
+    } catch (Exception e) {
+      logger.error("Check '{}' failed", name, e);
+      return new CheckResult(name, CheckStatus.FAIL, e.getMessage());
+    }
+  }
+
+  /**
+   * Retrieves the information of the account the API key belongs to
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/all#/Account/GetAccountInformation
+   */
+  private static CheckResult checkApiKey(String name) {
+    AccountInformation accountInformation = createBitmovinApi(null).account.information.get();
+    String message = "Authenticated as " + accountInformation.getEmail();
+    if (!configProvider.getBitmovinApiBaseUrl().equals(ConfigProvider.DEFAULT_API_BASE_URL)) {
+      message += " via " + configProvider.getBitmovinApiBaseUrl();
+    }
+    return new CheckResult(name, CheckStatus.PASS, message);
+  }
+
+  /**
+   * Retrieves the tenant organization, and lists encodings in it to make sure the API key is
+   * allowed to act on behalf of the organization
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/all#/Account/GetAccountOrganizationsByOrganizationId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/GetEncodingEncodings
+   */
+  private static CheckResult checkTenantOrganization(String name) {
+    String tenantOrgId = configProvider.getParameterByKey("BITMOVIN_TENANT_ORG_ID", null);
+    if (tenantOrgId == null) {
+      return new CheckResult(name, CheckStatus.SKIP, "BITMOVIN_TENANT_ORG_ID is not configured");
+    }
+
+    Organization organization = createBitmovinApi(null).account.organizations.get(tenantOrgId);
+
+    EncodingListQueryParams queryParams = new EncodingListQueryParams();
+    queryParams.setLimit(1);
+    createBitmovinApi(tenantOrgId).encoding.encodings.list(queryParams);
+
+    return new CheckResult(
+        name, CheckStatus.PASS, "Acting on behalf of " + organization.getName());
+  }
+
+  /**
+   * Sends a HEAD request to the input host, or to the input file if HTTP_INPUT_FILE_PATH is
+   * configured. Any response of the host passes the check, but the input file has to exist.
+   */
+  private static CheckResult checkInputHost(String name) throws Exception {
+    String host = configProvider.getHttpInputHost();
+    String filePath = configProvider.getParameterByKey("HTTP_INPUT_FILE_PATH", null);
+
+    String path = filePath != null ? "/" + StringUtils.stripStart(filePath, "/") : "/";
+    URL url = new URL("http", host, path);
+    HttpURLConnection connection = (HttpURLConnection) url.openConnection();
+    try {
+      connection.setRequestMethod("HEAD");
+      connection.setConnectTimeout(HTTP_TIMEOUT_MILLIS);
+      connection.setReadTimeout(HTTP_TIMEOUT_MILLIS);
+      int responseCode = connection.getResponseCode();
+
+      if (filePath != null && responseCode >= 400) {
+        return new CheckResult(
+            name, CheckStatus.FAIL, String.format("%s responded with %d", url, responseCode));
+      }
+      return new CheckResult(
+          name, CheckStatus.PASS, String.format("%s responded with %d", url, responseCode));
+    } finally {
+      connection.disconnect();
+    }
+  }
+
+  /**
+   * Writes a small file to the output base path of the bucket and deletes it again, using the
+   * credentials of the S3 output
+   */
+  private static CheckResult checkOutputBucket(String name) {
+    String bucketName = configProvider.getS3OutputBucketName();
+    String key =
+        StringUtils.stripStart(
+            Paths.get(
+                    configProvider.getS3OutputBasePath(),
+                    "bitmovin-healthcheck-" + UUID.randomUUID() + ".txt")
+                .toString(),
+            "/");
+    AwsCloudRegion cloudRegion =
+        AwsCloudRegion.valueOf(
+            configProvider.getParameterByKey(
+                "S3_OUTPUT_CLOUD_REGION", AwsCloudRegion.US_EAST_1.name()));
+
+    try (S3Client s3Client =
+        S3Client.builder()
+            .credentialsProvider(
+                StaticCredentialsProvider.create(
+                    AwsBasicCredentials.create(
+                        configProvider.getS3OutputAccessKey(),
+                        configProvider.getS3OutputSecretKey())))
+            // AWS region names use dashes instead of the underscores of the enum constants
+            .region(Region.of(cloudRegion.name().toLowerCase(Locale.ROOT).replace('_', '-')))
+            .build()) {
+      s3Client.putObject(
+          PutObjectRequest.builder().bucket(bucketName).key(key).build(),
+          RequestBody.fromString("Written by the Bitmovin examples health check"));
+      s3Client.deleteObject(DeleteObjectRequest.builder().bucket(bucketName).key(key).build());
+    }
+
+    return new CheckResult(
+        name, CheckStatus.PASS, String.format("Wrote and deleted s3://%s/%s", bucketName, key));
+  }
+
+  /**
+   * Creates a Bitmovin API client
+   *
+   * @param tenantOrgId The ID of the organization to act on behalf of, or null for the account of
+   *     the API key itself
+   */
+  private static BitmovinApi createBitmovinApi(String tenantOrgId) {
+    BitmovinApi.Builder builder =
+        ApiClientFactory.accountBuilder(configProvider, Level.NONE)
+            .withApiKey(configProvider.getBitmovinApiKey());
+    if (tenantOrgId != null) {
+      builder.withTenantOrgId(tenantOrgId);
+    }
+    return builder.build();
+  }
+
+  @FunctionalInterface
+  private interface Check {
+    CheckResult run(String name) throws Exception;
+  }
+
+  private enum CheckStatus {
+    PASS,
+    FAIL,
+    SKIP
+  }
+
+  private static class CheckResult {
+
+    private final String name;
+    private final CheckStatus status;
+    private final String detail;
+
+    /**
+     * @param name The name of the check
+     * @param status The outcome of the check
+     * @param detail A short description of what was verified, or why the check failed
+     */
+    private CheckResult(String name, CheckStatus status, String detail) {
+      this.name = name;
+      this.status = status;
+      this.detail = detail;
+    }
+  }
+}
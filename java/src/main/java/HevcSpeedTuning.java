@@ -0,0 +1,463 @@
+import com.bitmovin.api.sdk.BitmovinApi;
+import com.bitmovin.api.sdk.common.BitmovinException;
+import com.bitmovin.api.sdk.model.AclEntry;
+import com.bitmovin.api.sdk.model.AclPermission;
+import com.bitmovin.api.sdk.model.CodecConfiguration;
+import com.bitmovin.api.sdk.model.Encoding;
+import com.bitmovin.api.sdk.model.EncodingOutput;
+import com.bitmovin.api.sdk.model.Fmp4Muxing;
+import com.bitmovin.api.sdk.model.H265VideoConfiguration;
+import com.bitmovin.api.sdk.model.HttpInput;
+import com.bitmovin.api.sdk.model.Input;
+import com.bitmovin.api.sdk.model.MessageType;
+import com.bitmovin.api.sdk.model.MuxingStream;
+import com.bitmovin.api.sdk.model.Output;
+import com.bitmovin.api.sdk.model.PixelFormat;
+import com.bitmovin.api.sdk.model.PresetConfiguration;
+import com.bitmovin.api.sdk.model.ProfileH265;
+import com.bitmovin.api.sdk.model.S3Output;
+import com.bitmovin.api.sdk.model.StartEncodingRequest;
+import com.bitmovin.api.sdk.model.Status;
+import com.bitmovin.api.sdk.model.Stream;
+import com.bitmovin.api.sdk.model.StreamInput;
+import com.bitmovin.api.sdk.model.Task;
+import common.ApiClientFactory;
+import common.BudgetTag;
+import common.ConfigProvider;
+import common.PreviewTrimming;
+import common.SegmentSharding;
+import java.nio.file.Paths;
+import java.time.Duration;
+import java.util.ArrayList;
+import java.util.Arrays;
+import java.util.Collection;
+import java.util.Iterator;
+import java.util.LinkedHashMap;
+import java.util.List;
+import java.util.Locale;
+import java.util.Map;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+
+/**
+ * This example demonstrates the performance related settings of the H.265 codec, and measures how
+ * they affect the turnaround time of UHD encodings. This helps to find the right balance between
+ * speed and compression efficiency before processing a large catalog.
+ *
+ * <p>The same input is encoded once per tuning variant, all encodings running in parallel. Each
+ * encoding produces a single 2160p rendition, so the measured times are not influenced by other
+ * renditions. The following variants are defined:
+ *
+ * <ul>
+ *   <li>QUALITY - The quality optimized preset without further tuning, as a baseline
+ *   <li>PARALLEL - The same preset with wavefront parallel processing, parallel lookahead slices
+ *       and a shorter lookahead, which increases the number of threads working on a frame at a
+ *       small cost of compression efficiency
+ *   <li>SLICES - Additionally splits each frame into independently encoded slices, which increases
+ *       parallelism further but reduces compression efficiency noticeably
+ *   <li>SPEED - The speed optimized preset, which uses faster but less thorough analysis throughout
+ * </ul>
+ *
+ * <p>Tiles, which are another way to split frames into independent regions in H.265, are not
+ * supported by the encoder. Slices are the closest alternative.
+ *
+ * <p>After all encodings have finished, the time spent encoding and the overall turnaround time
+ * including queuing are logged per variant. Compare the output of the variants visually or with
+ * quality metrics before deciding on settings for production use.
+ *
+ * <p>The following configuration parameters are expected:
+ *
+ * <ul>
+ *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin API
+ *   <li>BITMOVIN_TENANT_ORG_ID - (optional) The ID of the Organisation in which you want to perform
+ *       the encoding.
+ *   <li>BITMOVIN_API_BASE_URL - (optional) The base URL of the Bitmovin API, e.g. of a mock server
+ *       or an API gateway. Default: https://api.bitmovin.com/v1
+ *   <li>HTTP_INPUT_HOST - The Hostname or IP address of the HTTP server hosting your input files,
+ *       e.g.: my-storage.biz
+ *   <li>HTTP_INPUT_FILE_PATH - The path to your input file on the provided HTTP server Example:
+ *       videos/1080p_Sintel.mp4
+ *   <li>S3_OUTPUT_BUCKET_NAME - The name of your S3 output bucket. Example: my-bucket-name
+ *   <li>S3_OUTPUT_ACCESS_KEY - The access key of your S3 output bucket
+ *   <li>S3_OUTPUT_SECRET_KEY - The secret key of your S3 output bucket
+ *   <li>S3_OUTPUT_BASE_PATH - The base path on your S3 output bucket where content will be written.
+ *       Example: /outputs
+ *   <li>HEVC_TUNING_VARIANTS - (optional) A comma separated list of the variants to be compared.
+ *       Default: QUALITY,PARALLEL,SLICES,SPEED
+ * </ul>
+ *
+ * <p>Configuration parameters will be retrieved from these sources in the listed order:
+ *
+ * <ol>
+ *   <li>command line arguments (eg BITMOVIN_API_KEY=xyz)
+ *   <li>properties file located in the root folder of the JAVA examples at ./examples.properties
+ *       (see examples.properties.template as reference)
+ *   <li>environment variables
+ *   <li>properties file located in the home folder at ~/.bitmovin/examples.properties (see
+ *       examples.properties.template as reference)
+ * </ol>
+ */
+public class HevcSpeedTuning {
+  private static final Logger logger = LoggerFactory.getLogger(HevcSpeedTuning.class);
+
+  private static BitmovinApi bitmovinApi;
+  private static ConfigProvider configProvider;
+
+  private static final int HEIGHT = 2160;
+  private static final long BITRATE = 15_000_000L;
+
+  /** This list defines the tuning variants that can be compared */
+  private static List<TuningVariant> tuningVariants =
+      Arrays.asList(
+          new TuningVariant(
+              "QUALITY", PresetConfiguration.VOD_HIGH_QUALITY, null, null, null, null),
+          new TuningVariant("PARALLEL", PresetConfiguration.VOD_HIGH_QUALITY, true, 4, 20, null),
+          new TuningVariant("SLICES", PresetConfiguration.VOD_HIGH_QUALITY, true, 4, 20, 4),
+          new TuningVariant("SPEED", PresetConfiguration.VOD_SPEED, null, null, null, null));
+
+  public static void main(String[] args) throws Exception {
+    configProvider = new ConfigProvider(args);
+    bitmovinApi =
+        ApiClientFactory.builder(configProvider)
+            .withApiKey(configProvider.getBitmovinApiKey())
+            .build();
+
+    HttpInput input = createHttpInput(configProvider.getHttpInputHost());
+    Output output =
+        createS3Output(
+            configProvider.getS3OutputBucketName(),
+            configProvider.getS3OutputAccessKey(),
+            configProvider.getS3OutputSecretKey());
+
+    String inputFilePath = configProvider.getHttpInputFilePath();
+    List<String> variantNames =
+        Arrays.asList(
+            configProvider
+                .getParameterByKey("HEVC_TUNING_VARIANTS", "QUALITY,PARALLEL,SLICES,SPEED")
+                .split(","));
+
+    Map<TuningVariant, Encoding> encodings = new LinkedHashMap<>();
+    for (TuningVariant variant : tuningVariants) {
+      if (!variantNames.contains(variant.name)) {
+        continue;
+      }
+
+      Encoding encoding =
+          createEncoding(
+              "HEVC tuning " + variant.name, "Encoding with H.265 tuning variant " + variant.name);
+      H265VideoConfiguration videoConfiguration = createH265VideoConfig(variant);
+      Stream videoStream = createStream(encoding, input, inputFilePath, videoConfiguration);
+      createFmp4Muxing(encoding, output, variant.name.toLowerCase(Locale.ROOT), videoStream);
+
+      bitmovinApi.encoding.encodings.start(encoding.getId(), new StartEncodingRequest());
+      encodings.put(variant, encoding);
+    }
+
+    waitForEncodingsToFinish(encodings.values());
+
+    for (Map.Entry<TuningVariant, Encoding> entry : encodings.entrySet()) {
+      logTurnaround(entry.getKey(), entry.getValue());
+    }
+  }
+
+  /**
+   * Creates a configuration for the H.265 video codec, applying the performance related settings
+   * of the given tuning variant. Settings that are not defined by the variant are left to the
+   * preset.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsVideoH265
+   *
+   * @param variant The tuning variant to apply
+   */
+  private static H265VideoConfiguration createH265VideoConfig(TuningVariant variant)
+      throws BitmovinException {
+    H265VideoConfiguration config = new H265VideoConfiguration();
+    config.setName(String.format("H.265 %dp %s", HEIGHT, variant.name));
+    config.setPresetConfiguration(variant.preset);
+    config.setHeight(HEIGHT);
+    config.setBitrate(BITRATE);
+    config.setProfile(ProfileH265.MAIN10);
+    config.setPixelFormat(PixelFormat.YUV420P10LE);
+
+    // encode rows of coding tree units in parallel, each row starting as soon as the required
+    // units of the row above are done
+    config.setWpp(variant.wavefrontParallelProcessing);
+    // split the lookahead analysis of each frame into slices that are processed in parallel
+    config.setLookaheadSlices(variant.lookaheadSlices);
+    // number of frames analysed ahead for rate control, fewer frames reduce latency and memory
+    config.setRcLookahead(variant.rcLookahead);
+    // split each frame into independently encoded slices
+    config.setSlices(variant.slices);
+
+    return bitmovinApi.encoding.configurations.video.h265.create(config);
+  }
+
+  /**
+   * Periodically polls the status of the given encodings until all of them reached a final state
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/GetEncodingEncodingsStatusByEncodingId
+   *
+   * @param encodings The encodings to wait for
+   */
+  private static void waitForEncodingsToFinish(Collection<Encoding> encodings)
+      throws InterruptedException, BitmovinException {
+    List<Encoding> pending = new ArrayList<>(encodings);
+    while (!pending.isEmpty()) {
+      Thread.sleep(5000);
+
+      Iterator<Encoding> iterator = pending.iterator();
+      while (iterator.hasNext()) {
+        Encoding encoding = iterator.next();
+        Task task = bitmovinApi.encoding.encodings.status(encoding.getId());
+        logger.info(
+            "encoding {} status is {} (progress: {} %)",
+            encoding.getName(),
+            task.getStatus(),
+            task.getProgress());
+
+        if (task.getStatus() == Status.ERROR || task.getStatus() == Status.CANCELED) {
+          logTaskErrors(task);
+          iterator.remove();
+        } else if (task.getStatus() == Status.FINISHED) {
+          iterator.remove();
+        }
+      }
+    }
+  }
+
+  /**
+   * Logs the time the encoding of a tuning variant spent running, and its overall turnaround time
+   * from being queued until it finished
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/GetEncodingEncodingsByEncodingId
+   *
+   * @param variant The tuning variant of the encoding
+   * @param encoding The encoding to be evaluated
+   */
+  private static void logTurnaround(TuningVariant variant, Encoding encoding)
+      throws BitmovinException {
+    Encoding finishedEncoding = bitmovinApi.encoding.encodings.get(encoding.getId());
+    if (finishedEncoding.getStatus() != Status.FINISHED) {
+      logger.info("{}: encoding did not finish ({})", variant.name, finishedEncoding.getStatus());
+      return;
+    }
+
+    Duration encodingTime =
+        Duration.between(
+            finishedEncoding.getRunningAt().toInstant(),
+            finishedEncoding.getFinishedAt().toInstant());
+    Duration turnaroundTime =
+        Duration.between(
+            finishedEncoding.getQueuedAt().toInstant(),
+            finishedEncoding.getFinishedAt().toInstant());
+
+    logger.info(
+        "{}: encoding time {} s, turnaround time {} s",
+        variant.name,
+        encodingTime.getSeconds(),
+        turnaroundTime.getSeconds());
+  }
+
+  private static class TuningVariant {
+
+    private String name;
+    private PresetConfiguration preset;
+    private Boolean wavefrontParallelProcessing;
+    private Integer lookaheadSlices;
+    private Integer rcLookahead;
+    private Integer slices;
+
+    /**
+     * @param name The name of the variant
+     * @param preset The preset the settings are based on
+     * @param wavefrontParallelProcessing Whether wavefront parallel processing is enabled, or null
+     *     to use the setting of the preset
+     * @param lookaheadSlices The number of lookahead slices, or null to use the preset
+     * @param rcLookahead The number of frames of the rate control lookahead, or null to use the
+     *     preset
+     * @param slices The number of slices per frame, or null to use the preset
+     */
+    private TuningVariant(
+        String name,
+        PresetConfiguration preset,
+        Boolean wavefrontParallelProcessing,
+        Integer lookaheadSlices,
+        Integer rcLookahead,
+        Integer slices) {
+      this.name = name;
+      this.preset = preset;
+      this.wavefrontParallelProcessing = wavefrontParallelProcessing;
+      this.lookaheadSlices = lookaheadSlices;
+      this.rcLookahead = rcLookahead;
+      this.slices = slices;
+    }
+  }
+
+  /**
+   * Creates a resource representing an HTTP server providing the input files. For alternative input
+   * methods see <a
+   * href="https://bitmovin.com/docs/encoding/articles/supported-input-output-storages">list of
+   * supported input and output storages</a>
+   *
+   * <p>For reasons of simplicity, a new input resource is created on each execution of this
+   * example. In production use, this method should be replaced by a <a
+   * href="https://bitmovin.com/docs/encoding/api-reference/sections/inputs#/Encoding/GetEncodingInputsHttpByInputId">get
+   * call</a> to retrieve an existing resource.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/inputs#/Encoding/PostEncodingInputsHttp
+   *
+   * @param host The hostname or IP address of the HTTP server e.g.: my-storage.biz
+   */
+  private static HttpInput createHttpInput(String host) throws BitmovinException {
+    HttpInput input = new HttpInput();
+    input.setHost(host);
+
+    return bitmovinApi.encoding.inputs.http.create(input);
+  }
+
+  /**
+   * Creates a resource representing an AWS S3 cloud storage bucket to which generated content will
+   * be transferred. For alternative output methods see <a
+   * href="https://bitmovin.com/docs/encoding/articles/supported-input-output-storages">list of
+   * supported input and output storages</a>
+   *
+   * <p>The provided credentials need to allow <i>read</i>, <i>write</i> and <i>list</i> operations.
+   * <i>delete</i> should also be granted to allow overwriting of existings files. See <a
+   * href="https://bitmovin.com/docs/encoding/faqs/how-do-i-create-a-aws-s3-bucket-which-can-be-used-as-output-location">creating
+   * an S3 bucket and setting permissions</a> for further information
+   *
+   * <p>For reasons of simplicity, a new output resource is created on each execution of this
+   * example. In production use, this method should be replaced by a <a
+   * href="https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/GetEncodingOutputsS3">get
+   * call</a> retrieving an existing resource.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/PostEncodingOutputsS3
+   *
+   * @param bucketName The name of the S3 bucket
+   * @param accessKey The access key of your S3 account
+   * @param secretKey The secret key of your S3 account
+   */
+  private static S3Output createS3Output(String bucketName, String accessKey, String secretKey)
+      throws BitmovinException {
+
+    S3Output s3Output = new S3Output();
+    s3Output.setBucketName(bucketName);
+    s3Output.setAccessKey(accessKey);
+    s3Output.setSecretKey(secretKey);
+
+    return bitmovinApi.encoding.outputs.s3.create(s3Output);
+  }
+
+  /**
+   * Creates an encoding object. This is the base object to configure your encoding.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodings
+   *
+   * @param name This is the name of the encoding
+   * @param description This is the description of the encoding
+   */
+  private static Encoding createEncoding(String name, String description) throws BitmovinException {
+    Encoding encoding = new Encoding();
+    encoding.setName(name);
+    encoding.setDescription(description);
+    new BudgetTag(configProvider).applyTo(encoding);
+
+    return bitmovinApi.encoding.encodings.create(encoding);
+  }
+
+  /**
+   * Creates a stream which binds an input file to a codec configuration. The stream is used for
+   * muxings later on.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsStreamsByEncodingId
+   *
+   * @param encoding The encoding to add the stream onto
+   * @param input The input that should be used
+   * @param inputPath The path to the input file
+   * @param codecConfiguration The codec configuration to be applied to the stream
+   */
+  private static Stream createStream(
+      Encoding encoding, Input input, String inputPath, CodecConfiguration codecConfiguration)
+      throws BitmovinException {
+    StreamInput streamInput =
+        PreviewTrimming.createStreamInput(bitmovinApi, configProvider, encoding, input, inputPath);
+
+    Stream stream = new Stream();
+    stream.addInputStreamsItem(streamInput);
+    stream.setCodecConfigId(codecConfiguration.getId());
+
+    return bitmovinApi.encoding.encodings.streams.create(encoding.getId(), stream);
+  }
+
+  /**
+   * Creates a fragmented MP4 muxing. This will generate segments with a given segment length for
+   * adaptive streaming.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/all#/Encoding/PostEncodingEncodingsMuxingsFmp4ByEncodingId
+   *
+   * @param encoding The encoding where to add the muxing to
+   * @param output The output that should be used for the muxing to write the segments to
+   * @param outputPath The output path where the fragmented segments will be written to
+   * @param stream The stream that is associated with the muxing
+   */
+  private static Fmp4Muxing createFmp4Muxing(
+      Encoding encoding, Output output, String outputPath, Stream stream) throws BitmovinException {
+    MuxingStream muxingStream = new MuxingStream();
+    muxingStream.setStreamId(stream.getId());
+
+    Fmp4Muxing muxing = new Fmp4Muxing();
+    muxing.addOutputsItem(buildEncodingOutput(output, outputPath));
+    muxing.addStreamsItem(muxingStream);
+    muxing.setSegmentLength(4.0);
+    SegmentSharding.apply(configProvider, muxing);
+
+    return bitmovinApi.encoding.encodings.muxings.fmp4.create(encoding.getId(), muxing);
+  }
+
+  /**
+   * Builds an EncodingOutput object which defines where the output content (e.g. of a muxing) will
+   * be written to. Public read permissions will be set for the files written, so they can be
+   * accessed easily via HTTP.
+   *
+   * @param output The output resource to be used by the EncodingOutput
+   * @param outputPath The path where the content will be written to
+   */
+  private static EncodingOutput buildEncodingOutput(Output output, String outputPath) {
+    AclEntry aclEntry = new AclEntry();
+    aclEntry.setPermission(AclPermission.PUBLIC_READ);
+
+    EncodingOutput encodingOutput = new EncodingOutput();
+    encodingOutput.setOutputPath(buildAbsolutePath(outputPath));
+    encodingOutput.setOutputId(output.getId());
+    encodingOutput.addAclItem(aclEntry);
+    return encodingOutput;
+  }
+
+  /**
+   * Builds an absolute path by concatenating the S3_OUTPUT_BASE_PATH configuration parameter, the
+   * name of this example class and the given relative path
+   *
+   * <p>e.g.: /s3/base/path/ClassName/relative/path
+   *
+   * @param relativePath The relative path that is concatenated
+   * @return The absolute path
+   */
+  public static String buildAbsolutePath(String relativePath) {
+    String className = HevcSpeedTuning.class.getSimpleName();
+    return Paths.get(configProvider.getS3OutputBasePath(), className, relativePath).toString();
+  }
+
+  private static void logTaskErrors(Task task) {
+    task.getMessages().stream()
+        .filter(msg -> msg.getType() == MessageType.ERROR)
+        .forEach(msg -> logger.error(msg.getText()));
+  }
+}
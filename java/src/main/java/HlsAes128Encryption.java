@@ -0,0 +1,591 @@
+import com.bitmovin.api.sdk.BitmovinApi;
+import com.bitmovin.api.sdk.common.BitmovinException;
+import com.bitmovin.api.sdk.model.AacAudioConfiguration;
+import com.bitmovin.api.sdk.model.AclEntry;
+import com.bitmovin.api.sdk.model.AclPermission;
+import com.bitmovin.api.sdk.model.AesEncryptionDrm;
+import com.bitmovin.api.sdk.model.AesEncryptionMethod;
+import com.bitmovin.api.sdk.model.AudioMediaInfo;
+import com.bitmovin.api.sdk.model.CodecConfiguration;
+import com.bitmovin.api.sdk.model.Encoding;
+import com.bitmovin.api.sdk.model.EncodingOutput;
+import com.bitmovin.api.sdk.model.H264VideoConfiguration;
+import com.bitmovin.api.sdk.model.HlsManifest;
+import com.bitmovin.api.sdk.model.HttpInput;
+import com.bitmovin.api.sdk.model.Input;
+import com.bitmovin.api.sdk.model.MessageType;
+import com.bitmovin.api.sdk.model.MuxingStream;
+import com.bitmovin.api.sdk.model.Output;
+import com.bitmovin.api.sdk.model.PresetConfiguration;
+import com.bitmovin.api.sdk.model.S3Output;
+import com.bitmovin.api.sdk.model.StartEncodingRequest;
+import com.bitmovin.api.sdk.model.Status;
+import com.bitmovin.api.sdk.model.Stream;
+import com.bitmovin.api.sdk.model.StreamInfo;
+import com.bitmovin.api.sdk.model.StreamInput;
+import com.bitmovin.api.sdk.model.Task;
+import com.bitmovin.api.sdk.model.TsMuxing;
+import common.ApiClientFactory;
+import common.BudgetTag;
+import common.ConfigProvider;
+import common.EncodingFailedException;
+import common.PreviewTrimming;
+import common.SegmentSharding;
+import java.nio.file.Paths;
+import java.util.ArrayList;
+import java.util.Arrays;
+import java.util.List;
+import org.apache.commons.lang3.StringUtils;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import software.amazon.awssdk.auth.credentials.AwsBasicCredentials;
+import software.amazon.awssdk.auth.credentials.StaticCredentialsProvider;
+import software.amazon.awssdk.regions.Region;
+import software.amazon.awssdk.services.s3.S3Client;
+import software.amazon.awssdk.services.s3.model.GetObjectRequest;
+
+/**
+ * This example demonstrates how to protect an HLS stream with AES-128 envelope encryption, where
+ * each TS segment is encrypted as a whole with a static key. Unlike DRM systems, AES-128 only
+ * requires a web server delivering the key to authorized viewers, and is supported by all HLS
+ * players.
+ *
+ * <p>The segments of every rendition are written by TS muxings without outputs, so no unencrypted
+ * content is written to the storage. AES encryption resources added to the muxings encrypt the
+ * segments with the configured key and IV and write them to the output. The HLS manifest references
+ * these resources, so the media playlists contain an EXT-X-KEY tag with the key URI and IV. After
+ * the manifest has been created, the example reads the media playlists from the S3 output bucket
+ * and verifies that every one of them contains the EXT-X-KEY tag.
+ *
+ * <p>The key itself is not written by the encoding. Upload it as a 16 byte binary file to your key
+ * server, so it can be requested from the configured key URI.
+ *
+ * <p>The following configuration parameters are expected:
+ *
+ * <ul>
+ *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin API
+ *   <li>BITMOVIN_TENANT_ORG_ID - (optional) The ID of the Organisation in which you want to perform
+ *       the encoding.
+ *   <li>BITMOVIN_API_BASE_URL - (optional) The base URL of the Bitmovin API, e.g. of a mock server
+ *       or an API gateway. Default: https://api.bitmovin.com/v1
+ *   <li>HTTP_INPUT_HOST - The Hostname or IP address of the HTTP server hosting your input files,
+ *       e.g.: my-storage.biz
+ *   <li>HTTP_INPUT_FILE_PATH - The path to your input file on the provided HTTP server Example:
+ *       videos/1080p_Sintel.mp4
+ *   <li>S3_OUTPUT_BUCKET_NAME - The name of your S3 output bucket. Example: my-bucket-name
+ *   <li>S3_OUTPUT_ACCESS_KEY - The access key of your S3 output bucket
+ *   <li>S3_OUTPUT_SECRET_KEY - The secret key of your S3 output bucket
+ *   <li>S3_OUTPUT_BASE_PATH - The base path on your S3 output bucket where content will be written.
+ *       Example: /outputs
+ *   <li>DRM_KEY - 16 byte encryption key, represented as 32 hexadecimal characters Example:
+ *       cab5b529ae28d5cc5e3e7bc3fd4a544d
+ *   <li>HLS_AES_IV - (optional) 16 byte initialization vector, represented as 32 hexadecimal
+ *       characters. If not set, the media sequence number of each segment is used as IV
+ *   <li>HLS_AES_KEY_URI - The URI the players request the key from. Example:
+ *       https://keys.example.com/stream.key
+ *   <li>HLS_AES_AWS_REGION - (optional) The AWS region of the S3 output bucket, used to read back
+ *       the playlists. Default: us-east-1
+ * </ul>
+ *
+ * <p>Configuration parameters will be retrieved from these sources in the listed order:
+ *
+ * <ol>
+ *   <li>command line arguments (eg BITMOVIN_API_KEY=xyz)
+ *   <li>properties file located in the root folder of the JAVA examples at ./examples.properties
+ *       (see examples.properties.template as reference)
+ *   <li>environment variables
+ *   <li>properties file located in the home folder at ~/.bitmovin/examples.properties (see
+ *       examples.properties.template as reference)
+ * </ol>
+ */
+public class HlsAes128Encryption {
+  private static final Logger logger = LoggerFactory.getLogger(HlsAes128Encryption.class);
+
+  private static BitmovinApi bitmovinApi;
+  private static ConfigProvider configProvider;
+
+  /** This list defines the video renditions that will be generated */
+  private static List<VideoRendition> videoRenditions =
+      Arrays.asList(
+          new VideoRendition(1080, 4_800_000L),
+          new VideoRendition(720, 2_400_000L),
+          new VideoRendition(480, 1_200_000L));
+
+  private static final String AUDIO_GROUP_ID = "audio";
+  private static final String AUDIO_SEGMENT_PATH = "audio";
+  private static final String AUDIO_PLAYLIST = "audio.m3u8";
+
+  public static void main(String[] args) throws Exception {
+    configProvider = new ConfigProvider(args);
+    bitmovinApi =
+        ApiClientFactory.builder(configProvider)
+            .withApiKey(configProvider.getBitmovinApiKey())
+            .build();
+
+    Encoding encoding =
+        createEncoding("HLS AES-128", "Encoding with AES-128 encrypted TS segments");
+
+    HttpInput input = createHttpInput(configProvider.getHttpInputHost());
+    Output output =
+        createS3Output(
+            configProvider.getS3OutputBucketName(),
+            configProvider.getS3OutputAccessKey(),
+            configProvider.getS3OutputSecretKey());
+
+    String inputFilePath = configProvider.getHttpInputFilePath();
+
+    for (VideoRendition videoRendition : videoRenditions) {
+      H264VideoConfiguration videoConfiguration =
+          createH264VideoConfig(videoRendition.height, videoRendition.bitrate);
+      videoRendition.stream = createStream(encoding, input, inputFilePath, videoConfiguration);
+      videoRendition.muxing = createTsMuxing(encoding, videoRendition.stream);
+      videoRendition.drm =
+          createAesEncryptionDrm(
+              encoding, videoRendition.muxing, output, videoRendition.getSegmentPath());
+    }
+
+    AacAudioConfiguration aacConfig = createAacAudioConfig();
+    Stream audioStream = createStream(encoding, input, inputFilePath, aacConfig);
+    TsMuxing audioMuxing = createTsMuxing(encoding, audioStream);
+    AesEncryptionDrm audioDrm =
+        createAesEncryptionDrm(encoding, audioMuxing, output, AUDIO_SEGMENT_PATH);
+
+    executeEncoding(encoding);
+
+    HlsManifest hlsManifest = createHlsMasterManifest("master.m3u8", output, "/");
+    addHlsPlaylists(encoding, hlsManifest, audioStream, audioMuxing, audioDrm);
+    executeHlsManifestCreation(hlsManifest);
+
+    List<String> mediaPlaylists = new ArrayList<>();
+    mediaPlaylists.add(AUDIO_PLAYLIST);
+    for (VideoRendition videoRendition : videoRenditions) {
+      mediaPlaylists.add(videoRendition.getPlaylistName());
+    }
+    verifyKeyTags(mediaPlaylists);
+  }
+
+  /**
+   * Creates a TS muxing. The unencrypted segments will not be written to a permanent storage as
+   * there's no output defined for the muxing. Instead, an output needs to be defined for the DRM
+   * configuration resource which will later be added to this muxing.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsMuxingsTsByEncodingId
+   *
+   * @param encoding The encoding to add the muxing to
+   * @param stream The stream to be muxed
+   */
+  private static TsMuxing createTsMuxing(Encoding encoding, Stream stream)
+      throws BitmovinException {
+    MuxingStream muxingStream = new MuxingStream();
+    muxingStream.setStreamId(stream.getId());
+
+    TsMuxing muxing = new TsMuxing();
+    muxing.addStreamsItem(muxingStream);
+    muxing.setSegmentLength(4.0);
+    SegmentSharding.apply(configProvider, muxing);
+
+    return bitmovinApi.encoding.encodings.muxings.ts.create(encoding.getId(), muxing);
+  }
+
+  /**
+   * Adds an AES-128 encryption configuration with the configured key and IV to a TS muxing
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsMuxingsTsDrmAesByEncodingIdAndMuxingId
+   *
+   * @param encoding The encoding to which the muxing belongs to
+   * @param muxing The muxing to apply the encryption to
+   * @param output The output resource to which the encrypted segments will be written to
+   * @param outputPath The output path where the encrypted segments will be written to
+   */
+  private static AesEncryptionDrm createAesEncryptionDrm(
+      Encoding encoding, TsMuxing muxing, Output output, String outputPath)
+      throws BitmovinException {
+    AesEncryptionDrm aesDrm = new AesEncryptionDrm();
+    aesDrm.addOutputsItem(buildEncodingOutput(output, outputPath));
+    aesDrm.setKey(configProvider.getDrmKey());
+    aesDrm.setIv(configProvider.getParameterByKey("HLS_AES_IV", null));
+    aesDrm.setKeyFileUri(configProvider.getParameterByKey("HLS_AES_KEY_URI"));
+    aesDrm.setMethod(AesEncryptionMethod.AES_128);
+
+    return bitmovinApi.encoding.encodings.muxings.ts.drm.aes.create(
+        encoding.getId(), muxing.getId(), aesDrm);
+  }
+
+  /**
+   * Adds an audio media playlist and a variant stream for each video rendition to the HLS
+   * manifest. Referencing the encryption resources makes the media playlists point to the
+   * encrypted segments and contain the EXT-X-KEY tag.
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsHlsMediaAudioByManifestId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsHlsStreamsByManifestId
+   *
+   * @param encoding The encoding to which the muxings belong to
+   * @param hlsManifest The HLS manifest to add the playlists to
+   * @param audioStream The audio stream
+   * @param audioMuxing The TS muxing of the audio stream
+   * @param audioDrm The AES encryption of the audio muxing
+   */
+  private static void addHlsPlaylists(
+      Encoding encoding,
+      HlsManifest hlsManifest,
+      Stream audioStream,
+      TsMuxing audioMuxing,
+      AesEncryptionDrm audioDrm)
+      throws BitmovinException {
+    AudioMediaInfo audioMediaInfo = new AudioMediaInfo();
+    audioMediaInfo.setName("English");
+    audioMediaInfo.setUri(AUDIO_PLAYLIST);
+    audioMediaInfo.setGroupId(AUDIO_GROUP_ID);
+    audioMediaInfo.setEncodingId(encoding.getId());
+    audioMediaInfo.setStreamId(audioStream.getId());
+    audioMediaInfo.setMuxingId(audioMuxing.getId());
+    audioMediaInfo.setDrmId(audioDrm.getId());
+    audioMediaInfo.setLanguage("en");
+    audioMediaInfo.setAutoselect(true);
+    audioMediaInfo.setIsDefault(true);
+    audioMediaInfo.setSegmentPath(AUDIO_SEGMENT_PATH);
+
+    bitmovinApi.encoding.manifests.hls.media.audio.create(hlsManifest.getId(), audioMediaInfo);
+
+    for (VideoRendition videoRendition : videoRenditions) {
+      StreamInfo streamInfo = new StreamInfo();
+      streamInfo.setUri(videoRendition.getPlaylistName());
+      streamInfo.setEncodingId(encoding.getId());
+      streamInfo.setStreamId(videoRendition.stream.getId());
+      streamInfo.setMuxingId(videoRendition.muxing.getId());
+      streamInfo.setDrmId(videoRendition.drm.getId());
+      streamInfo.setAudio(AUDIO_GROUP_ID);
+      streamInfo.setSegmentPath(videoRendition.getSegmentPath());
+
+      bitmovinApi.encoding.manifests.hls.streams.create(hlsManifest.getId(), streamInfo);
+    }
+  }
+
+  /**
+   * Reads the media playlists from the S3 output bucket and verifies that each of them contains
+   * an EXT-X-KEY tag for AES-128 with the configured key URI
+   *
+   * @param mediaPlaylists The names of the media playlists, relative to the output path of this
+   *     example
+   */
+  private static void verifyKeyTags(List<String> mediaPlaylists) {
+    String expectedTag =
+        String.format(
+            "#EXT-X-KEY:METHOD=AES-128,URI=\"%s\"",
+            configProvider.getParameterByKey("HLS_AES_KEY_URI"));
+
+    StaticCredentialsProvider credentialsProvider =
+        StaticCredentialsProvider.create(
+            AwsBasicCredentials.create(
+                configProvider.getS3OutputAccessKey(), configProvider.getS3OutputSecretKey()));
+    Region region = Region.of(configProvider.getParameterByKey("HLS_AES_AWS_REGION", "us-east-1"));
+
+    try (S3Client s3Client =
+        S3Client.builder().credentialsProvider(credentialsProvider).region(region).build()) {
+      for (String mediaPlaylist : mediaPlaylists) {
+        GetObjectRequest getObjectRequest =
+            GetObjectRequest.builder()
+                .bucket(configProvider.getS3OutputBucketName())
+                .key(StringUtils.removeStart(buildAbsolutePath(mediaPlaylist), "/"))
+                .build();
+        String playlist = s3Client.getObjectAsBytes(getObjectRequest).asUtf8String();
+
+        boolean hasKeyTag = false;
+        for (String line : playlist.split("\n")) {
+          if (line.trim().startsWith(expectedTag)) {
+            hasKeyTag = true;
+            break;
+          }
+        }
+        if (!hasKeyTag) {
+          throw new IllegalStateException(
+              String.format("Media playlist %s does not contain %s", mediaPlaylist, expectedTag));
+        }
+        logger.info("Media playlist {} contains the EXT-X-KEY tag", mediaPlaylist);
+      }
+    }
+  }
+
+  private static class VideoRendition {
+
+    private int height;
+    private long bitrate;
+    private Stream stream;
+    private TsMuxing muxing;
+    private AesEncryptionDrm drm;
+
+    /**
+     * @param height The target output height of the rendition
+     * @param bitrate The target output bitrate of the rendition
+     */
+    private VideoRendition(int height, long bitrate) {
+      this.height = height;
+      this.bitrate = bitrate;
+    }
+
+    private String getSegmentPath() {
+      return "video/" + height;
+    }
+
+    private String getPlaylistName() {
+      return String.format("video_%dp.m3u8", height);
+    }
+  }
+
+  /** Creates the HLS master manifest. */
+  private static HlsManifest createHlsMasterManifest(String name, Output output, String outputPath)
+      throws BitmovinException {
+
+    HlsManifest hlsManifest = new HlsManifest();
+    hlsManifest.setName(name);
+    hlsManifest.addOutputsItem(buildEncodingOutput(output, outputPath));
+
+    return bitmovinApi.encoding.manifests.hls.create(hlsManifest);
+  }
+
+  /**
+   * Creates a resource representing an HTTP server providing the input files. For alternative input
+   * methods see <a
+   * href="https://bitmovin.com/docs/encoding/articles/supported-input-output-storages">list of
+   * supported input and output storages</a>
+   *
+   * <p>For reasons of simplicity, a new input resource is created on each execution of this
+   * example. In production use, this method should be replaced by a <a
+   * href="https://bitmovin.com/docs/encoding/api-reference/sections/inputs#/Encoding/GetEncodingInputsHttpByInputId">get
+   * call</a> to retrieve an existing resource.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/inputs#/Encoding/PostEncodingInputsHttp
+   *
+   * @param host The hostname or IP address of the HTTP server e.g.: my-storage.biz
+   */
+  private static HttpInput createHttpInput(String host) throws BitmovinException {
+    HttpInput input = new HttpInput();
+    input.setHost(host);
+
+    return bitmovinApi.encoding.inputs.http.create(input);
+  }
+
+  /**
+   * Creates a resource representing an AWS S3 cloud storage bucket to which generated content will
+   * be transferred. For alternative output methods see <a
+   * href="https://bitmovin.com/docs/encoding/articles/supported-input-output-storages">list of
+   * supported input and output storages</a>
+   *
+   * <p>The provided credentials need to allow <i>read</i>, <i>write</i> and <i>list</i> operations.
+   * <i>delete</i> should also be granted to allow overwriting of existings files. See <a
+   * href="https://bitmovin.com/docs/encoding/faqs/how-do-i-create-a-aws-s3-bucket-which-can-be-used-as-output-location">creating
+   * an S3 bucket and setting permissions</a> for further information
+   *
+   * <p>For reasons of simplicity, a new output resource is created on each execution of this
+   * example. In production use, this method should be replaced by a <a
+   * href="https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/GetEncodingOutputsS3">get
+   * call</a> retrieving an existing resource.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/PostEncodingOutputsS3
+   *
+   * @param bucketName The name of the S3 bucket
+   * @param accessKey The access key of your S3 account
+   * @param secretKey The secret key of your S3 account
+   */
+  private static S3Output createS3Output(String bucketName, String accessKey, String secretKey)
+      throws BitmovinException {
+
+    S3Output s3Output = new S3Output();
+    s3Output.setBucketName(bucketName);
+    s3Output.setAccessKey(accessKey);
+    s3Output.setSecretKey(secretKey);
+
+    return bitmovinApi.encoding.outputs.s3.create(s3Output);
+  }
+
+  /**
+   * Creates an encoding object. This is the base object to configure your encoding.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodings
+   *
+   * @param name This is the name of the encoding
+   * @param description This is the description of the encoding
+   */
+  private static Encoding createEncoding(String name, String description) throws BitmovinException {
+    Encoding encoding = new Encoding();
+    encoding.setName(name);
+    encoding.setDescription(description);
+    new BudgetTag(configProvider).applyTo(encoding);
+
+    return bitmovinApi.encoding.encodings.create(encoding);
+  }
+
+  /**
+   * Creates a stream which binds an input file to a codec configuration. The stream is used for
+   * muxings later on.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsStreamsByEncodingId
+   *
+   * @param encoding The encoding to add the stream onto
+   * @param input The input that should be used
+   * @param inputPath The path to the input file
+   * @param codecConfiguration The codec configuration to be applied to the stream
+   */
+  private static Stream createStream(
+      Encoding encoding, Input input, String inputPath, CodecConfiguration codecConfiguration)
+      throws BitmovinException {
+    StreamInput streamInput =
+        PreviewTrimming.createStreamInput(bitmovinApi, configProvider, encoding, input, inputPath);
+
+    Stream stream = new Stream();
+    stream.addInputStreamsItem(streamInput);
+    stream.setCodecConfigId(codecConfiguration.getId());
+
+    return bitmovinApi.encoding.encodings.streams.create(encoding.getId(), stream);
+  }
+
+  /**
+   * Creates a configuration for the H.264 video codec to be applied to video streams.
+   *
+   * <p>The output resolution is defined by setting only the height. Width will be determined
+   * automatically to maintain the aspect ratio of your input video.
+   *
+   * <p>To keep things simple, we use a quality-optimized VoD preset configuration, which will apply
+   * proven settings for the codec. See <a
+   * href="https://bitmovin.com/docs/encoding/tutorials/how-to-optimize-your-h264-codec-configuration-for-different-use-cases">How
+   * to optimize your H264 codec configuration for different use-cases</a> for alternative presets.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsVideoH264
+   *
+   * @param height The height of the output video
+   * @param bitrate The target bitrate of the output video
+   */
+  private static H264VideoConfiguration createH264VideoConfig(int height, long bitrate)
+      throws BitmovinException {
+    H264VideoConfiguration config = new H264VideoConfiguration();
+    config.setName(String.format("H.264 %dp", height));
+    config.setPresetConfiguration(PresetConfiguration.VOD_STANDARD);
+    config.setHeight(height);
+    config.setBitrate(bitrate);
+
+    return bitmovinApi.encoding.configurations.video.h264.create(config);
+  }
+
+  /**
+   * Creates a configuration for the AAC audio codec to be applied to audio streams.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsAudioAac
+   */
+  private static AacAudioConfiguration createAacAudioConfig() throws BitmovinException {
+    AacAudioConfiguration config = new AacAudioConfiguration();
+    config.setName("AAC 128 kbit/s");
+    config.setBitrate(128_000L);
+
+    return bitmovinApi.encoding.configurations.audio.aac.create(config);
+  }
+
+  /**
+   * Builds an EncodingOutput object which defines where the output content (e.g. of a muxing) will
+   * be written to. Public read permissions will be set for the files written, so they can be
+   * accessed easily via HTTP.
+   *
+   * @param output The output resource to be used by the EncodingOutput
+   * @param outputPath The path where the content will be written to
+   */
+  private static EncodingOutput buildEncodingOutput(Output output, String outputPath) {
+    AclEntry aclEntry = new AclEntry();
+    aclEntry.setPermission(AclPermission.PUBLIC_READ);
+
+    EncodingOutput encodingOutput = new EncodingOutput();
+    encodingOutput.setOutputPath(buildAbsolutePath(outputPath));
+    encodingOutput.setOutputId(output.getId());
+    encodingOutput.addAclItem(aclEntry);
+    return encodingOutput;
+  }
+
+  /**
+   * Builds an absolute path by concatenating the S3_OUTPUT_BASE_PATH configuration parameter, the
+   * name of this example class and the given relative path
+   *
+   * <p>e.g.: /s3/base/path/ClassName/relative/path
+   *
+   * @param relativePath The relative path that is concatenated
+   * @return The absolute path
+   */
+  public static String buildAbsolutePath(String relativePath) {
+    String className = HlsAes128Encryption.class.getSimpleName();
+    return Paths.get(configProvider.getS3OutputBasePath(), className, relativePath).toString();
+  }
+
+  /**
+   * Starts the actual encoding process and periodically polls its status until it reaches a final
+   * state
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/all#/Encoding/PostEncodingEncodingsStartByEncodingId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/GetEncodingEncodingsStatusByEncodingId
+   *
+   * <p>Please note that you can also use our webhooks API instead of polling the status. For more
+   * information consult the API spec:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/notifications-webhooks
+   *
+   * @param encoding The encoding to be started
+   */
+  private static void executeEncoding(Encoding encoding)
+      throws InterruptedException, BitmovinException {
+    bitmovinApi.encoding.encodings.start(encoding.getId(), new StartEncodingRequest());
+
+    Task task;
+    do {
+      Thread.sleep(5000);
+      task = bitmovinApi.encoding.encodings.status(encoding.getId());
+      logger.info("encoding status is {} (progress: {} %)", task.getStatus(), task.getProgress());
+    } while (task.getStatus() != Status.FINISHED
+        && task.getStatus() != Status.ERROR
+        && task.getStatus() != Status.CANCELED);
+
+    if (task.getStatus() != Status.FINISHED) {
+      logTaskErrors(task);
+      throw new EncodingFailedException(task.getStatus());
+    }
+    logger.info("encoding finished successfully");
+  }
+
+  /**
+   * Starts the HLS manifest creation and periodically polls its status until it reaches a final
+   * state
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsHlsStartByManifestId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/GetEncodingManifestsHlsStatusByManifestId
+   *
+   * @param hlsManifest The HLS manifest to be created
+   */
+  private static void executeHlsManifestCreation(HlsManifest hlsManifest)
+      throws BitmovinException, InterruptedException {
+
+    bitmovinApi.encoding.manifests.hls.start(hlsManifest.getId());
+
+    Task task;
+    do {
+      Thread.sleep(1000);
+      task = bitmovinApi.encoding.manifests.hls.status(hlsManifest.getId());
+    } while (task.getStatus() != Status.FINISHED && task.getStatus() != Status.ERROR);
+
+    if (task.getStatus() == Status.ERROR) {
+      logTaskErrors(task);
+      throw new RuntimeException("HLS manifest creation failed");
+    }
+    logger.info("HLS manifest creation finished successfully");
+  }
+
+  private static void logTaskErrors(Task task) {
+    task.getMessages().stream()
+        .filter(msg -> msg.getType() == MessageType.ERROR)
+        .forEach(msg -> logger.error(msg.getText()));
+  }
+}
@@ -0,0 +1,769 @@
+import com.bitmovin.api.sdk.BitmovinApi;
+import com.bitmovin.api.sdk.common.BitmovinException;
+import com.bitmovin.api.sdk.model.AacAudioConfiguration;
+import com.bitmovin.api.sdk.model.AclEntry;
+import com.bitmovin.api.sdk.model.AclPermission;
+import com.bitmovin.api.sdk.model.AesEncryptionDrm;
+import com.bitmovin.api.sdk.model.AesEncryptionMethod;
+import com.bitmovin.api.sdk.model.CodecConfiguration;
+import com.bitmovin.api.sdk.model.Encoding;
+import com.bitmovin.api.sdk.model.EncodingOutput;
+import com.bitmovin.api.sdk.model.H264VideoConfiguration;
+import com.bitmovin.api.sdk.model.HlsManifest;
+import com.bitmovin.api.sdk.model.HttpInput;
+import com.bitmovin.api.sdk.model.IngestInputStream;
+import com.bitmovin.api.sdk.model.Input;
+import com.bitmovin.api.sdk.model.InputStream;
+import com.bitmovin.api.sdk.model.MessageType;
+import com.bitmovin.api.sdk.model.MuxingStream;
+import com.bitmovin.api.sdk.model.Output;
+import com.bitmovin.api.sdk.model.PresetConfiguration;
+import com.bitmovin.api.sdk.model.S3Output;
+import com.bitmovin.api.sdk.model.StartEncodingRequest;
+import com.bitmovin.api.sdk.model.Status;
+import com.bitmovin.api.sdk.model.Stream;
+import com.bitmovin.api.sdk.model.StreamInfo;
+import com.bitmovin.api.sdk.model.StreamInput;
+import com.bitmovin.api.sdk.model.StreamMode;
+import com.bitmovin.api.sdk.model.StreamSelectionMode;
+import com.bitmovin.api.sdk.model.Task;
+import com.bitmovin.api.sdk.model.TimeBasedTrimmingInputStream;
+import com.bitmovin.api.sdk.model.TsMuxing;
+import common.ApiClientFactory;
+import common.BudgetTag;
+import common.ConfigProvider;
+import common.EncodingFailedException;
+import common.PreviewTrimming;
+import common.SegmentSharding;
+import java.nio.charset.StandardCharsets;
+import java.nio.file.Paths;
+import java.security.SecureRandom;
+import java.util.ArrayList;
+import java.util.Arrays;
+import java.util.List;
+import java.util.Locale;
+import org.apache.commons.lang3.StringUtils;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import software.amazon.awssdk.auth.credentials.AwsBasicCredentials;
+import software.amazon.awssdk.auth.credentials.StaticCredentialsProvider;
+import software.amazon.awssdk.core.sync.RequestBody;
+import software.amazon.awssdk.regions.Region;
+import software.amazon.awssdk.services.s3.S3Client;
+import software.amazon.awssdk.services.s3.model.GetObjectRequest;
+import software.amazon.awssdk.services.s3.model.PutObjectRequest;
+
+/**
+ * This example demonstrates how to rotate the AES encryption key of a VoD HLS stream every N
+ * segments, which limits the amount of content exposed if a single key leaks.
+ *
+ * <p>The input is split into consecutive time ranges of N segments each, using time-based trimming
+ * input streams. Every time range gets its own TS muxing, encrypted with a newly generated AES key.
+ * The key files are written to a separate "keys" prefix of the S3 output bucket, which should not
+ * be publicly accessible, but served by a key server that authorizes the viewers.
+ *
+ * <p>As every muxing carries a different key, the HLS media playlist is assembled by this example.
+ * The Bitmovin API writes a media playlist per key period first, which lists the segments that
+ * have actually been created and their durations. These playlists are combined into a single one,
+ * with an EXT-X-KEY tag at every key boundary, preceded by an EXT-X-DISCONTINUITY tag, as the
+ * timestamps of every time range start at zero.
+ *
+ * <p>The following configuration parameters are expected:
+ *
+ * <ul>
+ *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin API
+ *   <li>BITMOVIN_TENANT_ORG_ID - (optional) The ID of the Organisation in which you want to perform
+ *       the encoding.
+ *   <li>BITMOVIN_API_BASE_URL - (optional) The base URL of the Bitmovin API, e.g. of a mock server
+ *       or an API gateway. Default: https://api.bitmovin.com/v1
+ *   <li>HTTP_INPUT_HOST - The Hostname or IP address of the HTTP server hosting your input files,
+ *       e.g.: my-storage.biz
+ *   <li>HTTP_INPUT_FILE_PATH - The path to your input file on the provided HTTP server Example:
+ *       videos/1080p_Sintel.mp4
+ *   <li>S3_OUTPUT_BUCKET_NAME - The name of your S3 output bucket. Example: my-bucket-name
+ *   <li>S3_OUTPUT_ACCESS_KEY - The access key of your S3 output bucket
+ *   <li>S3_OUTPUT_SECRET_KEY - The secret key of your S3 output bucket
+ *   <li>S3_OUTPUT_BASE_PATH - The base path on your S3 output bucket where content will be written.
+ *       Example: /outputs
+ *   <li>KEY_ROTATION_INPUT_DURATION - The duration of the input file in seconds, used to split it
+ *       into key periods. Example: 888
+ *   <li>KEY_ROTATION_SEGMENTS - (optional) The number of segments after which the key is rotated.
+ *       Default: 10
+ *   <li>KEY_ROTATION_ENCRYPTION_METHOD - (optional) AES_128 or SAMPLE_AES. Default: AES_128
+ *   <li>KEY_ROTATION_KEY_URI_PREFIX - (optional) The URI prefix of the key files used in the
+ *       playlist, e.g. the URL of your key server. Default: keys
+ *   <li>KEY_ROTATION_AWS_REGION - (optional) The AWS region of the S3 output bucket. Default:
+ *       us-east-1
+ * </ul>
+ *
+ * <p>Configuration parameters will be retrieved from these sources in the listed order:
+ *
+ * <ol>
+ *   <li>command line arguments (eg BITMOVIN_API_KEY=xyz)
+ *   <li>properties file located in the root folder of the JAVA examples at ./examples.properties
+ *       (see examples.properties.template as reference)
+ *   <li>environment variables
+ *   <li>properties file located in the home folder at ~/.bitmovin/examples.properties (see
+ *       examples.properties.template as reference)
+ * </ol>
+ */
+public class HlsAesKeyRotation {
+  private static final Logger logger = LoggerFactory.getLogger(HlsAesKeyRotation.class);
+
+  private static final SecureRandom secureRandom = new SecureRandom();
+
+  private static BitmovinApi bitmovinApi;
+  private static ConfigProvider configProvider;
+
+  /** The length of each TS segment in seconds */
+  private static final double segmentLength = 4.0;
+
+  public static void main(String[] args) throws Exception {
+    configProvider = new ConfigProvider(args);
+    bitmovinApi =
+        ApiClientFactory.builder(configProvider)
+            .withApiKey(configProvider.getBitmovinApiKey())
+            .build();
+
+    double inputDuration =
+        Double.parseDouble(configProvider.getParameterByKey("KEY_ROTATION_INPUT_DURATION"));
+    Double previewDuration = PreviewTrimming.getPreviewDuration(configProvider);
+    if (previewDuration != null) {
+      // the input is trimmed per key period already, so only the key periods are limited
+      inputDuration = Math.min(inputDuration, previewDuration);
+    }
+    int segmentsPerKey =
+        Integer.parseInt(configProvider.getParameterByKey("KEY_ROTATION_SEGMENTS", "10"));
+    AesEncryptionMethod encryptionMethod =
+        AesEncryptionMethod.valueOf(
+            configProvider.getParameterByKey("KEY_ROTATION_ENCRYPTION_METHOD", "AES_128"));
+    String keyUriPrefix =
+        StringUtils.removeEnd(
+            configProvider.getParameterByKey("KEY_ROTATION_KEY_URI_PREFIX", "keys"), "/");
+
+    Encoding encoding =
+        createEncoding("HLS AES key rotation", "Encrypted HLS stream with rotating AES keys");
+
+    HttpInput input = createHttpInput(configProvider.getHttpInputHost());
+    Output output =
+        createS3Output(
+            configProvider.getS3OutputBucketName(),
+            configProvider.getS3OutputAccessKey(),
+            configProvider.getS3OutputSecretKey());
+
+    IngestInputStream ingestInputStream =
+        createIngestInputStream(encoding, input, configProvider.getHttpInputFilePath());
+
+    H264VideoConfiguration h264Config = createH264VideoConfig(1080, 4_800_000L);
+    AacAudioConfiguration aacConfig = createAacAudioConfig();
+
+    double keyPeriod = segmentsPerKey * segmentLength;
+    List<KeyPeriod> keyPeriods = new ArrayList<>();
+
+    for (int index = 0; index * keyPeriod < inputDuration; index++) {
+      KeyPeriod period =
+          new KeyPeriod(
+              index, index * keyPeriod, Math.min(keyPeriod, inputDuration - index * keyPeriod));
+
+      InputStream trimmedInputStream =
+          createTimeBasedTrimmingInputStream(
+              encoding, ingestInputStream, period.offset, period.duration);
+      Stream videoStream = createStream(encoding, trimmedInputStream, h264Config);
+      Stream audioStream = createStream(encoding, trimmedInputStream, aacConfig);
+
+      period.muxing = createTsMuxing(encoding, Arrays.asList(videoStream, audioStream), period);
+      period.drm =
+          createAesEncryptionDrm(
+              encoding, period.muxing, output, "segments", encryptionMethod, period);
+
+      keyPeriods.add(period);
+    }
+
+    executeEncoding(encoding);
+
+    HlsManifest periodManifest = createPeriodManifest(encoding, output, keyPeriods);
+    executeHlsManifestCreation(periodManifest);
+
+    StaticCredentialsProvider credentialsProvider =
+        StaticCredentialsProvider.create(
+            AwsBasicCredentials.create(
+                configProvider.getS3OutputAccessKey(), configProvider.getS3OutputSecretKey()));
+    Region region =
+        Region.of(configProvider.getParameterByKey("KEY_ROTATION_AWS_REGION", "us-east-1"));
+
+    try (S3Client s3Client =
+        S3Client.builder().credentialsProvider(credentialsProvider).region(region).build()) {
+      for (KeyPeriod period : keyPeriods) {
+        uploadFile(
+            s3Client,
+            "keys/" + period.getKeyFileName(),
+            RequestBody.fromBytes(period.key),
+            "application/octet-stream");
+      }
+
+      for (KeyPeriod period : keyPeriods) {
+        period.segments = readSegments(s3Client, period.getPlaylistName());
+      }
+
+      String playlist = buildMediaPlaylist(keyPeriods, encryptionMethod, keyUriPrefix);
+      uploadFile(
+          s3Client,
+          "stream.m3u8",
+          RequestBody.fromString(playlist, StandardCharsets.UTF_8),
+          "application/vnd.apple.mpegurl");
+    }
+  }
+
+  /**
+   * Creates an HLS manifest with a media playlist per key period. The playlists are only used to
+   * retrieve the segments of every key period and their durations, which are not known before the
+   * encoding has finished.
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsHls
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsHlsStreamsByManifestId
+   *
+   * @param encoding The encoding the key periods belong to
+   * @param output The output resource to which the playlists will be written to
+   * @param keyPeriods The key periods for which a playlist is created
+   */
+  private static HlsManifest createPeriodManifest(
+      Encoding encoding, Output output, List<KeyPeriod> keyPeriods) throws BitmovinException {
+    HlsManifest hlsManifest = new HlsManifest();
+    hlsManifest.setName("HLS key periods");
+    hlsManifest.setManifestName("periods.m3u8");
+    hlsManifest.addOutputsItem(buildEncodingOutput(output, "periods"));
+    hlsManifest = bitmovinApi.encoding.manifests.hls.create(hlsManifest);
+
+    for (KeyPeriod period : keyPeriods) {
+      StreamInfo streamInfo = new StreamInfo();
+      streamInfo.setUri(period.getPlaylistName());
+      streamInfo.setEncodingId(encoding.getId());
+      streamInfo.setStreamId(period.muxing.getStreams().get(0).getStreamId());
+      streamInfo.setMuxingId(period.muxing.getId());
+      streamInfo.setDrmId(period.drm.getId());
+      streamInfo.setSegmentPath("../segments");
+
+      bitmovinApi.encoding.manifests.hls.streams.create(hlsManifest.getId(), streamInfo);
+    }
+
+    return hlsManifest;
+  }
+
+  /**
+   * Reads the segments and their durations from a media playlist written by the Bitmovin API
+   *
+   * @param s3Client The S3 client used to read the playlist
+   * @param playlistName The name of the playlist within the "periods" folder
+   */
+  private static List<Segment> readSegments(S3Client s3Client, String playlistName) {
+    String objectKey = StringUtils.removeStart(buildAbsolutePath("periods/" + playlistName), "/");
+    GetObjectRequest getObjectRequest =
+        GetObjectRequest.builder()
+            .bucket(configProvider.getS3OutputBucketName())
+            .key(objectKey)
+            .build();
+    String playlist = s3Client.getObjectAsBytes(getObjectRequest).asUtf8String();
+
+    List<Segment> segments = new ArrayList<>();
+    Double duration = null;
+    for (String line : playlist.split("\\r?\\n")) {
+      if (line.startsWith("#EXTINF:")) {
+        duration = Double.parseDouble(StringUtils.substringBefore(line.substring(8), ",").trim());
+      } else if (duration != null && !line.isEmpty() && !line.startsWith("#")) {
+        // the segment URIs are relative to the "periods" folder
+        segments.add(new Segment(StringUtils.removeStart(line, "../"), duration));
+        duration = null;
+      }
+    }
+
+    if (segments.isEmpty()) {
+      throw new IllegalStateException("No segments found in " + objectKey);
+    }
+    return segments;
+  }
+
+  /**
+   * Builds the HLS media playlist, which references the segments of all key periods. An EXT-X-KEY
+   * tag is written at the start of every key period, so players switch to the next key exactly at
+   * the key boundary.
+   *
+   * @param keyPeriods The key periods in playback order, with the segments read from their
+   *     playlists
+   * @param encryptionMethod The encryption method used for all key periods
+   * @param keyUriPrefix The URI prefix of the key files
+   */
+  private static String buildMediaPlaylist(
+      List<KeyPeriod> keyPeriods, AesEncryptionMethod encryptionMethod, String keyUriPrefix) {
+    boolean sampleAes = encryptionMethod == AesEncryptionMethod.SAMPLE_AES;
+    String method = sampleAes ? "SAMPLE-AES" : "AES-128";
+
+    double maxSegmentDuration =
+        keyPeriods.stream()
+            .flatMap(period -> period.segments.stream())
+            .mapToDouble(segment -> segment.duration)
+            .max()
+            .orElse(segmentLength);
+
+    StringBuilder playlist = new StringBuilder();
+    playlist.append("#EXTM3U\n");
+    // SAMPLE-AES requires protocol version 5
+    playlist.append(String.format("#EXT-X-VERSION:%d\n", sampleAes ? 5 : 3));
+    playlist.append(
+        String.format("#EXT-X-TARGETDURATION:%d\n", Math.round(Math.ceil(maxSegmentDuration))));
+    playlist.append("#EXT-X-MEDIA-SEQUENCE:0\n");
+    playlist.append("#EXT-X-PLAYLIST-TYPE:VOD\n");
+
+    for (KeyPeriod period : keyPeriods) {
+      // the timestamps of every key period start at zero, as it is encoded from a separate stream
+      if (period.index > 0) {
+        playlist.append("#EXT-X-DISCONTINUITY\n");
+      }
+      playlist.append(
+          String.format(
+              "#EXT-X-KEY:METHOD=%s,URI=\"%s/%s\",IV=0x%s\n",
+              method, keyUriPrefix, period.getKeyFileName(), period.iv));
+
+      for (Segment segment : period.segments) {
+        playlist.append(String.format(Locale.US, "#EXTINF:%.3f,\n", segment.duration));
+        playlist.append(segment.uri).append("\n");
+      }
+    }
+
+    playlist.append("#EXT-X-ENDLIST\n");
+    return playlist.toString();
+  }
+
+  /**
+   * Writes a file to the S3 output bucket, relative to the output path of this example
+   *
+   * @param s3Client The S3 client used to write the file
+   * @param relativePath The path of the file relative to the output path of this example
+   * @param content The content of the file
+   * @param contentType The content type of the file
+   */
+  private static void uploadFile(
+      S3Client s3Client, String relativePath, RequestBody content, String contentType) {
+    String objectKey = StringUtils.removeStart(buildAbsolutePath(relativePath), "/");
+
+    PutObjectRequest putObjectRequest =
+        PutObjectRequest.builder()
+            .bucket(configProvider.getS3OutputBucketName())
+            .key(objectKey)
+            .contentType(contentType)
+            .build();
+    s3Client.putObject(putObjectRequest, content);
+    logger.info("Wrote s3://{}/{}", configProvider.getS3OutputBucketName(), objectKey);
+  }
+
+  /**
+   * Creates an IngestInputStream and adds it to an encoding
+   *
+   * <p>The IngestInputStream is used to define where a file to read a stream from is located
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsInputStreamsIngestByEncodingId
+   *
+   * @param encoding The encoding to which the stream will be added
+   * @param input The input resource providing the input file
+   * @param inputPath The path to the input file
+   */
+  private static IngestInputStream createIngestInputStream(
+      Encoding encoding, Input input, String inputPath) throws BitmovinException {
+    IngestInputStream ingestInputStream = new IngestInputStream();
+    ingestInputStream.setInputId(input.getId());
+    ingestInputStream.setInputPath(inputPath);
+    ingestInputStream.setSelectionMode(StreamSelectionMode.AUTO);
+
+    return bitmovinApi.encoding.encodings.inputStreams.ingest.create(
+        encoding.getId(), ingestInputStream);
+  }
+
+  /**
+   * Creates a TimeBasedTrimmingInputStream, which only uses a time range of the given input stream
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsInputStreamsTrimmingTimeBasedByEncodingId
+   *
+   * @param encoding The encoding to which the input stream will be added
+   * @param inputStream The input stream to be trimmed
+   * @param offset The start of the time range in seconds
+   * @param duration The duration of the time range in seconds
+   */
+  private static TimeBasedTrimmingInputStream createTimeBasedTrimmingInputStream(
+      Encoding encoding, InputStream inputStream, double offset, double duration)
+      throws BitmovinException {
+    TimeBasedTrimmingInputStream trimmingInputStream = new TimeBasedTrimmingInputStream();
+    trimmingInputStream.setInputStreamId(inputStream.getId());
+    trimmingInputStream.setOffset(offset);
+    trimmingInputStream.setDuration(duration);
+
+    return bitmovinApi.encoding.encodings.inputStreams.trimming.timeBased.create(
+        encoding.getId(), trimmingInputStream);
+  }
+
+  /**
+   * Adds a video or audio stream to an encoding, by mapping a codec configuration to an input
+   * stream
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsStreamsByEncodingId
+   *
+   * @param encoding The encoding to which the stream will be added
+   * @param inputStream The inputStream resource providing the input file
+   * @param codecConfiguration The codec configuration to be applied to the stream
+   */
+  private static Stream createStream(
+      Encoding encoding, InputStream inputStream, CodecConfiguration codecConfiguration)
+      throws BitmovinException {
+    StreamInput streamInput = new StreamInput();
+    streamInput.setInputStreamId(inputStream.getId());
+
+    Stream stream = new Stream();
+    stream.addInputStreamsItem(streamInput);
+    stream.setCodecConfigId(codecConfiguration.getId());
+    stream.setMode(StreamMode.STANDARD);
+
+    return bitmovinApi.encoding.encodings.streams.create(encoding.getId(), stream);
+  }
+
+  /**
+   * Creates a TS muxing for a key period. The unencrypted segments will not be written to a
+   * permanent storage as there's no output defined for the muxing. Instead, an output needs to be
+   * defined for the DRM configuration resource which will later be added to this muxing. The
+   * segment names contain the index of the key period, so that the segments of all key periods can
+   * be written to the same folder.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsMuxingsTsByEncodingId
+   *
+   * @param encoding The encoding to add the muxing to
+   * @param streams The streams to be muxed
+   * @param period The key period the muxing belongs to
+   */
+  private static TsMuxing createTsMuxing(
+      Encoding encoding, List<Stream> streams, KeyPeriod period) throws BitmovinException {
+    TsMuxing muxing = new TsMuxing();
+    muxing.setSegmentLength(segmentLength);
+    muxing.setSegmentNaming(period.getSegmentName("%number%"));
+    SegmentSharding.apply(configProvider, muxing);
+
+    for (Stream stream : streams) {
+      MuxingStream muxingStream = new MuxingStream();
+      muxingStream.setStreamId(stream.getId());
+      muxing.addStreamsItem(muxingStream);
+    }
+
+    return bitmovinApi.encoding.encodings.muxings.ts.create(encoding.getId(), muxing);
+  }
+
+  /**
+   * Adds an AES encryption configuration with the key of the key period to the muxing.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsMuxingsTsDrmAesByEncodingIdAndMuxingId
+   *
+   * @param encoding The encoding to which the muxing belongs to
+   * @param muxing The muxing to apply the encryption to
+   * @param output The output resource to which the encrypted segments will be written to
+   * @param outputPath The output path where the encrypted segments will be written to
+   * @param encryptionMethod The AES encryption method
+   * @param period The key period providing the key and IV
+   */
+  private static AesEncryptionDrm createAesEncryptionDrm(
+      Encoding encoding,
+      TsMuxing muxing,
+      Output output,
+      String outputPath,
+      AesEncryptionMethod encryptionMethod,
+      KeyPeriod period)
+      throws BitmovinException {
+    AesEncryptionDrm aesDrm = new AesEncryptionDrm();
+    aesDrm.addOutputsItem(buildEncodingOutput(output, outputPath));
+    aesDrm.setKey(toHexString(period.key));
+    aesDrm.setIv(period.iv);
+    aesDrm.setMethod(encryptionMethod);
+
+    return bitmovinApi.encoding.encodings.muxings.ts.drm.aes.create(
+        encoding.getId(), muxing.getId(), aesDrm);
+  }
+
+  private static String toHexString(byte[] bytes) {
+    StringBuilder hex = new StringBuilder();
+    for (byte b : bytes) {
+      hex.append(String.format("%02x", b));
+    }
+    return hex.toString();
+  }
+
+  private static class KeyPeriod {
+
+    private int index;
+    private double offset;
+    private double duration;
+    private byte[] key = new byte[16];
+    private String iv;
+    private TsMuxing muxing;
+    private AesEncryptionDrm drm;
+    private List<Segment> segments;
+
+    /**
+     * Creates a key period with a newly generated random key and IV
+     *
+     * @param index The index of the key period
+     * @param offset The start of the key period in seconds
+     * @param duration The duration of the key period in seconds
+     */
+    private KeyPeriod(int index, double offset, double duration) {
+      this.index = index;
+      this.offset = offset;
+      this.duration = duration;
+
+      byte[] ivBytes = new byte[16];
+      secureRandom.nextBytes(key);
+      secureRandom.nextBytes(ivBytes);
+      this.iv = toHexString(ivBytes);
+    }
+
+    private String getKeyFileName() {
+      return String.format("key_%d.key", index);
+    }
+
+    private String getSegmentName(Object segmentNumber) {
+      return String.format("segment_%d_%s.ts", index, segmentNumber);
+    }
+
+    private String getPlaylistName() {
+      return String.format("period_%d.m3u8", index);
+    }
+  }
+
+  private static class Segment {
+
+    private String uri;
+    private double duration;
+
+    private Segment(String uri, double duration) {
+      this.uri = uri;
+      this.duration = duration;
+    }
+  }
+
+  /**
+   * Creates a resource representing an HTTP server providing the input files. For alternative input
+   * methods see <a
+   * href="https://bitmovin.com/docs/encoding/articles/supported-input-output-storages">list of
+   * supported input and output storages</a>
+   *
+   * <p>For reasons of simplicity, a new input resource is created on each execution of this
+   * example. In production use, this method should be replaced by a <a
+   * href="https://bitmovin.com/docs/encoding/api-reference/sections/inputs#/Encoding/GetEncodingInputsHttpByInputId">get
+   * call</a> to retrieve an existing resource.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/inputs#/Encoding/PostEncodingInputsHttp
+   *
+   * @param host The hostname or IP address of the HTTP server e.g.: my-storage.biz
+   */
+  private static HttpInput createHttpInput(String host) throws BitmovinException {
+    HttpInput input = new HttpInput();
+    input.setHost(host);
+
+    return bitmovinApi.encoding.inputs.http.create(input);
+  }
+
+  /**
+   * Creates a resource representing an AWS S3 cloud storage bucket to which generated content will
+   * be transferred. For alternative output methods see <a
+   * href="https://bitmovin.com/docs/encoding/articles/supported-input-output-storages">list of
+   * supported input and output storages</a>
+   *
+   * <p>The provided credentials need to allow <i>read</i>, <i>write</i> and <i>list</i> operations.
+   * <i>delete</i> should also be granted to allow overwriting of existings files. See <a
+   * href="https://bitmovin.com/docs/encoding/faqs/how-do-i-create-a-aws-s3-bucket-which-can-be-used-as-output-location">creating
+   * an S3 bucket and setting permissions</a> for further information
+   *
+   * <p>For reasons of simplicity, a new output resource is created on each execution of this
+   * example. In production use, this method should be replaced by a <a
+   * href="https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/GetEncodingOutputsS3">get
+   * call</a> retrieving an existing resource.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/PostEncodingOutputsS3
+   *
+   * @param bucketName The name of the S3 bucket
+   * @param accessKey The access key of your S3 account
+   * @param secretKey The secret key of your S3 account
+   */
+  private static S3Output createS3Output(String bucketName, String accessKey, String secretKey)
+      throws BitmovinException {
+
+    S3Output s3Output = new S3Output();
+    s3Output.setBucketName(bucketName);
+    s3Output.setAccessKey(accessKey);
+    s3Output.setSecretKey(secretKey);
+
+    return bitmovinApi.encoding.outputs.s3.create(s3Output);
+  }
+
+  /**
+   * Creates an encoding object. This is the base object to configure your encoding.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodings
+   *
+   * @param name This is the name of the encoding
+   * @param description This is the description of the encoding
+   */
+  private static Encoding createEncoding(String name, String description) throws BitmovinException {
+    Encoding encoding = new Encoding();
+    encoding.setName(name);
+    encoding.setDescription(description);
+    new BudgetTag(configProvider).applyTo(encoding);
+
+    return bitmovinApi.encoding.encodings.create(encoding);
+  }
+
+  /**
+   * Creates a configuration for the H.264 video codec to be applied to video streams.
+   *
+   * <p>The output resolution is defined by setting only the height. Width will be determined
+   * automatically to maintain the aspect ratio of your input video.
+   *
+   * <p>To keep things simple, we use a quality-optimized VoD preset configuration, which will apply
+   * proven settings for the codec. See <a
+   * href="https://bitmovin.com/docs/encoding/tutorials/how-to-optimize-your-h264-codec-configuration-for-different-use-cases">How
+   * to optimize your H264 codec configuration for different use-cases</a> for alternative presets.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsVideoH264
+   *
+   * @param height The height of the output video
+   * @param bitrate The target bitrate of the output video
+   */
+  private static H264VideoConfiguration createH264VideoConfig(int height, long bitrate)
+      throws BitmovinException {
+    H264VideoConfiguration config = new H264VideoConfiguration();
+    config.setName(String.format("H.264 %dp", height));
+    config.setPresetConfiguration(PresetConfiguration.VOD_STANDARD);
+    config.setHeight(height);
+    config.setBitrate(bitrate);
+
+    return bitmovinApi.encoding.configurations.video.h264.create(config);
+  }
+
+  /**
+   * Creates a configuration for the AAC audio codec to be applied to audio streams.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsAudioAac
+   */
+  private static AacAudioConfiguration createAacAudioConfig() throws BitmovinException {
+    AacAudioConfiguration config = new AacAudioConfiguration();
+    config.setName("AAC 128 kbit/s");
+    config.setBitrate(128_000L);
+
+    return bitmovinApi.encoding.configurations.audio.aac.create(config);
+  }
+
+  /**
+   * Builds an EncodingOutput object which defines where the output content (e.g. of a muxing) will
+   * be written to. Public read permissions will be set for the files written, so they can be
+   * accessed easily via HTTP.
+   *
+   * @param output The output resource to be used by the EncodingOutput
+   * @param outputPath The path where the content will be written to
+   */
+  private static EncodingOutput buildEncodingOutput(Output output, String outputPath) {
+    AclEntry aclEntry = new AclEntry();
+    aclEntry.setPermission(AclPermission.PUBLIC_READ);
+
+    EncodingOutput encodingOutput = new EncodingOutput();
+    encodingOutput.setOutputPath(buildAbsolutePath(outputPath));
+    encodingOutput.setOutputId(output.getId());
+    encodingOutput.addAclItem(aclEntry);
+    return encodingOutput;
+  }
+
+  /**
+   * Builds an absolute path by concatenating the S3_OUTPUT_BASE_PATH configuration parameter, the
+   * name of this example class and the given relative path
+   *
+   * <p>e.g.: /s3/base/path/ClassName/relative/path
+   *
+   * @param relativePath The relative path that is concatenated
+   * @return The absolute path
+   */
+  public static String buildAbsolutePath(String relativePath) {
+    String className = HlsAesKeyRotation.class.getSimpleName();
+    return Paths.get(configProvider.getS3OutputBasePath(), className, relativePath).toString();
+  }
+
+  /**
+   * Starts the actual encoding process and periodically polls its status until it reaches a final
+   * state
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/all#/Encoding/PostEncodingEncodingsStartByEncodingId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/GetEncodingEncodingsStatusByEncodingId
+   *
+   * <p>Please note that you can also use our webhooks API instead of polling the status. For more
+   * information consult the API spec:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/notifications-webhooks
+   *
+   * @param encoding The encoding to be started
+   */
+  private static void executeEncoding(Encoding encoding)
+      throws InterruptedException, BitmovinException {
+    bitmovinApi.encoding.encodings.start(encoding.getId(), new StartEncodingRequest());
+
+    Task task;
+    do {
+      Thread.sleep(5000);
+      task = bitmovinApi.encoding.encodings.status(encoding.getId());
+      logger.info("encoding status is {} (progress: {} %)", task.getStatus(), task.getProgress());
+    } while (task.getStatus() != Status.FINISHED
+        && task.getStatus() != Status.ERROR
+        && task.getStatus() != Status.CANCELED);
+
+    if (task.getStatus() != Status.FINISHED) {
+      logTaskErrors(task);
+      throw new EncodingFailedException(task.getStatus());
+    }
+    logger.info("encoding finished successfully");
+  }
+
+  /**
+   * Starts the HLS manifest creation and periodically polls its status until it reaches a final
+   * state
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsHlsStartByManifestId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/GetEncodingManifestsHlsStatusByManifestId
+   *
+   * @param hlsManifest The HLS manifest to be created
+   */
+  private static void executeHlsManifestCreation(HlsManifest hlsManifest)
+      throws BitmovinException, InterruptedException {
+
+    bitmovinApi.encoding.manifests.hls.start(hlsManifest.getId());
+
+    Task task;
+    do {
+      Thread.sleep(1000);
+      task = bitmovinApi.encoding.manifests.hls.status(hlsManifest.getId());
+    } while (task.getStatus() != Status.FINISHED && task.getStatus() != Status.ERROR);
+
+    if (task.getStatus() == Status.ERROR) {
+      logTaskErrors(task);
+      throw new RuntimeException("HLS manifest creation failed");
+    }
+    logger.info("HLS manifest creation finished successfully");
+  }
+
+  private static void logTaskErrors(Task task) {
+    task.getMessages().stream()
+        .filter(msg -> msg.getType() == MessageType.ERROR)
+        .forEach(msg -> logger.error(msg.getText()));
+  }
+}
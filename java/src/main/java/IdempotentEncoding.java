@@ -0,0 +1,480 @@
+import com.bitmovin.api.sdk.BitmovinApi;
+import com.bitmovin.api.sdk.common.BitmovinException;
+import com.bitmovin.api.sdk.encoding.configurations.audio.aac.AacAudioConfigurationListQueryParams;
+import com.bitmovin.api.sdk.encoding.configurations.video.h264.H264VideoConfigurationListQueryParams;
+import com.bitmovin.api.sdk.encoding.encodings.EncodingListQueryParams;
+import com.bitmovin.api.sdk.encoding.encodings.muxings.mp4.Mp4MuxingListQueryParams;
+import com.bitmovin.api.sdk.encoding.encodings.streams.StreamListQueryParams;
+import com.bitmovin.api.sdk.encoding.inputs.http.HttpInputListQueryParams;
+import com.bitmovin.api.sdk.encoding.outputs.s3.S3OutputListQueryParams;
+import com.bitmovin.api.sdk.model.AacAudioConfiguration;
+import com.bitmovin.api.sdk.model.AclEntry;
+import com.bitmovin.api.sdk.model.AclPermission;
+import com.bitmovin.api.sdk.model.CodecConfiguration;
+import com.bitmovin.api.sdk.model.Encoding;
+import com.bitmovin.api.sdk.model.EncodingOutput;
+import com.bitmovin.api.sdk.model.H264VideoConfiguration;
+import com.bitmovin.api.sdk.model.HttpInput;
+import com.bitmovin.api.sdk.model.Input;
+import com.bitmovin.api.sdk.model.MessageType;
+import com.bitmovin.api.sdk.model.Mp4Muxing;
+import com.bitmovin.api.sdk.model.MuxingStream;
+import com.bitmovin.api.sdk.model.Output;
+import com.bitmovin.api.sdk.model.PaginationResponse;
+import com.bitmovin.api.sdk.model.PresetConfiguration;
+import com.bitmovin.api.sdk.model.S3Output;
+import com.bitmovin.api.sdk.model.StartEncodingRequest;
+import com.bitmovin.api.sdk.model.Status;
+import com.bitmovin.api.sdk.model.Stream;
+import com.bitmovin.api.sdk.model.StreamInput;
+import com.bitmovin.api.sdk.model.Task;
+import common.ApiClientFactory;
+import common.BudgetTag;
+import common.ConfigProvider;
+import common.EncodingFailedException;
+import common.IdempotentResources;
+import common.PreviewTrimming;
+import java.nio.file.Paths;
+import java.util.Arrays;
+import java.util.List;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+
+/**
+ * This example demonstrates how to make an encoding workflow retry-safe. If the example is
+ * interrupted (e.g. by a network issue or a crash) and run again with the same configuration, it
+ * resumes with the resources that were already created instead of duplicating everything from
+ * scratch.
+ *
+ * <p>An idempotency key is derived from the configuration of the example using {@link
+ * IdempotentResources}. It is appended to the names of all created resources and added to their
+ * custom data. Before a resource is created, the existing resources are searched for a matching
+ * name, and reused if found. Depending on the status of a reused encoding, it is either started,
+ * awaited or skipped because it has already finished.
+ *
+ * <p>To start over with a fresh set of resources for the same input, set IDEMPOTENCY_SEED to a new
+ * value.
+ *
+ * <p>The following configuration parameters are expected:
+ *
+ * <ul>
+ *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin API
+ *   <li>BITMOVIN_TENANT_ORG_ID - (optional) The ID of the Organisation in which you want to perform
+ *       the encoding.
+ *   <li>BITMOVIN_API_BASE_URL - (optional) The base URL of the Bitmovin API, e.g. of a mock server
+ *       or an API gateway. Default: https://api.bitmovin.com/v1
+ *   <li>HTTP_INPUT_HOST - The Hostname or IP address of the HTTP server hosting your input files,
+ *       e.g.: my-storage.biz
+ *   <li>HTTP_INPUT_FILE_PATH - The path to your input file on the provided HTTP server Example:
+ *       videos/1080p_Sintel.mp4
+ *   <li>S3_OUTPUT_BUCKET_NAME - The name of your S3 output bucket. Example: my-bucket-name
+ *   <li>S3_OUTPUT_ACCESS_KEY - The access key of your S3 output bucket
+ *   <li>S3_OUTPUT_SECRET_KEY - The secret key of your S3 output bucket
+ *   <li>S3_OUTPUT_BASE_PATH - The base path on your S3 output bucket where content will be written.
+ *       Example: /outputs
+ *   <li>IDEMPOTENCY_SEED - (optional) An additional value the idempotency key is derived from.
+ *       Example: run-2
+ * </ul>
+ *
+ * <p>Configuration parameters will be retrieved from these sources in the listed order:
+ *
+ * <ol>
+ *   <li>command line arguments (eg BITMOVIN_API_KEY=xyz)
+ *   <li>properties file located in the root folder of the JAVA examples at ./examples.properties
+ *       (see examples.properties.template as reference)
+ *   <li>environment variables
+ *   <li>properties file located in the home folder at ~/.bitmovin/examples.properties (see
+ *       examples.properties.template as reference)
+ * </ol>
+ */
+public class IdempotentEncoding {
+  private static final Logger logger = LoggerFactory.getLogger(IdempotentEncoding.class);
+
+  private static BitmovinApi bitmovinApi;
+  private static ConfigProvider configProvider;
+  private static IdempotentResources idempotentResources;
+
+  public static void main(String[] args) throws Exception {
+    configProvider = new ConfigProvider(args);
+    bitmovinApi =
+        ApiClientFactory.builder(configProvider)
+            .withApiKey(configProvider.getBitmovinApiKey())
+            .build();
+
+    idempotentResources =
+        new IdempotentResources(
+            IdempotentEncoding.class.getSimpleName(),
+            configProvider.getHttpInputHost(),
+            configProvider.getHttpInputFilePath(),
+            configProvider.getS3OutputBucketName(),
+            configProvider.getS3OutputBasePath(),
+            configProvider.getParameterByKey("IDEMPOTENCY_SEED", ""));
+
+    Encoding encoding =
+        findOrCreateEncoding(
+            "Idempotent encoding", "Encoding that can be resumed after an interruption");
+
+    Status status = bitmovinApi.encoding.encodings.status(encoding.getId()).getStatus();
+    if (status == Status.FINISHED) {
+      logger.info("Encoding {} has already finished, nothing to do", encoding.getId());
+      return;
+    }
+    if (status == Status.QUEUED || status == Status.RUNNING) {
+      logger.info("Encoding {} has already been started, waiting for it", encoding.getId());
+      waitForEncodingToFinish(encoding);
+      return;
+    }
+    if (status != Status.CREATED) {
+      throw new IllegalStateException(
+          String.format(
+              "Encoding %s is in status %s and cannot be resumed. "
+                  + "Set IDEMPOTENCY_SEED to a new value to start over.",
+              encoding.getId(), status));
+    }
+
+    HttpInput input = findOrCreateHttpInput(configProvider.getHttpInputHost());
+    Output output =
+        findOrCreateS3Output(
+            configProvider.getS3OutputBucketName(),
+            configProvider.getS3OutputAccessKey(),
+            configProvider.getS3OutputSecretKey());
+
+    String inputFilePath = configProvider.getHttpInputFilePath();
+
+    AacAudioConfiguration aacConfig = findOrCreateAacAudioConfig();
+    Stream audioStream = findOrCreateStream(encoding, input, inputFilePath, aacConfig, "Audio");
+
+    List<Integer> heights = Arrays.asList(1080, 720, 480);
+    List<Long> bitrates = Arrays.asList(4_800_000L, 2_400_000L, 1_200_000L);
+
+    for (int i = 0; i < heights.size(); i++) {
+      int height = heights.get(i);
+      H264VideoConfiguration videoConfig = findOrCreateH264VideoConfig(height, bitrates.get(i));
+      Stream videoStream =
+          findOrCreateStream(
+              encoding, input, inputFilePath, videoConfig, String.format("Video %dp", height));
+      findOrCreateMp4Muxing(
+          encoding,
+          output,
+          "/",
+          Arrays.asList(videoStream, audioStream),
+          String.format("video_%dp.mp4", height));
+    }
+
+    bitmovinApi.encoding.encodings.start(encoding.getId(), new StartEncodingRequest());
+    waitForEncodingToFinish(encoding);
+  }
+
+  /**
+   * Returns the encoding created by a previous run of this example with the same configuration, or
+   * creates a new one.
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/GetEncodingEncodings
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodings
+   *
+   * @param name This is the name of the encoding, the idempotency key will be appended
+   * @param description This is the description of the encoding
+   */
+  private static Encoding findOrCreateEncoding(String name, String description)
+      throws BitmovinException {
+    return idempotentResources.findOrCreate(
+        idempotentResources.buildName(name),
+        existingName -> {
+          EncodingListQueryParams queryParams = new EncodingListQueryParams();
+          queryParams.setName(existingName);
+          return bitmovinApi.encoding.encodings.list(queryParams).getItems();
+        },
+        Encoding::getName,
+        newName -> {
+          Encoding encoding = new Encoding();
+          encoding.setName(newName);
+          encoding.setDescription(description);
+          encoding.setCustomData(idempotentResources.buildCustomData());
+          new BudgetTag(configProvider).applyTo(encoding);
+
+          return bitmovinApi.encoding.encodings.create(encoding);
+        });
+  }
+
+  /**
+   * Returns the HTTP input created by a previous run of this example with the same configuration,
+   * or creates a new one.
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/inputs#/Encoding/GetEncodingInputsHttp
+   * https://bitmovin.com/docs/encoding/api-reference/sections/inputs#/Encoding/PostEncodingInputsHttp
+   *
+   * @param host The hostname or IP address of the HTTP server e.g.: my-storage.biz
+   */
+  private static HttpInput findOrCreateHttpInput(String host) throws BitmovinException {
+    return idempotentResources.findOrCreate(
+        idempotentResources.buildName("HTTP input " + host),
+        existingName -> {
+          HttpInputListQueryParams queryParams = new HttpInputListQueryParams();
+          queryParams.setName(existingName);
+          return bitmovinApi.encoding.inputs.http.list(queryParams).getItems();
+        },
+        HttpInput::getName,
+        newName -> {
+          HttpInput input = new HttpInput();
+          input.setName(newName);
+          input.setHost(host);
+          input.setCustomData(idempotentResources.buildCustomData());
+
+          return bitmovinApi.encoding.inputs.http.create(input);
+        });
+  }
+
+  /**
+   * Returns the S3 output created by a previous run of this example with the same configuration, or
+   * creates a new one.
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/GetEncodingOutputsS3
+   * https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/PostEncodingOutputsS3
+   *
+   * @param bucketName The name of the S3 bucket
+   * @param accessKey The access key of your S3 account
+   * @param secretKey The secret key of your S3 account
+   */
+  private static S3Output findOrCreateS3Output(
+      String bucketName, String accessKey, String secretKey) throws BitmovinException {
+    return idempotentResources.findOrCreate(
+        idempotentResources.buildName("S3 output " + bucketName),
+        existingName -> {
+          S3OutputListQueryParams queryParams = new S3OutputListQueryParams();
+          queryParams.setName(existingName);
+          return bitmovinApi.encoding.outputs.s3.list(queryParams).getItems();
+        },
+        S3Output::getName,
+        newName -> {
+          S3Output s3Output = new S3Output();
+          s3Output.setName(newName);
+          s3Output.setBucketName(bucketName);
+          s3Output.setAccessKey(accessKey);
+          s3Output.setSecretKey(secretKey);
+          s3Output.setCustomData(idempotentResources.buildCustomData());
+
+          return bitmovinApi.encoding.outputs.s3.create(s3Output);
+        });
+  }
+
+  /**
+   * Returns the H.264 video configuration created by a previous run of this example with the same
+   * configuration, or creates a new one.
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/GetEncodingConfigurationsVideoH264
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsVideoH264
+   *
+   * @param height The height of the output video
+   * @param bitrate The target bitrate of the output video
+   */
+  private static H264VideoConfiguration findOrCreateH264VideoConfig(int height, long bitrate)
+      throws BitmovinException {
+    return idempotentResources.findOrCreate(
+        idempotentResources.buildName(String.format("H.264 %dp", height)),
+        existingName -> {
+          H264VideoConfigurationListQueryParams queryParams =
+              new H264VideoConfigurationListQueryParams();
+          queryParams.setName(existingName);
+          return bitmovinApi.encoding.configurations.video.h264.list(queryParams).getItems();
+        },
+        H264VideoConfiguration::getName,
+        newName -> {
+          H264VideoConfiguration config = new H264VideoConfiguration();
+          config.setName(newName);
+          config.setPresetConfiguration(PresetConfiguration.VOD_STANDARD);
+          config.setHeight(height);
+          config.setBitrate(bitrate);
+          config.setCustomData(idempotentResources.buildCustomData());
+
+          return bitmovinApi.encoding.configurations.video.h264.create(config);
+        });
+  }
+
+  /**
+   * Returns the AAC audio configuration created by a previous run of this example with the same
+   * configuration, or creates a new one.
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/GetEncodingConfigurationsAudioAac
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsAudioAac
+   */
+  private static AacAudioConfiguration findOrCreateAacAudioConfig() throws BitmovinException {
+    return idempotentResources.findOrCreate(
+        idempotentResources.buildName("AAC 128 kbit/s"),
+        existingName -> {
+          AacAudioConfigurationListQueryParams queryParams =
+              new AacAudioConfigurationListQueryParams();
+          queryParams.setName(existingName);
+          return bitmovinApi.encoding.configurations.audio.aac.list(queryParams).getItems();
+        },
+        AacAudioConfiguration::getName,
+        newName -> {
+          AacAudioConfiguration config = new AacAudioConfiguration();
+          config.setName(newName);
+          config.setBitrate(128_000L);
+          config.setCustomData(idempotentResources.buildCustomData());
+
+          return bitmovinApi.encoding.configurations.audio.aac.create(config);
+        });
+  }
+
+  /**
+   * Returns the stream of the encoding which was added by a previous run of this example, or adds a
+   * new one. Streams are only listed for the given encoding, so their names are not filtered by the
+   * API.
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/GetEncodingEncodingsStreamsByEncodingId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsStreamsByEncodingId
+   *
+   * @param encoding The encoding to add the stream onto
+   * @param input The input that should be used
+   * @param inputPath The path to the input file
+   * @param codecConfiguration The codec configuration to be applied to the stream
+   * @param name The name of the stream, the idempotency key will be appended
+   */
+  private static Stream findOrCreateStream(
+      Encoding encoding,
+      Input input,
+      String inputPath,
+      CodecConfiguration codecConfiguration,
+      String name)
+      throws BitmovinException {
+    return idempotentResources.findOrCreate(
+        idempotentResources.buildName(name),
+        existingName -> {
+          StreamListQueryParams queryParams = new StreamListQueryParams();
+          queryParams.setLimit(100);
+          PaginationResponse<Stream> page =
+              bitmovinApi.encoding.encodings.streams.list(encoding.getId(), queryParams);
+          return page.getItems();
+        },
+        Stream::getName,
+        newName -> {
+          StreamInput streamInput =
+              PreviewTrimming.createStreamInput(
+                  bitmovinApi, configProvider, encoding, input, inputPath);
+
+          Stream stream = new Stream();
+          stream.setName(newName);
+          stream.addInputStreamsItem(streamInput);
+          stream.setCodecConfigId(codecConfiguration.getId());
+          stream.setCustomData(idempotentResources.buildCustomData());
+
+          return bitmovinApi.encoding.encodings.streams.create(encoding.getId(), stream);
+        });
+  }
+
+  /**
+   * Returns the MP4 muxing of the encoding which was added by a previous run of this example, or
+   * adds a new one. The file name is used as the name of the muxing.
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/GetEncodingEncodingsMuxingsMp4ByEncodingId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsMuxingsMp4ByEncodingId
+   *
+   * @param encoding The encoding to add the MP4 muxing to
+   * @param output The output that should be used for the muxing to write the file to
+   * @param outputPath The output path where the file will be written to
+   * @param streams A list of streams to be added to the muxing
+   * @param fileName The name of the file that will be written to the output
+   */
+  private static Mp4Muxing findOrCreateMp4Muxing(
+      Encoding encoding, Output output, String outputPath, List<Stream> streams, String fileName)
+      throws BitmovinException {
+    return idempotentResources.findOrCreate(
+        idempotentResources.buildName(fileName),
+        existingName -> {
+          Mp4MuxingListQueryParams queryParams = new Mp4MuxingListQueryParams();
+          queryParams.setLimit(100);
+          PaginationResponse<Mp4Muxing> page =
+              bitmovinApi.encoding.encodings.muxings.mp4.list(encoding.getId(), queryParams);
+          return page.getItems();
+        },
+        Mp4Muxing::getName,
+        newName -> {
+          Mp4Muxing muxing = new Mp4Muxing();
+          muxing.setName(newName);
+          muxing.addOutputsItem(buildEncodingOutput(output, outputPath));
+          muxing.setFilename(fileName);
+          muxing.setCustomData(idempotentResources.buildCustomData());
+
+          for (Stream stream : streams) {
+            MuxingStream muxingStream = new MuxingStream();
+            muxingStream.setStreamId(stream.getId());
+            muxing.addStreamsItem(muxingStream);
+          }
+
+          return bitmovinApi.encoding.encodings.muxings.mp4.create(encoding.getId(), muxing);
+        });
+  }
+
+  /**
+   * Periodically polls the status of an encoding until it reaches a final state
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/GetEncodingEncodingsStatusByEncodingId
+   *
+   * @param encoding The encoding to wait for
+   */
+  private static void waitForEncodingToFinish(Encoding encoding)
+      throws InterruptedException, BitmovinException {
+    Task task;
+    do {
+      Thread.sleep(5000);
+      task = bitmovinApi.encoding.encodings.status(encoding.getId());
+      logger.info("encoding status is {} (progress: {} %)", task.getStatus(), task.getProgress());
+    } while (task.getStatus() != Status.FINISHED
+        && task.getStatus() != Status.ERROR
+        && task.getStatus() != Status.CANCELED);
+
+    if (task.getStatus() != Status.FINISHED) {
+      logTaskErrors(task);
+      throw new EncodingFailedException(task.getStatus());
+    }
+    logger.info("encoding finished successfully");
+  }
+
+  /**
+   * Builds an EncodingOutput object which defines where the output content (e.g. of a muxing) will
+   * be written to. Public read permissions will be set for the files written, so they can be
+   * accessed easily via HTTP.
+   *
+   * @param output The output resource to be used by the EncodingOutput
+   * @param outputPath The path where the content will be written to
+   */
+  private static EncodingOutput buildEncodingOutput(Output output, String outputPath) {
+    AclEntry aclEntry = new AclEntry();
+    aclEntry.setPermission(AclPermission.PUBLIC_READ);
+
+    EncodingOutput encodingOutput = new EncodingOutput();
+    encodingOutput.setOutputPath(buildAbsolutePath(outputPath));
+    encodingOutput.setOutputId(output.getId());
+    encodingOutput.addAclItem(aclEntry);
+    return encodingOutput;
+  }
+
+  /**
+   * Builds an absolute path by concatenating the S3_OUTPUT_BASE_PATH configuration parameter, the
+   * name of this example class and the given relative path
+   *
+   * <p>e.g.: /s3/base/path/ClassName/relative/path
+   *
+   * @param relativePath The relative path that is concatenated
+   * @return The absolute path
+   */
+  public static String buildAbsolutePath(String relativePath) {
+    String className = IdempotentEncoding.class.getSimpleName();
+    return Paths.get(configProvider.getS3OutputBasePath(), className, relativePath).toString();
+  }
+
+  private static void logTaskErrors(Task task) {
+    task.getMessages().stream()
+        .filter(msg -> msg.getType() == MessageType.ERROR)
+        .forEach(msg -> logger.error(msg.getText()));
+  }
+}
@@ -0,0 +1,765 @@
+import com.bitmovin.api.sdk.BitmovinApi;
+import com.bitmovin.api.sdk.common.BitmovinException;
+import com.bitmovin.api.sdk.encoding.encodings.EncodingListQueryParams;
+import com.bitmovin.api.sdk.model.AacAudioConfiguration;
+import com.bitmovin.api.sdk.model.AclEntry;
+import com.bitmovin.api.sdk.model.AclPermission;
+import com.bitmovin.api.sdk.model.CodecConfiguration;
+import com.bitmovin.api.sdk.model.DashManifest;
+import com.bitmovin.api.sdk.model.DashManifestDefault;
+import com.bitmovin.api.sdk.model.DashManifestDefaultVersion;
+import com.bitmovin.api.sdk.model.Encoding;
+import com.bitmovin.api.sdk.model.EncodingOutput;
+import com.bitmovin.api.sdk.model.Fmp4Muxing;
+import com.bitmovin.api.sdk.model.H264VideoConfiguration;
+import com.bitmovin.api.sdk.model.HlsManifest;
+import com.bitmovin.api.sdk.model.HlsManifestDefault;
+import com.bitmovin.api.sdk.model.HlsManifestDefaultVersion;
+import com.bitmovin.api.sdk.model.HttpInput;
+import com.bitmovin.api.sdk.model.Input;
+import com.bitmovin.api.sdk.model.MessageType;
+import com.bitmovin.api.sdk.model.MuxingStream;
+import com.bitmovin.api.sdk.model.Output;
+import com.bitmovin.api.sdk.model.PresetConfiguration;
+import com.bitmovin.api.sdk.model.S3Output;
+import com.bitmovin.api.sdk.model.StartEncodingRequest;
+import com.bitmovin.api.sdk.model.Status;
+import com.bitmovin.api.sdk.model.Stream;
+import com.bitmovin.api.sdk.model.StreamInput;
+import com.bitmovin.api.sdk.model.Task;
+import com.fasterxml.jackson.databind.JsonNode;
+import com.fasterxml.jackson.databind.ObjectMapper;
+import common.ApiClientFactory;
+import common.BudgetTag;
+import common.ConfigProvider;
+import common.EncodingFailedException;
+import common.PreviewTrimming;
+import common.SegmentSharding;
+import java.io.IOException;
+import java.nio.file.Paths;
+import java.time.Duration;
+import java.util.Arrays;
+import java.util.Collections;
+import java.util.LinkedHashMap;
+import java.util.List;
+import java.util.Map;
+import java.util.Properties;
+import org.apache.kafka.clients.consumer.CommitFailedException;
+import org.apache.kafka.clients.consumer.ConsumerConfig;
+import org.apache.kafka.clients.consumer.ConsumerRecord;
+import org.apache.kafka.clients.consumer.ConsumerRecords;
+import org.apache.kafka.clients.consumer.KafkaConsumer;
+import org.apache.kafka.clients.consumer.OffsetAndMetadata;
+import org.apache.kafka.clients.producer.KafkaProducer;
+import org.apache.kafka.clients.producer.ProducerConfig;
+import org.apache.kafka.clients.producer.ProducerRecord;
+import org.apache.kafka.common.TopicPartition;
+import org.apache.kafka.common.errors.WakeupException;
+import org.apache.kafka.common.serialization.StringDeserializer;
+import org.apache.kafka.common.serialization.StringSerializer;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+
+/**
+ * This example demonstrates an encoding worker for streaming platforms that coordinate their
+ * backends via Apache Kafka. The worker consumes encode jobs from a Kafka topic, runs an encoding
+ * for each of them and produces the outcome to a result topic, so other services never talk to the
+ * Bitmovin API directly. Any number of workers can be started with the same consumer group, Kafka
+ * distributes the partitions of the job topic among them.
+ *
+ * <p>A job is a JSON message with the following fields:
+ *
+ * <ul>
+ *   <li>jobId - A unique ID of the job, used as the key of the result message
+ *   <li>inputPath - The path to the input file on HTTP_INPUT_HOST
+ *   <li>outputPath - (optional) The path below S3_OUTPUT_BASE_PATH the output is written to.
+ *       Default: the job ID
+ * </ul>
+ *
+ * <p>The result is a JSON message with the jobId, the encodingId, the final status of the encoding
+ * (FINISHED, ERROR or CANCELED, or INVALID for malformed jobs) and an error message if the job
+ * failed.
+ *
+ * <p>Jobs are handled at least once: offsets are committed manually, only after the result of a job
+ * has been produced. If the worker crashes or loses its partitions while an encoding is running,
+ * the job is delivered again, to this or another worker. To not encode the same job twice,
+ * encodings are named after the job ID, and a redelivered job resumes with the existing encoding if
+ * it is queued, running or finished. Jobs are processed one at a time. While an encoding runs, the
+ * partitions are paused and the consumer keeps polling, so it stays in the consumer group no matter
+ * how long the encoding takes.
+ *
+ * <p>Transient errors, e.g. of the Bitmovin API, are not reported as a result. Instead, the job is
+ * retried after a short delay. The worker runs until it is stopped with Ctrl+C.
+ *
+ * <p>The following configuration parameters are expected:
+ *
+ * <ul>
+ *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin API
+ *   <li>BITMOVIN_TENANT_ORG_ID - (optional) The ID of the Organisation in which you want to perform
+ *       the encoding.
+ *   <li>BITMOVIN_API_BASE_URL - (optional) The base URL of the Bitmovin API, e.g. of a mock server
+ *       or an API gateway. Default: https://api.bitmovin.com/v1
+ *   <li>HTTP_INPUT_HOST - The Hostname or IP address of the HTTP server hosting your input files,
+ *       e.g.: my-storage.biz
+ *   <li>S3_OUTPUT_BUCKET_NAME - The name of your S3 output bucket. Example: my-bucket-name
+ *   <li>S3_OUTPUT_ACCESS_KEY - The access key of your S3 output bucket
+ *   <li>S3_OUTPUT_SECRET_KEY - The secret key of your S3 output bucket
+ *   <li>S3_OUTPUT_BASE_PATH - The base path on your S3 output bucket where content will be written.
+ *       Example: /outputs
+ *   <li>KAFKA_BOOTSTRAP_SERVERS - The Kafka brokers to connect to. Example:
+ *       kafka-1:9092,kafka-2:9092
+ *   <li>KAFKA_JOBS_TOPIC - (optional) The topic the jobs are consumed from. Default: encoding-jobs
+ *   <li>KAFKA_RESULTS_TOPIC - (optional) The topic the results are produced to. Default:
+ *       encoding-results
+ *   <li>KAFKA_CONSUMER_GROUP - (optional) The consumer group of the workers. Default:
+ *       bitmovin-encoding-worker
+ * </ul>
+ *
+ * <p>Configuration parameters will be retrieved from these sources in the listed order:
+ *
+ * <ol>
+ *   <li>command line arguments (eg BITMOVIN_API_KEY=xyz)
+ *   <li>properties file located in the root folder of the JAVA examples at ./examples.properties
+ *       (see examples.properties.template as reference)
+ *   <li>environment variables
+ *   <li>properties file located in the home folder at ~/.bitmovin/examples.properties (see
+ *       examples.properties.template as reference)
+ * </ol>
+ */
+public class KafkaEncodingWorker {
+  private static final Logger logger = LoggerFactory.getLogger(KafkaEncodingWorker.class);
+
+  private static BitmovinApi bitmovinApi;
+  private static ConfigProvider configProvider;
+
+  private static final ObjectMapper objectMapper = new ObjectMapper();
+
+  /** The prefix of the encoding names, followed by the job ID */
+  private static final String ENCODING_NAME_PREFIX = "kafka-job-";
+
+  private static final Duration POLL_TIMEOUT = Duration.ofSeconds(5);
+  private static final Duration RETRY_DELAY = Duration.ofSeconds(30);
+
+  /** This list defines the video renditions that will be generated */
+  private static List<VideoRendition> videoRenditions =
+      Arrays.asList(
+          new VideoRendition(1080, 4_800_000L),
+          new VideoRendition(720, 2_400_000L),
+          new VideoRendition(480, 1_200_000L));
+
+  public static void main(String[] args) throws Exception {
+    configProvider = new ConfigProvider(args);
+    bitmovinApi =
+        ApiClientFactory.builder(configProvider)
+            .withApiKey(configProvider.getBitmovinApiKey())
+            .build();
+
+    HttpInput input = createHttpInput(configProvider.getHttpInputHost());
+    Output output =
+        createS3Output(
+            configProvider.getS3OutputBucketName(),
+            configProvider.getS3OutputAccessKey(),
+            configProvider.getS3OutputSecretKey());
+
+    String jobsTopic = configProvider.getParameterByKey("KAFKA_JOBS_TOPIC", "encoding-jobs");
+    String resultsTopic =
+        configProvider.getParameterByKey("KAFKA_RESULTS_TOPIC", "encoding-results");
+
+    try (KafkaConsumer<String, String> consumer = createConsumer();
+        KafkaProducer<String, String> producer = createProducer()) {
+      // wakeup() makes a blocking poll() throw a WakeupException, so the worker can leave the
+      // consumer group cleanly instead of waiting for the session to time out
+      Thread mainThread = Thread.currentThread();
+      Runtime.getRuntime()
+          .addShutdownHook(
+              new Thread(
+                  () -> {
+                    consumer.wakeup();
+                    try {
+                      mainThread.join();
+                    } catch (InterruptedException e) {
+                      Thread.currentThread().interrupt();
+                    }
+                  }));
+
+      consumer.subscribe(Collections.singletonList(jobsTopic));
+      logger.info("Waiting for jobs on topic {}", jobsTopic);
+
+      while (true) {
+        for (ConsumerRecord<String, String> record : consumer.poll(POLL_TIMEOUT)) {
+          handleRecord(consumer, producer, resultsTopic, record, input, output);
+        }
+      }
+    } catch (WakeupException e) {
+      logger.info("Worker stopped");
+    }
+  }
+
+  /**
+   * Handles a single job and commits its offset once the result has been produced. If the job
+   * could not be handled due to a transient error, the consumer is rewound to the job, so it is
+   * polled again after a delay.
+   *
+   * @param consumer The consumer the job has been polled with
+   * @param producer The producer used for the result
+   * @param resultsTopic The topic the result is produced to
+   * @param record The job message
+   * @param input The input the input files are read from
+   * @param output The output the encoded files are written to
+   */
+  private static void handleRecord(
+      KafkaConsumer<String, String> consumer,
+      KafkaProducer<String, String> producer,
+      String resultsTopic,
+      ConsumerRecord<String, String> record,
+      Input input,
+      Output output)
+      throws Exception {
+    TopicPartition partition = new TopicPartition(record.topic(), record.partition());
+    logger.info("Received job at offset {} of {}", record.offset(), partition);
+
+    consumer.pause(consumer.assignment());
+    try {
+      Map<String, Object> result = processJob(consumer, record.value(), input, output);
+      String jobId = (String) result.get("jobId");
+      producer
+          .send(
+              new ProducerRecord<>(
+                  resultsTopic, jobId, objectMapper.writeValueAsString(result)))
+          .get();
+      logger.info("Produced result of job {} with status {}", jobId, result.get("status"));
+
+      consumer.commitSync(
+          Collections.singletonMap(partition, new OffsetAndMetadata(record.offset() + 1)));
+    } catch (CommitFailedException e) {
+      // the partition has been assigned to another worker in the meantime, which receives the job
+      // again and finds the existing encoding
+      logger.warn("Could not commit the offset of the job, it will be delivered again", e);
+    } catch (WakeupException e) {
+      throw e;
+    } catch (Exception e) {
+      logger.error("Job failed with a transient error, retrying in {}", RETRY_DELAY, e);
+      consumer.seek(partition, record.offset());
+      waitWhilePaused(consumer, RETRY_DELAY);
+    } finally {
+      consumer.resume(consumer.paused());
+    }
+  }
+
+  /**
+   * Processes a job message and returns the result message. Jobs that cannot be parsed or whose
+   * encoding failed are reported in the result, all other errors are thrown.
+   *
+   * @param consumer The consumer to keep polling while the encoding runs
+   * @param message The job message
+   * @param input The input the input file is read from
+   * @param output The output the encoded files are written to
+   */
+  private static Map<String, Object> processJob(
+      KafkaConsumer<String, String> consumer, String message, Input input, Output output)
+      throws Exception {
+    Map<String, Object> result = new LinkedHashMap<>();
+
+    JsonNode job;
+    try {
+      job = objectMapper.readTree(message);
+    } catch (IOException e) {
+      job = null;
+    }
+    if (job == null || !job.hasNonNull("jobId") || !job.hasNonNull("inputPath")) {
+      logger.warn("Skipping malformed job: {}", message);
+      result.put("jobId", job != null ? job.path("jobId").asText(null) : null);
+      result.put("status", "INVALID");
+      result.put("error", "A job requires a jobId and an inputPath");
+      return result;
+    }
+
+    String jobId = job.get("jobId").asText();
+    String inputPath = job.get("inputPath").asText();
+    String outputPath = job.hasNonNull("outputPath") ? job.get("outputPath").asText() : jobId;
+    result.put("jobId", jobId);
+
+    Encoding encoding = findEncoding(jobId);
+    if (encoding != null) {
+      logger.info("Resuming job {} with existing encoding {}", jobId, encoding.getId());
+    } else {
+      encoding = createAndStartEncoding(jobId, input, inputPath, output, outputPath);
+      logger.info("Started encoding {} for job {}", encoding.getId(), jobId);
+    }
+    result.put("encodingId", encoding.getId());
+
+    try {
+      waitForEncoding(consumer, encoding);
+    } catch (EncodingFailedException e) {
+      result.put("status", e.getStatus().toString());
+      result.put("error", e.getMessage());
+      return result;
+    }
+
+    generateDashManifest(encoding, output, outputPath);
+    generateHlsManifest(encoding, output, outputPath);
+
+    result.put("status", Status.FINISHED.toString());
+    return result;
+  }
+
+  /**
+   * Searches for an encoding of a job that has already been delivered before. Encodings that were
+   * not started or did not finish are ignored, so a new encoding is created for the job.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/GetEncodingEncodings
+   *
+   * @param jobId The ID of the job
+   */
+  private static Encoding findEncoding(String jobId) throws BitmovinException {
+    EncodingListQueryParams queryParams = new EncodingListQueryParams();
+    queryParams.setName(ENCODING_NAME_PREFIX + jobId);
+
+    for (Encoding encoding : bitmovinApi.encoding.encodings.list(queryParams).getItems()) {
+      if (encoding.getStatus() == Status.QUEUED
+          || encoding.getStatus() == Status.RUNNING
+          || encoding.getStatus() == Status.FINISHED) {
+        return encoding;
+      }
+    }
+    return null;
+  }
+
+  /**
+   * Creates an encoding for a job with an H.264 ladder and AAC audio, and starts it
+   *
+   * @param jobId The ID of the job, the encoding is named after
+   * @param input The input the input file is read from
+   * @param inputPath The path to the input file
+   * @param output The output the encoded files are written to
+   * @param outputPath The path the encoded files are written to
+   */
+  private static Encoding createAndStartEncoding(
+      String jobId, Input input, String inputPath, Output output, String outputPath)
+      throws BitmovinException {
+    Encoding encoding =
+        createEncoding(ENCODING_NAME_PREFIX + jobId, "Encoding of Kafka job " + jobId);
+
+    for (VideoRendition videoRendition : videoRenditions) {
+      H264VideoConfiguration videoConfiguration =
+          createH264VideoConfig(videoRendition.height, videoRendition.bitrate);
+      Stream videoStream = createStream(encoding, input, inputPath, videoConfiguration);
+      createFmp4Muxing(
+          encoding, output, outputPath + "/video/" + videoRendition.height, videoStream);
+    }
+
+    AacAudioConfiguration aacConfig = createAacAudioConfig();
+    Stream audioStream = createStream(encoding, input, inputPath, aacConfig);
+    createFmp4Muxing(encoding, output, outputPath + "/audio", audioStream);
+
+    bitmovinApi.encoding.encodings.start(encoding.getId(), new StartEncodingRequest());
+    return encoding;
+  }
+
+  /**
+   * Periodically polls the status of the encoding until it reaches a final state. Instead of
+   * sleeping, the paused consumer is polled in between, which keeps the worker in the consumer
+   * group.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/GetEncodingEncodingsStatusByEncodingId
+   *
+   * @param consumer The paused consumer
+   * @param encoding The encoding to wait for
+   */
+  private static void waitForEncoding(KafkaConsumer<String, String> consumer, Encoding encoding)
+      throws BitmovinException {
+    Task task;
+    do {
+      waitWhilePaused(consumer, POLL_TIMEOUT);
+      task = bitmovinApi.encoding.encodings.status(encoding.getId());
+      logger.info("encoding status is {} (progress: {} %)", task.getStatus(), task.getProgress());
+    } while (task.getStatus() != Status.FINISHED
+        && task.getStatus() != Status.ERROR
+        && task.getStatus() != Status.CANCELED);
+
+    if (task.getStatus() != Status.FINISHED) {
+      logTaskErrors(task);
+      throw new EncodingFailedException(task.getStatus());
+    }
+    logger.info("encoding finished successfully");
+  }
+
+  /**
+   * Polls the consumer for the given duration without processing any jobs. Partitions assigned
+   * during a rebalance are not paused yet, so jobs polled from them are rewound to be polled again
+   * later.
+   *
+   * @param consumer The consumer to poll
+   * @param duration How long to wait
+   */
+  private static void waitWhilePaused(KafkaConsumer<String, String> consumer, Duration duration) {
+    long deadline = System.currentTimeMillis() + duration.toMillis();
+    while (System.currentTimeMillis() < deadline) {
+      consumer.pause(consumer.assignment());
+      ConsumerRecords<String, String> records = consumer.poll(POLL_TIMEOUT);
+      for (TopicPartition partition : records.partitions()) {
+        consumer.seek(partition, records.records(partition).get(0).offset());
+      }
+    }
+  }
+
+  /**
+   * Creates a consumer that commits offsets manually, and only fetches a single job per poll, as
+   * jobs are processed one at a time
+   */
+  private static KafkaConsumer<String, String> createConsumer() {
+    Properties properties = new Properties();
+    properties.put(
+        ConsumerConfig.BOOTSTRAP_SERVERS_CONFIG,
+        configProvider.getParameterByKey("KAFKA_BOOTSTRAP_SERVERS"));
+    properties.put(
+        ConsumerConfig.GROUP_ID_CONFIG,
+        configProvider.getParameterByKey("KAFKA_CONSUMER_GROUP", "bitmovin-encoding-worker"));
+    properties.put(ConsumerConfig.ENABLE_AUTO_COMMIT_CONFIG, false);
+    properties.put(ConsumerConfig.AUTO_OFFSET_RESET_CONFIG, "earliest");
+    properties.put(ConsumerConfig.MAX_POLL_RECORDS_CONFIG, 1);
+    properties.put(
+        ConsumerConfig.KEY_DESERIALIZER_CLASS_CONFIG, StringDeserializer.class.getName());
+    properties.put(
+        ConsumerConfig.VALUE_DESERIALIZER_CLASS_CONFIG, StringDeserializer.class.getName());
+    return new KafkaConsumer<>(properties);
+  }
+
+  /** Creates a producer that waits until a result has been written to all in-sync replicas */
+  private static KafkaProducer<String, String> createProducer() {
+    Properties properties = new Properties();
+    properties.put(
+        ProducerConfig.BOOTSTRAP_SERVERS_CONFIG,
+        configProvider.getParameterByKey("KAFKA_BOOTSTRAP_SERVERS"));
+    properties.put(ProducerConfig.ACKS_CONFIG, "all");
+    properties.put(ProducerConfig.ENABLE_IDEMPOTENCE_CONFIG, true);
+    properties.put(ProducerConfig.KEY_SERIALIZER_CLASS_CONFIG, StringSerializer.class.getName());
+    properties.put(ProducerConfig.VALUE_SERIALIZER_CLASS_CONFIG, StringSerializer.class.getName());
+    return new KafkaProducer<>(properties);
+  }
+
+  private static class VideoRendition {
+
+    private int height;
+    private long bitrate;
+
+    /**
+     * @param height The target output height of the rendition
+     * @param bitrate The target output bitrate of the rendition
+     */
+    private VideoRendition(int height, long bitrate) {
+      this.height = height;
+      this.bitrate = bitrate;
+    }
+  }
+
+  /**
+   * Creates a resource representing an HTTP server providing the input files. For alternative input
+   * methods see <a
+   * href="https://bitmovin.com/docs/encoding/articles/supported-input-output-storages">list of
+   * supported input and output storages</a>
+   *
+   * <p>For reasons of simplicity, a new input resource is created on each execution of this
+   * example. In production use, this method should be replaced by a <a
+   * href="https://bitmovin.com/docs/encoding/api-reference/sections/inputs#/Encoding/GetEncodingInputsHttpByInputId">get
+   * call</a> to retrieve an existing resource.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/inputs#/Encoding/PostEncodingInputsHttp
+   *
+   * @param host The hostname or IP address of the HTTP server e.g.: my-storage.biz
+   */
+  private static HttpInput createHttpInput(String host) throws BitmovinException {
+    HttpInput input = new HttpInput();
+    input.setHost(host);
+
+    return bitmovinApi.encoding.inputs.http.create(input);
+  }
+
+  /**
+   * Creates a resource representing an AWS S3 cloud storage bucket to which generated content will
+   * be transferred. For alternative output methods see <a
+   * href="https://bitmovin.com/docs/encoding/articles/supported-input-output-storages">list of
+   * supported input and output storages</a>
+   *
+   * <p>The provided credentials need to allow <i>read</i>, <i>write</i> and <i>list</i> operations.
+   * <i>delete</i> should also be granted to allow overwriting of existings files. See <a
+   * href="https://bitmovin.com/docs/encoding/faqs/how-do-i-create-a-aws-s3-bucket-which-can-be-used-as-output-location">creating
+   * an S3 bucket and setting permissions</a> for further information
+   *
+   * <p>For reasons of simplicity, a new output resource is created on each execution of this
+   * example. In production use, this method should be replaced by a <a
+   * href="https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/GetEncodingOutputsS3">get
+   * call</a> retrieving an existing resource.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/PostEncodingOutputsS3
+   *
+   * @param bucketName The name of the S3 bucket
+   * @param accessKey The access key of your S3 account
+   * @param secretKey The secret key of your S3 account
+   */
+  private static S3Output createS3Output(String bucketName, String accessKey, String secretKey)
+      throws BitmovinException {
+
+    S3Output s3Output = new S3Output();
+    s3Output.setBucketName(bucketName);
+    s3Output.setAccessKey(accessKey);
+    s3Output.setSecretKey(secretKey);
+
+    return bitmovinApi.encoding.outputs.s3.create(s3Output);
+  }
+
+  /**
+   * Creates an encoding object. This is the base object to configure your encoding.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodings
+   *
+   * @param name This is the name of the encoding
+   * @param description This is the description of the encoding
+   */
+  private static Encoding createEncoding(String name, String description) throws BitmovinException {
+    Encoding encoding = new Encoding();
+    encoding.setName(name);
+    encoding.setDescription(description);
+    new BudgetTag(configProvider).applyTo(encoding);
+
+    return bitmovinApi.encoding.encodings.create(encoding);
+  }
+
+  /**
+   * Creates a stream which binds an input file to a codec configuration. The stream is used for
+   * muxings later on.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsStreamsByEncodingId
+   *
+   * @param encoding The encoding to add the stream onto
+   * @param input The input that should be used
+   * @param inputPath The path to the input file
+   * @param codecConfiguration The codec configuration to be applied to the stream
+   */
+  private static Stream createStream(
+      Encoding encoding, Input input, String inputPath, CodecConfiguration codecConfiguration)
+      throws BitmovinException {
+    StreamInput streamInput =
+        PreviewTrimming.createStreamInput(bitmovinApi, configProvider, encoding, input, inputPath);
+
+    Stream stream = new Stream();
+    stream.addInputStreamsItem(streamInput);
+    stream.setCodecConfigId(codecConfiguration.getId());
+
+    return bitmovinApi.encoding.encodings.streams.create(encoding.getId(), stream);
+  }
+
+  /**
+   * Creates a configuration for the H.264 video codec to be applied to video streams.
+   *
+   * <p>The output resolution is defined by setting only the height. Width will be determined
+   * automatically to maintain the aspect ratio of your input video.
+   *
+   * <p>To keep things simple, we use a quality-optimized VoD preset configuration, which will apply
+   * proven settings for the codec. See <a
+   * href="https://bitmovin.com/docs/encoding/tutorials/how-to-optimize-your-h264-codec-configuration-for-different-use-cases">How
+   * to optimize your H264 codec configuration for different use-cases</a> for alternative presets.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsVideoH264
+   *
+   * @param height The height of the output video
+   * @param bitrate The target bitrate of the output video
+   */
+  private static H264VideoConfiguration createH264VideoConfig(int height, long bitrate)
+      throws BitmovinException {
+    H264VideoConfiguration config = new H264VideoConfiguration();
+    config.setName(String.format("H.264 %dp", height));
+    config.setPresetConfiguration(PresetConfiguration.VOD_STANDARD);
+    config.setHeight(height);
+    config.setBitrate(bitrate);
+
+    return bitmovinApi.encoding.configurations.video.h264.create(config);
+  }
+
+  /**
+   * Creates a configuration for the AAC audio codec to be applied to audio streams.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsAudioAac
+   */
+  private static AacAudioConfiguration createAacAudioConfig() throws BitmovinException {
+    AacAudioConfiguration config = new AacAudioConfiguration();
+    config.setName("AAC 128 kbit/s");
+    config.setBitrate(128_000L);
+
+    return bitmovinApi.encoding.configurations.audio.aac.create(config);
+  }
+
+  /**
+   * Creates a fragmented MP4 muxing. This will generate segments with a given segment length for
+   * adaptive streaming.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/all#/Encoding/PostEncodingEncodingsMuxingsFmp4ByEncodingId
+   *
+   * @param encoding The encoding where to add the muxing to
+   * @param output The output that should be used for the muxing to write the segments to
+   * @param outputPath The output path where the fragmented segments will be written to
+   * @param stream The stream that is associated with the muxing
+   */
+  private static Fmp4Muxing createFmp4Muxing(
+      Encoding encoding, Output output, String outputPath, Stream stream) throws BitmovinException {
+    MuxingStream muxingStream = new MuxingStream();
+    muxingStream.setStreamId(stream.getId());
+
+    Fmp4Muxing muxing = new Fmp4Muxing();
+    muxing.addOutputsItem(buildEncodingOutput(output, outputPath));
+    muxing.addStreamsItem(muxingStream);
+    muxing.setSegmentLength(4.0);
+    SegmentSharding.apply(configProvider, muxing);
+
+    return bitmovinApi.encoding.encodings.muxings.fmp4.create(encoding.getId(), muxing);
+  }
+
+  /**
+   * Builds an EncodingOutput object which defines where the output content (e.g. of a muxing) will
+   * be written to. Public read permissions will be set for the files written, so they can be
+   * accessed easily via HTTP.
+   *
+   * @param output The output resource to be used by the EncodingOutput
+   * @param outputPath The path where the content will be written to
+   */
+  private static EncodingOutput buildEncodingOutput(Output output, String outputPath) {
+    AclEntry aclEntry = new AclEntry();
+    aclEntry.setPermission(AclPermission.PUBLIC_READ);
+
+    EncodingOutput encodingOutput = new EncodingOutput();
+    encodingOutput.setOutputPath(buildAbsolutePath(outputPath));
+    encodingOutput.setOutputId(output.getId());
+    encodingOutput.addAclItem(aclEntry);
+    return encodingOutput;
+  }
+
+  /**
+   * Builds an absolute path by concatenating the S3_OUTPUT_BASE_PATH configuration parameter, the
+   * name of this example class and the given relative path
+   *
+   * <p>e.g.: /s3/base/path/ClassName/relative/path
+   *
+   * @param relativePath The relative path that is concatenated
+   * @return The absolute path
+   */
+  public static String buildAbsolutePath(String relativePath) {
+    String className = KafkaEncodingWorker.class.getSimpleName();
+    return Paths.get(configProvider.getS3OutputBasePath(), className, relativePath).toString();
+  }
+
+  private static void logTaskErrors(Task task) {
+    task.getMessages().stream()
+        .filter(msg -> msg.getType() == MessageType.ERROR)
+        .forEach(msg -> logger.error(msg.getText()));
+  }
+
+  /**
+   * Creates an HLS default manifest that automatically includes all representations configured in
+   * the encoding.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsHlsDefault
+   *
+   * @param encoding The encoding for which the manifest should be generated
+   * @param output The output to which the manifest should be written
+   * @param outputPath The path to which the manifest should be written
+   */
+  private static void generateHlsManifest(Encoding encoding, Output output, String outputPath)
+      throws Exception {
+    HlsManifestDefault hlsManifestDefault = new HlsManifestDefault();
+    hlsManifestDefault.setEncodingId(encoding.getId());
+    hlsManifestDefault.addOutputsItem(buildEncodingOutput(output, outputPath));
+    hlsManifestDefault.setName("master.m3u8");
+    hlsManifestDefault.setVersion(HlsManifestDefaultVersion.V1);
+
+    hlsManifestDefault = bitmovinApi.encoding.manifests.hls.defaultapi.create(hlsManifestDefault);
+    executeHlsManifestCreation(hlsManifestDefault);
+  }
+
+  /**
+   * Creates a DASH default manifest that automatically includes all representations configured in
+   * the encoding.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsDash
+   *
+   * @param encoding The encoding for which the manifest should be generated
+   * @param output The output to which the manifest should be written
+   * @param outputPath The path to which the manifest should be written
+   */
+  private static void generateDashManifest(Encoding encoding, Output output, String outputPath)
+      throws Exception {
+    DashManifestDefault dashManifestDefault = new DashManifestDefault();
+    dashManifestDefault.setEncodingId(encoding.getId());
+    dashManifestDefault.setManifestName("stream.mpd");
+    dashManifestDefault.setVersion(DashManifestDefaultVersion.V1);
+    dashManifestDefault.addOutputsItem(buildEncodingOutput(output, outputPath));
+    dashManifestDefault =
+        bitmovinApi.encoding.manifests.dash.defaultapi.create(dashManifestDefault);
+    executeDashManifestCreation(dashManifestDefault);
+  }
+
+  /**
+   * Starts the DASH manifest creation and periodically polls its status until it reaches a final
+   * state
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsDashStartByManifestId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/GetEncodingManifestsDashStatusByManifestId
+   *
+   * @param dashManifest The DASH manifest to be created
+   */
+  private static void executeDashManifestCreation(DashManifest dashManifest)
+      throws BitmovinException, InterruptedException {
+    bitmovinApi.encoding.manifests.dash.start(dashManifest.getId());
+
+    Task task;
+    do {
+      Thread.sleep(1000);
+      task = bitmovinApi.encoding.manifests.dash.status(dashManifest.getId());
+    } while (task.getStatus() != Status.FINISHED && task.getStatus() != Status.ERROR);
+
+    if (task.getStatus() == Status.ERROR) {
+      logTaskErrors(task);
+      throw new RuntimeException("DASH manifest creation failed");
+    }
+    logger.info("DASH manifest creation finished successfully");
+  }
+
+  /**
+   * Starts the HLS manifest creation and periodically polls its status until it reaches a final
+   * state
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsHlsStartByManifestId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/GetEncodingManifestsHlsStatusByManifestId
+   *
+   * @param hlsManifest The HLS manifest to be created
+   */
+  private static void executeHlsManifestCreation(HlsManifest hlsManifest)
+      throws BitmovinException, InterruptedException {
+
+    bitmovinApi.encoding.manifests.hls.start(hlsManifest.getId());
+
+    Task task;
+    do {
+      Thread.sleep(1000);
+      task = bitmovinApi.encoding.manifests.hls.status(hlsManifest.getId());
+    } while (task.getStatus() != Status.FINISHED && task.getStatus() != Status.ERROR);
+
+    if (task.getStatus() == Status.ERROR) {
+      logTaskErrors(task);
+      throw new RuntimeException("HLS manifest creation failed");
+    }
+    logger.info("HLS manifest creation finished successfully");
+  }
+}
@@ -0,0 +1,327 @@
+import common.ConfigProvider;
+import java.io.ByteArrayInputStream;
+import java.io.IOException;
+import java.net.URL;
+import java.nio.charset.StandardCharsets;
+import java.util.ArrayList;
+import java.util.Arrays;
+import java.util.Collections;
+import java.util.HashMap;
+import java.util.HashSet;
+import java.util.List;
+import java.util.Map;
+import java.util.Scanner;
+import java.util.Set;
+import java.util.regex.Matcher;
+import java.util.regex.Pattern;
+import javax.xml.parsers.DocumentBuilderFactory;
+import org.apache.commons.lang3.StringUtils;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import org.w3c.dom.Document;
+import org.w3c.dom.Element;
+import org.w3c.dom.NodeList;
+
+/**
+ * This tool downloads generated HLS and DASH manifests and checks them for common pitfalls, which
+ * are known to cause issues with some players. For every issue found, a hint on how to fix it is
+ * printed.
+ *
+ * <p>The following checks are performed:
+ *
+ * <ul>
+ *   <li>HLS multivariant playlists: missing CODECS attributes, missing or unknown AUDIO group
+ *       references and a missing #EXT-X-INDEPENDENT-SEGMENTS tag
+ *   <li>HLS media playlists: segments longer than #EXT-X-TARGETDURATION and mixed segment durations
+ *   <li>DASH manifests: representations without codecs attribute and mixed segment durations in
+ *       segment timelines
+ * </ul>
+ *
+ * <p>All media playlists referenced by a multivariant playlist are checked as well. If any issue is
+ * found, the tool fails after all manifests have been checked.
+ *
+ * <p>The following configuration parameters are expected:
+ *
+ * <ul>
+ *   <li>LINT_MANIFEST_URLS - A comma-separated list of URLs of HLS (.m3u8) or DASH (.mpd)
+ *       manifests. Example:
+ *       https://my-cdn.com/outputs/master.m3u8,https://my-cdn.com/outputs/stream.mpd
+ *   <li>LINT_SEGMENT_DURATION_TOLERANCE - (optional) The maximum difference in seconds between
+ *       segment durations before they are considered mixed. Default: 0.5
+ * </ul>
+ *
+ * <p>Configuration parameters will be retrieved from these sources in the listed order:
+ *
+ * <ol>
+ *   <li>command line arguments (eg BITMOVIN_API_KEY=xyz)
+ *   <li>properties file located in the root folder of the JAVA examples at ./examples.properties
+ *       (see examples.properties.template as reference)
+ *   <li>environment variables
+ *   <li>properties file located in the home folder at ~/.bitmovin/examples.properties (see
+ *       examples.properties.template as reference)
+ * </ol>
+ */
+public class ManifestLinter {
+  private static final Logger logger = LoggerFactory.getLogger(ManifestLinter.class);
+
+  private static final Pattern ATTRIBUTE_PATTERN =
+      Pattern.compile("([A-Z0-9-]+)=(\"[^\"]*\"|[^,]*)");
+
+  private static ConfigProvider configProvider;
+  private static double segmentDurationTolerance;
+
+  private static final Set<String> lintedUrls = new HashSet<>();
+  private static final List<LintIssue> issues = new ArrayList<>();
+
+  public static void main(String[] args) throws Exception {
+    configProvider = new ConfigProvider(args);
+
+    segmentDurationTolerance =
+        Double.parseDouble(
+            configProvider.getParameterByKey("LINT_SEGMENT_DURATION_TOLERANCE", "0.5"));
+
+    for (String manifestUrl : configProvider.getParameterByKey("LINT_MANIFEST_URLS").split(",")) {
+      lintManifest(new URL(manifestUrl.trim()));
+    }
+
+    if (issues.isEmpty()) {
+      logger.info("No issues found in {} manifests", lintedUrls.size());
+      return;
+    }
+
+    for (LintIssue issue : issues) {
+      logger.warn("{}: {}", issue.url, issue.message);
+      logger.warn("  Hint: {}", issue.hint);
+    }
+    throw new IllegalStateException(
+        String.format("Found %d issues in %d manifests", issues.size(), lintedUrls.size()));
+  }
+
+  private static void lintManifest(URL url) throws Exception {
+    if (!lintedUrls.add(url.toString())) {
+      return;
+    }
+
+    logger.info("Checking {}", url);
+    String content = download(url);
+
+    if (url.getPath().endsWith(".mpd")) {
+      lintDashManifest(url, content);
+    } else if (content.contains("#EXT-X-STREAM-INF")) {
+      lintHlsMultivariantPlaylist(url, content);
+    } else {
+      lintHlsMediaPlaylist(url, content);
+    }
+  }
+
+  /**
+   * Checks the variant streams of an HLS multivariant playlist, and all media playlists referenced
+   * by it.
+   */
+  private static void lintHlsMultivariantPlaylist(URL url, String content) throws Exception {
+    List<String> lines = Arrays.asList(content.split("\\r?\\n"));
+
+    if (!lines.contains("#EXT-X-INDEPENDENT-SEGMENTS")) {
+      addIssue(
+          url,
+          "#EXT-X-INDEPENDENT-SEGMENTS is missing",
+          "If all segments start with a keyframe, add the tag so players can switch renditions at "
+              + "any segment boundary");
+    }
+
+    Set<String> audioGroups = new HashSet<>();
+    for (String line : lines) {
+      if (line.startsWith("#EXT-X-MEDIA:")) {
+        Map<String, String> attributes = parseAttributes(line);
+        if ("AUDIO".equals(attributes.get("TYPE"))) {
+          audioGroups.add(attributes.get("GROUP-ID"));
+        }
+        if (attributes.containsKey("URI")) {
+          lintManifest(new URL(url, attributes.get("URI")));
+        }
+      }
+    }
+
+    for (int i = 0; i < lines.size(); i++) {
+      String line = lines.get(i);
+      if (!line.startsWith("#EXT-X-STREAM-INF:")) {
+        continue;
+      }
+
+      String variantUri = i + 1 < lines.size() ? lines.get(i + 1).trim() : "";
+      Map<String, String> attributes = parseAttributes(line);
+
+      if (!attributes.containsKey("CODECS")) {
+        addIssue(
+            url,
+            "Variant " + variantUri + " has no CODECS attribute",
+            "Add the CODECS attribute, otherwise players may select variants they cannot decode");
+      }
+
+      String audioGroup = attributes.get("AUDIO");
+      if (audioGroup == null && !audioGroups.isEmpty()) {
+        addIssue(
+            url,
+            "Variant " + variantUri + " does not reference an AUDIO group",
+            String.format(
+                "Reference one of the audio groups %s, otherwise it plays without audio on some "
+                    + "players",
+                audioGroups));
+      } else if (audioGroup != null && !audioGroups.contains(audioGroup)) {
+        addIssue(
+            url,
+            "Variant " + variantUri + " references the unknown AUDIO group " + audioGroup,
+            "Add an #EXT-X-MEDIA tag with TYPE=AUDIO and GROUP-ID=\"" + audioGroup + "\"");
+      }
+
+      if (!variantUri.isEmpty() && !variantUri.startsWith("#")) {
+        lintManifest(new URL(url, variantUri));
+      }
+    }
+  }
+
+  /** Checks the segment durations of an HLS media playlist. */
+  private static void lintHlsMediaPlaylist(URL url, String content) {
+    Integer targetDuration = null;
+    List<Double> segmentDurations = new ArrayList<>();
+
+    for (String line : content.split("\\r?\\n")) {
+      if (line.startsWith("#EXT-X-TARGETDURATION:")) {
+        targetDuration = Integer.valueOf(line.substring(line.indexOf(':') + 1).trim());
+      } else if (line.startsWith("#EXTINF:")) {
+        String duration = line.substring(line.indexOf(':') + 1).split(",")[0];
+        segmentDurations.add(Double.valueOf(duration));
+      }
+    }
+
+    if (targetDuration == null) {
+      addIssue(
+          url,
+          "#EXT-X-TARGETDURATION is missing",
+          "Add the tag with the maximum segment duration, rounded to the nearest integer");
+    } else {
+      for (double duration : segmentDurations) {
+        if (Math.round(duration) > targetDuration) {
+          addIssue(
+              url,
+              String.format(
+                  "A segment of %.3f s exceeds the target duration of %d s",
+                  duration, targetDuration),
+              "Set #EXT-X-TARGETDURATION to the rounded maximum segment duration");
+          break;
+        }
+      }
+    }
+
+    checkSegmentDurations(url, segmentDurations);
+  }
+
+  /**
+   * Checks the representations of a DASH manifest for codecs attributes, and the segment timelines
+   * for mixed segment durations.
+   */
+  private static void lintDashManifest(URL url, String content) throws Exception {
+    DocumentBuilderFactory factory = DocumentBuilderFactory.newInstance();
+    factory.setNamespaceAware(true);
+    Document document =
+        factory
+            .newDocumentBuilder()
+            .parse(new ByteArrayInputStream(content.getBytes(StandardCharsets.UTF_8)));
+
+    NodeList representations = document.getElementsByTagNameNS("*", "Representation");
+    for (int i = 0; i < representations.getLength(); i++) {
+      Element representation = (Element) representations.item(i);
+      Element adaptationSet = (Element) representation.getParentNode();
+
+      if (!representation.hasAttribute("codecs") && !adaptationSet.hasAttribute("codecs")) {
+        addIssue(
+            url,
+            "Representation " + representation.getAttribute("id") + " has no codecs attribute",
+            "Add the codecs attribute to the Representation or its AdaptationSet");
+      }
+    }
+
+    NodeList segmentTemplates = document.getElementsByTagNameNS("*", "SegmentTemplate");
+    for (int i = 0; i < segmentTemplates.getLength(); i++) {
+      Element segmentTemplate = (Element) segmentTemplates.item(i);
+      double timescale =
+          segmentTemplate.hasAttribute("timescale")
+              ? Double.parseDouble(segmentTemplate.getAttribute("timescale"))
+              : 1;
+
+      List<Double> segmentDurations = new ArrayList<>();
+      NodeList timelineEntries = segmentTemplate.getElementsByTagNameNS("*", "S");
+      for (int j = 0; j < timelineEntries.getLength(); j++) {
+        Element entry = (Element) timelineEntries.item(j);
+        double duration = Double.parseDouble(entry.getAttribute("d")) / timescale;
+        int repeat = entry.hasAttribute("r") ? Integer.parseInt(entry.getAttribute("r")) : 0;
+
+        for (int r = 0; r <= Math.max(repeat, 0); r++) {
+          segmentDurations.add(duration);
+        }
+      }
+
+      checkSegmentDurations(url, segmentDurations);
+    }
+  }
+
+  /**
+   * Checks whether the durations of all segments except the last one are within the configured
+   * tolerance. The last segment is excluded, as it is usually shorter.
+   */
+  private static void checkSegmentDurations(URL url, List<Double> segmentDurations) {
+    if (segmentDurations.size() < 3) {
+      return;
+    }
+
+    List<Double> fullSegments = segmentDurations.subList(0, segmentDurations.size() - 1);
+    double min = Collections.min(fullSegments);
+    double max = Collections.max(fullSegments);
+
+    if (max - min > segmentDurationTolerance) {
+      addIssue(
+          url,
+          String.format("Mixed segment durations between %.3f s and %.3f s", min, max),
+          "Use a fixed GOP size or keyframe interval matching the segment length, so that all "
+              + "segments have the same duration");
+    }
+  }
+
+  private static Map<String, String> parseAttributes(String line) {
+    Map<String, String> attributes = new HashMap<>();
+    Matcher matcher = ATTRIBUTE_PATTERN.matcher(line.substring(line.indexOf(':') + 1));
+
+    while (matcher.find()) {
+      attributes.put(matcher.group(1), StringUtils.strip(matcher.group(2), "\""));
+    }
+    return attributes;
+  }
+
+  private static String download(URL url) throws IOException {
+    try (Scanner scanner = new Scanner(url.openStream(), StandardCharsets.UTF_8.name())) {
+      return scanner.useDelimiter("\\A").hasNext() ? scanner.next() : "";
+    }
+  }
+
+  private static void addIssue(URL url, String message, String hint) {
+    issues.add(new LintIssue(url.toString(), message, hint));
+  }
+
+  private static class LintIssue {
+
+    private String url;
+    private String message;
+    private String hint;
+
+    /**
+     * @param url The URL of the manifest the issue was found in
+     * @param message The description of the issue
+     * @param hint A hint on how to fix the issue
+     */
+    private LintIssue(String url, String message, String hint) {
+      this.url = url;
+      this.message = message;
+      this.hint = hint;
+    }
+  }
+}
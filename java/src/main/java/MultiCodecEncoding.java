@@ -33,16 +33,19 @@ import com.bitmovin.api.sdk.model.Status;
 import com.bitmovin.api.sdk.model.Stream;
 import com.bitmovin.api.sdk.model.StreamInfo;
 import com.bitmovin.api.sdk.model.StreamInput;
-import com.bitmovin.api.sdk.model.StreamSelectionMode;
 import com.bitmovin.api.sdk.model.Task;
 import com.bitmovin.api.sdk.model.TsMuxing;
 import com.bitmovin.api.sdk.model.VideoAdaptationSet;
 import com.bitmovin.api.sdk.model.VorbisAudioConfiguration;
 import com.bitmovin.api.sdk.model.Vp9VideoConfiguration;
 import com.bitmovin.api.sdk.model.WebmMuxing;
+import common.ApiClientFactory;
+import common.BudgetTag;
 import common.ConfigProvider;
+import common.EncodingFailedException;
+import common.PreviewTrimming;
+import common.SegmentSharding;
 import feign.Logger.Level;
-import feign.slf4j.Slf4jLogger;
 import java.nio.file.Paths;
 import java.text.SimpleDateFormat;
 import java.util.Arrays;
@@ -68,6 +71,8 @@ import org.slf4j.LoggerFactory;
  *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin API
  *   <li>BITMOVIN_TENANT_ORG_ID - (optional) The ID of the Organisation in which you want to perform
  *       the encoding.
+ *   <li>BITMOVIN_API_BASE_URL - (optional) The base URL of the Bitmovin API, e.g. of a mock server
+ *       or an API gateway. Default: https://api.bitmovin.com/v1
  *   <li>HTTP_INPUT_HOST - The Hostname or IP address of the HTTP server hosting your input files,
  *       e.g.: my-storage.biz
  *   <li>HTTP_INPUT_FILE_PATH - The path to your input file on the provided HTTP server Example:
@@ -201,12 +206,8 @@ public class MultiCodecEncoding {
   public static void main(String[] args) throws Exception {
     configProvider = new ConfigProvider(args);
     bitmovinApi =
-        BitmovinApi.builder()
+        ApiClientFactory.builder(configProvider, Level.FULL)
             .withApiKey(configProvider.getBitmovinApiKey())
-            // uncomment the following line if you are working with a multi-tenant account
-            // .withTenantOrgId(configProvider.getBitmovinTenantOrgId())
-            .withLogger(
-                new Slf4jLogger(), Level.FULL) // set the logger and log level for the API client
             .build();
 
     HttpInput input = createHttpInput(configProvider.getHttpInputHost());
@@ -664,6 +665,7 @@ public class MultiCodecEncoding {
     Encoding encoding = new Encoding();
     encoding.setName(name);
     encoding.setDescription(description);
+    new BudgetTag(configProvider).applyTo(encoding);
 
     return bitmovinApi.encoding.encodings.create(encoding);
   }
@@ -682,10 +684,8 @@ public class MultiCodecEncoding {
    */
   private static Stream createStream(
       Encoding encoding, HttpInput input, String inputPath, CodecConfiguration codecConfiguration) {
-    StreamInput streamInput = new StreamInput();
-    streamInput.setInputId(input.getId());
-    streamInput.setInputPath(inputPath);
-    streamInput.setSelectionMode(StreamSelectionMode.AUTO);
+    StreamInput streamInput =
+        PreviewTrimming.createStreamInput(bitmovinApi, configProvider, encoding, input, inputPath);
 
     Stream stream = new Stream();
     stream.addInputStreamsItem(streamInput);
@@ -802,6 +802,7 @@ public class MultiCodecEncoding {
     muxing.addOutputsItem(buildEncodingOutput(output, outputPath));
     muxing.addStreamsItem(muxingStream);
     muxing.setSegmentLength(4.0);
+    SegmentSharding.apply(configProvider, muxing);
 
     return bitmovinApi.encoding.encodings.muxings.fmp4.create(encoding.getId(), muxing);
   }
@@ -827,6 +828,7 @@ public class MultiCodecEncoding {
     muxing.addOutputsItem(buildEncodingOutput(output, outputPath));
     muxing.addStreamsItem(muxingStream);
     muxing.setSegmentLength(4.0);
+    SegmentSharding.apply(configProvider, muxing);
 
     return bitmovinApi.encoding.encodings.muxings.cmaf.create(encoding.getId(), muxing);
   }
@@ -877,6 +879,7 @@ public class MultiCodecEncoding {
     muxing.addOutputsItem(buildEncodingOutput(output, outputPath));
     muxing.addStreamsItem(muxingStream);
     muxing.setSegmentLength(4.0);
+    SegmentSharding.apply(configProvider, muxing);
 
     return bitmovinApi.encoding.encodings.muxings.ts.create(encoding.getId(), muxing);
   }
@@ -955,11 +958,13 @@ public class MultiCodecEncoding {
       Thread.sleep(5000);
       task = bitmovinApi.encoding.encodings.status(encoding.getId());
       logger.info("encoding status is {} (progress: {} %)", task.getStatus(), task.getProgress());
-    } while (task.getStatus() != Status.FINISHED && task.getStatus() != Status.ERROR);
+    } while (task.getStatus() != Status.FINISHED
+        && task.getStatus() != Status.ERROR
+        && task.getStatus() != Status.CANCELED);
 
-    if (task.getStatus() == Status.ERROR) {
+    if (task.getStatus() != Status.FINISHED) {
       logTaskErrors(task);
-      throw new RuntimeException("Encoding failed");
+      throw new EncodingFailedException(task.getStatus());
     }
     logger.info("Encoding finished successfully");
     return encoding;
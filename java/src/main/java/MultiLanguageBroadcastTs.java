@@ -25,11 +25,13 @@ import com.bitmovin.api.sdk.model.StreamInput;
 import com.bitmovin.api.sdk.model.StreamMode;
 import com.bitmovin.api.sdk.model.StreamSelectionMode;
 import com.bitmovin.api.sdk.model.Task;
+import common.ApiClientFactory;
+import common.BudgetTag;
 import common.ConfigProvider;
-import feign.Logger.Level;
-import feign.slf4j.Slf4jLogger;
+import common.EncodingFailedException;
+import common.PreviewTrimming;
 import java.nio.file.Paths;
-import java.util.HashMap;
+import java.util.LinkedHashMap;
 import java.util.Map;
 import org.slf4j.Logger;
 import org.slf4j.LoggerFactory;
@@ -37,16 +39,28 @@ import org.slf4j.LoggerFactory;
 /**
  * This example demonstrates how multiple audio streams can be included in a BroadcastTS muxing
  *
+ * <p>Broadcast sources often carry one audio track per language. Each track is selected from the
+ * input file by its position, either relative to the other audio tracks (AUDIO_RELATIVE) or
+ * relative to all tracks of the file (POSITION_ABSOLUTE), and tagged with its ISO 639-2 language
+ * code in the program map table of the TS muxing. The packet identifiers of the audio tracks are
+ * assigned in the configured order, starting at 2000.
+ *
  * <p>The following configuration parameters are expected:
  *
  * <ul>
  *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin platform
  *   <li>BITMOVIN_TENANT_ORG_ID - (optional) The ID of the Organisation in which you want to perform
  *       the encoding.
+ *   <li>BITMOVIN_API_BASE_URL - (optional) The base URL of the Bitmovin API, e.g. of a mock server
+ *       or an API gateway. Default: https://api.bitmovin.com/v1
  *   <li>HTTP_INPUT_HOST - The Hostname or IP address of the HTTP server hosting your input file.
  *       Example: http://my-storage.biz
  *   <li>HTTP_INPUT_FILE_PATH - The path to your input file on the HTTP host. NOTE: This example
  *       will only work for files with at least two audio streams. Example: videos/1080p_Sintel.mp4
+ *   <li>BROADCAST_TS_AUDIO_TRACKS - (optional) A comma separated list of the audio tracks to be
+ *       included, each defined by its language code and position. Default: eng:0,deu:1
+ *   <li>BROADCAST_TS_AUDIO_SELECTION_MODE - (optional) How the positions of the audio tracks are
+ *       interpreted, either AUDIO_RELATIVE or POSITION_ABSOLUTE. Default: AUDIO_RELATIVE
  *   <li>S3_OUTPUT_BUCKET_NAME - The name of your S3 output bucket. Example: my-bucket-name
  *   <li>S3_OUTPUT_ACCESS_KEY - The access key of your S3 output bucket
  *   <li>S3_OUTPUT_SECRET_KEY - The secret key of your S3 output bucket
@@ -74,12 +88,8 @@ public class MultiLanguageBroadcastTs {
   public static void main(String[] args) throws Exception {
     configProvider = new ConfigProvider(args);
     bitmovinApi =
-        BitmovinApi.builder()
+        ApiClientFactory.builder(configProvider)
             .withApiKey(configProvider.getBitmovinApiKey())
-            // uncomment the following line if you are working with a multi-tenant account
-            // .withTenantOrgId(configProvider.getBitmovinTenantOrgId())
-            .withLogger(
-                new Slf4jLogger(), Level.BASIC) // set the logger and log level for the API client
             .build();
 
     Encoding encoding =
@@ -100,18 +110,28 @@ public class MultiLanguageBroadcastTs {
         createStream(
             encoding, input, inputFilePath, h264Config, StreamSelectionMode.VIDEO_RELATIVE, 0);
 
-    Mp2AudioConfiguration mp2Config = createMp2AudioConfig();
-    HashMap<String, Stream> audioStreams = new HashMap<>();
-
-    audioStreams.put(
-        "eng",
-        createStream(
-            encoding, input, inputFilePath, mp2Config, StreamSelectionMode.AUDIO_RELATIVE, 0));
+    StreamSelectionMode audioSelectionMode =
+        StreamSelectionMode.valueOf(
+            configProvider.getParameterByKey(
+                "BROADCAST_TS_AUDIO_SELECTION_MODE", StreamSelectionMode.AUDIO_RELATIVE.name()));
 
-    audioStreams.put(
-        "deu",
-        createStream(
-            encoding, input, inputFilePath, mp2Config, StreamSelectionMode.AUDIO_RELATIVE, 1));
+    Mp2AudioConfiguration mp2Config = createMp2AudioConfig();
+    // a linked map keeps the order of the tracks, so packet identifiers are assigned in that order
+    Map<String, Stream> audioStreams = new LinkedHashMap<>();
+
+    for (String track :
+        configProvider.getParameterByKey("BROADCAST_TS_AUDIO_TRACKS", "eng:0,deu:1").split(",")) {
+      String[] languageAndPosition = track.trim().split(":");
+      audioStreams.put(
+          languageAndPosition[0],
+          createStream(
+              encoding,
+              input,
+              inputFilePath,
+              mp2Config,
+              audioSelectionMode,
+              Integer.parseInt(languageAndPosition[1])));
+    }
 
     createBroadcastTsMuxing(encoding, videoStream, audioStreams, output, "/");
 
@@ -132,6 +152,7 @@ public class MultiLanguageBroadcastTs {
     Encoding encoding = new Encoding();
     encoding.setName(name);
     encoding.setDescription(description);
+    new BudgetTag(configProvider).applyTo(encoding);
 
     return bitmovinApi.encoding.encodings.create(encoding);
   }
@@ -159,11 +180,15 @@ public class MultiLanguageBroadcastTs {
       StreamSelectionMode streamSelectionMode,
       int position)
       throws BitmovinException {
-    StreamInput streamInput = new StreamInput();
-    streamInput.setInputId(input.getId());
-    streamInput.setInputPath(inputPath);
-    streamInput.setSelectionMode(streamSelectionMode);
-    streamInput.setPosition(position);
+    StreamInput streamInput =
+        PreviewTrimming.createStreamInput(
+            bitmovinApi,
+            configProvider,
+            encoding,
+            input,
+            inputPath,
+            streamSelectionMode,
+            position);
 
     Stream stream = new Stream();
     stream.addInputStreamsItem(streamInput);
@@ -381,11 +406,13 @@ public class MultiLanguageBroadcastTs {
       Thread.sleep(5000);
       task = bitmovinApi.encoding.encodings.status(encoding.getId());
       logger.info("encoding status is {} (progress: {} %)", task.getStatus(), task.getProgress());
-    } while (task.getStatus() != Status.FINISHED && task.getStatus() != Status.ERROR);
+    } while (task.getStatus() != Status.FINISHED
+        && task.getStatus() != Status.ERROR
+        && task.getStatus() != Status.CANCELED);
 
-    if (task.getStatus() == Status.ERROR) {
+    if (task.getStatus() != Status.FINISHED) {
       logTaskErrors(task);
-      throw new RuntimeException("Encoding failed");
+      throw new EncodingFailedException(task.getStatus());
     }
     logger.info("encoding finished successfully");
   }
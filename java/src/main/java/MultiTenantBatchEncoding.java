@@ -0,0 +1,679 @@
+import com.bitmovin.api.sdk.BitmovinApi;
+import com.bitmovin.api.sdk.common.BitmovinException;
+import com.bitmovin.api.sdk.encoding.encodings.EncodingListQueryParams;
+import com.bitmovin.api.sdk.model.AacAudioConfiguration;
+import com.bitmovin.api.sdk.model.AclEntry;
+import com.bitmovin.api.sdk.model.AclPermission;
+import com.bitmovin.api.sdk.model.AudioConfiguration;
+import com.bitmovin.api.sdk.model.CodecConfiguration;
+import com.bitmovin.api.sdk.model.Encoding;
+import com.bitmovin.api.sdk.model.EncodingOutput;
+import com.bitmovin.api.sdk.model.Fmp4Muxing;
+import com.bitmovin.api.sdk.model.H264VideoConfiguration;
+import com.bitmovin.api.sdk.model.HttpInput;
+import com.bitmovin.api.sdk.model.Input;
+import com.bitmovin.api.sdk.model.Message;
+import com.bitmovin.api.sdk.model.MessageType;
+import com.bitmovin.api.sdk.model.MuxingStream;
+import com.bitmovin.api.sdk.model.Output;
+import com.bitmovin.api.sdk.model.PaginationResponse;
+import com.bitmovin.api.sdk.model.PresetConfiguration;
+import com.bitmovin.api.sdk.model.RetryHint;
+import com.bitmovin.api.sdk.model.S3Output;
+import com.bitmovin.api.sdk.model.StartEncodingRequest;
+import com.bitmovin.api.sdk.model.Status;
+import com.bitmovin.api.sdk.model.Stream;
+import com.bitmovin.api.sdk.model.StreamInput;
+import com.bitmovin.api.sdk.model.Task;
+import com.bitmovin.api.sdk.model.VideoConfiguration;
+import common.BudgetTag;
+import common.ConfigProvider;
+import common.PreviewTrimming;
+import common.SegmentSharding;
+import common.TenantProfiles;
+import java.nio.charset.StandardCharsets;
+import java.nio.file.Files;
+import java.nio.file.Paths;
+import java.util.ArrayList;
+import java.util.Arrays;
+import java.util.LinkedHashMap;
+import java.util.List;
+import java.util.Map;
+import java.util.stream.Collectors;
+import org.apache.commons.lang3.StringUtils;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+
+/**
+ * This example demonstrates how to execute a batch of encodings on behalf of several organisations,
+ * e.g. by an agency encoding content for multiple clients. It extends the approach of the
+ * BatchEncoding example: each job of the batch names a tenant profile, and the encoding is created
+ * and started with the credentials of that profile.
+ *
+ * <p>The credentials of a profile are resolved with {@link TenantProfiles}. As encodings, inputs,
+ * outputs and codec configurations belong to an organisation, these resources are created once per
+ * tenant, and the queue of each tenant is filled up separately, as queue limits apply per
+ * organisation.
+ *
+ * <p>The job list is read from a CSV file with one job per line in the format
+ * <i>profile,inputFilePath,outputPath,encodingName</i>. Empty lines and lines starting with '#' are
+ * ignored.
+ *
+ * <p>The following configuration parameters are expected:
+ *
+ * <ul>
+ *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin API, used for all profiles that don't
+ *       define their own API key
+ *   <li>TENANT_PROFILE_&lt;NAME&gt;_API_KEY - (optional) The API key of the tenant profile NAME
+ *   <li>TENANT_PROFILE_&lt;NAME&gt;_ORG_ID - (optional) The ID of the organisation in which the
+ *       encodings of the tenant profile NAME are performed
+ *   <li>MULTI_TENANT_JOBS_FILE - The path to the CSV file containing the job list. Example:
+ *       /path/to/jobs.csv
+ *   <li>HTTP_INPUT_HOST - The Hostname or IP address of the HTTP server hosting your input files,
+ *       e.g.: my-storage.biz
+ *   <li>S3_OUTPUT_BUCKET_NAME - The name of your S3 output bucket. Example: my-bucket-name
+ *   <li>S3_OUTPUT_ACCESS_KEY - The access key of your S3 output bucket
+ *   <li>S3_OUTPUT_SECRET_KEY - The secret key of your S3 output bucket
+ *   <li>S3_OUTPUT_BASE_PATH - The base path on your S3 output bucket where content will be written.
+ *       Example: /outputs
+ * </ul>
+ *
+ * <p>Configuration parameters will be retrieved from these sources in the listed order:
+ *
+ * <ol>
+ *   <li>command line arguments (eg BITMOVIN_API_KEY=xyz)
+ *   <li>properties file located in the root folder of the JAVA examples at ./examples.properties
+ *       (see examples.properties.template as reference)
+ *   <li>environment variables
+ *   <li>properties file located in the home folder at ~/.bitmovin/examples.properties (see
+ *       examples.properties.template as reference)
+ * </ol>
+ */
+public class MultiTenantBatchEncoding {
+  private static final Logger logger = LoggerFactory.getLogger(MultiTenantBatchEncoding.class);
+
+  private static ConfigProvider configProvider;
+  private static TenantProfiles tenantProfiles;
+
+  /**
+   * The example will strive to always keep this number of encodings in state 'queued' for each
+   * tenant. Make sure not to choose a size larger than the queue size limit of any of the
+   * organisations, otherwise encoding start calls will fail.
+   */
+  private static int targetQueueSize = 3;
+
+  /**
+   * The maximum number of retries per job, in case the start call or the encoding process is not
+   * successful. However, no retries will be performed after receiving an error that is considered
+   * permanent. Error code 8004 (platform queue limit exceeded) will always be retried.
+   */
+  private static int maxRetries = 2;
+
+  public static void main(String[] args) throws Exception {
+    configProvider = new ConfigProvider(args);
+    tenantProfiles = new TenantProfiles(configProvider);
+
+    JobDispatcher jobDispatcher =
+        new JobDispatcher(configProvider.getParameterByKey("MULTI_TENANT_JOBS_FILE"));
+
+    Map<String, Tenant> tenants = new LinkedHashMap<>();
+    for (String profile : jobDispatcher.getProfiles()) {
+      tenants.put(profile, createTenant(profile));
+    }
+
+    do {
+      for (Tenant tenant : tenants.values()) {
+        long queuedEncodingsCount = countQueuedEncodings(tenant.api);
+        long freeSlots = targetQueueSize - queuedEncodingsCount;
+        if (freeSlots <= 0) {
+          logger.info(
+              "[{}] There are currently {} encodings queued. Waiting for free slots...",
+              tenant.profile,
+              queuedEncodingsCount);
+          continue;
+        }
+
+        List<EncodingJob> jobsToStart = jobDispatcher.getJobsToStart(tenant.profile, freeSlots);
+        if (!jobsToStart.isEmpty()) {
+          logger.info(
+              "[{}] There are currently {} encodings queued. Starting {} more to reach target queue size of {}",
+              tenant.profile,
+              queuedEncodingsCount,
+              jobsToStart.size(),
+              targetQueueSize);
+          startEncodings(tenant, jobsToStart);
+        }
+      }
+
+      Thread.sleep(10000);
+      for (EncodingJob job : jobDispatcher.getStartedJobs()) {
+        updateEncodingJob(tenants.get(job.profile).api, job);
+        Thread.sleep(300);
+      }
+    } while (!jobDispatcher.allJobsFinished());
+    logger.info("All encoding jobs are finished!");
+
+    jobDispatcher.logSummary();
+    jobDispatcher.logFailedJobs();
+  }
+
+  /**
+   * Resolves the API client of a tenant profile and creates the input, output and codec
+   * configurations in the organisation of the tenant.
+   *
+   * @param profile The name of the tenant profile
+   */
+  private static Tenant createTenant(String profile) throws BitmovinException {
+    BitmovinApi api = tenantProfiles.getApi(profile);
+    logger.info(
+        "Preparing tenant profile '{}' (organisation: {})",
+        profile,
+        StringUtils.defaultIfBlank(tenantProfiles.getOrgId(profile), "of the API key"));
+
+    Tenant tenant = new Tenant(profile, api);
+    tenant.input = createHttpInput(api, configProvider.getHttpInputHost());
+    tenant.output =
+        createS3Output(
+            api,
+            configProvider.getS3OutputBucketName(),
+            configProvider.getS3OutputAccessKey(),
+            configProvider.getS3OutputSecretKey());
+    tenant.codecConfigs =
+        Arrays.asList(
+            createH264VideoConfig(api, 480, 800_000L),
+            createH264VideoConfig(api, 720, 1_200_000L),
+            createH264VideoConfig(api, 1080, 2_000_000L),
+            createAacAudioConfig(api));
+
+    return tenant;
+  }
+
+  /**
+   * This method queries the encodings currently in QUEUED state in the organisation of the given
+   * API client and returns the total result count of that query
+   *
+   * @param api The API client of the tenant
+   */
+  private static long countQueuedEncodings(BitmovinApi api) throws BitmovinException {
+    EncodingListQueryParams queryParams = new EncodingListQueryParams();
+    queryParams.setStatus(Status.QUEUED.toString());
+
+    PaginationResponse<Encoding> encodingPage = api.encoding.encodings.list(queryParams);
+    return encodingPage.getTotalCount();
+  }
+
+  /**
+   * This method will start new encodings created from {@link EncodingJob} objects of a tenant and
+   * update the started {@link EncodingJob} objects
+   *
+   * @param tenant The tenant the jobs belong to
+   * @param jobsToStart The encoding jobs that should be started
+   */
+  private static void startEncodings(Tenant tenant, List<EncodingJob> jobsToStart)
+      throws BitmovinException, InterruptedException {
+    for (EncodingJob job : jobsToStart) {
+      if (StringUtils.isBlank(job.encodingId)) {
+        Encoding encoding =
+            createAndConfigureEncoding(tenant, job.inputFilePath, job.encodingName, job.outputPath);
+        job.encodingId = encoding.getId();
+      }
+      try {
+        tenant.api.encoding.encodings.start(job.encodingId, new StartEncodingRequest());
+        job.status = EncodingJobStatus.STARTED;
+        logger.info(
+            "[{}] Encoding {} ('{}') has been started.",
+            job.profile,
+            job.encodingId,
+            job.encodingName);
+      } catch (BitmovinException ex) {
+
+        if (ex.getErrorCode() == 8004) {
+          logger.warn(
+              "[{}] Encoding {} ('{}') could not be started because the platform limit for queued encodings has been reached. Will retry.",
+              job.profile,
+              job.encodingId,
+              job.encodingName);
+          return;
+        }
+
+        job.retryCount++;
+        if (job.retryCount > maxRetries) {
+          logger.error(
+              "[{}] Encoding {} ('{}') has reached the maximum number of retries. Giving up.",
+              job.profile,
+              job.encodingId,
+              job.encodingName);
+          job.status = EncodingJobStatus.GIVEN_UP;
+          job.errorMessages.add("The encoding could not be started: " + ex.getMessage());
+        }
+      }
+      Thread.sleep(300);
+    }
+  }
+
+  /**
+   * This checks the status of the associated encoding of the encoding job and updates the encoding
+   * job accordingly.
+   *
+   * @param api The API client of the tenant the job belongs to
+   * @param job The encoding job to update
+   */
+  private static void updateEncodingJob(BitmovinApi api, EncodingJob job) throws BitmovinException {
+    Task task = api.encoding.encodings.status(job.encodingId);
+
+    if (task.getStatus() == Status.FINISHED) {
+      job.status = EncodingJobStatus.SUCCESSFUL;
+    } else if (task.getStatus() == Status.ERROR) {
+      if (!isRetryableError(task)) {
+        logger.error(
+            "[{}] Encoding {} ('{}') failed with a permanent error. Giving up.",
+            job.profile,
+            job.encodingId,
+            job.encodingName);
+        job.status = EncodingJobStatus.GIVEN_UP;
+        job.errorMessages.addAll(getErrorMessages(task));
+        return;
+      }
+      if (job.retryCount > maxRetries) {
+        logger.error(
+            "[{}] Encoding {} ('{}') has reached the maximum number of retries. Giving up.",
+            job.profile,
+            job.encodingId,
+            job.encodingName);
+        job.status = EncodingJobStatus.GIVEN_UP;
+        job.errorMessages.addAll(getErrorMessages(task));
+        return;
+      }
+
+      logger.error(
+          "[{}] Encoding {} ('{}') has failed. Will attempt {} more retries.",
+          job.profile,
+          job.encodingId,
+          job.encodingName,
+          maxRetries - job.retryCount);
+      job.retryCount++;
+      job.status = EncodingJobStatus.WAITING;
+    }
+  }
+
+  private static boolean isRetryableError(Task encodingTaskStatus) {
+    return encodingTaskStatus.getStatus() == Status.ERROR
+        && encodingTaskStatus.getError() != null
+        && encodingTaskStatus.getError().getRetryHint() != RetryHint.NO_RETRY;
+  }
+
+  private static List<String> getErrorMessages(Task task) {
+    return task.getMessages().stream()
+        .filter(msg -> msg.getType() == MessageType.ERROR)
+        .map(Message::getText)
+        .collect(Collectors.toList());
+  }
+
+  /**
+   * Creates an Encoding object in the organisation of the tenant and adds a stream and a muxing for
+   * each codec configuration of the tenant to it. This creates a fully configured encoding.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodings
+   *
+   * @param tenant The tenant the encoding is created for
+   * @param inputPath The path to the input file which should be used for the encoding
+   * @param encodingName A name for the encoding
+   * @param outputPath The path where the content of the encoding will be written to
+   */
+  private static Encoding createAndConfigureEncoding(
+      Tenant tenant, String inputPath, String encodingName, String outputPath)
+      throws BitmovinException {
+    Encoding encoding = new Encoding();
+    encoding.setName(encodingName);
+    new BudgetTag(configProvider).applyTo(encoding);
+
+    encoding = tenant.api.encoding.encodings.create(encoding);
+
+    for (CodecConfiguration codecConfig : tenant.codecConfigs) {
+      Stream stream = createStream(tenant.api, encoding, tenant.input, inputPath, codecConfig);
+
+      String muxingOutputPath;
+      if (codecConfig instanceof VideoConfiguration) {
+        muxingOutputPath =
+            String.format(
+                "%s/video/%s", outputPath, ((VideoConfiguration) codecConfig).getHeight());
+      } else {
+        muxingOutputPath =
+            String.format(
+                "%s/audio/%s", outputPath, ((AudioConfiguration) codecConfig).getBitrate() / 1000);
+      }
+      createFmp4Muxing(tenant.api, encoding, stream, tenant.output, muxingOutputPath);
+    }
+    return encoding;
+  }
+
+  /**
+   * Creates a fragmented MP4 muxing. This will generate segments with a given segment length for
+   * adaptive streaming.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/all#/Encoding/PostEncodingEncodingsMuxingsFmp4ByEncodingId
+   *
+   * @param api The API client of the tenant
+   * @param encoding The encoding to add the FMP4 muxing to
+   * @param stream The stream that is associated with the muxing
+   * @param output The output that should be used for the muxing to write the segments to
+   * @param outputPath The output path where the fragmented segments will be written to
+   */
+  private static Fmp4Muxing createFmp4Muxing(
+      BitmovinApi api, Encoding encoding, Stream stream, Output output, String outputPath)
+      throws BitmovinException {
+    MuxingStream muxingStream = new MuxingStream();
+    muxingStream.setStreamId(stream.getId());
+
+    Fmp4Muxing muxing = new Fmp4Muxing();
+    muxing.addOutputsItem(buildEncodingOutput(output, outputPath));
+    muxing.addStreamsItem(muxingStream);
+    muxing.setSegmentLength(4.0);
+    SegmentSharding.apply(configProvider, muxing);
+
+    return api.encoding.encodings.muxings.fmp4.create(encoding.getId(), muxing);
+  }
+
+  /**
+   * Creates a stream which binds an input file and input stream to a codec configuration. The
+   * stream is used for muxings later on.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsStreamsByEncodingId
+   *
+   * @param api The API client of the tenant
+   * @param encoding The encoding to add the stream to
+   * @param input The input that should be used
+   * @param inputPath The path to the input file
+   * @param codecConfiguration The codec configuration to be applied to the stream
+   */
+  private static Stream createStream(
+      BitmovinApi api,
+      Encoding encoding,
+      Input input,
+      String inputPath,
+      CodecConfiguration codecConfiguration)
+      throws BitmovinException {
+
+    StreamInput streamInput =
+        PreviewTrimming.createStreamInput(api, configProvider, encoding, input, inputPath);
+
+    Stream stream = new Stream();
+    stream.addInputStreamsItem(streamInput);
+    stream.setCodecConfigId(codecConfiguration.getId());
+
+    return api.encoding.encodings.streams.create(encoding.getId(), stream);
+  }
+
+  /**
+   * Creates a configuration for the H.264 video codec to be applied to video streams.
+   *
+   * <p>The output resolution is defined by setting only the height. Width will be determined
+   * automatically to maintain the aspect ratio of your input video.
+   *
+   * <p>To keep things simple, we use a quality-optimized VoD preset configuration, which will apply
+   * proven settings for the codec. See <a
+   * href="https://bitmovin.com/docs/encoding/tutorials/how-to-optimize-your-h264-codec-configuration-for-different-use-cases">How
+   * to optimize your H264 codec configuration for different use-cases</a> for alternative presets.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsVideoH264
+   *
+   * @param api The API client of the tenant
+   * @param height The height of the output video
+   * @param bitrate The target bitrate of the output video
+   */
+  private static H264VideoConfiguration createH264VideoConfig(
+      BitmovinApi api, int height, long bitrate) throws BitmovinException {
+    H264VideoConfiguration config = new H264VideoConfiguration();
+    config.setName(String.format("H.264 %dp", height));
+    config.setPresetConfiguration(PresetConfiguration.VOD_STANDARD);
+    config.setHeight(height);
+    config.setBitrate(bitrate);
+
+    return api.encoding.configurations.video.h264.create(config);
+  }
+
+  /**
+   * Creates a configuration for the AAC audio codec to be applied to audio streams.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsAudioAac
+   *
+   * @param api The API client of the tenant
+   */
+  private static AacAudioConfiguration createAacAudioConfig(BitmovinApi api)
+      throws BitmovinException {
+    AacAudioConfiguration config = new AacAudioConfiguration();
+    config.setName("AAC 128 kbit/s");
+    config.setBitrate(128_000L);
+
+    return api.encoding.configurations.audio.aac.create(config);
+  }
+
+  /**
+   * Creates a resource representing an HTTP server providing the input files. For alternative input
+   * methods see <a
+   * href="https://bitmovin.com/docs/encoding/articles/supported-input-output-storages">list of
+   * supported input and output storages</a>
+   *
+   * <p>For reasons of simplicity, a new input resource is created on each execution of this
+   * example. In production use, this method should be replaced by a <a
+   * href="https://bitmovin.com/docs/encoding/api-reference/sections/inputs#/Encoding/GetEncodingInputsHttpByInputId">get
+   * call</a> to retrieve an existing resource.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/inputs#/Encoding/PostEncodingInputsHttp
+   *
+   * @param api The API client of the tenant
+   * @param host The hostname or IP address of the HTTP server e.g.: my-storage.biz
+   */
+  private static HttpInput createHttpInput(BitmovinApi api, String host) throws BitmovinException {
+    HttpInput input = new HttpInput();
+    input.setHost(host);
+
+    return api.encoding.inputs.http.create(input);
+  }
+
+  /**
+   * Creates a resource representing an AWS S3 cloud storage bucket to which generated content will
+   * be transferred. For alternative output methods see <a
+   * href="https://bitmovin.com/docs/encoding/articles/supported-input-output-storages">list of
+   * supported input and output storages</a>
+   *
+   * <p>The provided credentials need to allow <i>read</i>, <i>write</i> and <i>list</i> operations.
+   * <i>delete</i> should also be granted to allow overwriting of existings files. See <a
+   * href="https://bitmovin.com/docs/encoding/faqs/how-do-i-create-a-aws-s3-bucket-which-can-be-used-as-output-location">creating
+   * an S3 bucket and setting permissions</a> for further information
+   *
+   * <p>For reasons of simplicity, a new output resource is created on each execution of this
+   * example. In production use, this method should be replaced by a <a
+   * href="https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/GetEncodingOutputsS3">get
+   * call</a> retrieving an existing resource.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/PostEncodingOutputsS3
+   *
+   * @param api The API client of the tenant
+   * @param bucketName The name of the S3 bucket
+   * @param accessKey The access key of your S3 account
+   * @param secretKey The secret key of your S3 account
+   */
+  private static S3Output createS3Output(
+      BitmovinApi api, String bucketName, String accessKey, String secretKey)
+      throws BitmovinException {
+    S3Output s3Output = new S3Output();
+    s3Output.setBucketName(bucketName);
+    s3Output.setAccessKey(accessKey);
+    s3Output.setSecretKey(secretKey);
+
+    return api.encoding.outputs.s3.create(s3Output);
+  }
+
+  /**
+   * Builds an EncodingOutput object which defines where the output content (e.g. of a muxing) will
+   * be written to. Public read permissions will be set for the files written, so they can be
+   * accessed easily via HTTP.
+   *
+   * @param output The output resource to be used by the EncodingOutput
+   * @param outputPath The path where the content will be written to
+   */
+  private static EncodingOutput buildEncodingOutput(Output output, String outputPath) {
+    AclEntry aclEntry = new AclEntry();
+    aclEntry.setPermission(AclPermission.PUBLIC_READ);
+
+    EncodingOutput encodingOutput = new EncodingOutput();
+    encodingOutput.setOutputPath(buildAbsolutePath(outputPath));
+    encodingOutput.setOutputId(output.getId());
+    encodingOutput.addAclItem(aclEntry);
+    return encodingOutput;
+  }
+
+  /**
+   * Builds an absolute path by concatenating the S3_OUTPUT_BASE_PATH configuration parameter, the
+   * name of this example class and the given relative path
+   *
+   * <p>e.g.: /s3/base/path/ClassName/relative/path
+   *
+   * @param relativePath The relative path that is concatenated
+   * @return The absolute path
+   */
+  public static String buildAbsolutePath(String relativePath) {
+    String className = MultiTenantBatchEncoding.class.getSimpleName();
+    return Paths.get(configProvider.getS3OutputBasePath(), className, relativePath).toString();
+  }
+
+  /**
+   * Helper class holding the API client of a tenant profile and the resources created in the
+   * organisation of the tenant
+   */
+  private static class Tenant {
+
+    private String profile;
+    private BitmovinApi api;
+    private Input input;
+    private Output output;
+    private List<CodecConfiguration> codecConfigs;
+
+    private Tenant(String profile, BitmovinApi api) {
+      this.profile = profile;
+      this.api = api;
+    }
+  }
+
+  /**
+   * Helper class managing the encodings to be processed in the batch
+   *
+   * <p>NOTE: The job list is read from a file on each execution of the example. For production
+   * use, we suggest using a persistent data store (eg. a database) to save and reload the job list
+   * including the status of each job.
+   */
+  private static class JobDispatcher {
+
+    private List<EncodingJob> encodingJobs = new ArrayList<>();
+
+    public JobDispatcher(String jobsFilePath) throws Exception {
+      List<String> lines = Files.readAllLines(Paths.get(jobsFilePath), StandardCharsets.UTF_8);
+      for (String line : lines) {
+        if (StringUtils.isBlank(line) || line.trim().startsWith("#")) {
+          continue;
+        }
+        String[] fields = line.split(",");
+        if (fields.length != 4) {
+          throw new IllegalArgumentException(
+              String.format(
+                  "Invalid job '%s', expected: profile,inputFilePath,outputPath,encodingName",
+                  line));
+        }
+        encodingJobs.add(
+            new EncodingJob(
+                fields[0].trim(), fields[1].trim(), fields[2].trim(), fields[3].trim()));
+      }
+    }
+
+    public List<String> getProfiles() {
+      return encodingJobs.stream().map(job -> job.profile).distinct().collect(Collectors.toList());
+    }
+
+    public List<EncodingJob> getJobsToStart(String profile, long limit) {
+      return encodingJobs.stream()
+          .filter(job -> job.profile.equals(profile) && job.status == EncodingJobStatus.WAITING)
+          .limit(limit)
+          .collect(Collectors.toList());
+    }
+
+    public List<EncodingJob> getStartedJobs() {
+      return encodingJobs.stream()
+          .filter(job -> job.status == EncodingJobStatus.STARTED)
+          .collect(Collectors.toList());
+    }
+
+    public boolean allJobsFinished() {
+      return encodingJobs.stream()
+          .allMatch(
+              job ->
+                  job.status == EncodingJobStatus.SUCCESSFUL
+                      || job.status == EncodingJobStatus.GIVEN_UP);
+    }
+
+    public void logSummary() {
+      for (String profile : getProfiles()) {
+        List<EncodingJob> jobs =
+            encodingJobs.stream()
+                .filter(job -> job.profile.equals(profile))
+                .collect(Collectors.toList());
+        long successful =
+            jobs.stream().filter(job -> job.status == EncodingJobStatus.SUCCESSFUL).count();
+        logger.info(
+            "[{}] {} of {} encodings finished successfully", profile, successful, jobs.size());
+      }
+    }
+
+    public void logFailedJobs() {
+      encodingJobs.stream()
+          .filter(job -> job.status == EncodingJobStatus.GIVEN_UP)
+          .forEach(
+              encodingJob ->
+                  logger.error(
+                      "[{}] Encoding {} ('{}') could not be finished successfully: {}",
+                      encodingJob.profile,
+                      encodingJob.encodingId,
+                      encodingJob.encodingName,
+                      encodingJob.errorMessages));
+    }
+  }
+
+  /**
+   * Helper class representing a single job in the batch, holding config values and keeping track of
+   * its status
+   */
+  private static class EncodingJob {
+
+    private String profile;
+    private String encodingName;
+    private String inputFilePath;
+    private String outputPath;
+    private String encodingId;
+    private int retryCount;
+    private EncodingJobStatus status;
+    private List<String> errorMessages = new ArrayList<>();
+
+    private EncodingJob(
+        String profile, String inputFilePath, String outputPath, String encodingName) {
+      this.profile = profile;
+      this.inputFilePath = inputFilePath;
+      this.outputPath = outputPath;
+      this.encodingName = encodingName;
+      this.status = EncodingJobStatus.WAITING;
+    }
+  }
+
+  public enum EncodingJobStatus {
+    WAITING,
+    STARTED,
+    SUCCESSFUL,
+    GIVEN_UP
+  }
+}
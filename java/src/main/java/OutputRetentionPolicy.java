@@ -0,0 +1,405 @@
+import com.bitmovin.api.sdk.BitmovinApi;
+import com.bitmovin.api.sdk.common.BitmovinException;
+import com.bitmovin.api.sdk.encoding.encodings.EncodingListQueryParams;
+import com.bitmovin.api.sdk.encoding.encodings.muxings.MuxingListQueryParams;
+import com.bitmovin.api.sdk.encoding.manifests.dash.DashManifestListQueryParams;
+import com.bitmovin.api.sdk.encoding.manifests.hls.HlsManifestListQueryParams;
+import com.bitmovin.api.sdk.model.AwsCloudRegion;
+import com.bitmovin.api.sdk.model.DashManifest;
+import com.bitmovin.api.sdk.model.Encoding;
+import com.bitmovin.api.sdk.model.EncodingOutput;
+import com.bitmovin.api.sdk.model.HlsManifest;
+import com.bitmovin.api.sdk.model.Muxing;
+import com.bitmovin.api.sdk.model.OutputType;
+import com.bitmovin.api.sdk.model.S3Output;
+import common.ApiClientFactory;
+import common.ConfigProvider;
+import java.nio.file.Paths;
+import java.time.Duration;
+import java.time.Instant;
+import java.util.ArrayList;
+import java.util.Collections;
+import java.util.Date;
+import java.util.HashMap;
+import java.util.List;
+import java.util.Locale;
+import java.util.Map;
+import java.util.stream.Collectors;
+import org.apache.commons.lang3.StringUtils;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import software.amazon.awssdk.auth.credentials.AwsBasicCredentials;
+import software.amazon.awssdk.auth.credentials.StaticCredentialsProvider;
+import software.amazon.awssdk.regions.Region;
+import software.amazon.awssdk.services.s3.S3Client;
+import software.amazon.awssdk.services.s3.model.Delete;
+import software.amazon.awssdk.services.s3.model.DeleteObjectRequest;
+import software.amazon.awssdk.services.s3.model.DeleteObjectsRequest;
+import software.amazon.awssdk.services.s3.model.ListObjectsV2Request;
+import software.amazon.awssdk.services.s3.model.ObjectIdentifier;
+import software.amazon.awssdk.services.s3.model.S3Object;
+
+/**
+ * This tool enforces a retention period for temporary outputs, e.g. of test encodings or preview
+ * renditions that are not needed any more after review. Encodings are marked as temporary by adding
+ * the label "temporary" when creating them, e.g. with
+ * encoding.setLabels(Arrays.asList("temporary")).
+ *
+ * <p>All encodings carrying the retention label that have been created before the retention period
+ * are listed. For every encoding, the files written by its muxings and the files of its DASH and
+ * HLS manifests are deleted from the storage, and the manifest resources are deleted from the
+ * Bitmovin API. The encoding resources themselves are kept, so their statistics remain available
+ * for reporting.
+ *
+ * <p>As several encodings may write to the same output path, e.g. when a test encoding is repeated,
+ * only the files below the output path of a muxing which have been written while the encoding was
+ * running are deleted. Files written by other encodings before or after it are kept. Encodings
+ * which have not ended yet are skipped.
+ *
+ * <p>Deleting files is only supported for S3 outputs, using the credentials of the S3 output bucket
+ * from the configuration. Files of other output types are skipped with a warning. Encrypted
+ * segments that are written by DRM configurations instead of muxings are not covered by this tool.
+ *
+ * <p>By default, the tool only logs what would be deleted. Set RETENTION_DRY_RUN to false to
+ * actually delete files and resources. As deleted files cannot be recovered, run it in dry run mode
+ * first and check its output.
+ *
+ * <p>The following configuration parameters are expected:
+ *
+ * <ul>
+ *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin API
+ *   <li>BITMOVIN_TENANT_ORG_ID - (optional) The ID of the Organisation in which you want to perform
+ *       the encoding.
+ *   <li>BITMOVIN_API_BASE_URL - (optional) The base URL of the Bitmovin API, e.g. of a mock server
+ *       or an API gateway. Default: https://api.bitmovin.com/v1
+ *   <li>S3_OUTPUT_ACCESS_KEY - The access key of your S3 output bucket
+ *   <li>S3_OUTPUT_SECRET_KEY - The secret key of your S3 output bucket
+ *   <li>RETENTION_DAYS - (optional) The number of days after which temporary outputs are deleted.
+ *       Default: 30
+ *   <li>RETENTION_LABEL - (optional) The label marking encodings whose outputs may be deleted.
+ *       Default: temporary
+ *   <li>RETENTION_DRY_RUN - (optional) If true, nothing is deleted and the files and resources that
+ *       would be deleted are logged. Default: true
+ * </ul>
+ *
+ * <p>Configuration parameters will be retrieved from these sources in the listed order:
+ *
+ * <ol>
+ *   <li>command line arguments (eg BITMOVIN_API_KEY=xyz)
+ *   <li>properties file located in the root folder of the JAVA examples at ./examples.properties
+ *       (see examples.properties.template as reference)
+ *   <li>environment variables
+ *   <li>properties file located in the home folder at ~/.bitmovin/examples.properties (see
+ *       examples.properties.template as reference)
+ * </ol>
+ */
+public class OutputRetentionPolicy {
+  private static final Logger logger = LoggerFactory.getLogger(OutputRetentionPolicy.class);
+
+  private static BitmovinApi bitmovinApi;
+  private static ConfigProvider configProvider;
+
+  private static final int PAGE_SIZE = 100;
+
+  /** The time files may be written before the start or after the end of an encoding */
+  private static final Duration WRITE_TIME_TOLERANCE = Duration.ofMinutes(1);
+
+  private static boolean dryRun;
+  private static Map<String, S3Output> s3Outputs = new HashMap<>();
+
+  public static void main(String[] args) throws Exception {
+    configProvider = new ConfigProvider(args);
+    bitmovinApi =
+        ApiClientFactory.builder(configProvider)
+            .withApiKey(configProvider.getBitmovinApiKey())
+            .build();
+
+    int retentionDays = Integer.parseInt(configProvider.getParameterByKey("RETENTION_DAYS", "30"));
+    String label = configProvider.getParameterByKey("RETENTION_LABEL", "temporary");
+    dryRun = Boolean.parseBoolean(configProvider.getParameterByKey("RETENTION_DRY_RUN", "true"));
+
+    Instant retentionStart = Instant.now().minus(Duration.ofDays(retentionDays));
+    List<Encoding> encodings = listExpiredEncodings(label, retentionStart);
+    logger.info(
+        "Found {} encodings labelled '{}' created before {}",
+        encodings.size(),
+        label,
+        retentionStart);
+
+    for (Encoding encoding : encodings) {
+      logger.info(
+          "Applying retention policy to encoding {} ({})", encoding.getId(), encoding.getName());
+
+      Date startedAt =
+          encoding.getStartedAt() != null ? encoding.getStartedAt() : encoding.getCreatedAt();
+      Date endedAt =
+          encoding.getFinishedAt() != null ? encoding.getFinishedAt() : encoding.getErrorAt();
+      if (endedAt == null) {
+        logger.warn("Skipping encoding {}, which has not ended yet", encoding.getId());
+        continue;
+      }
+
+      for (Muxing muxing : listMuxings(encoding.getId())) {
+        for (EncodingOutput output : nullToEmpty(muxing.getOutputs())) {
+          deleteFiles(output, startedAt.toInstant(), endedAt.toInstant());
+        }
+      }
+
+      for (DashManifest dashManifest : listDashManifests(encoding.getId())) {
+        for (EncodingOutput output : nullToEmpty(dashManifest.getOutputs())) {
+          deleteFile(output, dashManifest.getManifestName());
+        }
+        deleteManifest("DASH", dashManifest.getId());
+      }
+
+      for (HlsManifest hlsManifest : listHlsManifests(encoding.getId())) {
+        for (EncodingOutput output : nullToEmpty(hlsManifest.getOutputs())) {
+          deleteFile(output, hlsManifest.getManifestName());
+        }
+        deleteManifest("HLS", hlsManifest.getId());
+      }
+    }
+
+    if (dryRun) {
+      logger.info("Dry run finished, set RETENTION_DRY_RUN=false to delete the listed items");
+    }
+  }
+
+  /**
+   * Lists all encodings with the given label which have been created before the given instant,
+   * requesting as many pages as needed.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/GetEncodingEncodings
+   *
+   * @param label The label the encodings have to carry
+   * @param createdBefore The instant the encodings have to be created before
+   */
+  private static List<Encoding> listExpiredEncodings(String label, Instant createdBefore)
+      throws BitmovinException {
+    EncodingListQueryParams queryParams = new EncodingListQueryParams();
+    queryParams.setLabels(label);
+    queryParams.setCreatedAtOlderThan(Date.from(createdBefore));
+    queryParams.setLimit(PAGE_SIZE);
+
+    List<Encoding> encodings = new ArrayList<>();
+    List<Encoding> page;
+    do {
+      queryParams.setOffset(encodings.size());
+      page = bitmovinApi.encoding.encodings.list(queryParams).getItems();
+      encodings.addAll(page);
+    } while (page.size() == PAGE_SIZE);
+
+    return encodings;
+  }
+
+  /**
+   * Lists the muxings of all types of an encoding
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/GetEncodingEncodingsMuxingsByEncodingId
+   *
+   * @param encodingId The ID of the encoding
+   */
+  private static List<Muxing> listMuxings(String encodingId) throws BitmovinException {
+    MuxingListQueryParams queryParams = new MuxingListQueryParams();
+    queryParams.setLimit(PAGE_SIZE);
+    return bitmovinApi.encoding.encodings.muxings.list(encodingId, queryParams).getItems();
+  }
+
+  /**
+   * Lists the DASH manifests created for an encoding
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/GetEncodingManifestsDash
+   *
+   * @param encodingId The ID of the encoding
+   */
+  private static List<DashManifest> listDashManifests(String encodingId)
+      throws BitmovinException {
+    DashManifestListQueryParams queryParams = new DashManifestListQueryParams();
+    queryParams.setEncodingId(encodingId);
+    queryParams.setLimit(PAGE_SIZE);
+    return bitmovinApi.encoding.manifests.dash.list(queryParams).getItems();
+  }
+
+  /**
+   * Lists the HLS manifests created for an encoding
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/GetEncodingManifestsHls
+   *
+   * @param encodingId The ID of the encoding
+   */
+  private static List<HlsManifest> listHlsManifests(String encodingId) throws BitmovinException {
+    HlsManifestListQueryParams queryParams = new HlsManifestListQueryParams();
+    queryParams.setEncodingId(encodingId);
+    queryParams.setLimit(PAGE_SIZE);
+    return bitmovinApi.encoding.manifests.hls.list(queryParams).getItems();
+  }
+
+  /**
+   * Deletes a DASH or HLS manifest resource
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/DeleteEncodingManifestsDashByManifestId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/DeleteEncodingManifestsHlsByManifestId
+   *
+   * @param type The type of the manifest, either "DASH" or "HLS"
+   * @param manifestId The ID of the manifest
+   */
+  private static void deleteManifest(String type, String manifestId) throws BitmovinException {
+    logger.info("{}Deleting {} manifest resource {}", dryRunPrefix(), type, manifestId);
+    if (dryRun) {
+      return;
+    }
+
+    if (type.equals("DASH")) {
+      bitmovinApi.encoding.manifests.dash.delete(manifestId);
+    } else {
+      bitmovinApi.encoding.manifests.hls.delete(manifestId);
+    }
+  }
+
+  /**
+   * Deletes the files below the output path of a muxing output which have been written in the given
+   * time window. As the clocks of the storage and the encoder may differ slightly, the time window
+   * is extended by {@link #WRITE_TIME_TOLERANCE} on both sides.
+   *
+   * @param output The output of the muxing
+   * @param startedAt The time the encoding has been started
+   * @param endedAt The time the encoding has finished or failed
+   */
+  private static void deleteFiles(EncodingOutput output, Instant startedAt, Instant endedAt)
+      throws BitmovinException {
+    S3Output s3Output = getS3Output(output.getOutputId());
+    if (s3Output == null) {
+      return;
+    }
+
+    String prefix = StringUtils.strip(output.getOutputPath(), "/");
+    // never delete the whole bucket because of an output writing to its root
+    if (prefix.isEmpty()) {
+      logger.warn("Skipping output {} writing to the root of the bucket", output.getOutputId());
+      return;
+    }
+
+    try (S3Client s3Client = createS3Client(s3Output)) {
+      ListObjectsV2Request listRequest =
+          ListObjectsV2Request.builder()
+              .bucket(s3Output.getBucketName())
+              .prefix(prefix + "/")
+              .build();
+
+      Instant writtenAfter = startedAt.minus(WRITE_TIME_TOLERANCE);
+      Instant writtenBefore = endedAt.plus(WRITE_TIME_TOLERANCE);
+
+      for (List<S3Object> objects :
+          s3Client.listObjectsV2Paginator(listRequest).stream()
+              .map(
+                  response ->
+                      response.contents().stream()
+                          .filter(object -> object.lastModified().isAfter(writtenAfter))
+                          .filter(object -> object.lastModified().isBefore(writtenBefore))
+                          .collect(Collectors.toList()))
+              .collect(Collectors.toList())) {
+        if (objects.isEmpty()) {
+          continue;
+        }
+        logger.info(
+            "{}Deleting {} files below s3://{}/{}/",
+            dryRunPrefix(),
+            objects.size(),
+            s3Output.getBucketName(),
+            prefix);
+        if (dryRun) {
+          continue;
+        }
+
+        List<ObjectIdentifier> identifiers = new ArrayList<>();
+        for (S3Object object : objects) {
+          identifiers.add(ObjectIdentifier.builder().key(object.key()).build());
+        }
+        s3Client.deleteObjects(
+            DeleteObjectsRequest.builder()
+                .bucket(s3Output.getBucketName())
+                .delete(Delete.builder().objects(identifiers).build())
+                .build());
+      }
+    }
+  }
+
+  /**
+   * Deletes a single file of a manifest output
+   *
+   * @param output The output of the manifest
+   * @param fileName The name of the file, relative to the output path
+   */
+  private static void deleteFile(EncodingOutput output, String fileName) throws BitmovinException {
+    S3Output s3Output = getS3Output(output.getOutputId());
+    if (s3Output == null || fileName == null) {
+      return;
+    }
+
+    String key = StringUtils.strip(Paths.get(output.getOutputPath(), fileName).toString(), "/");
+    logger.info("{}Deleting s3://{}/{}", dryRunPrefix(), s3Output.getBucketName(), key);
+    if (dryRun) {
+      return;
+    }
+
+    try (S3Client s3Client = createS3Client(s3Output)) {
+      s3Client.deleteObject(
+          DeleteObjectRequest.builder().bucket(s3Output.getBucketName()).key(key).build());
+    }
+  }
+
+  /**
+   * Retrieves the S3 output resource with the given ID, or null if the output is of another type.
+   * The resources are cached, as many muxings usually write to the same output.
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/GetEncodingOutputsTypeByOutputId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/GetEncodingOutputsS3ByOutputId
+   *
+   * @param outputId The ID of the output resource
+   */
+  private static S3Output getS3Output(String outputId) throws BitmovinException {
+    if (!s3Outputs.containsKey(outputId)) {
+      OutputType type = bitmovinApi.encoding.outputs.type.get(outputId).getType();
+      if (type == OutputType.S3) {
+        s3Outputs.put(outputId, bitmovinApi.encoding.outputs.s3.get(outputId));
+      } else {
+        logger.warn("Skipping files of output {} with unsupported type {}", outputId, type);
+        s3Outputs.put(outputId, null);
+      }
+    }
+    return s3Outputs.get(outputId);
+  }
+
+  /**
+   * Creates an AWS S3 client with the credentials of the S3 output bucket from the configuration
+   *
+   * @param s3Output The S3 output resource, which provides the region of the bucket
+   */
+  private static S3Client createS3Client(S3Output s3Output) {
+    AwsCloudRegion cloudRegion =
+        s3Output.getCloudRegion() != null ? s3Output.getCloudRegion() : AwsCloudRegion.US_EAST_1;
+
+    return S3Client.builder()
+        .credentialsProvider(
+            StaticCredentialsProvider.create(
+                AwsBasicCredentials.create(
+                    configProvider.getS3OutputAccessKey(), configProvider.getS3OutputSecretKey())))
+        // AWS region names use dashes instead of the underscores of the enum constants
+        .region(Region.of(cloudRegion.name().toLowerCase(Locale.ROOT).replace('_', '-')))
+        .build();
+  }
+
+  private static String dryRunPrefix() {
+    return dryRun ? "[dry run] " : "";
+  }
+
+  private static <T> List<T> nullToEmpty(List<T> list) {
+    return list != null ? list : Collections.emptyList();
+  }
+}
@@ -0,0 +1,522 @@
+import com.bitmovin.api.sdk.BitmovinApi;
+import com.bitmovin.api.sdk.common.BitmovinException;
+import com.bitmovin.api.sdk.model.AacAudioConfiguration;
+import com.bitmovin.api.sdk.model.AclEntry;
+import com.bitmovin.api.sdk.model.AclPermission;
+import com.bitmovin.api.sdk.model.CodecConfiguration;
+import com.bitmovin.api.sdk.model.CropFilter;
+import com.bitmovin.api.sdk.model.Encoding;
+import com.bitmovin.api.sdk.model.EncodingOutput;
+import com.bitmovin.api.sdk.model.Filter;
+import com.bitmovin.api.sdk.model.H264VideoConfiguration;
+import com.bitmovin.api.sdk.model.HttpInput;
+import com.bitmovin.api.sdk.model.IngestInputStream;
+import com.bitmovin.api.sdk.model.Input;
+import com.bitmovin.api.sdk.model.InputStream;
+import com.bitmovin.api.sdk.model.MessageType;
+import com.bitmovin.api.sdk.model.Mp4Muxing;
+import com.bitmovin.api.sdk.model.MuxingStream;
+import com.bitmovin.api.sdk.model.Output;
+import com.bitmovin.api.sdk.model.PositionUnit;
+import com.bitmovin.api.sdk.model.PresetConfiguration;
+import com.bitmovin.api.sdk.model.S3Output;
+import com.bitmovin.api.sdk.model.StartEncodingRequest;
+import com.bitmovin.api.sdk.model.Status;
+import com.bitmovin.api.sdk.model.Stream;
+import com.bitmovin.api.sdk.model.StreamFilter;
+import com.bitmovin.api.sdk.model.StreamFilterList;
+import com.bitmovin.api.sdk.model.StreamInput;
+import com.bitmovin.api.sdk.model.StreamMode;
+import com.bitmovin.api.sdk.model.StreamSelectionMode;
+import com.bitmovin.api.sdk.model.Task;
+import com.bitmovin.api.sdk.model.TimeBasedTrimmingInputStream;
+import common.ApiClientFactory;
+import common.BudgetTag;
+import common.ConfigProvider;
+import common.EncodingFailedException;
+import java.nio.file.Paths;
+import java.util.ArrayList;
+import java.util.Arrays;
+import java.util.Collections;
+import java.util.List;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+
+/**
+ * This example demonstrates how to extract multiple clips from a wide master, where each clip
+ * covers a different time range and a different 16:9 region of the picture (pan and scan). This is
+ * useful e.g. to create social media clips from a cinemascope or panoramic master, following the
+ * action in each scene.
+ *
+ * <p>For every clip, a time-based trimming input stream selects the time range from a shared ingest
+ * input stream. The video stream of the clip is then cropped to the clip's region with a crop
+ * filter, which is applied before the video is scaled to the output resolution. All clips are
+ * processed by a single encoding and written to separate MP4 files.
+ *
+ * <p>Make sure to set the resolution of your input file in the {@code inputWidth} and {@code
+ * inputHeight} fields, so the crop regions can be calculated.
+ *
+ * <p>The following configuration parameters are expected:
+ *
+ * <ul>
+ *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin API
+ *   <li>BITMOVIN_TENANT_ORG_ID - (optional) The ID of the Organisation in which you want to perform
+ *       the encoding.
+ *   <li>BITMOVIN_API_BASE_URL - (optional) The base URL of the Bitmovin API, e.g. of a mock server
+ *       or an API gateway. Default: https://api.bitmovin.com/v1
+ *   <li>HTTP_INPUT_HOST - The Hostname or IP address of the HTTP server hosting your input files,
+ *       e.g.: my-storage.biz
+ *   <li>HTTP_INPUT_FILE_PATH - The path to your input file on the provided HTTP server Example:
+ *       videos/1080p_Sintel.mp4
+ *   <li>S3_OUTPUT_BUCKET_NAME - The name of your S3 output bucket. Example: my-bucket-name
+ *   <li>S3_OUTPUT_ACCESS_KEY - The access key of your S3 output bucket
+ *   <li>S3_OUTPUT_SECRET_KEY - The secret key of your S3 output bucket
+ *   <li>S3_OUTPUT_BASE_PATH - The base path on your S3 output bucket where content will be written.
+ *       Example: /outputs
+ * </ul>
+ *
+ * <p>Configuration parameters will be retrieved from these sources in the listed order:
+ *
+ * <ol>
+ *   <li>command line arguments (eg BITMOVIN_API_KEY=xyz)
+ *   <li>properties file located in the root folder of the JAVA examples at ./examples.properties
+ *       (see examples.properties.template as reference)
+ *   <li>environment variables
+ *   <li>properties file located in the home folder at ~/.bitmovin/examples.properties (see
+ *       examples.properties.template as reference)
+ * </ol>
+ */
+public class PanScanClips {
+  private static final Logger logger = LoggerFactory.getLogger(PanScanClips.class);
+
+  private static BitmovinApi bitmovinApi;
+  private static ConfigProvider configProvider;
+
+  /** Make sure to set the correct resolution of your input video */
+  private static int inputWidth = 4096;
+
+  private static int inputHeight = 1716;
+
+  /**
+   * This list defines the clips that will be generated. The horizontal position of the 16:9 region
+   * is given as the offset of its left edge in pixels.
+   */
+  private static List<Clip> clips =
+      Arrays.asList(
+          new Clip("clip_left", 10, 15, 0),
+          new Clip("clip_center", 60, 20, 522),
+          new Clip("clip_right", 120, 10, 1045));
+
+  public static void main(String[] args) throws Exception {
+    configProvider = new ConfigProvider(args);
+    bitmovinApi =
+        ApiClientFactory.builder(configProvider)
+            .withApiKey(configProvider.getBitmovinApiKey())
+            .build();
+
+    Encoding encoding =
+        createEncoding("Pan and scan clips", "Encoding of clips with individual crop regions");
+
+    HttpInput input = createHttpInput(configProvider.getHttpInputHost());
+    Output output =
+        createS3Output(
+            configProvider.getS3OutputBucketName(),
+            configProvider.getS3OutputAccessKey(),
+            configProvider.getS3OutputSecretKey());
+
+    IngestInputStream ingestInputStream =
+        createIngestInputStream(encoding, input, configProvider.getHttpInputFilePath());
+
+    H264VideoConfiguration h264Config = createH264VideoConfig(1080, 4_800_000L);
+    AacAudioConfiguration aacConfig = createAacAudioConfig();
+
+    // The height of the master is kept, the width of the region is derived from the 16:9 ratio
+    int regionWidth = (int) Math.round(inputHeight * 16 / 9.0);
+
+    for (Clip clip : clips) {
+      if (clip.regionLeft + regionWidth > inputWidth) {
+        throw new IllegalArgumentException(
+            String.format("The region of %s exceeds the width of the input", clip.name));
+      }
+
+      TimeBasedTrimmingInputStream trimmedInputStream =
+          createTimeBasedTrimmingInputStream(
+              encoding, ingestInputStream, clip.offset, clip.duration);
+
+      Stream videoStream = createStream(encoding, trimmedInputStream, h264Config);
+      CropFilter cropFilter =
+          createCropFilter(clip.regionLeft, inputWidth - clip.regionLeft - regionWidth, 0, 0);
+      createStreamFilterList(encoding, videoStream, Collections.singletonList(cropFilter));
+
+      Stream audioStream = createStream(encoding, trimmedInputStream, aacConfig);
+
+      createMp4Muxing(
+          encoding, output, clip.name, Arrays.asList(videoStream, audioStream), clip.name + ".mp4");
+    }
+
+    executeEncoding(encoding);
+  }
+
+  private static class Clip {
+
+    private String name;
+    private double offset;
+    private double duration;
+    private int regionLeft;
+
+    /**
+     * @param name The name of the clip, used as folder and file name
+     * @param offset The position in the input file in seconds where the clip starts
+     * @param duration The duration of the clip in seconds
+     * @param regionLeft The offset of the left edge of the 16:9 region in pixels
+     */
+    private Clip(String name, double offset, double duration, int regionLeft) {
+      this.name = name;
+      this.offset = offset;
+      this.duration = duration;
+      this.regionLeft = regionLeft;
+    }
+  }
+
+  /**
+   * Creates a resource representing an HTTP server providing the input files. For alternative input
+   * methods see <a
+   * href="https://bitmovin.com/docs/encoding/articles/supported-input-output-storages">list of
+   * supported input and output storages</a>
+   *
+   * <p>For reasons of simplicity, a new input resource is created on each execution of this
+   * example. In production use, this method should be replaced by a <a
+   * href="https://bitmovin.com/docs/encoding/api-reference/sections/inputs#/Encoding/GetEncodingInputsHttpByInputId">get
+   * call</a> to retrieve an existing resource.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/inputs#/Encoding/PostEncodingInputsHttp
+   *
+   * @param host The hostname or IP address of the HTTP server e.g.: my-storage.biz
+   */
+  private static HttpInput createHttpInput(String host) throws BitmovinException {
+    HttpInput input = new HttpInput();
+    input.setHost(host);
+
+    return bitmovinApi.encoding.inputs.http.create(input);
+  }
+
+  /**
+   * Creates a resource representing an AWS S3 cloud storage bucket to which generated content will
+   * be transferred. For alternative output methods see <a
+   * href="https://bitmovin.com/docs/encoding/articles/supported-input-output-storages">list of
+   * supported input and output storages</a>
+   *
+   * <p>The provided credentials need to allow <i>read</i>, <i>write</i> and <i>list</i> operations.
+   * <i>delete</i> should also be granted to allow overwriting of existings files. See <a
+   * href="https://bitmovin.com/docs/encoding/faqs/how-do-i-create-a-aws-s3-bucket-which-can-be-used-as-output-location">creating
+   * an S3 bucket and setting permissions</a> for further information
+   *
+   * <p>For reasons of simplicity, a new output resource is created on each execution of this
+   * example. In production use, this method should be replaced by a <a
+   * href="https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/GetEncodingOutputsS3">get
+   * call</a> retrieving an existing resource.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/PostEncodingOutputsS3
+   *
+   * @param bucketName The name of the S3 bucket
+   * @param accessKey The access key of your S3 account
+   * @param secretKey The secret key of your S3 account
+   */
+  private static S3Output createS3Output(String bucketName, String accessKey, String secretKey)
+      throws BitmovinException {
+
+    S3Output s3Output = new S3Output();
+    s3Output.setBucketName(bucketName);
+    s3Output.setAccessKey(accessKey);
+    s3Output.setSecretKey(secretKey);
+
+    return bitmovinApi.encoding.outputs.s3.create(s3Output);
+  }
+
+  /**
+   * Creates an encoding object. This is the base object to configure your encoding.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodings
+   *
+   * @param name This is the name of the encoding
+   * @param description This is the description of the encoding
+   */
+  private static Encoding createEncoding(String name, String description) throws BitmovinException {
+    Encoding encoding = new Encoding();
+    encoding.setName(name);
+    encoding.setDescription(description);
+    new BudgetTag(configProvider).applyTo(encoding);
+
+    return bitmovinApi.encoding.encodings.create(encoding);
+  }
+
+  /**
+   * Creates an IngestInputStream and adds it to an encoding
+   *
+   * <p>The IngestInputStream is used to define where a file to read a stream from is located
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsInputStreamsIngestByEncodingId
+   *
+   * @param encoding The encoding to which the stream will be added
+   * @param input The input resource providing the input file
+   * @param inputPath The path to the input file
+   */
+  private static IngestInputStream createIngestInputStream(
+      Encoding encoding, Input input, String inputPath) throws BitmovinException {
+    IngestInputStream ingestInputStream = new IngestInputStream();
+    ingestInputStream.setInputId(input.getId());
+    ingestInputStream.setInputPath(inputPath);
+    ingestInputStream.setSelectionMode(StreamSelectionMode.AUTO);
+
+    return bitmovinApi.encoding.encodings.inputStreams.ingest.create(
+        encoding.getId(), ingestInputStream);
+  }
+
+  /**
+   * Creates a TimeBasedTrimmingInputStream, which only uses a time range of the given input stream
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsInputStreamsTrimmingTimeBasedByEncodingId
+   *
+   * @param encoding The encoding to which the input stream will be added
+   * @param inputStream The input stream to be trimmed
+   * @param offset The start of the time range in seconds
+   * @param duration The duration of the time range in seconds
+   */
+  private static TimeBasedTrimmingInputStream createTimeBasedTrimmingInputStream(
+      Encoding encoding, InputStream inputStream, double offset, double duration)
+      throws BitmovinException {
+    TimeBasedTrimmingInputStream trimmingInputStream = new TimeBasedTrimmingInputStream();
+    trimmingInputStream.setInputStreamId(inputStream.getId());
+    trimmingInputStream.setOffset(offset);
+    trimmingInputStream.setDuration(duration);
+
+    return bitmovinApi.encoding.encodings.inputStreams.trimming.timeBased.create(
+        encoding.getId(), trimmingInputStream);
+  }
+
+  /**
+   * Adds a video or audio stream to an encoding, by mapping a codec configuration to an input
+   * stream
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsStreamsByEncodingId
+   *
+   * @param encoding The encoding to which the stream will be added
+   * @param inputStream The inputStream resource providing the input file
+   * @param codecConfiguration The codec configuration to be applied to the stream
+   */
+  private static Stream createStream(
+      Encoding encoding, InputStream inputStream, CodecConfiguration codecConfiguration)
+      throws BitmovinException {
+    StreamInput streamInput = new StreamInput();
+    streamInput.setInputStreamId(inputStream.getId());
+
+    Stream stream = new Stream();
+    stream.addInputStreamsItem(streamInput);
+    stream.setCodecConfigId(codecConfiguration.getId());
+    stream.setMode(StreamMode.STANDARD);
+
+    return bitmovinApi.encoding.encodings.streams.create(encoding.getId(), stream);
+  }
+
+  /**
+   * Creates a crop filter, which removes the given number of pixels at each edge of the video, e.g.
+   * to remove black bars.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/filters#/Encoding/PostEncodingFiltersCrop
+   *
+   * @param left The number of pixels to be removed on the left edge
+   * @param right The number of pixels to be removed on the right edge
+   * @param top The number of pixels to be removed on the top edge
+   * @param bottom The number of pixels to be removed on the bottom edge
+   */
+  private static CropFilter createCropFilter(int left, int right, int top, int bottom)
+      throws BitmovinException {
+    CropFilter cropFilter = new CropFilter();
+    cropFilter.setName("Remove black bars");
+    cropFilter.setLeft(left);
+    cropFilter.setRight(right);
+    cropFilter.setTop(top);
+    cropFilter.setBottom(bottom);
+    cropFilter.setUnit(PositionUnit.PIXELS);
+
+    return bitmovinApi.encoding.filters.crop.create(cropFilter);
+  }
+
+  /**
+   * Creates a list of the previously configured filter resources and adds them to a video stream.
+   * The <i>position</i> property of each StreamFilter determines the order in which they are
+   * applied.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsStreamsFiltersByEncodingIdAndStreamId
+   *
+   * @param encoding The encoding to which the video stream belongs to
+   * @param stream The video stream to apply the filters to
+   * @param filters A list of filter resources that have been created previously
+   */
+  private static StreamFilterList createStreamFilterList(
+      Encoding encoding, Stream stream, List<Filter> filters) throws BitmovinException {
+    int position = 0;
+    List<StreamFilter> streamFilters = new ArrayList<>();
+
+    for (Filter filter : filters) {
+      StreamFilter streamFilter = new StreamFilter();
+      streamFilter.setId(filter.getId());
+      streamFilter.setPosition(position++);
+      streamFilters.add(streamFilter);
+    }
+
+    return bitmovinApi.encoding.encodings.streams.filters.create(
+        encoding.getId(), stream.getId(), streamFilters);
+  }
+
+  /**
+   * Creates a configuration for the H.264 video codec to be applied to video streams.
+   *
+   * <p>The output resolution is defined by setting only the height. Width will be determined
+   * automatically to maintain the aspect ratio of your input video.
+   *
+   * <p>To keep things simple, we use a quality-optimized VoD preset configuration, which will apply
+   * proven settings for the codec. See <a
+   * href="https://bitmovin.com/docs/encoding/tutorials/how-to-optimize-your-h264-codec-configuration-for-different-use-cases">How
+   * to optimize your H264 codec configuration for different use-cases</a> for alternative presets.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsVideoH264
+   *
+   * @param height The height of the output video
+   * @param bitrate The target bitrate of the output video
+   */
+  private static H264VideoConfiguration createH264VideoConfig(int height, long bitrate)
+      throws BitmovinException {
+    H264VideoConfiguration config = new H264VideoConfiguration();
+    config.setName(String.format("H.264 %dp", height));
+    config.setPresetConfiguration(PresetConfiguration.VOD_STANDARD);
+    config.setHeight(height);
+    config.setBitrate(bitrate);
+
+    return bitmovinApi.encoding.configurations.video.h264.create(config);
+  }
+
+  /**
+   * Creates a configuration for the AAC audio codec to be applied to audio streams.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsAudioAac
+   */
+  private static AacAudioConfiguration createAacAudioConfig() throws BitmovinException {
+    AacAudioConfiguration config = new AacAudioConfiguration();
+    config.setName("AAC 128 kbit/s");
+    config.setBitrate(128_000L);
+
+    return bitmovinApi.encoding.configurations.audio.aac.create(config);
+  }
+
+  /**
+   * Creates an MP4 muxing.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsMuxingsMp4ByEncodingId
+   *
+   * @param encoding The encoding to add the MP4 muxing to
+   * @param output The output that should be used for the muxing to write the segments to
+   * @param outputPath The output path where the fragments will be written to
+   * @param streams A list of streams to be added to the muxing
+   * @param fileName The name of the file that will be written to the output
+   */
+  private static Mp4Muxing createMp4Muxing(
+      Encoding encoding, Output output, String outputPath, List<Stream> streams, String fileName)
+      throws BitmovinException {
+    Mp4Muxing muxing = new Mp4Muxing();
+    muxing.addOutputsItem(buildEncodingOutput(output, outputPath));
+    muxing.setFilename(fileName);
+
+    for (Stream stream : streams) {
+      MuxingStream muxingStream = new MuxingStream();
+      muxingStream.setStreamId(stream.getId());
+      muxing.addStreamsItem(muxingStream);
+    }
+
+    return bitmovinApi.encoding.encodings.muxings.mp4.create(encoding.getId(), muxing);
+  }
+
+  /**
+   * Builds an EncodingOutput object which defines where the output content (e.g. of a muxing) will
+   * be written to. Public read permissions will be set for the files written, so they can be
+   * accessed easily via HTTP.
+   *
+   * @param output The output resource to be used by the EncodingOutput
+   * @param outputPath The path where the content will be written to
+   */
+  private static EncodingOutput buildEncodingOutput(Output output, String outputPath) {
+    AclEntry aclEntry = new AclEntry();
+    aclEntry.setPermission(AclPermission.PUBLIC_READ);
+
+    EncodingOutput encodingOutput = new EncodingOutput();
+    encodingOutput.setOutputPath(buildAbsolutePath(outputPath));
+    encodingOutput.setOutputId(output.getId());
+    encodingOutput.addAclItem(aclEntry);
+    return encodingOutput;
+  }
+
+  /**
+   * Builds an absolute path by concatenating the S3_OUTPUT_BASE_PATH configuration parameter, the
+   * name of this example class and the given relative path
+   *
+   * <p>e.g.: /s3/base/path/ClassName/relative/path
+   *
+   * @param relativePath The relative path that is concatenated
+   * @return The absolute path
+   */
+  public static String buildAbsolutePath(String relativePath) {
+    String className = PanScanClips.class.getSimpleName();
+    return Paths.get(configProvider.getS3OutputBasePath(), className, relativePath).toString();
+  }
+
+  /**
+   * Starts the actual encoding process and periodically polls its status until it reaches a final
+   * state
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/all#/Encoding/PostEncodingEncodingsStartByEncodingId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/GetEncodingEncodingsStatusByEncodingId
+   *
+   * <p>Please note that you can also use our webhooks API instead of polling the status. For more
+   * information consult the API spec:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/notifications-webhooks
+   *
+   * @param encoding The encoding to be started
+   */
+  private static void executeEncoding(Encoding encoding)
+      throws InterruptedException, BitmovinException {
+    bitmovinApi.encoding.encodings.start(encoding.getId(), new StartEncodingRequest());
+
+    Task task;
+    do {
+      Thread.sleep(5000);
+      task = bitmovinApi.encoding.encodings.status(encoding.getId());
+      logger.info("encoding status is {} (progress: {} %)", task.getStatus(), task.getProgress());
+    } while (task.getStatus() != Status.FINISHED
+        && task.getStatus() != Status.ERROR
+        && task.getStatus() != Status.CANCELED);
+
+    if (task.getStatus() != Status.FINISHED) {
+      logTaskErrors(task);
+      throw new EncodingFailedException(task.getStatus());
+    }
+    logger.info("encoding finished successfully");
+  }
+
+  private static void logTaskErrors(Task task) {
+    task.getMessages().stream()
+        .filter(msg -> msg.getType() == MessageType.ERROR)
+        .forEach(msg -> logger.error(msg.getText()));
+  }
+}
@@ -0,0 +1,737 @@
+import com.bitmovin.api.sdk.BitmovinApi;
+import com.bitmovin.api.sdk.common.BitmovinException;
+import com.bitmovin.api.sdk.model.AacAudioConfiguration;
+import com.bitmovin.api.sdk.model.AclEntry;
+import com.bitmovin.api.sdk.model.AclPermission;
+import com.bitmovin.api.sdk.model.AudioAdaptationSet;
+import com.bitmovin.api.sdk.model.AudioMediaInfo;
+import com.bitmovin.api.sdk.model.CodecConfiguration;
+import com.bitmovin.api.sdk.model.DashFmp4Representation;
+import com.bitmovin.api.sdk.model.DashManifest;
+import com.bitmovin.api.sdk.model.DashProfile;
+import com.bitmovin.api.sdk.model.DashRepresentationType;
+import com.bitmovin.api.sdk.model.Encoding;
+import com.bitmovin.api.sdk.model.EncodingOutput;
+import com.bitmovin.api.sdk.model.Fmp4Muxing;
+import com.bitmovin.api.sdk.model.H264VideoConfiguration;
+import com.bitmovin.api.sdk.model.HlsManifest;
+import com.bitmovin.api.sdk.model.HttpInput;
+import com.bitmovin.api.sdk.model.IngestInputStream;
+import com.bitmovin.api.sdk.model.Input;
+import com.bitmovin.api.sdk.model.InputStream;
+import com.bitmovin.api.sdk.model.MessageType;
+import com.bitmovin.api.sdk.model.MuxingStream;
+import com.bitmovin.api.sdk.model.Output;
+import com.bitmovin.api.sdk.model.Period;
+import com.bitmovin.api.sdk.model.PresetConfiguration;
+import com.bitmovin.api.sdk.model.S3Output;
+import com.bitmovin.api.sdk.model.StartEncodingRequest;
+import com.bitmovin.api.sdk.model.Status;
+import com.bitmovin.api.sdk.model.Stream;
+import com.bitmovin.api.sdk.model.StreamInfo;
+import com.bitmovin.api.sdk.model.StreamInput;
+import com.bitmovin.api.sdk.model.StreamMode;
+import com.bitmovin.api.sdk.model.StreamSelectionMode;
+import com.bitmovin.api.sdk.model.Task;
+import com.bitmovin.api.sdk.model.TimeBasedTrimmingInputStream;
+import com.bitmovin.api.sdk.model.VideoAdaptationSet;
+import common.ApiClientFactory;
+import common.BudgetTag;
+import common.ConfigProvider;
+import common.EncodingFailedException;
+import common.SegmentSharding;
+import java.nio.file.Paths;
+import java.util.ArrayList;
+import java.util.Arrays;
+import java.util.List;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+
+/**
+ * This example demonstrates how to encode a full program and several highlight clips of it in a
+ * single encoding. Compared to separate encodings per clip, the input file is downloaded only once,
+ * which reduces the input transfer cost and the time until all assets are available.
+ *
+ * <p>The full program is encoded from an ingest input stream, each clip from a time-based trimming
+ * input stream selecting its time range from the same ingest input stream. Every asset gets the
+ * same bitrate ladder and is written to its own folder, together with a DASH and an HLS manifest
+ * that only reference the segments of that asset. As default manifests would reference the muxings
+ * of all assets, the manifests are created manually.
+ *
+ * <p>The following configuration parameters are expected:
+ *
+ * <ul>
+ *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin API
+ *   <li>BITMOVIN_TENANT_ORG_ID - (optional) The ID of the Organisation in which you want to perform
+ *       the encoding.
+ *   <li>BITMOVIN_API_BASE_URL - (optional) The base URL of the Bitmovin API, e.g. of a mock server
+ *       or an API gateway. Default: https://api.bitmovin.com/v1
+ *   <li>HTTP_INPUT_HOST - The Hostname or IP address of the HTTP server hosting your input files,
+ *       e.g.: my-storage.biz
+ *   <li>HTTP_INPUT_FILE_PATH - The path to your input file on the provided HTTP server Example:
+ *       videos/1080p_Sintel.mp4
+ *   <li>S3_OUTPUT_BUCKET_NAME - The name of your S3 output bucket. Example: my-bucket-name
+ *   <li>S3_OUTPUT_ACCESS_KEY - The access key of your S3 output bucket
+ *   <li>S3_OUTPUT_SECRET_KEY - The secret key of your S3 output bucket
+ *   <li>S3_OUTPUT_BASE_PATH - The base path on your S3 output bucket where content will be written.
+ *       Example: /outputs
+ * </ul>
+ *
+ * <p>Configuration parameters will be retrieved from these sources in the listed order:
+ *
+ * <ol>
+ *   <li>command line arguments (eg BITMOVIN_API_KEY=xyz)
+ *   <li>properties file located in the root folder of the JAVA examples at ./examples.properties
+ *       (see examples.properties.template as reference)
+ *   <li>environment variables
+ *   <li>properties file located in the home folder at ~/.bitmovin/examples.properties (see
+ *       examples.properties.template as reference)
+ * </ol>
+ */
+public class ProgramWithHighlightClips {
+  private static final Logger logger = LoggerFactory.getLogger(ProgramWithHighlightClips.class);
+
+  private static BitmovinApi bitmovinApi;
+  private static ConfigProvider configProvider;
+
+  /** This list defines the video renditions that will be generated for every asset */
+  private static List<VideoRendition> videoRenditions =
+      Arrays.asList(
+          new VideoRendition(1080, 4_800_000L),
+          new VideoRendition(720, 2_400_000L),
+          new VideoRendition(480, 1_200_000L));
+
+  /** This list defines the highlight clips that will be generated besides the full program */
+  private static List<Clip> clips =
+      Arrays.asList(
+          new Clip("highlight_1", 30, 20),
+          new Clip("highlight_2", 150, 15),
+          new Clip("highlight_3", 300, 25));
+
+  private static final String FULL_PROGRAM_NAME = "full";
+  private static final String AUDIO_GROUP_ID = "audio";
+  private static final String AUDIO_SEGMENT_PATH = "audio";
+
+  public static void main(String[] args) throws Exception {
+    configProvider = new ConfigProvider(args);
+    bitmovinApi =
+        ApiClientFactory.builder(configProvider)
+            .withApiKey(configProvider.getBitmovinApiKey())
+            .build();
+
+    Encoding encoding =
+        createEncoding("Program with highlight clips", "Encoding of a program and its highlights");
+
+    HttpInput input = createHttpInput(configProvider.getHttpInputHost());
+    Output output =
+        createS3Output(
+            configProvider.getS3OutputBucketName(),
+            configProvider.getS3OutputAccessKey(),
+            configProvider.getS3OutputSecretKey());
+
+    IngestInputStream ingestInputStream =
+        createIngestInputStream(encoding, input, configProvider.getHttpInputFilePath());
+
+    for (VideoRendition videoRendition : videoRenditions) {
+      videoRendition.codecConfiguration =
+          createH264VideoConfig(videoRendition.height, videoRendition.bitrate);
+    }
+    AacAudioConfiguration aacConfig = createAacAudioConfig();
+
+    List<Asset> assets = new ArrayList<>();
+    assets.add(createAsset(encoding, output, FULL_PROGRAM_NAME, ingestInputStream, aacConfig));
+
+    for (Clip clip : clips) {
+      TimeBasedTrimmingInputStream trimmedInputStream =
+          createTimeBasedTrimmingInputStream(
+              encoding, ingestInputStream, clip.offset, clip.duration);
+      assets.add(createAsset(encoding, output, clip.name, trimmedInputStream, aacConfig));
+    }
+
+    executeEncoding(encoding);
+
+    for (Asset asset : assets) {
+      createAssetDashManifest(encoding, output, asset);
+      createAssetHlsManifest(encoding, output, asset);
+    }
+  }
+
+  /**
+   * Creates the video streams of all renditions and the audio stream of an asset from the given
+   * input stream, and muxes them into fragmented MP4 segments in the folder of the asset
+   *
+   * @param encoding The encoding to which the streams will be added
+   * @param output The output resource to which the segments will be written to
+   * @param name The name of the asset, used as folder name
+   * @param inputStream The input stream providing the full program or the time range of a clip
+   * @param aacConfig The audio codec configuration
+   */
+  private static Asset createAsset(
+      Encoding encoding,
+      Output output,
+      String name,
+      InputStream inputStream,
+      AacAudioConfiguration aacConfig)
+      throws BitmovinException {
+    Asset asset = new Asset(name);
+
+    for (VideoRendition videoRendition : videoRenditions) {
+      Stream videoStream = createStream(encoding, inputStream, videoRendition.codecConfiguration);
+      String segmentPath = "video/" + videoRendition.height;
+      Fmp4Muxing videoMuxing =
+          createFmp4Muxing(encoding, output, name + "/" + segmentPath, videoStream);
+      asset.videoMuxings.add(new MuxedStream(videoStream, videoMuxing, segmentPath));
+    }
+
+    Stream audioStream = createStream(encoding, inputStream, aacConfig);
+    Fmp4Muxing audioMuxing =
+        createFmp4Muxing(encoding, output, name + "/" + AUDIO_SEGMENT_PATH, audioStream);
+    asset.audioMuxing = new MuxedStream(audioStream, audioMuxing, AUDIO_SEGMENT_PATH);
+
+    return asset;
+  }
+
+  /**
+   * Creates a DASH manifest in the folder of an asset, referencing only the muxings of that asset
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsDashPeriodsByManifestId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsDashPeriodsAdaptationsetsRepresentationsFmp4ByManifestIdAndPeriodIdAndAdaptationsetId
+   *
+   * @param encoding The encoding to which the muxings belong to
+   * @param output The output resource to which the manifest will be written to
+   * @param asset The asset to create the manifest for
+   */
+  private static void createAssetDashManifest(Encoding encoding, Output output, Asset asset)
+      throws BitmovinException {
+    DashManifest dashManifest =
+        createDashManifest(asset.name + ".mpd", DashProfile.LIVE, output, asset.name);
+    Period period =
+        bitmovinApi.encoding.manifests.dash.periods.create(dashManifest.getId(), new Period());
+
+    VideoAdaptationSet videoAdaptationSet =
+        bitmovinApi.encoding.manifests.dash.periods.adaptationsets.video.create(
+            dashManifest.getId(), period.getId(), new VideoAdaptationSet());
+    for (MuxedStream videoMuxing : asset.videoMuxings) {
+      createDashFmp4Representation(
+          encoding, dashManifest, period, videoAdaptationSet.getId(), videoMuxing);
+    }
+
+    AudioAdaptationSet audioAdaptationSet =
+        bitmovinApi.encoding.manifests.dash.periods.adaptationsets.audio.create(
+            dashManifest.getId(), period.getId(), new AudioAdaptationSet());
+    createDashFmp4Representation(
+        encoding, dashManifest, period, audioAdaptationSet.getId(), asset.audioMuxing);
+
+    executeDashManifestCreation(dashManifest);
+  }
+
+  /**
+   * Adds a representation of a fragmented MP4 muxing to an adaptation set of the DASH manifest.
+   *
+   * @param encoding The encoding to which the muxing belongs to
+   * @param dashManifest The DASH manifest to add the representation to
+   * @param period The period to add the representation to
+   * @param adaptationSetId The ID of the adaptation set to add the representation to
+   * @param muxedStream The muxing of the representation
+   */
+  private static void createDashFmp4Representation(
+      Encoding encoding,
+      DashManifest dashManifest,
+      Period period,
+      String adaptationSetId,
+      MuxedStream muxedStream)
+      throws BitmovinException {
+    DashFmp4Representation representation = new DashFmp4Representation();
+    representation.setType(DashRepresentationType.TEMPLATE);
+    representation.setEncodingId(encoding.getId());
+    representation.setMuxingId(muxedStream.muxing.getId());
+    representation.setSegmentPath(muxedStream.segmentPath);
+
+    bitmovinApi.encoding.manifests.dash.periods.adaptationsets.representations.fmp4.create(
+        dashManifest.getId(), period.getId(), adaptationSetId, representation);
+  }
+
+  /**
+   * Creates an HLS manifest in the folder of an asset, referencing only the muxings of that asset
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsHlsMediaAudioByManifestId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsHlsStreamsByManifestId
+   *
+   * @param encoding The encoding to which the muxings belong to
+   * @param output The output resource to which the manifest will be written to
+   * @param asset The asset to create the manifest for
+   */
+  private static void createAssetHlsManifest(Encoding encoding, Output output, Asset asset)
+      throws BitmovinException {
+    HlsManifest hlsManifest = createHlsMasterManifest(asset.name + ".m3u8", output, asset.name);
+
+    AudioMediaInfo audioMediaInfo = new AudioMediaInfo();
+    audioMediaInfo.setName("audio");
+    audioMediaInfo.setUri("audio.m3u8");
+    audioMediaInfo.setGroupId(AUDIO_GROUP_ID);
+    audioMediaInfo.setEncodingId(encoding.getId());
+    audioMediaInfo.setStreamId(asset.audioMuxing.stream.getId());
+    audioMediaInfo.setMuxingId(asset.audioMuxing.muxing.getId());
+    audioMediaInfo.setIsDefault(true);
+    audioMediaInfo.setAutoselect(true);
+    audioMediaInfo.setSegmentPath(asset.audioMuxing.segmentPath);
+
+    bitmovinApi.encoding.manifests.hls.media.audio.create(hlsManifest.getId(), audioMediaInfo);
+
+    for (MuxedStream videoMuxing : asset.videoMuxings) {
+      StreamInfo streamInfo = new StreamInfo();
+      streamInfo.setUri(videoMuxing.segmentPath.replace('/', '_') + ".m3u8");
+      streamInfo.setEncodingId(encoding.getId());
+      streamInfo.setStreamId(videoMuxing.stream.getId());
+      streamInfo.setMuxingId(videoMuxing.muxing.getId());
+      streamInfo.setAudio(AUDIO_GROUP_ID);
+      streamInfo.setSegmentPath(videoMuxing.segmentPath);
+
+      bitmovinApi.encoding.manifests.hls.streams.create(hlsManifest.getId(), streamInfo);
+    }
+
+    executeHlsManifestCreation(hlsManifest);
+  }
+
+  private static class VideoRendition {
+
+    private int height;
+    private long bitrate;
+    private H264VideoConfiguration codecConfiguration;
+
+    /**
+     * @param height The target output height of the rendition
+     * @param bitrate The target output bitrate of the rendition
+     */
+    private VideoRendition(int height, long bitrate) {
+      this.height = height;
+      this.bitrate = bitrate;
+    }
+  }
+
+  private static class Clip {
+
+    private String name;
+    private double offset;
+    private double duration;
+
+    /**
+     * @param name The name of the clip, used as folder and manifest name
+     * @param offset The position in the input file in seconds where the clip starts
+     * @param duration The duration of the clip in seconds
+     */
+    private Clip(String name, double offset, double duration) {
+      this.name = name;
+      this.offset = offset;
+      this.duration = duration;
+    }
+  }
+
+  /** The full program or a clip, with the muxings written to its folder */
+  private static class Asset {
+
+    private final String name;
+    private final List<MuxedStream> videoMuxings = new ArrayList<>();
+    private MuxedStream audioMuxing;
+
+    private Asset(String name) {
+      this.name = name;
+    }
+  }
+
+  private static class MuxedStream {
+
+    private final Stream stream;
+    private final Fmp4Muxing muxing;
+    private final String segmentPath;
+
+    /**
+     * @param stream The muxed stream
+     * @param muxing The muxing of the stream
+     * @param segmentPath The path of the segments, relative to the folder of the asset
+     */
+    private MuxedStream(Stream stream, Fmp4Muxing muxing, String segmentPath) {
+      this.stream = stream;
+      this.muxing = muxing;
+      this.segmentPath = segmentPath;
+    }
+  }
+
+  /**
+   * Creates a resource representing an HTTP server providing the input files. For alternative input
+   * methods see <a
+   * href="https://bitmovin.com/docs/encoding/articles/supported-input-output-storages">list of
+   * supported input and output storages</a>
+   *
+   * <p>For reasons of simplicity, a new input resource is created on each execution of this
+   * example. In production use, this method should be replaced by a <a
+   * href="https://bitmovin.com/docs/encoding/api-reference/sections/inputs#/Encoding/GetEncodingInputsHttpByInputId">get
+   * call</a> to retrieve an existing resource.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/inputs#/Encoding/PostEncodingInputsHttp
+   *
+   * @param host The hostname or IP address of the HTTP server e.g.: my-storage.biz
+   */
+  private static HttpInput createHttpInput(String host) throws BitmovinException {
+    HttpInput input = new HttpInput();
+    input.setHost(host);
+
+    return bitmovinApi.encoding.inputs.http.create(input);
+  }
+
+  /**
+   * Creates a resource representing an AWS S3 cloud storage bucket to which generated content will
+   * be transferred. For alternative output methods see <a
+   * href="https://bitmovin.com/docs/encoding/articles/supported-input-output-storages">list of
+   * supported input and output storages</a>
+   *
+   * <p>The provided credentials need to allow <i>read</i>, <i>write</i> and <i>list</i> operations.
+   * <i>delete</i> should also be granted to allow overwriting of existings files. See <a
+   * href="https://bitmovin.com/docs/encoding/faqs/how-do-i-create-a-aws-s3-bucket-which-can-be-used-as-output-location">creating
+   * an S3 bucket and setting permissions</a> for further information
+   *
+   * <p>For reasons of simplicity, a new output resource is created on each execution of this
+   * example. In production use, this method should be replaced by a <a
+   * href="https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/GetEncodingOutputsS3">get
+   * call</a> retrieving an existing resource.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/PostEncodingOutputsS3
+   *
+   * @param bucketName The name of the S3 bucket
+   * @param accessKey The access key of your S3 account
+   * @param secretKey The secret key of your S3 account
+   */
+  private static S3Output createS3Output(String bucketName, String accessKey, String secretKey)
+      throws BitmovinException {
+
+    S3Output s3Output = new S3Output();
+    s3Output.setBucketName(bucketName);
+    s3Output.setAccessKey(accessKey);
+    s3Output.setSecretKey(secretKey);
+
+    return bitmovinApi.encoding.outputs.s3.create(s3Output);
+  }
+
+  /**
+   * Creates an encoding object. This is the base object to configure your encoding.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodings
+   *
+   * @param name This is the name of the encoding
+   * @param description This is the description of the encoding
+   */
+  private static Encoding createEncoding(String name, String description) throws BitmovinException {
+    Encoding encoding = new Encoding();
+    encoding.setName(name);
+    encoding.setDescription(description);
+    new BudgetTag(configProvider).applyTo(encoding);
+
+    return bitmovinApi.encoding.encodings.create(encoding);
+  }
+
+  /**
+   * Creates an IngestInputStream and adds it to an encoding
+   *
+   * <p>The IngestInputStream is used to define where a file to read a stream from is located
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsInputStreamsIngestByEncodingId
+   *
+   * @param encoding The encoding to which the stream will be added
+   * @param input The input resource providing the input file
+   * @param inputPath The path to the input file
+   */
+  private static IngestInputStream createIngestInputStream(
+      Encoding encoding, Input input, String inputPath) throws BitmovinException {
+    IngestInputStream ingestInputStream = new IngestInputStream();
+    ingestInputStream.setInputId(input.getId());
+    ingestInputStream.setInputPath(inputPath);
+    ingestInputStream.setSelectionMode(StreamSelectionMode.AUTO);
+
+    return bitmovinApi.encoding.encodings.inputStreams.ingest.create(
+        encoding.getId(), ingestInputStream);
+  }
+
+  /**
+   * Creates a TimeBasedTrimmingInputStream, which only uses a time range of the given input stream
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsInputStreamsTrimmingTimeBasedByEncodingId
+   *
+   * @param encoding The encoding to which the input stream will be added
+   * @param inputStream The input stream to be trimmed
+   * @param offset The start of the time range in seconds
+   * @param duration The duration of the time range in seconds
+   */
+  private static TimeBasedTrimmingInputStream createTimeBasedTrimmingInputStream(
+      Encoding encoding, InputStream inputStream, double offset, double duration)
+      throws BitmovinException {
+    TimeBasedTrimmingInputStream trimmingInputStream = new TimeBasedTrimmingInputStream();
+    trimmingInputStream.setInputStreamId(inputStream.getId());
+    trimmingInputStream.setOffset(offset);
+    trimmingInputStream.setDuration(duration);
+
+    return bitmovinApi.encoding.encodings.inputStreams.trimming.timeBased.create(
+        encoding.getId(), trimmingInputStream);
+  }
+
+  /**
+   * Adds a video or audio stream to an encoding, by mapping a codec configuration to an input
+   * stream
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsStreamsByEncodingId
+   *
+   * @param encoding The encoding to which the stream will be added
+   * @param inputStream The inputStream resource providing the input file
+   * @param codecConfiguration The codec configuration to be applied to the stream
+   */
+  private static Stream createStream(
+      Encoding encoding, InputStream inputStream, CodecConfiguration codecConfiguration)
+      throws BitmovinException {
+    StreamInput streamInput = new StreamInput();
+    streamInput.setInputStreamId(inputStream.getId());
+
+    Stream stream = new Stream();
+    stream.addInputStreamsItem(streamInput);
+    stream.setCodecConfigId(codecConfiguration.getId());
+    stream.setMode(StreamMode.STANDARD);
+
+    return bitmovinApi.encoding.encodings.streams.create(encoding.getId(), stream);
+  }
+
+  /**
+   * Creates a configuration for the H.264 video codec to be applied to video streams.
+   *
+   * <p>The output resolution is defined by setting only the height. Width will be determined
+   * automatically to maintain the aspect ratio of your input video.
+   *
+   * <p>To keep things simple, we use a quality-optimized VoD preset configuration, which will apply
+   * proven settings for the codec. See <a
+   * href="https://bitmovin.com/docs/encoding/tutorials/how-to-optimize-your-h264-codec-configuration-for-different-use-cases">How
+   * to optimize your H264 codec configuration for different use-cases</a> for alternative presets.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsVideoH264
+   *
+   * @param height The height of the output video
+   * @param bitrate The target bitrate of the output video
+   */
+  private static H264VideoConfiguration createH264VideoConfig(int height, long bitrate)
+      throws BitmovinException {
+    H264VideoConfiguration config = new H264VideoConfiguration();
+    config.setName(String.format("H.264 %dp", height));
+    config.setPresetConfiguration(PresetConfiguration.VOD_STANDARD);
+    config.setHeight(height);
+    config.setBitrate(bitrate);
+
+    return bitmovinApi.encoding.configurations.video.h264.create(config);
+  }
+
+  /**
+   * Creates a configuration for the AAC audio codec to be applied to audio streams.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsAudioAac
+   */
+  private static AacAudioConfiguration createAacAudioConfig() throws BitmovinException {
+    AacAudioConfiguration config = new AacAudioConfiguration();
+    config.setName("AAC 128 kbit/s");
+    config.setBitrate(128_000L);
+
+    return bitmovinApi.encoding.configurations.audio.aac.create(config);
+  }
+
+  /**
+   * Creates a fragmented MP4 muxing. This will generate segments with a given segment length for
+   * adaptive streaming.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/all#/Encoding/PostEncodingEncodingsMuxingsFmp4ByEncodingId
+   *
+   * @param encoding The encoding where to add the muxing to
+   * @param output The output that should be used for the muxing to write the segments to
+   * @param outputPath The output path where the fragmented segments will be written to
+   * @param stream The stream that is associated with the muxing
+   */
+  private static Fmp4Muxing createFmp4Muxing(
+      Encoding encoding, Output output, String outputPath, Stream stream) throws BitmovinException {
+    MuxingStream muxingStream = new MuxingStream();
+    muxingStream.setStreamId(stream.getId());
+
+    Fmp4Muxing muxing = new Fmp4Muxing();
+    muxing.addOutputsItem(buildEncodingOutput(output, outputPath));
+    muxing.addStreamsItem(muxingStream);
+    muxing.setSegmentLength(4.0);
+    SegmentSharding.apply(configProvider, muxing);
+
+    return bitmovinApi.encoding.encodings.muxings.fmp4.create(encoding.getId(), muxing);
+  }
+
+  /**
+   * Builds an EncodingOutput object which defines where the output content (e.g. of a muxing) will
+   * be written to. Public read permissions will be set for the files written, so they can be
+   * accessed easily via HTTP.
+   *
+   * @param output The output resource to be used by the EncodingOutput
+   * @param outputPath The path where the content will be written to
+   */
+  private static EncodingOutput buildEncodingOutput(Output output, String outputPath) {
+    AclEntry aclEntry = new AclEntry();
+    aclEntry.setPermission(AclPermission.PUBLIC_READ);
+
+    EncodingOutput encodingOutput = new EncodingOutput();
+    encodingOutput.setOutputPath(buildAbsolutePath(outputPath));
+    encodingOutput.setOutputId(output.getId());
+    encodingOutput.addAclItem(aclEntry);
+    return encodingOutput;
+  }
+
+  /**
+   * Builds an absolute path by concatenating the S3_OUTPUT_BASE_PATH configuration parameter, the
+   * name of this example class and the given relative path
+   *
+   * <p>e.g.: /s3/base/path/ClassName/relative/path
+   *
+   * @param relativePath The relative path that is concatenated
+   * @return The absolute path
+   */
+  public static String buildAbsolutePath(String relativePath) {
+    String className = ProgramWithHighlightClips.class.getSimpleName();
+    return Paths.get(configProvider.getS3OutputBasePath(), className, relativePath).toString();
+  }
+
+  /**
+   * Starts the actual encoding process and periodically polls its status until it reaches a final
+   * state
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/all#/Encoding/PostEncodingEncodingsStartByEncodingId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/GetEncodingEncodingsStatusByEncodingId
+   *
+   * <p>Please note that you can also use our webhooks API instead of polling the status. For more
+   * information consult the API spec:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/notifications-webhooks
+   *
+   * @param encoding The encoding to be started
+   */
+  private static void executeEncoding(Encoding encoding)
+      throws InterruptedException, BitmovinException {
+    bitmovinApi.encoding.encodings.start(encoding.getId(), new StartEncodingRequest());
+
+    Task task;
+    do {
+      Thread.sleep(5000);
+      task = bitmovinApi.encoding.encodings.status(encoding.getId());
+      logger.info("encoding status is {} (progress: {} %)", task.getStatus(), task.getProgress());
+    } while (task.getStatus() != Status.FINISHED
+        && task.getStatus() != Status.ERROR
+        && task.getStatus() != Status.CANCELED);
+
+    if (task.getStatus() != Status.FINISHED) {
+      logTaskErrors(task);
+      throw new EncodingFailedException(task.getStatus());
+    }
+    logger.info("encoding finished successfully");
+  }
+
+  /** Creates the HLS master manifest. */
+  private static HlsManifest createHlsMasterManifest(String name, Output output, String outputPath)
+      throws BitmovinException {
+
+    HlsManifest hlsManifest = new HlsManifest();
+    hlsManifest.setName(name);
+    hlsManifest.addOutputsItem(buildEncodingOutput(output, outputPath));
+
+    return bitmovinApi.encoding.manifests.hls.create(hlsManifest);
+  }
+
+  /**
+   * Creates a DASH manifest
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsDash
+   *
+   * @param name the resource name
+   * @param dashProfile the DASH profile of the manifest (ON_DEMAND, LIVE)
+   * @param output the output of the manifest
+   * @param outputPath the output path where the manifest is written to
+   * @return the created manifest
+   */
+  private static DashManifest createDashManifest(
+      String name, DashProfile dashProfile, Output output, String outputPath)
+      throws BitmovinException {
+    DashManifest dashManifest = new DashManifest();
+    dashManifest.setName(name);
+    dashManifest.setProfile(dashProfile);
+    dashManifest.addOutputsItem(buildEncodingOutput(output, outputPath));
+
+    return bitmovinApi.encoding.manifests.dash.create(dashManifest);
+  }
+
+  /**
+   * Starts the HLS manifest creation and periodically polls its status until it reaches a final
+   * state
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsHlsStartByManifestId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/GetEncodingManifestsHlsStatusByManifestId
+   *
+   * @param hlsManifest The HLS manifest to be created
+   */
+  private static void executeHlsManifestCreation(HlsManifest hlsManifest)
+      throws BitmovinException, InterruptedException {
+
+    bitmovinApi.encoding.manifests.hls.start(hlsManifest.getId());
+
+    Task task;
+    do {
+      Thread.sleep(1000);
+      task = bitmovinApi.encoding.manifests.hls.status(hlsManifest.getId());
+    } while (task.getStatus() != Status.FINISHED && task.getStatus() != Status.ERROR);
+
+    if (task.getStatus() == Status.ERROR) {
+      logTaskErrors(task);
+      throw new RuntimeException("HLS manifest creation failed");
+    }
+    logger.info("HLS manifest creation finished successfully");
+  }
+
+  /**
+   * Starts the DASH manifest creation and periodically polls its status until it reaches a final
+   * state
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsDashStartByManifestId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/GetEncodingManifestsDashStatusByManifestId
+   *
+   * @param dashManifest The DASH manifest to be created
+   */
+  private static void executeDashManifestCreation(DashManifest dashManifest)
+      throws BitmovinException, InterruptedException {
+    bitmovinApi.encoding.manifests.dash.start(dashManifest.getId());
+
+    Task task;
+    do {
+      Thread.sleep(1000);
+      task = bitmovinApi.encoding.manifests.dash.status(dashManifest.getId());
+    } while (task.getStatus() != Status.FINISHED && task.getStatus() != Status.ERROR);
+
+    if (task.getStatus() == Status.ERROR) {
+      logTaskErrors(task);
+      throw new RuntimeException("DASH manifest creation failed");
+    }
+    logger.info("DASH manifest creation finished successfully");
+  }
+
+  private static void logTaskErrors(Task task) {
+    task.getMessages().stream()
+        .filter(msg -> msg.getType() == MessageType.ERROR)
+        .forEach(msg -> logger.error(msg.getText()));
+  }
+}
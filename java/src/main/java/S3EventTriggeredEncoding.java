@@ -0,0 +1,526 @@
+import com.bitmovin.api.sdk.BitmovinApi;
+import com.bitmovin.api.sdk.common.BitmovinException;
+import com.bitmovin.api.sdk.model.AacAudioConfiguration;
+import com.bitmovin.api.sdk.model.AclEntry;
+import com.bitmovin.api.sdk.model.AclPermission;
+import com.bitmovin.api.sdk.model.CodecConfiguration;
+import com.bitmovin.api.sdk.model.DashManifestDefault;
+import com.bitmovin.api.sdk.model.DashManifestDefaultVersion;
+import com.bitmovin.api.sdk.model.Encoding;
+import com.bitmovin.api.sdk.model.EncodingOutput;
+import com.bitmovin.api.sdk.model.Fmp4Muxing;
+import com.bitmovin.api.sdk.model.H264VideoConfiguration;
+import com.bitmovin.api.sdk.model.HlsManifestDefault;
+import com.bitmovin.api.sdk.model.HlsManifestDefaultVersion;
+import com.bitmovin.api.sdk.model.Input;
+import com.bitmovin.api.sdk.model.ManifestGenerator;
+import com.bitmovin.api.sdk.model.ManifestResource;
+import com.bitmovin.api.sdk.model.MuxingStream;
+import com.bitmovin.api.sdk.model.Output;
+import com.bitmovin.api.sdk.model.PresetConfiguration;
+import com.bitmovin.api.sdk.model.S3Output;
+import com.bitmovin.api.sdk.model.S3RoleBasedInput;
+import com.bitmovin.api.sdk.model.StartEncodingRequest;
+import com.bitmovin.api.sdk.model.Stream;
+import com.bitmovin.api.sdk.model.StreamInput;
+import com.fasterxml.jackson.databind.ObjectMapper;
+import common.ApiClientFactory;
+import common.BudgetTag;
+import common.ConfigProvider;
+import common.PreviewTrimming;
+import common.SegmentSharding;
+import java.net.URLDecoder;
+import java.nio.charset.StandardCharsets;
+import java.nio.file.Paths;
+import java.util.ArrayList;
+import java.util.Arrays;
+import java.util.HashMap;
+import java.util.HashSet;
+import java.util.List;
+import java.util.Locale;
+import java.util.Map;
+import java.util.Set;
+import org.apache.commons.lang3.StringUtils;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+
+/**
+ * This example demonstrates how to start encodings automatically when files are uploaded to an S3
+ * bucket, using a handler that can be deployed to AWS Lambda. The function is subscribed to the
+ * ObjectCreated events of the input bucket, and starts a standard VOD encoding with an H.264
+ * ladder, AAC audio and DASH and HLS manifests for each uploaded file.
+ *
+ * <p>To deploy it, package the examples with "mvn package", upload the jar with dependencies as a
+ * Lambda function with the Java 8 (Corretto) runtime and the handler
+ * "S3EventTriggeredEncoding::handleRequest", and configure the parameters below as environment
+ * variables of the function. Then add an S3 trigger for the ObjectCreated events of the input
+ * bucket.
+ *
+ * <p>The handler only starts the encodings and returns their IDs, as the time a Lambda function can
+ * run is limited. The manifests are created by the encoding itself once it has finished. Use
+ * webhooks (see {@link EncodingEventPublisher}) to get notified about the result.
+ *
+ * <p>The input bucket is accessed with a role that allows Bitmovin to read from it. Make sure the
+ * output is written to a different bucket or to a prefix not covered by the trigger, so the
+ * encoding results do not start new encodings.
+ *
+ * <p>The handler can be tested locally by running this class with S3_EVENT_FILE pointing to a
+ * sample event, which can be created with "sam local generate-event s3 put".
+ *
+ * <p>The following configuration parameters are expected:
+ *
+ * <ul>
+ *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin API
+ *   <li>BITMOVIN_TENANT_ORG_ID - (optional) The ID of the Organisation in which you want to perform
+ *       the encoding.
+ *   <li>BITMOVIN_API_BASE_URL - (optional) The base URL of the Bitmovin API, e.g. of a mock server
+ *       or an API gateway. Default: https://api.bitmovin.com/v1
+ *   <li>S3_INPUT_ARN_ROLE - The ARN of the role that allows Bitmovin to read from the buckets
+ *       sending events
+ *   <li>S3_INPUT_EXT_ID - The external ID of the role
+ *   <li>S3_OUTPUT_BUCKET_NAME - The name of your S3 output bucket. Example: my-bucket-name
+ *   <li>S3_OUTPUT_ACCESS_KEY - The access key of your S3 output bucket
+ *   <li>S3_OUTPUT_SECRET_KEY - The secret key of your S3 output bucket
+ *   <li>S3_OUTPUT_BASE_PATH - The base path on your S3 output bucket where content will be written.
+ *       Example: /outputs
+ *   <li>S3_EVENT_FILE_EXTENSIONS - (optional) A comma separated list of the file extensions that
+ *       start an encoding, other files are ignored. Default: mp4,mov,mxf,mkv
+ *   <li>S3_EVENT_FILE - (optional) The path to a JSON file with an S3 event, which is passed to the
+ *       handler when this class is run locally
+ * </ul>
+ *
+ * <p>Configuration parameters will be retrieved from these sources in the listed order:
+ *
+ * <ol>
+ *   <li>command line arguments (eg BITMOVIN_API_KEY=xyz)
+ *   <li>properties file located in the root folder of the JAVA examples at ./examples.properties
+ *       (see examples.properties.template as reference)
+ *   <li>environment variables
+ *   <li>properties file located in the home folder at ~/.bitmovin/examples.properties (see
+ *       examples.properties.template as reference)
+ * </ol>
+ */
+public class S3EventTriggeredEncoding {
+  private static final Logger logger = LoggerFactory.getLogger(S3EventTriggeredEncoding.class);
+
+  private static final ObjectMapper objectMapper = new ObjectMapper();
+
+  private static BitmovinApi bitmovinApi;
+  private static ConfigProvider configProvider;
+
+  /**
+   * The inputs created per bucket. Lambda reuses the instances of a function for subsequent events,
+   * so inputs are only created once per instance.
+   */
+  private static Map<String, S3RoleBasedInput> inputs = new HashMap<>();
+
+  private static Output output;
+
+  /** This list defines the video renditions that will be generated */
+  private static List<VideoRendition> videoRenditions =
+      Arrays.asList(
+          new VideoRendition(1080, 4_800_000L),
+          new VideoRendition(720, 2_400_000L),
+          new VideoRendition(480, 1_200_000L),
+          new VideoRendition(360, 800_000L));
+
+  /** Runs the handler locally with the S3 event read from the file configured with S3_EVENT_FILE */
+  @SuppressWarnings("unchecked")
+  public static void main(String[] args) throws Exception {
+    configProvider = new ConfigProvider(args);
+
+    Map<String, Object> event =
+        objectMapper.readValue(
+            Paths.get(configProvider.getParameterByKey("S3_EVENT_FILE")).toFile(), Map.class);
+
+    List<String> encodingIds = new S3EventTriggeredEncoding().handleRequest(event);
+    logger.info("Started encodings: {}", encodingIds);
+  }
+
+  /**
+   * The entry point of the Lambda function, which starts an encoding for each created object in the
+   * S3 event
+   *
+   * @param event The S3 event as delivered by AWS Lambda
+   * @return The IDs of the started encodings
+   */
+  @SuppressWarnings("unchecked")
+  public List<String> handleRequest(Map<String, Object> event) throws Exception {
+    initialize();
+
+    Set<String> fileExtensions =
+        new HashSet<>(
+            Arrays.asList(
+                configProvider
+                    .getParameterByKey("S3_EVENT_FILE_EXTENSIONS", "mp4,mov,mxf,mkv")
+                    .toLowerCase(Locale.ROOT)
+                    .split(",")));
+
+    List<String> encodingIds = new ArrayList<>();
+    for (Map<String, Object> record : (List<Map<String, Object>>) event.get("Records")) {
+      String eventName = (String) record.get("eventName");
+      Map<String, Object> s3 = (Map<String, Object>) record.get("s3");
+      String bucketName = (String) ((Map<String, Object>) s3.get("bucket")).get("name");
+      // object keys are URL encoded in S3 events
+      String objectKey =
+          URLDecoder.decode(
+              (String) ((Map<String, Object>) s3.get("object")).get("key"),
+              StandardCharsets.UTF_8.name());
+
+      if (eventName == null || !eventName.startsWith("ObjectCreated")) {
+        logger.info("Ignoring event {} for s3://{}/{}", eventName, bucketName, objectKey);
+        continue;
+      }
+      String extension = StringUtils.substringAfterLast(objectKey, ".").toLowerCase(Locale.ROOT);
+      if (!fileExtensions.contains(extension)) {
+        logger.info("Ignoring s3://{}/{} with unsupported extension", bucketName, objectKey);
+        continue;
+      }
+
+      Encoding encoding = startEncoding(bucketName, objectKey);
+      logger.info("Started encoding {} for s3://{}/{}", encoding.getId(), bucketName, objectKey);
+      encodingIds.add(encoding.getId());
+    }
+
+    return encodingIds;
+  }
+
+  /**
+   * Creates the API client and the output on the first invocation. In Lambda, the configuration is
+   * read from the environment variables of the function.
+   */
+  private static synchronized void initialize() throws BitmovinException {
+    if (configProvider == null) {
+      configProvider = new ConfigProvider(new String[0]);
+    }
+    if (bitmovinApi == null) {
+      bitmovinApi =
+          ApiClientFactory.builder(configProvider)
+              .withApiKey(configProvider.getBitmovinApiKey())
+              .build();
+    }
+    if (output == null) {
+      output =
+          createS3Output(
+              configProvider.getS3OutputBucketName(),
+              configProvider.getS3OutputAccessKey(),
+              configProvider.getS3OutputSecretKey());
+    }
+  }
+
+  /**
+   * Sets up and starts the encoding of an uploaded file. The output is written to a folder named
+   * after the object key, without the file extension.
+   *
+   * @param bucketName The name of the bucket the file has been uploaded to
+   * @param objectKey The key of the uploaded file
+   */
+  private static Encoding startEncoding(String bucketName, String objectKey)
+      throws BitmovinException {
+    S3RoleBasedInput input = inputs.get(bucketName);
+    if (input == null) {
+      input =
+          createS3RoleBasedInput(
+              bucketName,
+              configProvider.getS3InputArnRole(),
+              configProvider.getS3InputExternalId());
+      inputs.put(bucketName, input);
+    }
+
+    String outputPath = StringUtils.substringBeforeLast(objectKey, ".");
+    Encoding encoding =
+        createEncoding(objectKey, String.format("Encoding of s3://%s/%s", bucketName, objectKey));
+
+    for (VideoRendition videoRendition : videoRenditions) {
+      H264VideoConfiguration videoConfiguration =
+          createH264VideoConfig(videoRendition.height, videoRendition.bitrate);
+      Stream videoStream = createStream(encoding, input, objectKey, videoConfiguration);
+      createFmp4Muxing(
+          encoding, output, outputPath + "/video/" + videoRendition.height, videoStream);
+    }
+
+    AacAudioConfiguration aacConfig = createAacAudioConfig();
+    Stream aacAudioStream = createStream(encoding, input, objectKey, aacConfig);
+    createFmp4Muxing(encoding, output, outputPath + "/audio", aacAudioStream);
+
+    DashManifestDefault dashManifest = createDefaultDashManifest(encoding, output, outputPath);
+    HlsManifestDefault hlsManifest = createDefaultHlsManifest(encoding, output, outputPath);
+
+    StartEncodingRequest startEncodingRequest = new StartEncodingRequest();
+    startEncodingRequest.setManifestGenerator(ManifestGenerator.V2);
+    startEncodingRequest.addVodDashManifestsItem(buildManifestResource(dashManifest.getId()));
+    startEncodingRequest.addVodHlsManifestsItem(buildManifestResource(hlsManifest.getId()));
+
+    bitmovinApi.encoding.encodings.start(encoding.getId(), startEncodingRequest);
+
+    return encoding;
+  }
+
+  /**
+   * Creates a resource representing an AWS S3 bucket that is accessed with a role, so no
+   * credentials have to be stored in the function.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/inputs#/Encoding/PostEncodingInputsS3RoleBased
+   *
+   * @param bucketName The name of the S3 bucket
+   * @param roleArn The ARN of the role that allows Bitmovin to access the bucket
+   * @param externalId The external ID of the role
+   */
+  private static S3RoleBasedInput createS3RoleBasedInput(
+      String bucketName, String roleArn, String externalId) throws BitmovinException {
+    S3RoleBasedInput s3RoleBasedInput = new S3RoleBasedInput();
+    s3RoleBasedInput.setBucketName(bucketName);
+    s3RoleBasedInput.setRoleArn(roleArn);
+    s3RoleBasedInput.setExternalId(externalId);
+
+    return bitmovinApi.encoding.inputs.s3RoleBased.create(s3RoleBasedInput);
+  }
+
+  /**
+   * Creates a DASH default manifest, which is generated by the encoding once it has finished.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsDash
+   *
+   * @param encoding The encoding for which the manifest should be generated
+   * @param output The output to which the manifest should be written
+   * @param outputPath The path to which the manifest should be written
+   */
+  private static DashManifestDefault createDefaultDashManifest(
+      Encoding encoding, Output output, String outputPath) throws BitmovinException {
+    DashManifestDefault dashManifestDefault = new DashManifestDefault();
+    dashManifestDefault.setEncodingId(encoding.getId());
+    dashManifestDefault.setManifestName("stream.mpd");
+    dashManifestDefault.setVersion(DashManifestDefaultVersion.V1);
+    dashManifestDefault.addOutputsItem(buildEncodingOutput(output, outputPath));
+
+    return bitmovinApi.encoding.manifests.dash.defaultapi.create(dashManifestDefault);
+  }
+
+  /**
+   * Creates an HLS default manifest, which is generated by the encoding once it has finished.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsHlsDefault
+   *
+   * @param encoding The encoding for which the manifest should be generated
+   * @param output The output to which the manifest should be written
+   * @param outputPath The path to which the manifest should be written
+   */
+  private static HlsManifestDefault createDefaultHlsManifest(
+      Encoding encoding, Output output, String outputPath) throws BitmovinException {
+    HlsManifestDefault hlsManifestDefault = new HlsManifestDefault();
+    hlsManifestDefault.setEncodingId(encoding.getId());
+    hlsManifestDefault.addOutputsItem(buildEncodingOutput(output, outputPath));
+    hlsManifestDefault.setName("master.m3u8");
+    hlsManifestDefault.setVersion(HlsManifestDefaultVersion.V1);
+
+    return bitmovinApi.encoding.manifests.hls.defaultapi.create(hlsManifestDefault);
+  }
+
+  /**
+   * Wraps a manifest ID into a ManifestResource object, so it can be referenced in one of the
+   * StartEncodingRequest manifest lists.
+   *
+   * @param manifestId The ID of the manifest
+   */
+  private static ManifestResource buildManifestResource(String manifestId) {
+    ManifestResource manifestResource = new ManifestResource();
+    manifestResource.setManifestId(manifestId);
+    return manifestResource;
+  }
+
+  private static class VideoRendition {
+
+    private int height;
+    private long bitrate;
+
+    /**
+     * @param height The target output height of the rendition
+     * @param bitrate The target output bitrate of the rendition
+     */
+    private VideoRendition(int height, long bitrate) {
+      this.height = height;
+      this.bitrate = bitrate;
+    }
+  }
+
+  /**
+   * Creates a resource representing an AWS S3 cloud storage bucket to which generated content will
+   * be transferred. For alternative output methods see <a
+   * href="https://bitmovin.com/docs/encoding/articles/supported-input-output-storages">list of
+   * supported input and output storages</a>
+   *
+   * <p>The provided credentials need to allow <i>read</i>, <i>write</i> and <i>list</i> operations.
+   * <i>delete</i> should also be granted to allow overwriting of existings files. See <a
+   * href="https://bitmovin.com/docs/encoding/faqs/how-do-i-create-a-aws-s3-bucket-which-can-be-used-as-output-location">creating
+   * an S3 bucket and setting permissions</a> for further information
+   *
+   * <p>For reasons of simplicity, a new output resource is created on each execution of this
+   * example. In production use, this method should be replaced by a <a
+   * href="https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/GetEncodingOutputsS3">get
+   * call</a> retrieving an existing resource.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/PostEncodingOutputsS3
+   *
+   * @param bucketName The name of the S3 bucket
+   * @param accessKey The access key of your S3 account
+   * @param secretKey The secret key of your S3 account
+   */
+  private static S3Output createS3Output(String bucketName, String accessKey, String secretKey)
+      throws BitmovinException {
+
+    S3Output s3Output = new S3Output();
+    s3Output.setBucketName(bucketName);
+    s3Output.setAccessKey(accessKey);
+    s3Output.setSecretKey(secretKey);
+
+    return bitmovinApi.encoding.outputs.s3.create(s3Output);
+  }
+
+  /**
+   * Creates an encoding object. This is the base object to configure your encoding.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodings
+   *
+   * @param name This is the name of the encoding
+   * @param description This is the description of the encoding
+   */
+  private static Encoding createEncoding(String name, String description) throws BitmovinException {
+    Encoding encoding = new Encoding();
+    encoding.setName(name);
+    encoding.setDescription(description);
+    new BudgetTag(configProvider).applyTo(encoding);
+
+    return bitmovinApi.encoding.encodings.create(encoding);
+  }
+
+  /**
+   * Creates a stream which binds an input file to a codec configuration. The stream is used for
+   * muxings later on.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsStreamsByEncodingId
+   *
+   * @param encoding The encoding to add the stream onto
+   * @param input The input that should be used
+   * @param inputPath The path to the input file
+   * @param codecConfiguration The codec configuration to be applied to the stream
+   */
+  private static Stream createStream(
+      Encoding encoding, Input input, String inputPath, CodecConfiguration codecConfiguration)
+      throws BitmovinException {
+    StreamInput streamInput =
+        PreviewTrimming.createStreamInput(bitmovinApi, configProvider, encoding, input, inputPath);
+
+    Stream stream = new Stream();
+    stream.addInputStreamsItem(streamInput);
+    stream.setCodecConfigId(codecConfiguration.getId());
+
+    return bitmovinApi.encoding.encodings.streams.create(encoding.getId(), stream);
+  }
+
+  /**
+   * Creates a configuration for the H.264 video codec to be applied to video streams.
+   *
+   * <p>The output resolution is defined by setting only the height. Width will be determined
+   * automatically to maintain the aspect ratio of your input video.
+   *
+   * <p>To keep things simple, we use a quality-optimized VoD preset configuration, which will apply
+   * proven settings for the codec. See <a
+   * href="https://bitmovin.com/docs/encoding/tutorials/how-to-optimize-your-h264-codec-configuration-for-different-use-cases">How
+   * to optimize your H264 codec configuration for different use-cases</a> for alternative presets.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsVideoH264
+   *
+   * @param height The height of the output video
+   * @param bitrate The target bitrate of the output video
+   */
+  private static H264VideoConfiguration createH264VideoConfig(int height, long bitrate)
+      throws BitmovinException {
+    H264VideoConfiguration config = new H264VideoConfiguration();
+    config.setName(String.format("H.264 %dp", height));
+    config.setPresetConfiguration(PresetConfiguration.VOD_STANDARD);
+    config.setHeight(height);
+    config.setBitrate(bitrate);
+
+    return bitmovinApi.encoding.configurations.video.h264.create(config);
+  }
+
+  /**
+   * Creates a configuration for the AAC audio codec to be applied to audio streams.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsAudioAac
+   */
+  private static AacAudioConfiguration createAacAudioConfig() throws BitmovinException {
+    AacAudioConfiguration config = new AacAudioConfiguration();
+    config.setName("AAC 128 kbit/s");
+    config.setBitrate(128_000L);
+
+    return bitmovinApi.encoding.configurations.audio.aac.create(config);
+  }
+
+  /**
+   * Creates a fragmented MP4 muxing. This will generate segments with a given segment length for
+   * adaptive streaming.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/all#/Encoding/PostEncodingEncodingsMuxingsFmp4ByEncodingId
+   *
+   * @param encoding The encoding where to add the muxing to
+   * @param output The output that should be used for the muxing to write the segments to
+   * @param outputPath The output path where the fragmented segments will be written to
+   * @param stream The stream that is associated with the muxing
+   */
+  private static Fmp4Muxing createFmp4Muxing(
+      Encoding encoding, Output output, String outputPath, Stream stream) throws BitmovinException {
+    MuxingStream muxingStream = new MuxingStream();
+    muxingStream.setStreamId(stream.getId());
+
+    Fmp4Muxing muxing = new Fmp4Muxing();
+    muxing.addOutputsItem(buildEncodingOutput(output, outputPath));
+    muxing.addStreamsItem(muxingStream);
+    muxing.setSegmentLength(4.0);
+    SegmentSharding.apply(configProvider, muxing);
+
+    return bitmovinApi.encoding.encodings.muxings.fmp4.create(encoding.getId(), muxing);
+  }
+
+  /**
+   * Builds an EncodingOutput object which defines where the output content (e.g. of a muxing) will
+   * be written to. Public read permissions will be set for the files written, so they can be
+   * accessed easily via HTTP.
+   *
+   * @param output The output resource to be used by the EncodingOutput
+   * @param outputPath The path where the content will be written to
+   */
+  private static EncodingOutput buildEncodingOutput(Output output, String outputPath) {
+    AclEntry aclEntry = new AclEntry();
+    aclEntry.setPermission(AclPermission.PUBLIC_READ);
+
+    EncodingOutput encodingOutput = new EncodingOutput();
+    encodingOutput.setOutputPath(buildAbsolutePath(outputPath));
+    encodingOutput.setOutputId(output.getId());
+    encodingOutput.addAclItem(aclEntry);
+    return encodingOutput;
+  }
+
+  /**
+   * Builds an absolute path by concatenating the S3_OUTPUT_BASE_PATH configuration parameter, the
+   * name of this example class and the given relative path
+   *
+   * <p>e.g.: /s3/base/path/ClassName/relative/path
+   *
+   * @param relativePath The relative path that is concatenated
+   * @return The absolute path
+   */
+  public static String buildAbsolutePath(String relativePath) {
+    String className = S3EventTriggeredEncoding.class.getSimpleName();
+    return Paths.get(configProvider.getS3OutputBasePath(), className, relativePath).toString();
+  }
+}
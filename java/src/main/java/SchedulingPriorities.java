@@ -0,0 +1,494 @@
+import com.bitmovin.api.sdk.BitmovinApi;
+import com.bitmovin.api.sdk.common.BitmovinException;
+import com.bitmovin.api.sdk.model.AacAudioConfiguration;
+import com.bitmovin.api.sdk.model.AclEntry;
+import com.bitmovin.api.sdk.model.AclPermission;
+import com.bitmovin.api.sdk.model.CodecConfiguration;
+import com.bitmovin.api.sdk.model.Encoding;
+import com.bitmovin.api.sdk.model.EncodingOutput;
+import com.bitmovin.api.sdk.model.Fmp4Muxing;
+import com.bitmovin.api.sdk.model.H264VideoConfiguration;
+import com.bitmovin.api.sdk.model.HttpInput;
+import com.bitmovin.api.sdk.model.Input;
+import com.bitmovin.api.sdk.model.MessageType;
+import com.bitmovin.api.sdk.model.MuxingStream;
+import com.bitmovin.api.sdk.model.Output;
+import com.bitmovin.api.sdk.model.PresetConfiguration;
+import com.bitmovin.api.sdk.model.S3Output;
+import com.bitmovin.api.sdk.model.Scheduling;
+import com.bitmovin.api.sdk.model.StartEncodingRequest;
+import com.bitmovin.api.sdk.model.Status;
+import com.bitmovin.api.sdk.model.Stream;
+import com.bitmovin.api.sdk.model.StreamInput;
+import com.bitmovin.api.sdk.model.Task;
+import common.ApiClientFactory;
+import common.BudgetTag;
+import common.ConfigProvider;
+import common.EncodingFailedException;
+import common.PreviewTrimming;
+import common.SegmentSharding;
+import java.nio.file.Paths;
+import java.time.Duration;
+import java.time.Instant;
+import java.util.ArrayList;
+import java.util.Collections;
+import java.util.Comparator;
+import java.util.List;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+
+/**
+ * This example demonstrates how to control the order in which queued encodings are started, for
+ * accounts with a mix of urgent and bulk workloads, e.g. breaking news clips and a back catalog
+ * migration. When more encodings are started than your account can run in parallel, the remaining
+ * ones are queued, and the Scheduling of the StartEncodingRequest decides which queued encoding is
+ * started next:
+ *
+ * <ul>
+ *   <li>priority - A value from 0 to 100, the default is 50. Queued encodings with a higher
+ *       priority are started first, encodings with the same priority in the order they were
+ *       started.
+ *   <li>prewarmedEncoderPoolIds - The prewarmed encoder pools the encoding may use. A prewarmed
+ *       pool keeps encoder instances running, so an encoding using it skips the startup time of new
+ *       instances. Pools are created in the dashboard or via the API beforehand, and are billed
+ *       while they are running.
+ * </ul>
+ *
+ * <p>The example starts SCHEDULING_BULK_COUNT bulk encodings with a low priority first, and an
+ * urgent encoding with a high priority afterwards. It then polls all of them and logs when each
+ * encoding leaves the queue. If your account can run fewer encodings in parallel than are started,
+ * the urgent encoding overtakes the queued bulk encodings. Otherwise all encodings start right
+ * away, and the priorities have no visible effect.
+ *
+ * <p>Note that the priority only orders the queue of your account, it does not preempt encodings
+ * that are already running. The priority of a queued encoding can still be changed, see
+ * EncodingPriorityAging.
+ *
+ * <p>The following configuration parameters are expected:
+ *
+ * <ul>
+ *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin API
+ *   <li>BITMOVIN_TENANT_ORG_ID - (optional) The ID of the Organisation in which you want to perform
+ *       the encoding.
+ *   <li>BITMOVIN_API_BASE_URL - (optional) The base URL of the Bitmovin API, e.g. of a mock server
+ *       or an API gateway. Default: https://api.bitmovin.com/v1
+ *   <li>HTTP_INPUT_HOST - The Hostname or IP address of the HTTP server hosting your input files,
+ *       e.g.: my-storage.biz
+ *   <li>HTTP_INPUT_FILE_PATH - The path to your input file on the provided HTTP server Example:
+ *       videos/1080p_Sintel.mp4
+ *   <li>S3_OUTPUT_BUCKET_NAME - The name of your S3 output bucket. Example: my-bucket-name
+ *   <li>S3_OUTPUT_ACCESS_KEY - The access key of your S3 output bucket
+ *   <li>S3_OUTPUT_SECRET_KEY - The secret key of your S3 output bucket
+ *   <li>S3_OUTPUT_BASE_PATH - The base path on your S3 output bucket where content will be written.
+ *       Example: /outputs
+ *   <li>SCHEDULING_BULK_COUNT - (optional) The number of bulk encodings to start. Default: 3
+ *   <li>SCHEDULING_BULK_PRIORITY - (optional) The priority of the bulk encodings. Default: 10
+ *   <li>SCHEDULING_URGENT_PRIORITY - (optional) The priority of the urgent encoding. Default: 90
+ *   <li>PREWARMED_ENCODER_POOL_ID - (optional) The ID of a prewarmed encoder pool the urgent
+ *       encoding may use
+ * </ul>
+ *
+ * <p>Configuration parameters will be retrieved from these sources in the listed order:
+ *
+ * <ol>
+ *   <li>command line arguments (eg BITMOVIN_API_KEY=xyz)
+ *   <li>properties file located in the root folder of the JAVA examples at ./examples.properties
+ *       (see examples.properties.template as reference)
+ *   <li>environment variables
+ *   <li>properties file located in the home folder at ~/.bitmovin/examples.properties (see
+ *       examples.properties.template as reference)
+ * </ol>
+ */
+public class SchedulingPriorities {
+  private static final Logger logger = LoggerFactory.getLogger(SchedulingPriorities.class);
+
+  private static BitmovinApi bitmovinApi;
+  private static ConfigProvider configProvider;
+
+  public static void main(String[] args) throws Exception {
+    configProvider = new ConfigProvider(args);
+    bitmovinApi =
+        ApiClientFactory.builder(configProvider)
+            .withApiKey(configProvider.getBitmovinApiKey())
+            .build();
+
+    int bulkCount =
+        Integer.parseInt(configProvider.getParameterByKey("SCHEDULING_BULK_COUNT", "3"));
+    int bulkPriority =
+        Integer.parseInt(configProvider.getParameterByKey("SCHEDULING_BULK_PRIORITY", "10"));
+    int urgentPriority =
+        Integer.parseInt(configProvider.getParameterByKey("SCHEDULING_URGENT_PRIORITY", "90"));
+    String prewarmedEncoderPoolId =
+        configProvider.getParameterByKey("PREWARMED_ENCODER_POOL_ID", null);
+
+    HttpInput input = createHttpInput(configProvider.getHttpInputHost());
+    Output output =
+        createS3Output(
+            configProvider.getS3OutputBucketName(),
+            configProvider.getS3OutputAccessKey(),
+            configProvider.getS3OutputSecretKey());
+
+    String inputFilePath = configProvider.getHttpInputFilePath();
+
+    List<ScheduledEncoding> scheduledEncodings = new ArrayList<>();
+    for (int i = 1; i <= bulkCount; i++) {
+      Encoding encoding =
+          createEncoding("Bulk encoding " + i, "Bulk encoding with priority " + bulkPriority);
+      configureEncoding(encoding, input, inputFilePath, output, "bulk-" + i);
+      scheduledEncodings.add(startEncoding(encoding, bulkPriority, null));
+    }
+
+    Encoding urgentEncoding =
+        createEncoding("Urgent encoding", "Urgent encoding with priority " + urgentPriority);
+    configureEncoding(urgentEncoding, input, inputFilePath, output, "urgent");
+    scheduledEncodings.add(startEncoding(urgentEncoding, urgentPriority, prewarmedEncoderPoolId));
+
+    waitForEncodings(scheduledEncodings);
+    logQueueTimes(scheduledEncodings);
+
+    for (ScheduledEncoding scheduledEncoding : scheduledEncodings) {
+      if (scheduledEncoding.status != Status.FINISHED) {
+        throw new EncodingFailedException(scheduledEncoding.status);
+      }
+    }
+  }
+
+  /**
+   * Adds a single H.264 rendition and an AAC audio rendition to the encoding. The renditions are
+   * kept small, as this example is about the order in which the encodings are started.
+   *
+   * @param encoding The encoding to configure
+   * @param input The input the input file is read from
+   * @param inputFilePath The path to the input file
+   * @param output The output the encoded files are written to
+   * @param outputPath The path below the base path the encoded files are written to
+   */
+  private static void configureEncoding(
+      Encoding encoding, Input input, String inputFilePath, Output output, String outputPath)
+      throws BitmovinException {
+    H264VideoConfiguration videoConfiguration = createH264VideoConfig(480, 1_200_000L);
+    Stream videoStream = createStream(encoding, input, inputFilePath, videoConfiguration);
+    createFmp4Muxing(encoding, output, outputPath + "/video", videoStream);
+
+    AacAudioConfiguration aacConfig = createAacAudioConfig();
+    Stream audioStream = createStream(encoding, input, inputFilePath, aacConfig);
+    createFmp4Muxing(encoding, output, outputPath + "/audio", audioStream);
+  }
+
+  /**
+   * Starts an encoding with the given scheduling options
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/all#/Encoding/PostEncodingEncodingsStartByEncodingId
+   *
+   * @param encoding The encoding to start
+   * @param priority The priority of the encoding in the queue, from 0 to 100
+   * @param prewarmedEncoderPoolId The ID of a prewarmed encoder pool the encoding may use, or null
+   */
+  private static ScheduledEncoding startEncoding(
+      Encoding encoding, int priority, String prewarmedEncoderPoolId) throws BitmovinException {
+    Scheduling scheduling = new Scheduling();
+    scheduling.setPriority(priority);
+    if (prewarmedEncoderPoolId != null) {
+      scheduling.setPrewarmedEncoderPoolIds(Collections.singletonList(prewarmedEncoderPoolId));
+    }
+
+    StartEncodingRequest startEncodingRequest = new StartEncodingRequest();
+    startEncodingRequest.setScheduling(scheduling);
+    bitmovinApi.encoding.encodings.start(encoding.getId(), startEncodingRequest);
+
+    logger.info("Started {} ({}) with priority {}", encoding.getName(), encoding.getId(), priority);
+    return new ScheduledEncoding(encoding, priority);
+  }
+
+  /**
+   * Periodically polls the status of all encodings until each of them reached a final state, and
+   * records when they left the queue
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/GetEncodingEncodingsStatusByEncodingId
+   *
+   * @param scheduledEncodings The started encodings
+   */
+  private static void waitForEncodings(List<ScheduledEncoding> scheduledEncodings)
+      throws InterruptedException, BitmovinException {
+    List<ScheduledEncoding> pending = new ArrayList<>(scheduledEncodings);
+    while (!pending.isEmpty()) {
+      Thread.sleep(5000);
+
+      for (ScheduledEncoding scheduledEncoding : new ArrayList<>(pending)) {
+        Task task = bitmovinApi.encoding.encodings.status(scheduledEncoding.encoding.getId());
+        if (scheduledEncoding.dequeuedAt == null
+            && task.getStatus() != Status.CREATED
+            && task.getStatus() != Status.QUEUED) {
+          scheduledEncoding.dequeuedAt = Instant.now();
+          logger.info(
+              "{} left the queue after {} seconds",
+              scheduledEncoding.encoding.getName(),
+              scheduledEncoding.getQueueTime().getSeconds());
+        }
+
+        if (task.getStatus() == Status.FINISHED
+            || task.getStatus() == Status.ERROR
+            || task.getStatus() == Status.CANCELED) {
+          scheduledEncoding.status = task.getStatus();
+          if (task.getStatus() != Status.FINISHED) {
+            logTaskErrors(task);
+          }
+          pending.remove(scheduledEncoding);
+        }
+      }
+    }
+  }
+
+  /**
+   * Logs the encodings in the order they left the queue, with their priority and the time they
+   * were queued
+   *
+   * @param scheduledEncodings The encodings that have reached a final state
+   */
+  private static void logQueueTimes(List<ScheduledEncoding> scheduledEncodings) {
+    List<ScheduledEncoding> sorted = new ArrayList<>(scheduledEncodings);
+    sorted.sort(Comparator.comparing(scheduledEncoding -> scheduledEncoding.dequeuedAt));
+
+    logger.info("Encodings in the order they left the queue:");
+    for (ScheduledEncoding scheduledEncoding : sorted) {
+      logger.info(
+          "  {} - priority {}, queued for {} seconds, {}",
+          scheduledEncoding.encoding.getName(),
+          scheduledEncoding.priority,
+          scheduledEncoding.getQueueTime().getSeconds(),
+          scheduledEncoding.status);
+    }
+  }
+
+  private static class ScheduledEncoding {
+
+    private final Encoding encoding;
+    private final int priority;
+    private final Instant startedAt = Instant.now();
+    private Instant dequeuedAt;
+    private Status status;
+
+    /**
+     * @param encoding The started encoding
+     * @param priority The priority the encoding has been started with
+     */
+    private ScheduledEncoding(Encoding encoding, int priority) {
+      this.encoding = encoding;
+      this.priority = priority;
+    }
+
+    /** The time between starting the encoding and leaving the queue */
+    private Duration getQueueTime() {
+      return Duration.between(startedAt, dequeuedAt);
+    }
+  }
+
+  /**
+   * Creates a resource representing an HTTP server providing the input files. For alternative input
+   * methods see <a
+   * href="https://bitmovin.com/docs/encoding/articles/supported-input-output-storages">list of
+   * supported input and output storages</a>
+   *
+   * <p>For reasons of simplicity, a new input resource is created on each execution of this
+   * example. In production use, this method should be replaced by a <a
+   * href="https://bitmovin.com/docs/encoding/api-reference/sections/inputs#/Encoding/GetEncodingInputsHttpByInputId">get
+   * call</a> to retrieve an existing resource.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/inputs#/Encoding/PostEncodingInputsHttp
+   *
+   * @param host The hostname or IP address of the HTTP server e.g.: my-storage.biz
+   */
+  private static HttpInput createHttpInput(String host) throws BitmovinException {
+    HttpInput input = new HttpInput();
+    input.setHost(host);
+
+    return bitmovinApi.encoding.inputs.http.create(input);
+  }
+
+  /**
+   * Creates a resource representing an AWS S3 cloud storage bucket to which generated content will
+   * be transferred. For alternative output methods see <a
+   * href="https://bitmovin.com/docs/encoding/articles/supported-input-output-storages">list of
+   * supported input and output storages</a>
+   *
+   * <p>The provided credentials need to allow <i>read</i>, <i>write</i> and <i>list</i> operations.
+   * <i>delete</i> should also be granted to allow overwriting of existings files. See <a
+   * href="https://bitmovin.com/docs/encoding/faqs/how-do-i-create-a-aws-s3-bucket-which-can-be-used-as-output-location">creating
+   * an S3 bucket and setting permissions</a> for further information
+   *
+   * <p>For reasons of simplicity, a new output resource is created on each execution of this
+   * example. In production use, this method should be replaced by a <a
+   * href="https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/GetEncodingOutputsS3">get
+   * call</a> retrieving an existing resource.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/PostEncodingOutputsS3
+   *
+   * @param bucketName The name of the S3 bucket
+   * @param accessKey The access key of your S3 account
+   * @param secretKey The secret key of your S3 account
+   */
+  private static S3Output createS3Output(String bucketName, String accessKey, String secretKey)
+      throws BitmovinException {
+
+    S3Output s3Output = new S3Output();
+    s3Output.setBucketName(bucketName);
+    s3Output.setAccessKey(accessKey);
+    s3Output.setSecretKey(secretKey);
+
+    return bitmovinApi.encoding.outputs.s3.create(s3Output);
+  }
+
+  /**
+   * Creates an encoding object. This is the base object to configure your encoding.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodings
+   *
+   * @param name This is the name of the encoding
+   * @param description This is the description of the encoding
+   */
+  private static Encoding createEncoding(String name, String description) throws BitmovinException {
+    Encoding encoding = new Encoding();
+    encoding.setName(name);
+    encoding.setDescription(description);
+    new BudgetTag(configProvider).applyTo(encoding);
+
+    return bitmovinApi.encoding.encodings.create(encoding);
+  }
+
+  /**
+   * Creates a stream which binds an input file to a codec configuration. The stream is used for
+   * muxings later on.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsStreamsByEncodingId
+   *
+   * @param encoding The encoding to add the stream onto
+   * @param input The input that should be used
+   * @param inputPath The path to the input file
+   * @param codecConfiguration The codec configuration to be applied to the stream
+   */
+  private static Stream createStream(
+      Encoding encoding, Input input, String inputPath, CodecConfiguration codecConfiguration)
+      throws BitmovinException {
+    StreamInput streamInput =
+        PreviewTrimming.createStreamInput(bitmovinApi, configProvider, encoding, input, inputPath);
+
+    Stream stream = new Stream();
+    stream.addInputStreamsItem(streamInput);
+    stream.setCodecConfigId(codecConfiguration.getId());
+
+    return bitmovinApi.encoding.encodings.streams.create(encoding.getId(), stream);
+  }
+
+  /**
+   * Creates a configuration for the H.264 video codec to be applied to video streams.
+   *
+   * <p>The output resolution is defined by setting only the height. Width will be determined
+   * automatically to maintain the aspect ratio of your input video.
+   *
+   * <p>To keep things simple, we use a quality-optimized VoD preset configuration, which will apply
+   * proven settings for the codec. See <a
+   * href="https://bitmovin.com/docs/encoding/tutorials/how-to-optimize-your-h264-codec-configuration-for-different-use-cases">How
+   * to optimize your H264 codec configuration for different use-cases</a> for alternative presets.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsVideoH264
+   *
+   * @param height The height of the output video
+   * @param bitrate The target bitrate of the output video
+   */
+  private static H264VideoConfiguration createH264VideoConfig(int height, long bitrate)
+      throws BitmovinException {
+    H264VideoConfiguration config = new H264VideoConfiguration();
+    config.setName(String.format("H.264 %dp", height));
+    config.setPresetConfiguration(PresetConfiguration.VOD_STANDARD);
+    config.setHeight(height);
+    config.setBitrate(bitrate);
+
+    return bitmovinApi.encoding.configurations.video.h264.create(config);
+  }
+
+  /**
+   * Creates a configuration for the AAC audio codec to be applied to audio streams.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsAudioAac
+   */
+  private static AacAudioConfiguration createAacAudioConfig() throws BitmovinException {
+    AacAudioConfiguration config = new AacAudioConfiguration();
+    config.setName("AAC 128 kbit/s");
+    config.setBitrate(128_000L);
+
+    return bitmovinApi.encoding.configurations.audio.aac.create(config);
+  }
+
+  /**
+   * Creates a fragmented MP4 muxing. This will generate segments with a given segment length for
+   * adaptive streaming.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/all#/Encoding/PostEncodingEncodingsMuxingsFmp4ByEncodingId
+   *
+   * @param encoding The encoding where to add the muxing to
+   * @param output The output that should be used for the muxing to write the segments to
+   * @param outputPath The output path where the fragmented segments will be written to
+   * @param stream The stream that is associated with the muxing
+   */
+  private static Fmp4Muxing createFmp4Muxing(
+      Encoding encoding, Output output, String outputPath, Stream stream) throws BitmovinException {
+    MuxingStream muxingStream = new MuxingStream();
+    muxingStream.setStreamId(stream.getId());
+
+    Fmp4Muxing muxing = new Fmp4Muxing();
+    muxing.addOutputsItem(buildEncodingOutput(output, outputPath));
+    muxing.addStreamsItem(muxingStream);
+    muxing.setSegmentLength(4.0);
+    SegmentSharding.apply(configProvider, muxing);
+
+    return bitmovinApi.encoding.encodings.muxings.fmp4.create(encoding.getId(), muxing);
+  }
+
+  /**
+   * Builds an EncodingOutput object which defines where the output content (e.g. of a muxing) will
+   * be written to. Public read permissions will be set for the files written, so they can be
+   * accessed easily via HTTP.
+   *
+   * @param output The output resource to be used by the EncodingOutput
+   * @param outputPath The path where the content will be written to
+   */
+  private static EncodingOutput buildEncodingOutput(Output output, String outputPath) {
+    AclEntry aclEntry = new AclEntry();
+    aclEntry.setPermission(AclPermission.PUBLIC_READ);
+
+    EncodingOutput encodingOutput = new EncodingOutput();
+    encodingOutput.setOutputPath(buildAbsolutePath(outputPath));
+    encodingOutput.setOutputId(output.getId());
+    encodingOutput.addAclItem(aclEntry);
+    return encodingOutput;
+  }
+
+  /**
+   * Builds an absolute path by concatenating the S3_OUTPUT_BASE_PATH configuration parameter, the
+   * name of this example class and the given relative path
+   *
+   * <p>e.g.: /s3/base/path/ClassName/relative/path
+   *
+   * @param relativePath The relative path that is concatenated
+   * @return The absolute path
+   */
+  public static String buildAbsolutePath(String relativePath) {
+    String className = SchedulingPriorities.class.getSimpleName();
+    return Paths.get(configProvider.getS3OutputBasePath(), className, relativePath).toString();
+  }
+
+  private static void logTaskErrors(Task task) {
+    task.getMessages().stream()
+        .filter(msg -> msg.getType() == MessageType.ERROR)
+        .forEach(msg -> logger.error(msg.getText()));
+  }
+}
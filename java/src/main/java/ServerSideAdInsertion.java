@@ -27,18 +27,21 @@ import com.bitmovin.api.sdk.model.Stream;
 import com.bitmovin.api.sdk.model.StreamInfo;
 import com.bitmovin.api.sdk.model.StreamInput;
 import com.bitmovin.api.sdk.model.StreamMode;
-import com.bitmovin.api.sdk.model.StreamSelectionMode;
 import com.bitmovin.api.sdk.model.Task;
 import com.bitmovin.api.sdk.model.VideoConfiguration;
+import common.ApiClientFactory;
+import common.BudgetTag;
 import common.ConfigProvider;
-import feign.Logger.Level;
-import feign.slf4j.Slf4jLogger;
+import common.EncodingFailedException;
+import common.PreviewTrimming;
+import common.SegmentSharding;
 import java.nio.file.Paths;
 import java.util.ArrayList;
 import java.util.Arrays;
 import java.util.HashMap;
 import java.util.List;
 import java.util.Map;
+import java.util.stream.Collectors;
 import org.slf4j.Logger;
 import org.slf4j.LoggerFactory;
 
@@ -46,12 +49,18 @@ import org.slf4j.LoggerFactory;
  * This example demonstrates how to create multiple fMP4 renditions with Server Side Ad Insertion
  * (SSAI)
  *
+ * <p>At each ad break, a keyframe is inserted and the segments are cut, so that ads can be spliced
+ * in at segment boundaries. The HLS playlists are marked with a placement hint at these positions,
+ * optionally preceded by an EXT-X-DISCONTINUITY tag.
+ *
  * <p>The following configuration parameters are expected:
  *
  * <ul>
  *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin API
  *   <li>BITMOVIN_TENANT_ORG_ID - (optional) The ID of the Organisation in which you want to perform
  *       the encoding.
+ *   <li>BITMOVIN_API_BASE_URL - (optional) The base URL of the Bitmovin API, e.g. of a mock server
+ *       or an API gateway. Default: https://api.bitmovin.com/v1
  *   <li>HTTP_INPUT_HOST - The Hostname or IP address of the HTTP server hosting your input files,
  *       e.g.: my-storage.biz
  *   <li>HTTP_INPUT_FILE_PATH - The path to your input file on the provided HTTP server Example:
@@ -61,6 +70,12 @@ import org.slf4j.LoggerFactory;
  *   <li>S3_OUTPUT_SECRET_KEY - The secret key of your S3 output bucket
  *   <li>S3_OUTPUT_BASE_PATH - The base path on your S3 output bucket where content will be written.
  *       Example: /outputs
+ *   <li>SSAI_AD_BREAKS - (optional) A comma-separated list of the ad break positions in seconds.
+ *       Default: 5,15
+ *   <li>SSAI_PLACEMENT_TAG - (optional) The custom tag written at each ad break. Default:
+ *       #AD-PLACEMENT-OPPORTUNITY
+ *   <li>SSAI_INSERT_DISCONTINUITY - (optional) Set to true to write an EXT-X-DISCONTINUITY tag at
+ *       each ad break. Default: false
  * </ul>
  *
  * <p>Configuration parameters will be retrieved from these sources in the listed order:
@@ -84,12 +99,8 @@ public class ServerSideAdInsertion {
   public static void main(String[] args) throws Exception {
     configProvider = new ConfigProvider(args);
     bitmovinApi =
-        BitmovinApi.builder()
+        ApiClientFactory.builder(configProvider)
             .withApiKey(configProvider.getBitmovinApiKey())
-            // uncomment the following line if you are working with a multi-tenant account
-            // .withTenantOrgId(configProvider.getBitmovinTenantOrgId())
-            .withLogger(
-                new Slf4jLogger(), Level.BASIC) // set the logger and log level for the API client
             .build();
 
     Encoding encoding =
@@ -131,7 +142,10 @@ public class ServerSideAdInsertion {
 
     // Seconds in which to add a custom HLS tag for ad placement, as well as when to insert a
     // keyframe/split a segment
-    final List<Double> adBreakPlacements = Arrays.asList(5.0D, 15.0D);
+    final List<Double> adBreakPlacements =
+        Arrays.stream(configProvider.getParameterByKey("SSAI_AD_BREAKS", "5,15").split(","))
+            .map(position -> Double.parseDouble(position.trim()))
+            .collect(Collectors.toList());
 
     // define keyframes that are used to insert advertisement tags into the manifest
     List<Keyframe> keyframes = createKeyframes(encoding, adBreakPlacements);
@@ -234,6 +248,7 @@ public class ServerSideAdInsertion {
     Encoding encoding = new Encoding();
     encoding.setName(name);
     encoding.setDescription(description);
+    new BudgetTag(configProvider).applyTo(encoding);
 
     return bitmovinApi.encoding.encodings.create(encoding);
   }
@@ -259,10 +274,8 @@ public class ServerSideAdInsertion {
       StreamMode streamMode)
       throws BitmovinException {
 
-    StreamInput streamInput = new StreamInput();
-    streamInput.setInputId(input.getId());
-    streamInput.setInputPath(inputPath);
-    streamInput.setSelectionMode(StreamSelectionMode.AUTO);
+    StreamInput streamInput =
+        PreviewTrimming.createStreamInput(bitmovinApi, configProvider, encoding, input, inputPath);
 
     Stream stream = new Stream();
     stream.addInputStreamsItem(streamInput);
@@ -335,6 +348,7 @@ public class ServerSideAdInsertion {
     muxing.addOutputsItem(buildEncodingOutput(output, outputPath));
     muxing.addStreamsItem(muxingStream);
     muxing.setSegmentLength(4.0);
+    SegmentSharding.apply(configProvider, muxing);
 
     return bitmovinApi.encoding.encodings.muxings.fmp4.create(encoding.getId(), muxing);
   }
@@ -466,9 +480,10 @@ public class ServerSideAdInsertion {
       HlsManifest manifest, AudioMediaInfo audioMediaInfo, List<Keyframe> keyframes)
       throws BitmovinException {
     for (Keyframe keyframe : keyframes) {
-      CustomTag customTag = createAdvertisementTag(keyframe);
-      bitmovinApi.encoding.manifests.hls.media.customTags.create(
-          manifest.getId(), audioMediaInfo.getId(), customTag);
+      for (CustomTag customTag : createAdvertisementTags(keyframe)) {
+        bitmovinApi.encoding.manifests.hls.media.customTags.create(
+            manifest.getId(), audioMediaInfo.getId(), customTag);
+      }
     }
   }
 
@@ -482,23 +497,41 @@ public class ServerSideAdInsertion {
       HlsManifest manifest, StreamInfo streamInfo, List<Keyframe> keyframes)
       throws BitmovinException {
     for (Keyframe keyframe : keyframes) {
-      CustomTag customTag = createAdvertisementTag(keyframe);
-      bitmovinApi.encoding.manifests.hls.streams.customTags.create(
-          manifest.getId(), streamInfo.getId(), customTag);
+      for (CustomTag customTag : createAdvertisementTags(keyframe)) {
+        bitmovinApi.encoding.manifests.hls.streams.customTags.create(
+            manifest.getId(), streamInfo.getId(), customTag);
+      }
     }
   }
 
   /**
-   * Creates a custom hls tag which represents an advertisement opportunity at the given keyframe
-   * position
+   * Creates the custom hls tags which represent an advertisement opportunity at the given keyframe
+   * position. If configured, an EXT-X-DISCONTINUITY tag is written before the placement hint, to
+   * signal the player that the timestamps and encoding parameters of the inserted ad may differ.
    */
-  private static CustomTag createAdvertisementTag(Keyframe keyframe) {
-    CustomTag customTag = new CustomTag();
-    customTag.setKeyframeId(keyframe.getId());
-    customTag.setPositionMode(PositionMode.KEYFRAME);
-    customTag.setData("#AD-PLACEMENT-OPPORTUNITY");
+  private static List<CustomTag> createAdvertisementTags(Keyframe keyframe) {
+    boolean insertDiscontinuity =
+        Boolean.parseBoolean(
+            configProvider.getParameterByKey("SSAI_INSERT_DISCONTINUITY", "false"));
+    String placementTag =
+        configProvider.getParameterByKey("SSAI_PLACEMENT_TAG", "#AD-PLACEMENT-OPPORTUNITY");
+
+    List<String> tagData = new ArrayList<>();
+    if (insertDiscontinuity) {
+      tagData.add("#EXT-X-DISCONTINUITY");
+    }
+    tagData.add(placementTag);
+
+    List<CustomTag> customTags = new ArrayList<>();
+    for (String data : tagData) {
+      CustomTag customTag = new CustomTag();
+      customTag.setKeyframeId(keyframe.getId());
+      customTag.setPositionMode(PositionMode.KEYFRAME);
+      customTag.setData(data);
+      customTags.add(customTag);
+    }
 
-    return customTag;
+    return customTags;
   }
 
   /**
@@ -524,11 +557,13 @@ public class ServerSideAdInsertion {
       Thread.sleep(5000);
       task = bitmovinApi.encoding.encodings.status(encoding.getId());
       logger.info("encoding status is {} (progress: {} %)", task.getStatus(), task.getProgress());
-    } while (task.getStatus() != Status.FINISHED && task.getStatus() != Status.ERROR);
+    } while (task.getStatus() != Status.FINISHED
+        && task.getStatus() != Status.ERROR
+        && task.getStatus() != Status.CANCELED);
 
-    if (task.getStatus() == Status.ERROR) {
+    if (task.getStatus() != Status.FINISHED) {
       logTaskErrors(task);
-      throw new RuntimeException("Encoding failed");
+      throw new EncodingFailedException(task.getStatus());
     }
     logger.info("encoding finished successfully");
   }
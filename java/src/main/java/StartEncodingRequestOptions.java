@@ -0,0 +1,599 @@
+import com.bitmovin.api.sdk.BitmovinApi;
+import com.bitmovin.api.sdk.common.BitmovinException;
+import com.bitmovin.api.sdk.model.AacAudioConfiguration;
+import com.bitmovin.api.sdk.model.AclEntry;
+import com.bitmovin.api.sdk.model.AclPermission;
+import com.bitmovin.api.sdk.model.AudioVideoSyncMode;
+import com.bitmovin.api.sdk.model.AutoRepresentation;
+import com.bitmovin.api.sdk.model.CodecConfiguration;
+import com.bitmovin.api.sdk.model.DashManifestDefault;
+import com.bitmovin.api.sdk.model.DashManifestDefaultVersion;
+import com.bitmovin.api.sdk.model.Encoding;
+import com.bitmovin.api.sdk.model.EncodingMode;
+import com.bitmovin.api.sdk.model.EncodingOutput;
+import com.bitmovin.api.sdk.model.Fmp4Muxing;
+import com.bitmovin.api.sdk.model.H264PerTitleConfiguration;
+import com.bitmovin.api.sdk.model.H264VideoConfiguration;
+import com.bitmovin.api.sdk.model.HlsManifestDefault;
+import com.bitmovin.api.sdk.model.HlsManifestDefaultVersion;
+import com.bitmovin.api.sdk.model.HttpInput;
+import com.bitmovin.api.sdk.model.Input;
+import com.bitmovin.api.sdk.model.ManifestGenerator;
+import com.bitmovin.api.sdk.model.ManifestResource;
+import com.bitmovin.api.sdk.model.MessageType;
+import com.bitmovin.api.sdk.model.MuxingStream;
+import com.bitmovin.api.sdk.model.Output;
+import com.bitmovin.api.sdk.model.PerTitle;
+import com.bitmovin.api.sdk.model.PresetConfiguration;
+import com.bitmovin.api.sdk.model.S3Output;
+import com.bitmovin.api.sdk.model.Scheduling;
+import com.bitmovin.api.sdk.model.StartEncodingRequest;
+import com.bitmovin.api.sdk.model.Status;
+import com.bitmovin.api.sdk.model.Stream;
+import com.bitmovin.api.sdk.model.StreamInput;
+import com.bitmovin.api.sdk.model.StreamMode;
+import com.bitmovin.api.sdk.model.Task;
+import com.bitmovin.api.sdk.model.Trimming;
+import com.bitmovin.api.sdk.model.Tweaks;
+import common.ApiClientFactory;
+import common.BudgetTag;
+import common.ConfigProvider;
+import common.EncodingFailedException;
+import common.PreviewTrimming;
+import common.SegmentSharding;
+import java.nio.file.Paths;
+import java.util.Arrays;
+import java.util.List;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+
+/**
+ * This example demonstrates the options of the StartEncodingRequest, which change how an encoding
+ * is processed without changing its configuration. Each option can be toggled with a configuration
+ * parameter, and is left at the default of the Bitmovin API if the parameter is not set:
+ *
+ * <ul>
+ *   <li>Trimming (START_TRIMMING_OFFSET, START_TRIMMING_DURATION) - Encodes only a time range of
+ *       the input file, without creating trimming input streams
+ *   <li>Scheduling (START_PRIORITY) - The priority of the encoding in the queue of your account,
+ *       from 0 to 100. The default is 50.
+ *   <li>Tweaks (START_AUDIO_VIDEO_SYNC_MODE) - How audio and video are synchronized if their start
+ *       or end times in the input file differ: STANDARD, RESYNC_AT_START or RESYNC_AT_START_AND_END
+ *   <li>Variable frame rate handling (START_HANDLE_VARIABLE_INPUT_FPS) - Whether inputs with a
+ *       variable frame rate are converted to a constant frame rate
+ *   <li>Encoding mode (START_ENCODING_MODE) - STANDARD, SINGLE_PASS, TWO_PASS or THREE_PASS
+ *   <li>Manifest generator (START_MANIFEST_GENERATOR) - LEGACY or V2, the generator used for the
+ *       manifests referenced in the request
+ *   <li>Per-Title (START_PER_TITLE) - If true, the video renditions are chosen by the Per-Title
+ *       algorithm instead of the fixed bitrate ladder
+ * </ul>
+ *
+ * <p>The default DASH and HLS manifests are referenced as VoD manifests in the request, so they are
+ * generated by the encoding itself once it has finished. The request sent to the API is logged
+ * before the encoding is started.
+ *
+ * <p>The following configuration parameters are expected:
+ *
+ * <ul>
+ *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin API
+ *   <li>BITMOVIN_TENANT_ORG_ID - (optional) The ID of the Organisation in which you want to perform
+ *       the encoding.
+ *   <li>BITMOVIN_API_BASE_URL - (optional) The base URL of the Bitmovin API, e.g. of a mock server
+ *       or an API gateway. Default: https://api.bitmovin.com/v1
+ *   <li>HTTP_INPUT_HOST - The Hostname or IP address of the HTTP server hosting your input files,
+ *       e.g.: my-storage.biz
+ *   <li>HTTP_INPUT_FILE_PATH - The path to your input file on the provided HTTP server Example:
+ *       videos/1080p_Sintel.mp4
+ *   <li>S3_OUTPUT_BUCKET_NAME - The name of your S3 output bucket. Example: my-bucket-name
+ *   <li>S3_OUTPUT_ACCESS_KEY - The access key of your S3 output bucket
+ *   <li>S3_OUTPUT_SECRET_KEY - The secret key of your S3 output bucket
+ *   <li>S3_OUTPUT_BASE_PATH - The base path on your S3 output bucket where content will be written.
+ *       Example: /outputs
+ *   <li>START_TRIMMING_OFFSET - (optional) The position in the input file in seconds where the
+ *       encoding starts
+ *   <li>START_TRIMMING_DURATION - (optional) The duration of the encoded part of the input file in
+ *       seconds
+ *   <li>START_PRIORITY - (optional) The priority of the encoding, from 0 to 100
+ *   <li>START_AUDIO_VIDEO_SYNC_MODE - (optional) The audio/video synchronization mode
+ *   <li>START_HANDLE_VARIABLE_INPUT_FPS - (optional) Whether variable frame rate inputs are
+ *       converted to a constant frame rate
+ *   <li>START_ENCODING_MODE - (optional) The encoding mode
+ *   <li>START_MANIFEST_GENERATOR - (optional) The manifest generator. Default: V2
+ *   <li>START_PER_TITLE - (optional) Whether Per-Title is used for the video renditions. Default:
+ *       false
+ * </ul>
+ *
+ * <p>Configuration parameters will be retrieved from these sources in the listed order:
+ *
+ * <ol>
+ *   <li>command line arguments (eg BITMOVIN_API_KEY=xyz)
+ *   <li>properties file located in the root folder of the JAVA examples at ./examples.properties
+ *       (see examples.properties.template as reference)
+ *   <li>environment variables
+ *   <li>properties file located in the home folder at ~/.bitmovin/examples.properties (see
+ *       examples.properties.template as reference)
+ * </ol>
+ */
+public class StartEncodingRequestOptions {
+  private static final Logger logger = LoggerFactory.getLogger(StartEncodingRequestOptions.class);
+
+  private static BitmovinApi bitmovinApi;
+  private static ConfigProvider configProvider;
+
+  /** This list defines the video renditions that will be generated without Per-Title */
+  private static List<VideoRendition> videoRenditions =
+      Arrays.asList(
+          new VideoRendition(1080, 4_800_000L),
+          new VideoRendition(720, 2_400_000L),
+          new VideoRendition(480, 1_200_000L));
+
+  public static void main(String[] args) throws Exception {
+    configProvider = new ConfigProvider(args);
+    bitmovinApi =
+        ApiClientFactory.builder(configProvider)
+            .withApiKey(configProvider.getBitmovinApiKey())
+            .build();
+
+    boolean perTitle =
+        Boolean.parseBoolean(configProvider.getParameterByKey("START_PER_TITLE", "false"));
+
+    Encoding encoding =
+        createEncoding("StartEncodingRequest options", "Encoding with configurable start options");
+
+    HttpInput input = createHttpInput(configProvider.getHttpInputHost());
+    Output output =
+        createS3Output(
+            configProvider.getS3OutputBucketName(),
+            configProvider.getS3OutputAccessKey(),
+            configProvider.getS3OutputSecretKey());
+
+    String inputFilePath = configProvider.getHttpInputFilePath();
+
+    if (perTitle) {
+      Stream videoStream =
+          createStream(
+              encoding,
+              input,
+              inputFilePath,
+              createBaseH264VideoConfig(),
+              StreamMode.PER_TITLE_TEMPLATE);
+      createFmp4Muxing(encoding, output, "video/{height}/{bitrate}_{uuid}", videoStream);
+    } else {
+      for (VideoRendition videoRendition : videoRenditions) {
+        H264VideoConfiguration videoConfiguration =
+            createH264VideoConfig(videoRendition.height, videoRendition.bitrate);
+        Stream videoStream =
+            createStream(encoding, input, inputFilePath, videoConfiguration, StreamMode.STANDARD);
+        createFmp4Muxing(encoding, output, "video/" + videoRendition.height, videoStream);
+      }
+    }
+
+    AacAudioConfiguration aacConfig = createAacAudioConfig();
+    Stream audioStream =
+        createStream(encoding, input, inputFilePath, aacConfig, StreamMode.STANDARD);
+    createFmp4Muxing(encoding, output, "audio", audioStream);
+
+    DashManifestDefault dashManifest = createDefaultDashManifest(encoding, output, "/");
+    HlsManifestDefault hlsManifest = createDefaultHlsManifest(encoding, output, "/");
+
+    StartEncodingRequest startEncodingRequest =
+        buildStartEncodingRequest(perTitle, dashManifest, hlsManifest);
+    logger.info("Starting the encoding with {}", startEncodingRequest);
+
+    executeEncoding(encoding, startEncodingRequest);
+  }
+
+  /**
+   * Builds the StartEncodingRequest from the START_* configuration parameters. Options without a
+   * configuration value are not set, so the defaults of the Bitmovin API apply.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsStartByEncodingId
+   *
+   * @param perTitle Whether the Per-Title algorithm chooses the video renditions
+   * @param dashManifest The default DASH manifest to be generated by the encoding
+   * @param hlsManifest The default HLS manifest to be generated by the encoding
+   */
+  private static StartEncodingRequest buildStartEncodingRequest(
+      boolean perTitle, DashManifestDefault dashManifest, HlsManifestDefault hlsManifest) {
+    StartEncodingRequest startEncodingRequest = new StartEncodingRequest();
+
+    String trimmingOffset = configProvider.getParameterByKey("START_TRIMMING_OFFSET", null);
+    String trimmingDuration = configProvider.getParameterByKey("START_TRIMMING_DURATION", null);
+    if (trimmingOffset != null || trimmingDuration != null) {
+      Trimming trimming = new Trimming();
+      if (trimmingOffset != null) {
+        trimming.setOffset(Double.parseDouble(trimmingOffset));
+      }
+      if (trimmingDuration != null) {
+        trimming.setDuration(Double.parseDouble(trimmingDuration));
+      }
+      startEncodingRequest.setTrimming(trimming);
+    }
+
+    String priority = configProvider.getParameterByKey("START_PRIORITY", null);
+    if (priority != null) {
+      Scheduling scheduling = new Scheduling();
+      scheduling.setPriority(Integer.parseInt(priority));
+      startEncodingRequest.setScheduling(scheduling);
+    }
+
+    String audioVideoSyncMode =
+        configProvider.getParameterByKey("START_AUDIO_VIDEO_SYNC_MODE", null);
+    if (audioVideoSyncMode != null) {
+      Tweaks tweaks = new Tweaks();
+      tweaks.setAudioVideoSyncMode(AudioVideoSyncMode.valueOf(audioVideoSyncMode));
+      startEncodingRequest.setTweaks(tweaks);
+    }
+
+    String handleVariableInputFps =
+        configProvider.getParameterByKey("START_HANDLE_VARIABLE_INPUT_FPS", null);
+    if (handleVariableInputFps != null) {
+      startEncodingRequest.setHandleVariableInputFps(Boolean.parseBoolean(handleVariableInputFps));
+    }
+
+    String encodingMode = configProvider.getParameterByKey("START_ENCODING_MODE", null);
+    if (encodingMode != null) {
+      startEncodingRequest.setEncodingMode(EncodingMode.valueOf(encodingMode));
+    }
+
+    startEncodingRequest.setManifestGenerator(
+        ManifestGenerator.valueOf(
+            configProvider.getParameterByKey("START_MANIFEST_GENERATOR", "V2")));
+    startEncodingRequest.addVodDashManifestsItem(buildManifestResource(dashManifest.getId()));
+    startEncodingRequest.addVodHlsManifestsItem(buildManifestResource(hlsManifest.getId()));
+
+    if (perTitle) {
+      startEncodingRequest.setPerTitle(buildPerTitle());
+    }
+
+    return startEncodingRequest;
+  }
+
+  /**
+   * Builds a very basic H.264 Per-Title configuration that will let the Per-Title algorithm freely
+   * choose stream configurations and add streams.
+   *
+   * <p>See https://bitmovin.com/docs/encoding/tutorials/per-title-configuration-options-explained
+   * to get an insight into what properties can be set here.
+   */
+  private static PerTitle buildPerTitle() {
+    H264PerTitleConfiguration perTitleConfiguration = new H264PerTitleConfiguration();
+    perTitleConfiguration.setAutoRepresentations(new AutoRepresentation());
+
+    PerTitle perTitle = new PerTitle();
+    perTitle.setH264Configuration(perTitleConfiguration);
+    return perTitle;
+  }
+
+  /**
+   * Wraps a manifest ID into a ManifestResource object, so it can be referenced in one of the
+   * StartEncodingRequest manifest lists.
+   *
+   * @param manifestId The ID of the manifest
+   */
+  private static ManifestResource buildManifestResource(String manifestId) {
+    ManifestResource manifestResource = new ManifestResource();
+    manifestResource.setManifestId(manifestId);
+    return manifestResource;
+  }
+
+  /**
+   * Adds a video or audio stream to an encoding
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsStreamsByEncodingId
+   *
+   * @param encoding The encoding to which the stream will be added
+   * @param input The input resource providing the input file
+   * @param inputPath The path to the input file
+   * @param codecConfiguration The codec configuration to be applied to the stream
+   * @param streamMode The stream mode, PER_TITLE_TEMPLATE for the template of Per-Title renditions
+   */
+  private static Stream createStream(
+      Encoding encoding,
+      Input input,
+      String inputPath,
+      CodecConfiguration codecConfiguration,
+      StreamMode streamMode)
+      throws BitmovinException {
+    StreamInput streamInput =
+        PreviewTrimming.createStreamInput(bitmovinApi, configProvider, encoding, input, inputPath);
+
+    Stream stream = new Stream();
+    stream.addInputStreamsItem(streamInput);
+    stream.setCodecConfigId(codecConfiguration.getId());
+    stream.setMode(streamMode);
+
+    return bitmovinApi.encoding.encodings.streams.create(encoding.getId(), stream);
+  }
+
+  /**
+   * Creates a base H.264 video configuration. This is a base configuration, the optimal settings
+   * will be automatically chosen during the Per-Title encoding process.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsVideoH264
+   */
+  private static H264VideoConfiguration createBaseH264VideoConfig() throws BitmovinException {
+    H264VideoConfiguration config = new H264VideoConfiguration();
+    config.setName("Base H.264 video config");
+    config.setPresetConfiguration(PresetConfiguration.VOD_STANDARD);
+
+    return bitmovinApi.encoding.configurations.video.h264.create(config);
+  }
+
+  /**
+   * Creates a DASH default manifest, which is generated by the encoding once it has finished.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsDash
+   *
+   * @param encoding The encoding for which the manifest should be generated
+   * @param output The output to which the manifest should be written
+   * @param outputPath The path to which the manifest should be written
+   */
+  private static DashManifestDefault createDefaultDashManifest(
+      Encoding encoding, Output output, String outputPath) throws BitmovinException {
+    DashManifestDefault dashManifestDefault = new DashManifestDefault();
+    dashManifestDefault.setEncodingId(encoding.getId());
+    dashManifestDefault.setManifestName("stream.mpd");
+    dashManifestDefault.setVersion(DashManifestDefaultVersion.V1);
+    dashManifestDefault.addOutputsItem(buildEncodingOutput(output, outputPath));
+
+    return bitmovinApi.encoding.manifests.dash.defaultapi.create(dashManifestDefault);
+  }
+
+  /**
+   * Creates an HLS default manifest, which is generated by the encoding once it has finished.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/manifests#/Encoding/PostEncodingManifestsHlsDefault
+   *
+   * @param encoding The encoding for which the manifest should be generated
+   * @param output The output to which the manifest should be written
+   * @param outputPath The path to which the manifest should be written
+   */
+  private static HlsManifestDefault createDefaultHlsManifest(
+      Encoding encoding, Output output, String outputPath) throws BitmovinException {
+    HlsManifestDefault hlsManifestDefault = new HlsManifestDefault();
+    hlsManifestDefault.setEncodingId(encoding.getId());
+    hlsManifestDefault.addOutputsItem(buildEncodingOutput(output, outputPath));
+    hlsManifestDefault.setName("master.m3u8");
+    hlsManifestDefault.setVersion(HlsManifestDefaultVersion.V1);
+
+    return bitmovinApi.encoding.manifests.hls.defaultapi.create(hlsManifestDefault);
+  }
+
+  private static class VideoRendition {
+
+    private int height;
+    private long bitrate;
+
+    /**
+     * @param height The target output height of the rendition
+     * @param bitrate The target output bitrate of the rendition
+     */
+    private VideoRendition(int height, long bitrate) {
+      this.height = height;
+      this.bitrate = bitrate;
+    }
+  }
+
+  /**
+   * Creates a resource representing an HTTP server providing the input files. For alternative input
+   * methods see <a
+   * href="https://bitmovin.com/docs/encoding/articles/supported-input-output-storages">list of
+   * supported input and output storages</a>
+   *
+   * <p>For reasons of simplicity, a new input resource is created on each execution of this
+   * example. In production use, this method should be replaced by a <a
+   * href="https://bitmovin.com/docs/encoding/api-reference/sections/inputs#/Encoding/GetEncodingInputsHttpByInputId">get
+   * call</a> to retrieve an existing resource.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/inputs#/Encoding/PostEncodingInputsHttp
+   *
+   * @param host The hostname or IP address of the HTTP server e.g.: my-storage.biz
+   */
+  private static HttpInput createHttpInput(String host) throws BitmovinException {
+    HttpInput input = new HttpInput();
+    input.setHost(host);
+
+    return bitmovinApi.encoding.inputs.http.create(input);
+  }
+
+  /**
+   * Creates a resource representing an AWS S3 cloud storage bucket to which generated content will
+   * be transferred. For alternative output methods see <a
+   * href="https://bitmovin.com/docs/encoding/articles/supported-input-output-storages">list of
+   * supported input and output storages</a>
+   *
+   * <p>The provided credentials need to allow <i>read</i>, <i>write</i> and <i>list</i> operations.
+   * <i>delete</i> should also be granted to allow overwriting of existings files. See <a
+   * href="https://bitmovin.com/docs/encoding/faqs/how-do-i-create-a-aws-s3-bucket-which-can-be-used-as-output-location">creating
+   * an S3 bucket and setting permissions</a> for further information
+   *
+   * <p>For reasons of simplicity, a new output resource is created on each execution of this
+   * example. In production use, this method should be replaced by a <a
+   * href="https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/GetEncodingOutputsS3">get
+   * call</a> retrieving an existing resource.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/outputs#/Encoding/PostEncodingOutputsS3
+   *
+   * @param bucketName The name of the S3 bucket
+   * @param accessKey The access key of your S3 account
+   * @param secretKey The secret key of your S3 account
+   */
+  private static S3Output createS3Output(String bucketName, String accessKey, String secretKey)
+      throws BitmovinException {
+
+    S3Output s3Output = new S3Output();
+    s3Output.setBucketName(bucketName);
+    s3Output.setAccessKey(accessKey);
+    s3Output.setSecretKey(secretKey);
+
+    return bitmovinApi.encoding.outputs.s3.create(s3Output);
+  }
+
+  /**
+   * Creates an encoding object. This is the base object to configure your encoding.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodings
+   *
+   * @param name This is the name of the encoding
+   * @param description This is the description of the encoding
+   */
+  private static Encoding createEncoding(String name, String description) throws BitmovinException {
+    Encoding encoding = new Encoding();
+    encoding.setName(name);
+    encoding.setDescription(description);
+    new BudgetTag(configProvider).applyTo(encoding);
+
+    return bitmovinApi.encoding.encodings.create(encoding);
+  }
+
+  /**
+   * Creates a configuration for the H.264 video codec to be applied to video streams.
+   *
+   * <p>The output resolution is defined by setting only the height. Width will be determined
+   * automatically to maintain the aspect ratio of your input video.
+   *
+   * <p>To keep things simple, we use a quality-optimized VoD preset configuration, which will apply
+   * proven settings for the codec. See <a
+   * href="https://bitmovin.com/docs/encoding/tutorials/how-to-optimize-your-h264-codec-configuration-for-different-use-cases">How
+   * to optimize your H264 codec configuration for different use-cases</a> for alternative presets.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsVideoH264
+   *
+   * @param height The height of the output video
+   * @param bitrate The target bitrate of the output video
+   */
+  private static H264VideoConfiguration createH264VideoConfig(int height, long bitrate)
+      throws BitmovinException {
+    H264VideoConfiguration config = new H264VideoConfiguration();
+    config.setName(String.format("H.264 %dp", height));
+    config.setPresetConfiguration(PresetConfiguration.VOD_STANDARD);
+    config.setHeight(height);
+    config.setBitrate(bitrate);
+
+    return bitmovinApi.encoding.configurations.video.h264.create(config);
+  }
+
+  /**
+   * Creates a configuration for the AAC audio codec to be applied to audio streams.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/configurations#/Encoding/PostEncodingConfigurationsAudioAac
+   */
+  private static AacAudioConfiguration createAacAudioConfig() throws BitmovinException {
+    AacAudioConfiguration config = new AacAudioConfiguration();
+    config.setName("AAC 128 kbit/s");
+    config.setBitrate(128_000L);
+
+    return bitmovinApi.encoding.configurations.audio.aac.create(config);
+  }
+
+  /**
+   * Creates a fragmented MP4 muxing. This will generate segments with a given segment length for
+   * adaptive streaming.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/all#/Encoding/PostEncodingEncodingsMuxingsFmp4ByEncodingId
+   *
+   * @param encoding The encoding where to add the muxing to
+   * @param output The output that should be used for the muxing to write the segments to
+   * @param outputPath The output path where the fragmented segments will be written to
+   * @param stream The stream that is associated with the muxing
+   */
+  private static Fmp4Muxing createFmp4Muxing(
+      Encoding encoding, Output output, String outputPath, Stream stream) throws BitmovinException {
+    MuxingStream muxingStream = new MuxingStream();
+    muxingStream.setStreamId(stream.getId());
+
+    Fmp4Muxing muxing = new Fmp4Muxing();
+    muxing.addOutputsItem(buildEncodingOutput(output, outputPath));
+    muxing.addStreamsItem(muxingStream);
+    muxing.setSegmentLength(4.0);
+    SegmentSharding.apply(configProvider, muxing);
+
+    return bitmovinApi.encoding.encodings.muxings.fmp4.create(encoding.getId(), muxing);
+  }
+
+  /**
+   * Builds an EncodingOutput object which defines where the output content (e.g. of a muxing) will
+   * be written to. Public read permissions will be set for the files written, so they can be
+   * accessed easily via HTTP.
+   *
+   * @param output The output resource to be used by the EncodingOutput
+   * @param outputPath The path where the content will be written to
+   */
+  private static EncodingOutput buildEncodingOutput(Output output, String outputPath) {
+    AclEntry aclEntry = new AclEntry();
+    aclEntry.setPermission(AclPermission.PUBLIC_READ);
+
+    EncodingOutput encodingOutput = new EncodingOutput();
+    encodingOutput.setOutputPath(buildAbsolutePath(outputPath));
+    encodingOutput.setOutputId(output.getId());
+    encodingOutput.addAclItem(aclEntry);
+    return encodingOutput;
+  }
+
+  /**
+   * Builds an absolute path by concatenating the S3_OUTPUT_BASE_PATH configuration parameter, the
+   * name of this example class and the given relative path
+   *
+   * <p>e.g.: /s3/base/path/ClassName/relative/path
+   *
+   * @param relativePath The relative path that is concatenated
+   * @return The absolute path
+   */
+  public static String buildAbsolutePath(String relativePath) {
+    String className = StartEncodingRequestOptions.class.getSimpleName();
+    return Paths.get(configProvider.getS3OutputBasePath(), className, relativePath).toString();
+  }
+
+  /**
+   * Starts the actual encoding process and periodically polls its status until it reaches a final
+   * state
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/all#/Encoding/PostEncodingEncodingsStartByEncodingId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/GetEncodingEncodingsStatusByEncodingId
+   *
+   * <p>Please note that you can also use our webhooks API instead of polling the status. For more
+   * information consult the API spec:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/notifications-webhooks
+   *
+   * @param encoding The encoding to be started
+   * @param startEncodingRequest The request object to be sent with the start call
+   */
+  private static void executeEncoding(Encoding encoding, StartEncodingRequest startEncodingRequest)
+      throws InterruptedException, BitmovinException {
+    bitmovinApi.encoding.encodings.start(encoding.getId(), startEncodingRequest);
+
+    Task task;
+    do {
+      Thread.sleep(5000);
+      task = bitmovinApi.encoding.encodings.status(encoding.getId());
+      logger.info("encoding status is {} (progress: {} %)", task.getStatus(), task.getProgress());
+    } while (task.getStatus() != Status.FINISHED
+        && task.getStatus() != Status.ERROR
+        && task.getStatus() != Status.CANCELED);
+
+    if (task.getStatus() != Status.FINISHED) {
+      logTaskErrors(task);
+      throw new EncodingFailedException(task.getStatus());
+    }
+    logger.info("encoding finished successfully");
+  }
+
+  private static void logTaskErrors(Task task) {
+    task.getMessages().stream()
+        .filter(msg -> msg.getType() == MessageType.ERROR)
+        .forEach(msg -> logger.error(msg.getText()));
+  }
+}
@@ -0,0 +1,97 @@
+package common;
+
+import com.bitmovin.api.sdk.BitmovinApi;
+import feign.Client;
+import feign.Logger.Level;
+import feign.slf4j.Slf4jLogger;
+import java.io.IOException;
+import java.io.UncheckedIOException;
+import java.security.GeneralSecurityException;
+
+/**
+ * This class creates the builders of the API clients used by the examples, so the settings of the
+ * configuration are applied to all examples in the same way:
+ *
+ * <ul>
+ *   <li>the base URL of the Bitmovin API, see {@link ConfigProvider#getBitmovinApiBaseUrl()}
+ *   <li>the organisation the API calls are performed in, if BITMOVIN_TENANT_ORG_ID is configured
+ *   <li>the proxy and TLS settings of the HTTP client, see {@link HttpClientSettings}
+ *   <li>the connect, read and call timeouts of the HTTP client, so a loop polling the status of an
+ *       encoding fails instead of hanging when the API cannot be reached
+ *   <li>the debug log of all requests and responses if BITMOVIN_DEBUG is set to true, see {@link
+ *       ApiDebugLogger}
+ * </ul>
+ *
+ * <p>The examples only add their credentials before the API client is built:
+ *
+ * <pre>
+ * BitmovinApi bitmovinApi =
+ *     ApiClientFactory.builder(configProvider)
+ *         .withApiKey(configProvider.getBitmovinApiKey())
+ *         .build();
+ * </pre>
+ */
+public class ApiClientFactory {
+
+  private ApiClientFactory() {}
+
+  /**
+   * Returns a builder for an API client which logs the method, URL, status and duration of every
+   * API call
+   *
+   * @param configProvider the config provider the settings are read from
+   */
+  public static BitmovinApi.Builder builder(ConfigProvider configProvider) {
+    return builder(configProvider, Level.BASIC);
+  }
+
+  /**
+   * Returns a builder for an API client which logs its API calls with the given log level. If
+   * debug logging is enabled, the full requests and responses are written to the debug log file
+   * instead, regardless of the given log level.
+   *
+   * @param configProvider the config provider the settings are read from
+   * @param logLevel the level of detail the API calls are logged with, e.g. Level.FULL to log the
+   *     headers and bodies of requests and responses
+   */
+  public static BitmovinApi.Builder builder(ConfigProvider configProvider, Level logLevel) {
+    BitmovinApi.Builder builder = accountBuilder(configProvider, logLevel);
+
+    String tenantOrgId = configProvider.getParameterByKey("BITMOVIN_TENANT_ORG_ID", null);
+    if (tenantOrgId != null && !tenantOrgId.trim().isEmpty()) {
+      builder.withTenantOrgId(tenantOrgId.trim());
+    }
+    return builder;
+  }
+
+  /**
+   * Returns a builder for an API client which acts in the organisation of the API key itself,
+   * ignoring BITMOVIN_TENANT_ORG_ID. Apart from that, it is configured like {@link
+   * #builder(ConfigProvider, Level)}.
+   *
+   * @param configProvider the config provider the settings are read from
+   * @param logLevel the level of detail the API calls are logged with
+   */
+  public static BitmovinApi.Builder accountBuilder(ConfigProvider configProvider, Level logLevel) {
+    BitmovinApi.Builder builder =
+        BitmovinApi.builder()
+            .withBaseUrl(configProvider.getBitmovinApiBaseUrl())
+            .withClient(createClient(configProvider));
+
+    if (ApiDebugLogger.isEnabled(configProvider)) {
+      // log full requests and responses with redacted secrets to the debug log file
+      return builder.withLogger(new ApiDebugLogger(configProvider), Level.FULL);
+    }
+    return builder.withLogger(new Slf4jLogger(), logLevel);
+  }
+
+  private static Client createClient(ConfigProvider configProvider) {
+    try {
+      return HttpClientSettings.createClient(configProvider);
+    } catch (IOException e) {
+      throw new UncheckedIOException("Could not read the TLS_CA_BUNDLE_PATH certificates", e);
+    } catch (GeneralSecurityException e) {
+      throw new IllegalStateException("Could not apply the TLS settings", e);
+    }
+  }
+}
@@ -0,0 +1,72 @@
+package common;
+
+import feign.Logger;
+import java.io.FileWriter;
+import java.io.IOException;
+import java.io.PrintWriter;
+import java.io.UncheckedIOException;
+import java.time.Instant;
+import java.util.regex.Pattern;
+
+/**
+ * This logger writes the full HTTP requests and responses of the API client to a file, which is
+ * useful when investigating problems or filing support tickets. API keys, tenant IDs and secrets of
+ * storages or DRM systems are redacted before they are written, so the file can be shared safely.
+ *
+ * <p>The following configuration parameters are supported:
+ *
+ * <ul>
+ *   <li>BITMOVIN_DEBUG - (optional) Set to true to log all requests and responses. Default: false
+ *   <li>BITMOVIN_DEBUG_LOG_FILE - (optional) The file the requests and responses are appended to.
+ *       Default: bitmovin-api-debug.log
+ * </ul>
+ *
+ * <p>The logger is passed to the API clients of all examples by {@link ApiClientFactory} if
+ * BITMOVIN_DEBUG is enabled.
+ */
+public class ApiDebugLogger extends Logger {
+  private static final String REDACTED = "<redacted>";
+
+  private static final Pattern SECRET_HEADER =
+      Pattern.compile("(?i)^(x-api-key|x-tenant-org-id|authorization): .*$");
+
+  private static final Pattern SECRET_PROPERTY =
+      Pattern.compile(
+          "\"(apiKey|accessKey|secretKey|password|privateKey|passphrase|key|token|sasToken|"
+              + "credentials|serviceAccountCredentials)\"\\s*:\\s*\"[^\"]*\"");
+
+  private final PrintWriter writer;
+
+  /** @param configProvider the config provider the log file path is read from */
+  public ApiDebugLogger(ConfigProvider configProvider) {
+    String logFile =
+        configProvider.getParameterByKey("BITMOVIN_DEBUG_LOG_FILE", "bitmovin-api-debug.log");
+    try {
+      writer = new PrintWriter(new FileWriter(logFile, true), true);
+    } catch (IOException e) {
+      throw new UncheckedIOException("Could not open the debug log file " + logFile, e);
+    }
+  }
+
+  /**
+   * Returns whether debug logging has been enabled in the configuration
+   *
+   * @param configProvider the config provider the setting is read from
+   */
+  public static boolean isEnabled(ConfigProvider configProvider) {
+    return Boolean.parseBoolean(configProvider.getParameterByKey("BITMOVIN_DEBUG", "false"));
+  }
+
+  @Override
+  protected void log(String configKey, String format, Object... args) {
+    String message = redact(String.format(format, args));
+    synchronized (writer) {
+      writer.printf("%s %s %s%n", Instant.now(), configKey, message);
+    }
+  }
+
+  private static String redact(String message) {
+    String redacted = SECRET_HEADER.matcher(message).replaceAll("$1: " + REDACTED);
+    return SECRET_PROPERTY.matcher(redacted).replaceAll("\"$1\":\"" + REDACTED + "\"");
+  }
+}
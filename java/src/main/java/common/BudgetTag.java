@@ -0,0 +1,73 @@
+package common;
+
+import com.bitmovin.api.sdk.BitmovinApi;
+import com.bitmovin.api.sdk.common.BitmovinException;
+import com.bitmovin.api.sdk.model.CustomData;
+import com.bitmovin.api.sdk.model.Encoding;
+import java.util.HashMap;
+import java.util.Map;
+
+/**
+ * This class tags encodings with a budget tag, e.g. the name of the team or cost center the
+ * encoding is charged to. The tag is read from the BUDGET_TAG configuration parameter and stored in
+ * the custom data of the encoding, so the encoded minutes can later be aggregated per tag, as shown
+ * in the BudgetReport example.
+ *
+ * <p>All examples apply the tag to the encodings they create, so BUDGET_TAG can be passed to any of
+ * them. If no tag is configured, encodings are left untouched.
+ */
+public class BudgetTag {
+  public static final String CUSTOM_DATA_KEY = "budgetTag";
+  public static final String UNTAGGED = "untagged";
+
+  private final String tag;
+
+  /** @param configProvider the config provider the budget tag is read from */
+  public BudgetTag(ConfigProvider configProvider) {
+    this.tag = configProvider.getParameterByKey("BUDGET_TAG", null);
+  }
+
+  public String getTag() {
+    return tag;
+  }
+
+  /**
+   * Adds the budget tag to the custom data of the given encoding. This has to be done before the
+   * encoding is created. Existing custom data is preserved.
+   *
+   * @param encoding the encoding to be tagged
+   */
+  public void applyTo(Encoding encoding) {
+    if (tag == null) {
+      return;
+    }
+
+    Map<String, Object> customData = new HashMap<>();
+    if (encoding.getCustomData() != null) {
+      customData.putAll(encoding.getCustomData());
+    }
+    customData.put(CUSTOM_DATA_KEY, tag);
+    encoding.setCustomData(customData);
+  }
+
+  /**
+   * Reads the budget tag of an existing encoding from its custom data.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/GetEncodingEncodingsCustomdataByEncodingId
+   *
+   * @param bitmovinApi the API client used to retrieve the custom data
+   * @param encodingId the ID of the encoding
+   * @return the budget tag, or {@link #UNTAGGED} if the encoding has not been tagged
+   */
+  public static String readFrom(BitmovinApi bitmovinApi, String encodingId)
+      throws BitmovinException {
+    CustomData customData = bitmovinApi.encoding.encodings.customdata.get(encodingId);
+
+    if (customData.getCustomData() == null
+        || customData.getCustomData().get(CUSTOM_DATA_KEY) == null) {
+      return UNTAGGED;
+    }
+    return customData.getCustomData().get(CUSTOM_DATA_KEY).toString();
+  }
+}
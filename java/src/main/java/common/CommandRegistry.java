@@ -0,0 +1,168 @@
+package common;
+
+import java.io.IOException;
+import java.io.InputStream;
+import java.io.InputStreamReader;
+import java.io.Reader;
+import java.nio.charset.StandardCharsets;
+import java.util.ArrayList;
+import java.util.Arrays;
+import java.util.Collection;
+import java.util.List;
+import java.util.Map;
+import java.util.Properties;
+import java.util.TreeMap;
+import java.util.stream.Collectors;
+
+/**
+ * This class provides the commands of the {@link ExamplesCli}, i.e. the examples and tools grouped
+ * by their purpose, together with the configuration parameters each of them reads. The registry is
+ * loaded from the resource commands.properties, which documents its format.
+ */
+public class CommandRegistry {
+  private static final String RESOURCE = "/commands.properties";
+  private static final String OPTIONAL_MARKER = "?";
+
+  /** The groups of commands, in the order they are listed */
+  public static final List<String> GROUPS = Arrays.asList("encode", "manage", "report");
+
+  private final Properties properties;
+  private final Map<String, Command> commands = new TreeMap<>(String.CASE_INSENSITIVE_ORDER);
+
+  private CommandRegistry(Properties properties) {
+    this.properties = properties;
+    for (String key : properties.stringPropertyNames()) {
+      if (key.endsWith(".group") && !key.startsWith("group.")) {
+        String name = key.substring(0, key.length() - ".group".length());
+        commands.put(name, buildCommand(name));
+      }
+    }
+  }
+
+  /** Loads the registry from the resource commands.properties */
+  public static CommandRegistry load() {
+    try (InputStream inputStream = CommandRegistry.class.getResourceAsStream(RESOURCE)) {
+      if (inputStream == null) {
+        throw new IllegalStateException("Resource " + RESOURCE + " not found");
+      }
+      try (Reader reader = new InputStreamReader(inputStream, StandardCharsets.UTF_8)) {
+        Properties properties = new Properties();
+        properties.load(reader);
+        return new CommandRegistry(properties);
+      }
+    } catch (IOException e) {
+      throw new RuntimeException("Error reading resource " + RESOURCE, e);
+    }
+  }
+
+  /** @param group the name of the group, e.g. encode */
+  public String getGroupSummary(String group) {
+    return properties.getProperty("group." + group);
+  }
+
+  /** Returns all commands, sorted by name */
+  public Collection<Command> getCommands() {
+    return commands.values();
+  }
+
+  /**
+   * Returns the commands of a group, sorted by name
+   *
+   * @param group the name of the group, e.g. encode
+   */
+  public List<Command> getCommands(String group) {
+    return commands.values().stream()
+        .filter(command -> command.group.equals(group))
+        .collect(Collectors.toList());
+  }
+
+  /**
+   * Returns the command with the given name, or null if there is none. Names are matched case
+   * insensitively, so "healthcheck" finds HealthCheck.
+   *
+   * @param name the name of the command, which is the class name of the example or tool
+   */
+  public Command getCommand(String name) {
+    return commands.get(name);
+  }
+
+  private Command buildCommand(String name) {
+    List<Parameter> parameters = new ArrayList<>();
+    String parameterList = properties.getProperty(name + ".parameters", "");
+    for (String entry : parameterList.split(",")) {
+      if (entry.trim().isEmpty()) {
+        continue;
+      }
+      boolean optional = entry.trim().endsWith(OPTIONAL_MARKER);
+      String key = optional ? entry.trim().substring(0, entry.trim().length() - 1) : entry.trim();
+      String description =
+          properties.getProperty(
+              name + ".parameter." + key, properties.getProperty("parameter." + key, ""));
+      parameters.add(new Parameter(key, description, optional));
+    }
+
+    return new Command(
+        name,
+        properties.getProperty(name + ".group"),
+        properties.getProperty(name + ".summary", ""),
+        parameters);
+  }
+
+  /** An example or tool that can be run by the {@link ExamplesCli} */
+  public static class Command {
+    private final String name;
+    private final String group;
+    private final String summary;
+    private final List<Parameter> parameters;
+
+    private Command(String name, String group, String summary, List<Parameter> parameters) {
+      this.name = name;
+      this.group = group;
+      this.summary = summary;
+      this.parameters = parameters;
+    }
+
+    /** The class name of the example or tool, e.g. FixedBitrateLadder */
+    public String getName() {
+      return name;
+    }
+
+    public String getGroup() {
+      return group;
+    }
+
+    public String getSummary() {
+      return summary;
+    }
+
+    /** The configuration parameters read by the command, in the order of its documentation */
+    public List<Parameter> getParameters() {
+      return parameters;
+    }
+  }
+
+  /** A configuration parameter read by a command */
+  public static class Parameter {
+    private final String key;
+    private final String description;
+    private final boolean optional;
+
+    Parameter(String key, String description, boolean optional) {
+      this.key = key;
+      this.description = description;
+      this.optional = optional;
+    }
+
+    public String getKey() {
+      return key;
+    }
+
+    public String getDescription() {
+      return description;
+    }
+
+    public boolean isOptional() {
+      return optional;
+    }
+  }
+}
@@ -29,6 +29,12 @@ import org.slf4j.LoggerFactory;
 public class ConfigProvider {
   private static final Logger logger = LoggerFactory.getLogger(ConfigProvider.class);
 
+  /** The base URL of the Bitmovin API, which is used unless BITMOVIN_API_BASE_URL is configured */
+  public static final String DEFAULT_API_BASE_URL = "https://api.bitmovin.com/v1";
+
+  /** The name of the config source holding the command line arguments */
+  public static final String COMMAND_LINE_ARGUMENTS = "Command line arguments";
+
   private final Map<String, Map<String, String>> configuration = new LinkedHashMap<>();
 
   /**
@@ -37,7 +43,7 @@ public class ConfigProvider {
    */
   public ConfigProvider(String[] args) {
     // parse command line arguments
-    configuration.put("Command line arguments", parseCliArguments(args));
+    configuration.put(COMMAND_LINE_ARGUMENTS, parseCliArguments(args));
 
     // parse properties from ./examples.properties
     configuration.put("Local properties file", parsePropertiesFile("."));
@@ -61,6 +67,15 @@ public class ConfigProvider {
         "The ID of the Organisation in which you want to perform the encoding.");
   }
 
+  /**
+   * Returns the base URL the API client sends its requests to. It only has to be configured to
+   * target a mock server in tests, or to route the requests through an API gateway.
+   */
+  public String getBitmovinApiBaseUrl() {
+    return StringUtils.removeEnd(
+        getParameterByKey("BITMOVIN_API_BASE_URL", DEFAULT_API_BASE_URL), "/");
+  }
+
   public String getHttpInputHost() {
     return getOrThrowException(
         "HTTP_INPUT_HOST",
@@ -73,6 +88,12 @@ public class ConfigProvider {
         "The path to your Http input file. Example: videos/1080p_Sintel.mp4");
   }
 
+  public String getHttpInputSrtFilePath() {
+    return getOrThrowException(
+        "HTTP_INPUT_SRT_FILE_PATH",
+        "The path to your SRT subtitle file on the HTTP server. Example: subtitles/sintel_en.srt");
+  }
+
   public String getS3InputBucketName() {
     return getOrThrowException(
         "S3_INPUT_BUCKET_NAME", "The name of your S3 input bucket. Example: my-bucket-name");
@@ -83,6 +104,14 @@ public class ConfigProvider {
         "S3_INPUT_FILE_PATH", "The path to your S3 input file. Example: videos/1080p_Sintel.mp4");
   }
 
+  public String getS3InputAccessKey() {
+    return getOrThrowException("S3_INPUT_ACCESS_KEY", "The access key of your S3 input bucket.");
+  }
+
+  public String getS3InputSecretKey() {
+    return getOrThrowException("S3_INPUT_SECRET_KEY", "The secret key of your S3 input bucket.");
+  }
+
   public String getS3InputArnRole() {
     return getOrThrowException(
         "S3_INPUT_ARN_ROLE", "The ARN role of your S3 role based input bucket.");
@@ -116,6 +145,33 @@ public class ConfigProvider {
         "S3_OUTPUT_EXT_ID", "The external ID of your S3 role based output bucket.");
   }
 
+  public String getS3RoleBasedOutputRoleArn() {
+    return getOrThrowException(
+        "S3_ROLE_BASED_OUTPUT_ROLE_ARN",
+        "The ARN of the IAM role granting Bitmovin access to your S3 output bucket.");
+  }
+
+  public String getS3RoleBasedOutputExternalId() {
+    return getOrThrowException(
+        "S3_ROLE_BASED_OUTPUT_EXTERNAL_ID",
+        "The external ID required by the trust policy of your IAM role.");
+  }
+
+  public String getAzureOutputAccountName() {
+    return getOrThrowException(
+        "AZURE_OUTPUT_ACCOUNT_NAME", "The name of your Azure storage account.");
+  }
+
+  public String getAzureOutputAccountKey() {
+    return getOrThrowException(
+        "AZURE_OUTPUT_ACCOUNT_KEY", "The access key of your Azure storage account.");
+  }
+
+  public String getAzureOutputContainerName() {
+    return getOrThrowException(
+        "AZURE_OUTPUT_CONTAINER_NAME", "The name of your Azure storage container.");
+  }
+
   public String getS3OutputBasePath() {
     return StringUtils.appendIfMissing(
         StringUtils.removeStart(
@@ -169,7 +225,41 @@ public class ConfigProvider {
     return getOrThrowException(keyName, String.format("Configuration Parameter '%s'", keyName));
   }
 
+  /* Same as getParameterByKey, but falls back to a default value for optional config settings */
+  public String getParameterByKey(String keyName, String defaultValue) {
+    String value = getOrNull(keyName);
+    return value != null ? value : defaultValue;
+  }
+
+  /**
+   * Returns the values of a config setting in all config sources it is set in, in the order of
+   * their precedence. The first entry is the value returned by getParameterByKey, the others are
+   * overridden by it.
+   *
+   * @param keyName the name of the config setting
+   * @return the values by the name of the config source, e.g. "Environment variables"
+   */
+  public Map<String, String> getParameterSources(String keyName) {
+    Map<String, String> sources = new LinkedHashMap<>();
+    for (Map.Entry<String, Map<String, String>> entry : configuration.entrySet()) {
+      if (entry.getValue().containsKey(keyName)) {
+        sources.put(entry.getKey(), entry.getValue().get(keyName));
+      }
+    }
+
+    return sources;
+  }
+
   private String getOrThrowException(String key, String description) {
+    String value = getOrNull(key);
+    if (value == null) {
+      throw new MissingArgumentException(key, description);
+    }
+
+    return value;
+  }
+
+  private String getOrNull(String key) {
     for (String configurationName : configuration.keySet()) {
       Map<String, String> subConfiguration = this.configuration.get(configurationName);
       if (subConfiguration.containsKey(key)) {
@@ -179,7 +269,7 @@ public class ConfigProvider {
       }
     }
 
-    throw new MissingArgumentException(key, description);
+    return null;
   }
 
   private Map<String, String> parsePropertiesFile(String propertiesFileDirectory) {
@@ -212,8 +302,13 @@ public class ConfigProvider {
     return System.getenv();
   }
 
-  private static class MissingArgumentException extends RuntimeException {
-    MissingArgumentException(String argument, String description) {
+  /** Thrown if a required configuration parameter is not set in any of the config sources */
+  public static class MissingArgumentException extends RuntimeException {
+    /**
+     * @param argument the name of the missing configuration parameter
+     * @param description the description of the parameter, telling the user how to set it
+     */
+    public MissingArgumentException(String argument, String description) {
       super(argument + " - " + description);
     }
   }
@@ -0,0 +1,231 @@
+package common;
+
+import java.io.ByteArrayOutputStream;
+import java.nio.charset.StandardCharsets;
+import java.util.ArrayList;
+import java.util.Base64;
+import java.util.List;
+
+/**
+ * This class validates the DRM configuration parameters used by the examples, and derives the
+ * Widevine PSSH payload from the key ID, so correct test values can be generated instead of copied
+ * from elsewhere. It can be run like an example, and prints the validated values in the format of
+ * the properties file:
+ *
+ * <pre>
+ * run-example.sh common.DrmKeyMaterial DRM_WIDEVINE_KID=08eecef4b026deec395234d94218273d
+ * </pre>
+ *
+ * <p>The following configuration parameters are evaluated:
+ *
+ * <ul>
+ *   <li>DRM_WIDEVINE_KID - 16 byte encryption key id, represented as 32 hexadecimal characters
+ *   <li>DRM_WIDEVINE_CONTENT_ID - (optional) The content ID to include in the Widevine PSSH payload
+ *   <li>DRM_WIDEVINE_PSSH - (optional) An existing Widevine PSSH payload to validate instead of
+ *       deriving a new one
+ *   <li>DRM_KEY - (optional) 16 byte encryption key, represented as 32 hexadecimal characters
+ *   <li>DRM_FAIRPLAY_IV - (optional) 16 byte initialization vector, represented as 32 hexadecimal
+ *       characters
+ *   <li>DRM_FAIRPLAY_URI - (optional) URI of the FairPlay licensing server, starting with skd://
+ * </ul>
+ */
+public class DrmKeyMaterial {
+
+  private static final int KEY_LENGTH = 16;
+  private static final String FAIRPLAY_URI_SCHEME = "skd://";
+
+  /** The field numbers of key_id and content_id in the WidevinePsshData protobuf message */
+  private static final int PSSH_DATA_KEY_ID_FIELD = 2;
+  private static final int PSSH_DATA_CONTENT_ID_FIELD = 4;
+
+  private static final int PROTOBUF_WIRE_TYPE_VARINT = 0;
+  private static final int PROTOBUF_WIRE_TYPE_LENGTH_DELIMITED = 2;
+
+  public static void main(String[] args) {
+    ConfigProvider configProvider = new ConfigProvider(args);
+
+    String kid = configProvider.getDrmWidevineKid();
+    parseHex("DRM_WIDEVINE_KID", kid);
+
+    String pssh = configProvider.getParameterByKey("DRM_WIDEVINE_PSSH", null);
+    if (pssh == null) {
+      String contentId = configProvider.getParameterByKey("DRM_WIDEVINE_CONTENT_ID", null);
+      pssh = buildWidevinePssh(kid, contentId);
+    } else {
+      validateWidevinePssh("DRM_WIDEVINE_PSSH", pssh, kid);
+    }
+    System.out.println("DRM_WIDEVINE_KID=" + kid);
+    System.out.println("DRM_WIDEVINE_PSSH=" + pssh);
+
+    String key = configProvider.getParameterByKey("DRM_KEY", null);
+    if (key != null) {
+      parseHex("DRM_KEY", key);
+      System.out.println("DRM_KEY=" + key);
+    }
+
+    String iv = configProvider.getParameterByKey("DRM_FAIRPLAY_IV", null);
+    if (iv != null) {
+      parseHex("DRM_FAIRPLAY_IV", iv);
+      System.out.println("DRM_FAIRPLAY_IV=" + iv);
+    }
+
+    String uri = configProvider.getParameterByKey("DRM_FAIRPLAY_URI", null);
+    if (uri != null) {
+      validateFairPlayUri("DRM_FAIRPLAY_URI", uri);
+      System.out.println("DRM_FAIRPLAY_URI=" + uri);
+    }
+  }
+
+  /**
+   * Builds a Widevine PSSH payload, i.e. a WidevinePsshData protobuf message, referencing the given
+   * key ID and optionally a content ID
+   *
+   * @param kid the key ID, represented as 32 hexadecimal characters
+   * @param contentId the content ID, or null to omit it
+   * @return the Base64 encoded PSSH payload, as expected by DRM_WIDEVINE_PSSH
+   */
+  public static String buildWidevinePssh(String kid, String contentId) {
+    ByteArrayOutputStream psshData = new ByteArrayOutputStream();
+    writeLengthDelimitedField(psshData, PSSH_DATA_KEY_ID_FIELD, parseHex("DRM_WIDEVINE_KID", kid));
+    if (contentId != null) {
+      writeLengthDelimitedField(
+          psshData, PSSH_DATA_CONTENT_ID_FIELD, contentId.getBytes(StandardCharsets.UTF_8));
+    }
+
+    return Base64.getEncoder().encodeToString(psshData.toByteArray());
+  }
+
+  /**
+   * Checks that a Widevine PSSH payload is valid Base64, can be parsed as a WidevinePsshData
+   * protobuf message, and references the given key ID if it contains key IDs at all
+   *
+   * @param name the name or source of the value, e.g. the configuration parameter, used in the
+   *     error messages
+   * @param pssh the Base64 encoded PSSH payload
+   * @param kid the key ID, represented as 32 hexadecimal characters
+   * @throws IllegalArgumentException if the payload is invalid
+   */
+  public static void validateWidevinePssh(String name, String pssh, String kid) {
+    byte[] psshData;
+    try {
+      psshData = Base64.getDecoder().decode(pssh.trim());
+    } catch (IllegalArgumentException e) {
+      throw new IllegalArgumentException(name + " is not valid Base64", e);
+    }
+
+    List<String> keyIds = new ArrayList<>();
+    // the position is advanced by readVarint, as varints may be padded with continuation bytes
+    int[] position = {0};
+    while (position[0] < psshData.length) {
+      long tag = readVarint(name, psshData, position);
+      int fieldNumber = (int) (tag >>> 3);
+      int wireType = (int) (tag & 0x7);
+
+      if (wireType == PROTOBUF_WIRE_TYPE_VARINT) {
+        readVarint(name, psshData, position);
+      } else if (wireType == PROTOBUF_WIRE_TYPE_LENGTH_DELIMITED) {
+        long length = readVarint(name, psshData, position);
+        if (length < 0 || position[0] + length > psshData.length) {
+          throw new IllegalArgumentException(name + " is truncated");
+        }
+        if (fieldNumber == PSSH_DATA_KEY_ID_FIELD) {
+          keyIds.add(toHex(psshData, position[0], (int) length));
+        }
+        position[0] += (int) length;
+      } else {
+        throw new IllegalArgumentException(
+            name
+                + " is not a Widevine PSSH payload. Note that only the payload is expected, not a "
+                + "complete PSSH box");
+      }
+    }
+
+    if (!keyIds.isEmpty() && !keyIds.contains(kid.toLowerCase())) {
+      throw new IllegalArgumentException(
+          String.format("%s references the key IDs %s, but not the key ID %s", name, keyIds, kid));
+    }
+  }
+
+  /**
+   * Checks that a FairPlay licensing server URI uses the skd:// scheme expected by FairPlay
+   *
+   * @param name the name or source of the value, e.g. the configuration parameter, used in the
+   *     error message
+   * @param uri the URI of the licensing server
+   * @throws IllegalArgumentException if the URI is invalid
+   */
+  public static void validateFairPlayUri(String name, String uri) {
+    if (!uri.startsWith(FAIRPLAY_URI_SCHEME) || uri.length() == FAIRPLAY_URI_SCHEME.length()) {
+      throw new IllegalArgumentException(
+          name + " has to start with " + FAIRPLAY_URI_SCHEME + ", but is " + uri);
+    }
+  }
+
+  /**
+   * Parses a 16 byte value represented as 32 hexadecimal characters, as used for keys, key IDs and
+   * initialization vectors
+   *
+   * @param name the name or source of the value, e.g. the configuration parameter, used in the
+   *     error message
+   * @param hex the hexadecimal representation
+   * @throws IllegalArgumentException if the value is not 32 hexadecimal characters
+   */
+  public static byte[] parseHex(String name, String hex) {
+    if (hex.length() != KEY_LENGTH * 2 || !hex.matches("[0-9a-fA-F]+")) {
+      throw new IllegalArgumentException(
+          String.format(
+              "%s has to be %d hexadecimal characters, but is %s", name, KEY_LENGTH * 2, hex));
+    }
+
+    byte[] bytes = new byte[KEY_LENGTH];
+    for (int i = 0; i < KEY_LENGTH; i++) {
+      bytes[i] = (byte) Integer.parseInt(hex.substring(i * 2, i * 2 + 2), 16);
+    }
+    return bytes;
+  }
+
+  private static String toHex(byte[] bytes, int offset, int length) {
+    StringBuilder hex = new StringBuilder();
+    for (int i = offset; i < offset + length; i++) {
+      hex.append(String.format("%02x", bytes[i]));
+    }
+    return hex.toString();
+  }
+
+  private static void writeLengthDelimitedField(
+      ByteArrayOutputStream stream, int fieldNumber, byte[] value) {
+    writeVarint(stream, (fieldNumber << 3) | PROTOBUF_WIRE_TYPE_LENGTH_DELIMITED);
+    writeVarint(stream, value.length);
+    stream.write(value, 0, value.length);
+  }
+
+  private static void writeVarint(ByteArrayOutputStream stream, long value) {
+    while (value >= 0x80) {
+      stream.write((int) (value & 0x7f) | 0x80);
+      value >>>= 7;
+    }
+    stream.write((int) value);
+  }
+
+  /**
+   * Reads a varint and advances the position by the number of bytes it has been encoded with
+   *
+   * @param name the name or source of the value, used in the error messages
+   * @param bytes the protobuf message
+   * @param position the position of the varint, which is advanced past it
+   */
+  private static long readVarint(String name, byte[] bytes, int[] position) {
+    long value = 0;
+    for (int shift = 0; shift < 64; shift += 7) {
+      if (position[0] >= bytes.length) {
+        throw new IllegalArgumentException(name + " is truncated");
+      }
+      byte b = bytes[position[0]++];
+      value |= (long) (b & 0x7f) << shift;
+      if ((b & 0x80) == 0) {
+        return value;
+      }
+    }
+    throw new IllegalArgumentException(name + " contains an invalid varint");
+  }
+}
@@ -0,0 +1,22 @@
+package common;
+
+import com.bitmovin.api.sdk.model.Status;
+
+/**
+ * This exception is thrown by the examples if an encoding did not finish successfully, i.e. ended
+ * in the status ERROR or CANCELED. The status is used by {@link ExampleLauncher} to decide on the
+ * exit code.
+ */
+public class EncodingFailedException extends RuntimeException {
+  private final Status status;
+
+  /** @param status the final status of the encoding */
+  public EncodingFailedException(Status status) {
+    super(status == Status.CANCELED ? "Encoding canceled" : "Encoding failed");
+    this.status = status;
+  }
+
+  public Status getStatus() {
+    return status;
+  }
+}
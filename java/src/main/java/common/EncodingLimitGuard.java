@@ -0,0 +1,138 @@
+package common;
+
+import com.bitmovin.api.sdk.BitmovinApi;
+import com.bitmovin.api.sdk.common.BitmovinException;
+import com.bitmovin.api.sdk.encoding.encodings.EncodingListQueryParams;
+import com.bitmovin.api.sdk.encoding.statistics.daily.DailyStatisticsListByDateRangeQueryParams;
+import com.bitmovin.api.sdk.model.DailyStatistics;
+import com.bitmovin.api.sdk.model.Status;
+import java.time.Instant;
+import java.time.LocalDate;
+import java.time.ZoneOffset;
+import java.util.ArrayList;
+import java.util.Date;
+import java.util.List;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+
+/**
+ * This class checks the usage of the account against configurable limits before encodings are
+ * started, to prevent surprise overages caused by batch tools. The following limits can be set:
+ *
+ * <ul>
+ *   <li>LIMIT_GUARD_MAX_ACTIVE_ENCODINGS - the maximum number of encodings that may be queued or
+ *       running at the same time, including the ones about to be started
+ *   <li>LIMIT_GUARD_MAX_MONTHLY_MINUTES - the maximum number of minutes that may be encoded in the
+ *       current calendar month (UTC), e.g. the minutes included in your contract
+ * </ul>
+ *
+ * <p>Limits that are not configured are not checked. If a limit is exceeded, a warning is logged.
+ * If LIMIT_GUARD_MODE is set to REFUSE, the start of new encodings is refused as well.
+ */
+public class EncodingLimitGuard {
+  private static final Logger logger = LoggerFactory.getLogger(EncodingLimitGuard.class);
+
+  private static final int PAGE_SIZE = 100;
+
+  private final BitmovinApi bitmovinApi;
+  private final Long maxActiveEncodings;
+  private final Long maxMonthlyMinutes;
+  private final boolean refuseWhenExceeded;
+
+  /**
+   * @param bitmovinApi the API client used to query the usage of the account
+   * @param configProvider the config provider the limits are read from
+   */
+  public EncodingLimitGuard(BitmovinApi bitmovinApi, ConfigProvider configProvider) {
+    this.bitmovinApi = bitmovinApi;
+    this.maxActiveEncodings =
+        parseLimit(configProvider.getParameterByKey("LIMIT_GUARD_MAX_ACTIVE_ENCODINGS", null));
+    this.maxMonthlyMinutes =
+        parseLimit(configProvider.getParameterByKey("LIMIT_GUARD_MAX_MONTHLY_MINUTES", null));
+    this.refuseWhenExceeded =
+        "REFUSE".equalsIgnoreCase(configProvider.getParameterByKey("LIMIT_GUARD_MODE", "WARN"));
+  }
+
+  /**
+   * Checks whether the given number of encodings may be started without exceeding the configured
+   * limits.
+   *
+   * @param encodingsToStart the number of encodings that are about to be started
+   * @return false if a limit is exceeded and the guard is configured to refuse the start
+   */
+  public boolean allowsStart(int encodingsToStart) throws BitmovinException {
+    boolean limitExceeded = false;
+
+    if (maxActiveEncodings != null) {
+      long activeEncodings = countEncodings(Status.QUEUED) + countEncodings(Status.RUNNING);
+      logger.info(
+          "{} encodings are currently active, {} more are about to be started (limit: {})",
+          activeEncodings,
+          encodingsToStart,
+          maxActiveEncodings);
+
+      if (activeEncodings + encodingsToStart > maxActiveEncodings) {
+        logger.warn("The maximum number of active encodings ({}) is exceeded", maxActiveEncodings);
+        limitExceeded = true;
+      }
+    }
+
+    if (maxMonthlyMinutes != null) {
+      long encodedMinutes = getEncodedMinutesOfCurrentMonth();
+      logger.info(
+          "{} minutes have been encoded this month, {} minutes remaining",
+          encodedMinutes,
+          Math.max(0, maxMonthlyMinutes - encodedMinutes));
+
+      if (encodedMinutes >= maxMonthlyMinutes) {
+        logger.warn("The monthly limit of {} encoded minutes is exceeded", maxMonthlyMinutes);
+        limitExceeded = true;
+      }
+    }
+
+    if (limitExceeded && refuseWhenExceeded) {
+      logger.error("Refusing to start encodings, as configured limits are exceeded");
+      return false;
+    }
+
+    return true;
+  }
+
+  private long countEncodings(Status status) throws BitmovinException {
+    EncodingListQueryParams queryParams = new EncodingListQueryParams();
+    queryParams.setStatus(status.toString());
+
+    return bitmovinApi.encoding.encodings.list(queryParams).getTotalCount();
+  }
+
+  /** Returns the minutes encoded since the first day of the current month, including today */
+  private long getEncodedMinutesOfCurrentMonth() throws BitmovinException {
+    LocalDate today = LocalDate.now(ZoneOffset.UTC);
+    Date from = Date.from(today.withDayOfMonth(1).atStartOfDay(ZoneOffset.UTC).toInstant());
+    Date to = Date.from(Instant.now());
+
+    DailyStatisticsListByDateRangeQueryParams queryParams =
+        new DailyStatisticsListByDateRangeQueryParams();
+    queryParams.setLimit(PAGE_SIZE);
+
+    List<DailyStatistics> dailyStatistics = new ArrayList<>();
+    List<DailyStatistics> page;
+    do {
+      queryParams.setOffset(dailyStatistics.size());
+      page =
+          bitmovinApi.encoding.statistics.daily.listByDateRange(from, to, queryParams).getItems();
+      dailyStatistics.addAll(page);
+    } while (page.size() == PAGE_SIZE);
+
+    long encodedSeconds =
+        dailyStatistics.stream()
+            .filter(statistics -> statistics.getTimeEncoded() != null)
+            .mapToLong(DailyStatistics::getTimeEncoded)
+            .sum();
+    return encodedSeconds / 60;
+  }
+
+  private static Long parseLimit(String value) {
+    return value != null ? Long.valueOf(value) : null;
+  }
+}
@@ -0,0 +1,193 @@
+package common;
+
+import ch.qos.logback.classic.Level;
+import ch.qos.logback.classic.spi.ILoggingEvent;
+import ch.qos.logback.core.AppenderBase;
+import com.bitmovin.api.sdk.common.BitmovinException;
+import com.bitmovin.api.sdk.model.Status;
+import feign.RetryableException;
+import java.io.InterruptedIOException;
+import java.io.PrintStream;
+import java.lang.reflect.InvocationTargetException;
+import java.lang.reflect.Method;
+import java.time.Duration;
+import java.time.Instant;
+import java.util.ArrayList;
+import java.util.Arrays;
+import java.util.List;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+
+/**
+ * This class runs an example and terminates the process with an exit code describing the outcome,
+ * so shell based pipelines can branch on it reliably. It is used by run-example.sh and
+ * run-example.bat, and takes the name of the example as first argument, followed by its
+ * configuration parameters:
+ *
+ * <pre>
+ * java -cp bitmovin-api-sdk-example-jar-with-dependencies.jar common.ExampleLauncher \
+ *     FixedBitrateLadder --quiet BITMOVIN_API_KEY=xyz
+ * </pre>
+ *
+ * <p>The following exit codes are used:
+ *
+ * <ul>
+ *   <li>0 - The example finished successfully
+ *   <li>1 - The example failed with an unexpected error
+ *   <li>2 - A required configuration parameter is missing, or the example does not exist
+ *   <li>3 - A call to the Bitmovin API failed or timed out
+ *   <li>4 - An encoding ended in the status ERROR
+ *   <li>5 - An encoding has been canceled
+ * </ul>
+ *
+ * <p>If the argument --quiet is passed, all log output is suppressed and a single line with the
+ * outcome (e.g. "SUCCESS" or "CONFIG_ERROR BITMOVIN_API_KEY - Your API key for the Bitmovin API.")
+ * is written to stdout instead. Anything else the example prints, e.g. the results of HealthCheck,
+ * is written to stderr.
+ *
+ * <p>If EXAMPLES_TELEMETRY_ENDPOINT is configured, anonymous usage statistics of the run are
+ * reported to it, see {@link UsageTelemetry}. If an event sink is configured, the lifecycle of the
+ * run is emitted as CloudEvents, see {@link WorkflowEvents}.
+ */
+public class ExampleLauncher {
+  private static final Logger logger = LoggerFactory.getLogger(ExampleLauncher.class);
+
+  private static final String QUIET_ARGUMENT = "--quiet";
+
+  /** The possible outcomes of an example and their exit codes */
+  public enum Outcome {
+    SUCCESS(0),
+    UNEXPECTED_ERROR(1),
+    CONFIG_ERROR(2),
+    API_ERROR(3),
+    ENCODING_ERROR(4),
+    ENCODING_CANCELED(5);
+
+    private final int exitCode;
+
+    Outcome(int exitCode) {
+      this.exitCode = exitCode;
+    }
+
+    public int getExitCode() {
+      return exitCode;
+    }
+  }
+
+  public static void main(String[] args) {
+    List<String> exampleArgs = new ArrayList<>(Arrays.asList(args));
+    boolean quiet = exampleArgs.remove(QUIET_ARGUMENT);
+    PrintStream stdout = System.out;
+    ch.qos.logback.classic.Logger rootLogger =
+        (ch.qos.logback.classic.Logger) LoggerFactory.getLogger(Logger.ROOT_LOGGER_NAME);
+    if (quiet) {
+      // detach the console output instead of disabling logging, so progress events still work
+      rootLogger.detachAndStopAllAppenders();
+      rootLogger.setLevel(Level.INFO);
+      // keep stdout reserved for the outcome, as some examples print their results
+      System.setOut(System.err);
+    }
+
+    Outcome outcome;
+    String message = null;
+    Throwable failure = null;
+    Method exampleMain = null;
+    if (exampleArgs.isEmpty()) {
+      outcome = Outcome.CONFIG_ERROR;
+      message = "The name of the example has to be passed as first argument";
+    } else {
+      try {
+        exampleMain = Class.forName(exampleArgs.get(0)).getMethod("main", String[].class);
+        outcome = Outcome.SUCCESS;
+      } catch (ClassNotFoundException | NoSuchMethodException e) {
+        outcome = Outcome.CONFIG_ERROR;
+        message = "Unknown example " + exampleArgs.get(0);
+      }
+    }
+
+    if (exampleMain != null) {
+      List<String> runArgs = exampleArgs.subList(1, exampleArgs.size());
+      ConfigProvider configProvider = new ConfigProvider(runArgs.toArray(new String[0]));
+      Instant start = Instant.now();
+
+      try (WorkflowEvents events = new WorkflowEvents(configProvider, exampleArgs.get(0))) {
+        AppenderBase<ILoggingEvent> progressAppender = events.createProgressAppender();
+        if (events.isEnabled()) {
+          progressAppender.setContext(rootLogger.getLoggerContext());
+          progressAppender.start();
+          rootLogger.addAppender(progressAppender);
+        }
+        events.started();
+
+        try {
+          run(exampleMain, runArgs);
+        } catch (Throwable e) {
+          outcome = classify(e);
+          message = e.getMessage();
+          failure = e;
+        }
+
+        rootLogger.detachAppender(progressAppender);
+        Duration duration = Duration.between(start, Instant.now());
+        if (outcome == Outcome.SUCCESS) {
+          events.finished(duration);
+        } else {
+          events.failed(outcome, message, duration);
+        }
+        new UsageTelemetry(configProvider).report(exampleArgs.get(0), outcome, duration);
+      }
+    }
+
+    if (quiet) {
+      stdout.println(message != null ? outcome + " " + message : outcome.toString());
+    } else if (failure != null) {
+      logger.error("Example {} failed with outcome {}", exampleArgs.get(0), outcome, failure);
+    } else if (message != null) {
+      logger.error(message);
+    }
+
+    System.exit(outcome.getExitCode());
+  }
+
+  /**
+   * Runs the main method of the given example, and rethrows any exception it terminated with
+   *
+   * @param exampleMain the main method of the example
+   * @param args the arguments passed to the example
+   */
+  private static void run(Method exampleMain, List<String> args) throws Throwable {
+    try {
+      exampleMain.invoke(null, (Object) args.toArray(new String[0]));
+    } catch (InvocationTargetException e) {
+      throw e.getCause();
+    }
+  }
+
+  /**
+   * Returns the outcome matching the given exception or the first matching exception it has been
+   * caused by
+   *
+   * @param throwable the exception the example terminated with
+   */
+  public static Outcome classify(Throwable throwable) {
+    for (Throwable cause = throwable; cause != null; cause = cause.getCause()) {
+      if (cause instanceof ConfigProvider.MissingArgumentException) {
+        return Outcome.CONFIG_ERROR;
+      }
+      if (cause instanceof EncodingFailedException) {
+        return ((EncodingFailedException) cause).getStatus() == Status.CANCELED
+            ? Outcome.ENCODING_CANCELED
+            : Outcome.ENCODING_ERROR;
+      }
+      if (cause instanceof BitmovinException) {
+        return Outcome.API_ERROR;
+      }
+      if (cause instanceof RetryableException || cause instanceof InterruptedIOException) {
+        // the API could not be reached, or a call exceeded the timeouts of HttpClientSettings
+        return Outcome.API_ERROR;
+      }
+    }
+
+    return Outcome.UNEXPECTED_ERROR;
+  }
+}
@@ -0,0 +1,727 @@
+package common;
+
+import ch.qos.logback.classic.Level;
+import common.CommandRegistry.Command;
+import common.CommandRegistry.Parameter;
+import java.io.File;
+import java.io.FileReader;
+import java.io.IOException;
+import java.io.PrintStream;
+import java.io.Reader;
+import java.util.ArrayList;
+import java.util.Arrays;
+import java.util.HashMap;
+import java.util.LinkedHashMap;
+import java.util.List;
+import java.util.Map;
+import java.util.Properties;
+import java.util.Set;
+import java.util.TreeMap;
+import java.util.TreeSet;
+import java.util.stream.Collectors;
+import org.apache.commons.lang3.StringUtils;
+import org.slf4j.LoggerFactory;
+
+/**
+ * This class is the root command of the examples and tools, which are grouped into subcommands by
+ * their purpose. It is used by the bitmovin-examples script:
+ *
+ * <pre>
+ * bitmovin-examples [flags] encode|manage|report &lt;command&gt; [KEY=value ...]
+ * bitmovin-examples help [&lt;group&gt; [&lt;command&gt;]]
+ * bitmovin-examples diff-config &lt;file&gt; &lt;other file&gt;
+ * bitmovin-examples completion bash|zsh
+ * </pre>
+ *
+ * <p>The commands, their summaries and the configuration parameters shown in their help are read
+ * from the {@link CommandRegistry}. Running a command is delegated to {@link ExampleLauncher}, so
+ * it terminates with the same exit codes as run-example.sh.
+ *
+ * <p>The following flags are accepted by all commands, before or after the command name:
+ *
+ * <ul>
+ *   <li>--config &lt;file&gt; - Reads configuration parameters from the given properties file. They
+ *       take precedence over ./examples.properties, the environment variables and
+ *       ~/.bitmovin/examples.properties
+ *   <li>--tenant &lt;id&gt; - Acts on behalf of the given organisation, same as
+ *       BITMOVIN_TENANT_ORG_ID=&lt;id&gt;
+ *   <li>--dry-run - Prints the value each configuration parameter of the command resolves to,
+ *       instead of running it
+ *   <li>--print-effective-config - Prints the value each configuration parameter resolves to and
+ *       the config source it is taken from, together with the sources it overrides, instead of
+ *       running the command. Without a command, all parameters set in any source are printed
+ *   <li>--quiet - Only prints the outcome of the command, see {@link ExampleLauncher}
+ *   <li>--help - Prints the help of the group or command
+ * </ul>
+ *
+ * <p>Configuration parameters passed as KEY=value take precedence over the flags. Any other flag is
+ * passed on to the command, e.g. --resume of ResumableEncoding or --retry-failed of BatchEncoding.
+ *
+ * <p>diff-config compares two properties files, e.g. the ones of a staging and a production setup,
+ * and prints the parameters that are only set in one of them or have different values.
+ */
+public class ExamplesCli {
+  private static final String NAME = "bitmovin-examples";
+  private static final int WIDTH = 100;
+
+  private static final String CONFIG_FLAG = "--config";
+  private static final String TENANT_FLAG = "--tenant";
+  private static final String DRY_RUN_FLAG = "--dry-run";
+  private static final String PRINT_EFFECTIVE_CONFIG_FLAG = "--print-effective-config";
+  private static final String QUIET_FLAG = "--quiet";
+  private static final String HELP_FLAG = "--help";
+  private static final List<String> FLAGS =
+      Arrays.asList(
+          CONFIG_FLAG,
+          TENANT_FLAG,
+          DRY_RUN_FLAG,
+          PRINT_EFFECTIVE_CONFIG_FLAG,
+          QUIET_FLAG,
+          HELP_FLAG);
+
+  private static CommandRegistry registry;
+
+  public static void main(String[] args) throws Exception {
+    registry = CommandRegistry.load();
+
+    int exitCode;
+    try {
+      exitCode = execute(Options.parse(args));
+    } catch (IllegalArgumentException e) {
+      exitCode = fail(e.getMessage());
+    }
+    System.exit(exitCode);
+  }
+
+  /**
+   * Dispatches the parsed command line and returns the exit code. Only running a command does not
+   * return, as {@link ExampleLauncher} terminates the process itself.
+   *
+   * @param options the parsed command line
+   */
+  private static int execute(Options options) throws IOException {
+    List<String> positionals = options.positionals;
+    if (positionals.isEmpty() && options.printEffectiveConfig) {
+      return printEffectiveConfig(null, options);
+    }
+    if (positionals.isEmpty()) {
+      printUsage();
+      return ExampleLauncher.Outcome.SUCCESS.getExitCode();
+    }
+
+    String first = positionals.get(0);
+    if (first.equals("help")) {
+      return printHelp(positionals.subList(1, positionals.size()));
+    }
+    if (first.equals("completion")) {
+      return printCompletion(positionals.size() > 1 ? positionals.get(1) : null);
+    }
+    if (first.equals("diff-config")) {
+      if (positionals.size() != 3) {
+        return fail("Usage: " + NAME + " diff-config <file> <other file>");
+      }
+      return diffConfig(positionals.get(1), positionals.get(2));
+    }
+    if (!CommandRegistry.GROUPS.contains(first)) {
+      return fail(String.format("Unknown group '%s'", first));
+    }
+    if (positionals.size() == 1) {
+      printGroupHelp(first);
+      return ExampleLauncher.Outcome.SUCCESS.getExitCode();
+    }
+    if (positionals.size() > 2) {
+      return fail(
+          String.format(
+              "Unexpected argument '%s', parameters are passed as KEY=value", positionals.get(2)));
+    }
+
+    Command command = findCommand(first, positionals.get(1));
+    if (command == null) {
+      return fail(String.format("Unknown command '%s' in group %s", positionals.get(1), first));
+    }
+    if (options.help) {
+      printCommandHelp(command);
+      return ExampleLauncher.Outcome.SUCCESS.getExitCode();
+    }
+
+    if (options.printEffectiveConfig) {
+      return printEffectiveConfig(command, options);
+    }
+
+    List<String> runArgs = buildRunArgs(options);
+    if (options.dryRun) {
+      return dryRun(command, runArgs);
+    }
+
+    List<String> launcherArgs = new ArrayList<>();
+    launcherArgs.add(command.getName());
+    if (options.quiet) {
+      launcherArgs.add(QUIET_FLAG);
+    }
+    launcherArgs.addAll(runArgs);
+    launcherArgs.addAll(options.commandFlags);
+    ExampleLauncher.main(launcherArgs.toArray(new String[0]));
+    return ExampleLauncher.Outcome.SUCCESS.getExitCode();
+  }
+
+  /**
+   * Builds the configuration parameters passed to the command. Parameters passed as KEY=value
+   * override the flags, which override the values of the --config file.
+   *
+   * @param options the parsed command line
+   */
+  private static List<String> buildRunArgs(Options options) throws IOException {
+    Map<String, String> values = new LinkedHashMap<>();
+    if (options.configFile != null) {
+      values.putAll(readPropertiesFile(options.configFile));
+    }
+    if (options.tenant != null) {
+      values.put("BITMOVIN_TENANT_ORG_ID", options.tenant);
+    }
+    for (String parameter : options.parameters) {
+      String[] keyValue = parameter.split("=", 2);
+      values.put(keyValue[0], keyValue[1]);
+    }
+
+    return values.entrySet().stream()
+        .map(entry -> entry.getKey() + "=" + entry.getValue())
+        .collect(Collectors.toList());
+  }
+
+  /**
+   * Returns where each configuration parameter passed to the command comes from, which is hidden
+   * from the {@link ConfigProvider}, as it receives all of them as command line arguments
+   *
+   * @param options the parsed command line
+   */
+  private static Map<String, String> buildRunArgOrigins(Options options) throws IOException {
+    Map<String, String> origins = new HashMap<>();
+    if (options.configFile != null) {
+      for (String key : readPropertiesFile(options.configFile).keySet()) {
+        origins.put(key, CONFIG_FLAG + " " + options.configFile);
+      }
+    }
+    if (options.tenant != null) {
+      origins.put("BITMOVIN_TENANT_ORG_ID", TENANT_FLAG);
+    }
+    for (String parameter : options.parameters) {
+      origins.put(parameter.split("=", 2)[0], ConfigProvider.COMMAND_LINE_ARGUMENTS);
+    }
+    return origins;
+  }
+
+  /**
+   * Reads the non-empty values of a properties file, sorted by key
+   *
+   * @param path the path to the properties file
+   */
+  private static Map<String, String> readPropertiesFile(String path) throws IOException {
+    File file = new File(path);
+    if (!file.isFile()) {
+      throw new IllegalArgumentException("Config file not found: " + file.getAbsolutePath());
+    }
+
+    Map<String, String> values = new TreeMap<>();
+    try (Reader reader = new FileReader(file)) {
+      Properties properties = new Properties();
+      properties.load(reader);
+      for (String key : properties.stringPropertyNames()) {
+        if (StringUtils.isNotEmpty(properties.getProperty(key))) {
+          values.put(key, properties.getProperty(key));
+        }
+      }
+    }
+    return values;
+  }
+
+  /**
+   * Resolves the configuration parameters of a command from all config sources and prints them,
+   * without running the command. Secrets are not printed. Fails with the exit code of a
+   * configuration error if a required parameter is missing.
+   *
+   * @param command the command to resolve the parameters of
+   * @param runArgs the configuration parameters passed to the command
+   */
+  private static int dryRun(Command command, List<String> runArgs) {
+    ConfigProvider configProvider = createSilentConfigProvider(runArgs);
+
+    System.out.println(
+        String.format(
+            "Dry run of %s %s, nothing is executed", command.getGroup(), command.getName()));
+    System.out.println();
+
+    int keyWidth = maxKeyLength(command.getParameters());
+    int missing = 0;
+    for (Parameter parameter : command.getParameters()) {
+      String value = configProvider.getParameterByKey(parameter.getKey(), null);
+      String shown;
+      if (value == null) {
+        shown = parameter.isOptional() ? "(not set)" : "MISSING";
+        missing += parameter.isOptional() ? 0 : 1;
+      } else {
+        shown = isSecret(parameter.getKey()) ? "(set)" : value;
+      }
+      System.out.println(String.format("  %-" + keyWidth + "s  %s", parameter.getKey(), shown));
+    }
+
+    if (missing > 0) {
+      System.out.println();
+      System.out.println(String.format("%d required parameters are missing", missing));
+      return ExampleLauncher.Outcome.CONFIG_ERROR.getExitCode();
+    }
+    return ExampleLauncher.Outcome.SUCCESS.getExitCode();
+  }
+
+  /**
+   * Prints the value each configuration parameter resolves to and the config source it is taken
+   * from, followed by the sources it overrides. Secrets are not printed. Fails with the exit code
+   * of a configuration error if a required parameter of the command is missing.
+   *
+   * @param command the command to print the parameters of, or null to print all parameters known
+   *     to the registry or passed on the command line which are set in any config source
+   * @param options the parsed command line
+   */
+  private static int printEffectiveConfig(Command command, Options options) throws IOException {
+    ConfigProvider configProvider = createSilentConfigProvider(buildRunArgs(options));
+    Map<String, String> runArgOrigins = buildRunArgOrigins(options);
+
+    List<Parameter> parameters;
+    if (command != null) {
+      System.out.println(
+          String.format("Effective configuration of %s %s", command.getGroup(), command.getName()));
+      parameters = command.getParameters();
+    } else {
+      System.out.println("Effective configuration of all parameters set in any config source");
+      Set<String> keys = new TreeSet<>(runArgOrigins.keySet());
+      registry.getCommands().stream()
+          .flatMap(registered -> registered.getParameters().stream())
+          .forEach(parameter -> keys.add(parameter.getKey()));
+      parameters =
+          keys.stream()
+              .filter(key -> !configProvider.getParameterSources(key).isEmpty())
+              .map(key -> new Parameter(key, "", true))
+              .collect(Collectors.toList());
+    }
+    System.out.println();
+
+    int keyWidth = maxKeyLength(parameters);
+    int missing = 0;
+    for (Parameter parameter : parameters) {
+      String key = parameter.getKey();
+      Map<String, String> sources = configProvider.getParameterSources(key);
+      if (sources.isEmpty()) {
+        missing += parameter.isOptional() ? 0 : 1;
+        String shown = parameter.isOptional() ? "(not set)" : "MISSING";
+        System.out.println(String.format("  %-" + keyWidth + "s  %s", key, shown));
+        continue;
+      }
+
+      List<String> origins = new ArrayList<>();
+      for (String source : sources.keySet()) {
+        origins.add(
+            source.equals(ConfigProvider.COMMAND_LINE_ARGUMENTS)
+                ? runArgOrigins.getOrDefault(key, source)
+                : source);
+      }
+      String value = sources.values().iterator().next();
+      System.out.println(
+          String.format("  %-" + keyWidth + "s  %s", key, isSecret(key) ? "(set)" : value));
+      System.out.println(String.format("  %-" + keyWidth + "s    from %s", "", origins.get(0)));
+      for (String overridden : origins.subList(1, origins.size())) {
+        System.out.println(String.format("  %-" + keyWidth + "s    overrides %s", "", overridden));
+      }
+    }
+
+    if (missing > 0) {
+      System.out.println();
+      System.out.println(String.format("%d required parameters are missing", missing));
+      return ExampleLauncher.Outcome.CONFIG_ERROR.getExitCode();
+    }
+    return ExampleLauncher.Outcome.SUCCESS.getExitCode();
+  }
+
+  /**
+   * Compares two properties files and prints the parameters which are only set in one of them or
+   * have different values. Secrets are not printed, only whether they differ.
+   *
+   * @param path the path to the first properties file
+   * @param otherPath the path to the properties file to compare it with
+   */
+  private static int diffConfig(String path, String otherPath) throws IOException {
+    Map<String, String> values = readPropertiesFile(path);
+    Map<String, String> otherValues = readPropertiesFile(otherPath);
+
+    Set<String> keys = new TreeSet<>(values.keySet());
+    keys.addAll(otherValues.keySet());
+    List<String> differences =
+        keys.stream()
+            .filter(key -> !StringUtils.equals(values.get(key), otherValues.get(key)))
+            .collect(Collectors.toList());
+
+    System.out.println(String.format("Comparing %s with %s", path, otherPath));
+    System.out.println();
+    if (differences.isEmpty()) {
+      System.out.println("Both files set the same parameters to the same values");
+      return ExampleLauncher.Outcome.SUCCESS.getExitCode();
+    }
+
+    int keyWidth = differences.stream().mapToInt(String::length).max().orElse(1);
+    for (String key : differences) {
+      System.out.println("  " + key);
+      System.out.println(
+          String.format("  %-" + keyWidth + "s    < %s", "", describeValue(key, values.get(key))));
+      System.out.println(
+          String.format(
+              "  %-" + keyWidth + "s    > %s", "", describeValue(key, otherValues.get(key))));
+    }
+    System.out.println();
+    System.out.println(String.format("%d parameters differ", differences.size()));
+    return ExampleLauncher.Outcome.SUCCESS.getExitCode();
+  }
+
+  private static String describeValue(String key, String value) {
+    if (value == null) {
+      return "(not set)";
+    }
+    return isSecret(key) ? "(set)" : value;
+  }
+
+  /**
+   * Creates a config provider which does not log the values it retrieves, as they include secrets
+   *
+   * @param runArgs the configuration parameters passed to the command
+   */
+  private static ConfigProvider createSilentConfigProvider(List<String> runArgs) {
+    ((ch.qos.logback.classic.Logger) LoggerFactory.getLogger(ConfigProvider.class))
+        .setLevel(Level.WARN);
+    return new ConfigProvider(runArgs.toArray(new String[0]));
+  }
+
+  /** Parameters holding credentials or keys are never printed */
+  private static boolean isSecret(String key) {
+    return key.endsWith("_KEY") || key.contains("SECRET") || key.contains("PASSWORD");
+  }
+
+  /**
+   * Prints the help of a group or command, e.g. for "help encode FixedBitrateLadder"
+   *
+   * @param topic the group and command names following "help"
+   */
+  private static int printHelp(List<String> topic) {
+    if (topic.isEmpty()) {
+      printUsage();
+      return ExampleLauncher.Outcome.SUCCESS.getExitCode();
+    }
+    if (!CommandRegistry.GROUPS.contains(topic.get(0))) {
+      return fail(String.format("Unknown group '%s'", topic.get(0)));
+    }
+    if (topic.size() == 1) {
+      printGroupHelp(topic.get(0));
+      return ExampleLauncher.Outcome.SUCCESS.getExitCode();
+    }
+
+    Command command = findCommand(topic.get(0), topic.get(1));
+    if (command == null) {
+      return fail(String.format("Unknown command '%s' in group %s", topic.get(1), topic.get(0)));
+    }
+    printCommandHelp(command);
+    return ExampleLauncher.Outcome.SUCCESS.getExitCode();
+  }
+
+  private static void printUsage() {
+    PrintStream out = System.out;
+    out.println("Usage: " + NAME + " [flags] <group> <command> [KEY=value ...]");
+    out.println();
+    out.println("Runs the examples and tools of the Bitmovin API SDK.");
+    out.println();
+    out.println("Groups:");
+    for (String group : CommandRegistry.GROUPS) {
+      out.println(String.format("  %-12s%s", group, registry.getGroupSummary(group)));
+    }
+    out.println();
+    out.println("Other commands:");
+    out.println(String.format("  %-12s%s", "help", "Print the help of a group or command"));
+    out.println(String.format("  %-12s%s", "completion", "Print a shell completion script"));
+    out.println(String.format("  %-12s%s", "diff-config", "Compare two properties files"));
+    out.println();
+    printFlags();
+    out.println();
+    out.println(String.format("Run '%s help <group>' to list the commands of a group.", NAME));
+  }
+
+  /** @param group the name of the group */
+  private static void printGroupHelp(String group) {
+    PrintStream out = System.out;
+    out.println(String.format("Usage: %s [flags] %s <command> [KEY=value ...]", NAME, group));
+    out.println();
+    out.println(registry.getGroupSummary(group) + ".");
+    out.println();
+    out.println("Commands:");
+    for (Command command : registry.getCommands(group)) {
+      out.println("  " + command.getName());
+      for (String line : wrap(command.getSummary(), WIDTH - 6)) {
+        out.println("      " + line);
+      }
+    }
+    out.println();
+    out.println(
+        String.format(
+            "Run '%s help %s <command>' to list the parameters of a command.", NAME, group));
+  }
+
+  /** @param command the command to print the summary and parameters of */
+  private static void printCommandHelp(Command command) {
+    PrintStream out = System.out;
+    out.println(
+        String.format(
+            "Usage: %s [flags] %s %s [KEY=value ...]",
+            NAME,
+            command.getGroup(),
+            command.getName()));
+    out.println();
+    for (String line : wrap(command.getSummary(), WIDTH)) {
+      out.println(line);
+    }
+    out.println();
+    out.println("Configuration parameters:");
+    for (Parameter parameter : command.getParameters()) {
+      out.println("  " + parameter.getKey() + (parameter.isOptional() ? " (optional)" : ""));
+      for (String line : wrap(parameter.getDescription(), WIDTH - 6)) {
+        out.println("      " + line);
+      }
+    }
+    out.println();
+    printFlags();
+  }
+
+  private static void printFlags() {
+    PrintStream out = System.out;
+    out.println("Flags:");
+    out.println(
+        String.format(
+            "  %-18s%s", CONFIG_FLAG + " <file>", "Read parameters from a properties file"));
+    out.println(
+        String.format("  %-18s%s", TENANT_FLAG + " <id>", "Act on behalf of an organisation"));
+    out.println(
+        String.format(
+            "  %-18s%s", DRY_RUN_FLAG, "Print the resolved parameters instead of running"));
+    out.println(
+        String.format(
+            "  %-18s%s", PRINT_EFFECTIVE_CONFIG_FLAG, "Print the parameters and their sources"));
+    out.println(String.format("  %-18s%s", QUIET_FLAG, "Only print the outcome of the command"));
+    out.println(String.format("  %-18s%s", "-h, " + HELP_FLAG, "Print this help"));
+  }
+
+  /**
+   * Prints a completion script for the given shell, which is generated from the registry. It is
+   * enabled by adding the following line to ~/.bashrc or ~/.zshrc:
+   *
+   * <pre>
+   * source &lt;(bitmovin-examples completion bash)
+   * </pre>
+   *
+   * @param shell bash or zsh
+   */
+  private static int printCompletion(String shell) {
+    if (!"bash".equals(shell) && !"zsh".equals(shell)) {
+      return fail("Usage: " + NAME + " completion bash|zsh");
+    }
+
+    StringBuilder script = new StringBuilder();
+    if (shell.equals("zsh")) {
+      // zsh runs bash completion functions with its bashcompinit module
+      script.append("autoload -U +X bashcompinit && bashcompinit\n");
+    }
+    script.append("_bitmovin_examples_commands() {\n");
+    script.append("  case \"$1\" in\n");
+    for (String group : CommandRegistry.GROUPS) {
+      String names =
+          registry.getCommands(group).stream()
+              .map(Command::getName)
+              .collect(Collectors.joining(" "));
+      script.append(String.format("    %s) echo \"%s\" ;;\n", group, names));
+    }
+    script.append("  esac\n");
+    script.append("}\n\n");
+
+    script.append("_bitmovin_examples_parameters() {\n");
+    script.append("  case \"$1\" in\n");
+    for (Command command : registry.getCommands()) {
+      String keys =
+          command.getParameters().stream()
+              .map(parameter -> parameter.getKey() + "=")
+              .collect(Collectors.joining(" "));
+      script.append(String.format("    %s) echo \"%s\" ;;\n", command.getName(), keys));
+    }
+    script.append("  esac\n");
+    script.append("}\n\n");
+
+    // the line is split manually, as bash splits KEY=value into three words
+    script.append("_bitmovin_examples() {\n");
+    script.append("  local line=\"${COMP_LINE:0:COMP_POINT}\" words=() positionals=() cur i\n");
+    script.append("  read -ra words <<< \"$line\"\n");
+    script.append("  if [[ \"$line\" == *\" \" ]]; then\n");
+    script.append("    cur=\"\"\n");
+    script.append("  else\n");
+    script.append("    cur=\"${words[${#words[@]}-1]}\"\n");
+    script.append("    unset 'words[${#words[@]}-1]'\n");
+    script.append("  fi\n");
+    script.append("  COMPREPLY=()\n");
+    script.append("  case \"${words[${#words[@]}-1]}\" in\n");
+    script.append("    " + CONFIG_FLAG + ") COMPREPLY=($(compgen -f -- \"$cur\")); return ;;\n");
+    script.append("    " + TENANT_FLAG + ") return ;;\n");
+    script.append("  esac\n");
+    script.append("  [[ \"$cur\" == *=* ]] && return\n");
+    script.append("  if [[ \"$cur\" == -* ]]; then\n");
+    script.append(
+        String.format(
+            "    COMPREPLY=($(compgen -W \"%s\" -- \"$cur\"))\n", String.join(" ", FLAGS)));
+    script.append("    return\n");
+    script.append("  fi\n");
+    script.append("  for ((i = 1; i < ${#words[@]}; i++)); do\n");
+    script.append("    case \"${words[i]}\" in\n");
+    script.append("      " + CONFIG_FLAG + "|" + TENANT_FLAG + ") ((i++)) ;;\n");
+    script.append("      -*|*=*) ;;\n");
+    script.append("      *) positionals+=(\"${words[i]}\") ;;\n");
+    script.append("    esac\n");
+    script.append("  done\n");
+    script.append("  local candidates\n");
+    script.append("  case \"${#positionals[@]}:${positionals[0]}\" in\n");
+    script.append(
+        String.format(
+            "    0:) candidates=\"%s help completion diff-config\" ;;\n",
+            String.join(" ", CommandRegistry.GROUPS)));
+    script.append(
+        String.format(
+            "    1:help) candidates=\"%s\" ;;\n", String.join(" ", CommandRegistry.GROUPS)));
+    script.append("    1:completion) candidates=\"bash zsh\" ;;\n");
+    script.append(
+        "    1:diff-config|2:diff-config) COMPREPLY=($(compgen -f -- \"$cur\")); return ;;\n");
+    script.append("    1:*) candidates=$(_bitmovin_examples_commands \"${positionals[0]}\") ;;\n");
+    script.append(
+        "    2:help) candidates=$(_bitmovin_examples_commands \"${positionals[1]}\") ;;\n");
+    script.append("    *:help|*:completion|*:diff-config) ;;\n");
+    script.append("    *)\n");
+    script.append("      candidates=$(_bitmovin_examples_parameters \"${positionals[1]}\")\n");
+    script.append("      compopt -o nospace 2>/dev/null\n");
+    script.append("      ;;\n");
+    script.append("  esac\n");
+    script.append("  COMPREPLY=($(compgen -W \"$candidates\" -- \"$cur\"))\n");
+    script.append("}\n\n");
+    script.append("complete -F _bitmovin_examples " + NAME + "\n");
+
+    System.out.print(script);
+    return ExampleLauncher.Outcome.SUCCESS.getExitCode();
+  }
+
+  /**
+   * Returns the command with the given name if it belongs to the group, or null
+   *
+   * @param group the name of the group
+   * @param name the name of the command
+   */
+  private static Command findCommand(String group, String name) {
+    Command command = registry.getCommand(name);
+    return command != null && command.getGroup().equals(group) ? command : null;
+  }
+
+  /**
+   * Prints an error message and returns the exit code of a configuration error
+   *
+   * @param message the error message
+   */
+  private static int fail(String message) {
+    System.err.println("Error: " + message);
+    System.err.println(String.format("Run '%s help' for usage.", NAME));
+    return ExampleLauncher.Outcome.CONFIG_ERROR.getExitCode();
+  }
+
+  private static int maxKeyLength(List<Parameter> parameters) {
+    return parameters.stream().mapToInt(parameter -> parameter.getKey().length()).max().orElse(1);
+  }
+
+  /**
+   * Splits a text into lines of at most the given width, breaking at spaces only
+   *
+   * @param text the text to wrap
+   * @param width the maximum length of a line
+   */
+  private static List<String> wrap(String text, int width) {
+    List<String> lines = new ArrayList<>();
+    StringBuilder line = new StringBuilder();
+    for (String word : text.split(" ")) {
+      if (line.length() > 0 && line.length() + 1 + word.length() > width) {
+        lines.add(line.toString());
+        line.setLength(0);
+      }
+      if (line.length() > 0) {
+        line.append(' ');
+      }
+      line.append(word);
+    }
+    if (line.length() > 0) {
+      lines.add(line.toString());
+    }
+    return lines;
+  }
+
+  /** The flags, configuration parameters and positional arguments of a command line */
+  static class Options {
+    String configFile;
+    String tenant;
+    boolean dryRun;
+    boolean printEffectiveConfig;
+    boolean quiet;
+    boolean help;
+    final List<String> parameters = new ArrayList<>();
+    final List<String> positionals = new ArrayList<>();
+    /** The flags which are not handled by the CLI itself, but passed on to the command */
+    final List<String> commandFlags = new ArrayList<>();
+
+    /**
+     * Parses the command line. Flags may appear anywhere, and their values may be passed as the
+     * following argument or after an equals sign, e.g. --config=staging.properties.
+     *
+     * @param args the command line arguments
+     */
+    static Options parse(String[] args) {
+      Options options = new Options();
+      for (int i = 0; i < args.length; i++) {
+        String arg = args[i];
+        String flag = arg.startsWith("--") ? StringUtils.substringBefore(arg, "=") : arg;
+        String inlineValue =
+            arg.startsWith("--") && arg.contains("=") ? StringUtils.substringAfter(arg, "=") : null;
+
+        if (flag.equals(CONFIG_FLAG) || flag.equals(TENANT_FLAG)) {
+          String value = inlineValue;
+          if (value == null) {
+            if (i + 1 >= args.length) {
+              throw new IllegalArgumentException("Flag " + flag + " requires a value");
+            }
+            value = args[++i];
+          }
+          if (flag.equals(CONFIG_FLAG)) {
+            options.configFile = value;
+          } else {
+            options.tenant = value;
+          }
+        } else if (flag.equals(DRY_RUN_FLAG)) {
+          options.dryRun = true;
+        } else if (flag.equals(PRINT_EFFECTIVE_CONFIG_FLAG)) {
+          options.printEffectiveConfig = true;
+        } else if (flag.equals(QUIET_FLAG)) {
+          options.quiet = true;
+        } else if (flag.equals(HELP_FLAG) || flag.equals("-h")) {
+          options.help = true;
+        } else if (arg.startsWith("-")) {
+          options.commandFlags.add(arg);
+        } else if (arg.contains("=")) {
+          options.parameters.add(arg);
+        } else {
+          options.positionals.add(arg);
+        }
+      }
+      return options;
+    }
+  }
+}
@@ -0,0 +1,254 @@
+package common;
+
+import feign.Client;
+import feign.Request;
+import feign.Response;
+import feign.Util;
+import java.io.FileInputStream;
+import java.io.IOException;
+import java.io.InputStream;
+import java.io.InterruptedIOException;
+import java.net.InetAddress;
+import java.net.InetSocketAddress;
+import java.net.Proxy;
+import java.net.Socket;
+import java.security.GeneralSecurityException;
+import java.security.KeyStore;
+import java.security.cert.Certificate;
+import java.security.cert.CertificateFactory;
+import java.util.Arrays;
+import java.util.List;
+import java.util.concurrent.ExecutionException;
+import java.util.concurrent.ExecutorService;
+import java.util.concurrent.Executors;
+import java.util.concurrent.Future;
+import java.util.concurrent.TimeUnit;
+import java.util.concurrent.TimeoutException;
+import javax.net.ssl.HttpsURLConnection;
+import javax.net.ssl.SSLContext;
+import javax.net.ssl.SSLSocket;
+import javax.net.ssl.SSLSocketFactory;
+import javax.net.ssl.TrustManagerFactory;
+
+/**
+ * This class creates the HTTP client used by the API client from network settings in the
+ * configuration. It is required in networks that can only reach the Bitmovin API through an egress
+ * proxy or that intercept TLS connections with a corporate certificate authority.
+ *
+ * <p>The client is passed to the API clients of all examples by {@link ApiClientFactory}.
+ *
+ * <p>The following configuration parameters are supported:
+ *
+ * <ul>
+ *   <li>HTTP_PROXY_HOST - (optional) The hostname or IP address of the HTTP(S) proxy
+ *   <li>HTTP_PROXY_PORT - (optional) The port of the HTTP(S) proxy. Default: 8080
+ *   <li>TLS_CA_BUNDLE_PATH - (optional) The path to a PEM file with the certificates of the
+ *       certificate authorities to be trusted instead of the default ones of the JVM
+ *   <li>TLS_MIN_VERSION - (optional) The minimum TLS version to be used, e.g. TLSv1.3. Default:
+ *       TLSv1.2
+ *   <li>HTTP_CONNECT_TIMEOUT_SECONDS - (optional) The time to wait for a connection to the API to
+ *       be established. Default: 10
+ *   <li>HTTP_READ_TIMEOUT_SECONDS - (optional) The time to wait for data of a response. Default: 60
+ *   <li>HTTP_CALL_TIMEOUT_SECONDS - (optional) The maximum duration of a whole API call including
+ *       the transfer of the response. By default, only the connect and read timeouts apply
+ * </ul>
+ *
+ * <p>If a timeout is exceeded, the API call fails with an exception instead of blocking the caller,
+ * e.g. a loop polling the status of an encoding, indefinitely.
+ */
+public class HttpClientSettings {
+  private static final List<String> TLS_VERSIONS = Arrays.asList("TLSv1.2", "TLSv1.3");
+
+  /**
+   * Creates an HTTP client applying the proxy and TLS settings of the configuration
+   *
+   * @param configProvider the config provider the settings are read from
+   */
+  public static Client createClient(ConfigProvider configProvider)
+      throws GeneralSecurityException, IOException {
+    String minTlsVersion = configProvider.getParameterByKey("TLS_MIN_VERSION", "TLSv1.2");
+    if (!TLS_VERSIONS.contains(minTlsVersion)) {
+      throw new IllegalArgumentException(
+          String.format(
+              "Unsupported TLS version %s, expected one of %s", minTlsVersion, TLS_VERSIONS));
+    }
+
+    SSLContext sslContext = SSLContext.getInstance("TLS");
+    sslContext.init(null, createTrustManagerFactory(configProvider).getTrustManagers(), null);
+    SSLSocketFactory sslSocketFactory =
+        new MinVersionSSLSocketFactory(
+            sslContext.getSocketFactory(),
+            TLS_VERSIONS.subList(TLS_VERSIONS.indexOf(minTlsVersion), TLS_VERSIONS.size()));
+
+    Client client;
+    String proxyHost = configProvider.getParameterByKey("HTTP_PROXY_HOST", null);
+    if (proxyHost == null) {
+      client =
+          new Client.Default(sslSocketFactory, HttpsURLConnection.getDefaultHostnameVerifier());
+    } else {
+      int proxyPort = Integer.parseInt(configProvider.getParameterByKey("HTTP_PROXY_PORT", "8080"));
+      Proxy proxy = new Proxy(Proxy.Type.HTTP, new InetSocketAddress(proxyHost, proxyPort));
+      client =
+          new Client.Proxied(
+              sslSocketFactory, HttpsURLConnection.getDefaultHostnameVerifier(), proxy);
+    }
+
+    Request.Options options =
+        new Request.Options(
+            Long.parseLong(configProvider.getParameterByKey("HTTP_CONNECT_TIMEOUT_SECONDS", "10")),
+            TimeUnit.SECONDS,
+            Long.parseLong(configProvider.getParameterByKey("HTTP_READ_TIMEOUT_SECONDS", "60")),
+            TimeUnit.SECONDS,
+            true);
+    String callTimeout = configProvider.getParameterByKey("HTTP_CALL_TIMEOUT_SECONDS", null);
+
+    return new TimeoutClient(
+        client, options, callTimeout != null ? Long.parseLong(callTimeout) : null);
+  }
+
+  /**
+   * Creates a trust manager factory for the certificates of the configured CA bundle, or for the
+   * default certificates of the JVM if no bundle is configured
+   */
+  private static TrustManagerFactory createTrustManagerFactory(ConfigProvider configProvider)
+      throws GeneralSecurityException, IOException {
+    TrustManagerFactory trustManagerFactory =
+        TrustManagerFactory.getInstance(TrustManagerFactory.getDefaultAlgorithm());
+
+    String caBundlePath = configProvider.getParameterByKey("TLS_CA_BUNDLE_PATH", null);
+    if (caBundlePath == null) {
+      trustManagerFactory.init((KeyStore) null);
+      return trustManagerFactory;
+    }
+
+    KeyStore keyStore = KeyStore.getInstance(KeyStore.getDefaultType());
+    keyStore.load(null, null);
+    try (InputStream inputStream = new FileInputStream(caBundlePath)) {
+      int index = 0;
+      for (Certificate certificate :
+          CertificateFactory.getInstance("X.509").generateCertificates(inputStream)) {
+        keyStore.setCertificateEntry("ca-" + index++, certificate);
+      }
+    }
+    trustManagerFactory.init(keyStore);
+
+    return trustManagerFactory;
+  }
+
+  /**
+   * Client that applies the configured timeouts to all calls, and optionally aborts calls that
+   * exceed the overall call timeout
+   */
+  private static class TimeoutClient implements Client {
+
+    private static final ExecutorService executor =
+        Executors.newCachedThreadPool(
+            runnable -> {
+              Thread thread = new Thread(runnable, "bitmovin-api-call");
+              thread.setDaemon(true);
+              return thread;
+            });
+
+    private final Client delegate;
+    private final Request.Options options;
+    private final Long callTimeoutSeconds;
+
+    private TimeoutClient(Client delegate, Request.Options options, Long callTimeoutSeconds) {
+      this.delegate = delegate;
+      this.options = options;
+      this.callTimeoutSeconds = callTimeoutSeconds;
+    }
+
+    @Override
+    public Response execute(Request request, Request.Options ignored) throws IOException {
+      if (callTimeoutSeconds == null) {
+        return delegate.execute(request, options);
+      }
+
+      Future<Response> call = executor.submit(() -> readFully(delegate.execute(request, options)));
+      try {
+        return call.get(callTimeoutSeconds, TimeUnit.SECONDS);
+      } catch (TimeoutException e) {
+        call.cancel(true);
+        throw new InterruptedIOException(
+            String.format(
+                "%s %s did not complete within %d seconds",
+                request.httpMethod(), request.url(), callTimeoutSeconds));
+      } catch (ExecutionException e) {
+        if (e.getCause() instanceof IOException) {
+          throw (IOException) e.getCause();
+        }
+        throw new IOException(e.getCause());
+      } catch (InterruptedException e) {
+        Thread.currentThread().interrupt();
+        throw new InterruptedIOException("The API call has been interrupted");
+      }
+    }
+
+    /** Reads the body of the response, so the transfer of the body counts towards the timeout */
+    private static Response readFully(Response response) throws IOException {
+      if (response.body() == null) {
+        return response;
+      }
+      byte[] body = Util.toByteArray(response.body().asInputStream());
+      return response.toBuilder().body(body).build();
+    }
+  }
+
+  /** Socket factory that restricts the TLS versions of all created sockets */
+  private static class MinVersionSSLSocketFactory extends SSLSocketFactory {
+
+    private final SSLSocketFactory delegate;
+    private final String[] protocols;
+
+    private MinVersionSSLSocketFactory(SSLSocketFactory delegate, List<String> protocols) {
+      this.delegate = delegate;
+      this.protocols = protocols.toArray(new String[0]);
+    }
+
+    private Socket restrict(Socket socket) {
+      if (socket instanceof SSLSocket) {
+        ((SSLSocket) socket).setEnabledProtocols(protocols);
+      }
+      return socket;
+    }
+
+    @Override
+    public String[] getDefaultCipherSuites() {
+      return delegate.getDefaultCipherSuites();
+    }
+
+    @Override
+    public String[] getSupportedCipherSuites() {
+      return delegate.getSupportedCipherSuites();
+    }
+
+    @Override
+    public Socket createSocket(Socket socket, String host, int port, boolean autoClose)
+        throws IOException {
+      return restrict(delegate.createSocket(socket, host, port, autoClose));
+    }
+
+    @Override
+    public Socket createSocket(String host, int port) throws IOException {
+      return restrict(delegate.createSocket(host, port));
+    }
+
+    @Override
+    public Socket createSocket(String host, int port, InetAddress localHost, int localPort)
+        throws IOException {
+      return restrict(delegate.createSocket(host, port, localHost, localPort));
+    }
+
+    @Override
+    public Socket createSocket(InetAddress host, int port) throws IOException {
+      return restrict(delegate.createSocket(host, port));
+    }
+
+    @Override
+    public Socket createSocket(
+        InetAddress address, int port, InetAddress localAddress, int localPort) throws IOException {
+      return restrict(delegate.createSocket(address, port, localAddress, localPort));
+    }
+  }
+}
@@ -0,0 +1,106 @@
+package common;
+
+import com.bitmovin.api.sdk.common.BitmovinException;
+import java.nio.charset.StandardCharsets;
+import java.security.MessageDigest;
+import java.security.NoSuchAlgorithmException;
+import java.util.HashMap;
+import java.util.List;
+import java.util.Map;
+import java.util.function.Function;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+
+/**
+ * This class helps to make the creation of API resources retry-safe. An idempotency key is derived
+ * deterministically from the given key parts (e.g. the example name and the input file), and added
+ * to the names and custom data of all created resources.
+ *
+ * <p>Before a resource is created, existing resources are searched for a matching name. If one is
+ * found, it is reused instead of creating a duplicate. This way, re-running an interrupted example
+ * with the same configuration resumes where it left off.
+ */
+public class IdempotentResources {
+  private static final Logger logger = LoggerFactory.getLogger(IdempotentResources.class);
+
+  public static final String CUSTOM_DATA_KEY = "idempotencyKey";
+
+  private final String idempotencyKey;
+
+  /** @param keyParts the values the idempotency key is derived from */
+  public IdempotentResources(String... keyParts) {
+    this.idempotencyKey = deriveKey(keyParts);
+    logger.info("Using idempotency key '{}'", idempotencyKey);
+  }
+
+  public String getIdempotencyKey() {
+    return idempotencyKey;
+  }
+
+  /** Appends the idempotency key to the given name, e.g. "My encoding [3f2a9c1b7e4d]" */
+  public String buildName(String name) {
+    return String.format("%s [%s]", name, idempotencyKey);
+  }
+
+  /** Builds custom data containing the idempotency key, to be attached to created resources */
+  public Map<String, Object> buildCustomData() {
+    Map<String, Object> customData = new HashMap<>();
+    customData.put(CUSTOM_DATA_KEY, idempotencyKey);
+    return customData;
+  }
+
+  /**
+   * Returns an existing resource with the given name, or creates a new one if none exists.
+   *
+   * @param name the name of the resource, which should be built with {@link #buildName(String)}
+   * @param listExisting lists the existing resources which are candidates for reuse. The list may
+   *     already be filtered by name, but does not have to be.
+   * @param getName returns the name of a resource
+   * @param create creates a new resource with the given name
+   */
+  public <T> T findOrCreate(
+      String name,
+      ResourceLister<T> listExisting,
+      Function<T, String> getName,
+      ResourceCreator<T> create)
+      throws BitmovinException {
+    for (T resource : listExisting.list(name)) {
+      if (name.equals(getName.apply(resource))) {
+        logger.info("Reusing existing resource '{}'", name);
+        return resource;
+      }
+    }
+
+    logger.info("Creating resource '{}'", name);
+    return create.create(name);
+  }
+
+  private static String deriveKey(String... keyParts) {
+    try {
+      MessageDigest digest = MessageDigest.getInstance("SHA-256");
+      for (String keyPart : keyParts) {
+        digest.update(String.valueOf(keyPart).getBytes(StandardCharsets.UTF_8));
+        // separate the parts, so that e.g. ("ab", "c") and ("a", "bc") result in different keys
+        digest.update((byte) 0);
+      }
+
+      StringBuilder hex = new StringBuilder();
+      for (byte b : digest.digest()) {
+        hex.append(String.format("%02x", b));
+      }
+      return hex.substring(0, 12);
+    } catch (NoSuchAlgorithmException e) {
+      throw new IllegalStateException("SHA-256 is not supported", e);
+    }
+  }
+
+  @FunctionalInterface
+  public interface ResourceLister<T> {
+    List<T> list(String name) throws BitmovinException;
+  }
+
+  @FunctionalInterface
+  public interface ResourceCreator<T> {
+    T create(String name) throws BitmovinException;
+  }
+}
@@ -0,0 +1,235 @@
+package common;
+
+import com.bitmovin.api.sdk.BitmovinApi;
+import com.bitmovin.api.sdk.common.BitmovinException;
+import com.bitmovin.api.sdk.model.Encoding;
+import com.bitmovin.api.sdk.model.IngestInputStream;
+import com.bitmovin.api.sdk.model.Input;
+import com.bitmovin.api.sdk.model.InputStream;
+import com.bitmovin.api.sdk.model.StreamInput;
+import com.bitmovin.api.sdk.model.StreamSelectionMode;
+import com.bitmovin.api.sdk.model.TimeBasedTrimmingInputStream;
+import java.util.Map;
+import java.util.concurrent.ConcurrentHashMap;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+
+/**
+ * This class creates the stream inputs of the examples. If PREVIEW_DURATION_SECONDS is configured,
+ * the input file is not read directly, but through a time-based trimming input stream covering its
+ * first seconds only. This allows validating a full workflow, including its outputs and manifests,
+ * on a short excerpt (e.g. 30 seconds) before running it on the full-length input.
+ *
+ * <p>If no preview duration is configured, the stream input reads the input file directly, the same
+ * way the examples did before. Examples creating ingest input streams on their own trim them with
+ * {@link #trimInputStream}. Live encodings are not affected.
+ */
+public class PreviewTrimming {
+  private static final Logger logger = LoggerFactory.getLogger(PreviewTrimming.class);
+
+  private static final String PREVIEW_DURATION_KEY = "PREVIEW_DURATION_SECONDS";
+
+  /** Trimming input stream IDs by encoding, input, path, selection mode and position */
+  private static final Map<String, String> trimmingInputStreamIds = new ConcurrentHashMap<>();
+
+  private PreviewTrimming() {}
+
+  /**
+   * Creates a stream input automatically selecting the track of the input file
+   *
+   * @param bitmovinApi the API client used to create the input streams
+   * @param configProvider the config provider the preview duration is read from
+   * @param encoding the encoding the stream input is used in
+   * @param input the input resource providing the input file
+   * @param inputPath the path to the input file
+   */
+  public static StreamInput createStreamInput(
+      BitmovinApi bitmovinApi,
+      ConfigProvider configProvider,
+      Encoding encoding,
+      Input input,
+      String inputPath)
+      throws BitmovinException {
+    return createStreamInput(
+        bitmovinApi, configProvider, encoding, input, inputPath, StreamSelectionMode.AUTO, null);
+  }
+
+  /**
+   * Creates a stream input selecting a track of the input file. If a preview duration is
+   * configured, the trimming input stream is created once per encoding and track, so all streams
+   * of the track share it.
+   *
+   * <p>API endpoints:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsInputStreamsIngestByEncodingId
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsInputStreamsTrimmingTimeBasedByEncodingId
+   *
+   * @param bitmovinApi the API client used to create the input streams
+   * @param configProvider the config provider the preview duration is read from
+   * @param encoding the encoding the stream input is used in
+   * @param input the input resource providing the input file
+   * @param inputPath the path to the input file
+   * @param selectionMode the mode the track of the input file is selected by
+   * @param position the position of the track, or null if the selection mode does not need one
+   */
+  public static StreamInput createStreamInput(
+      BitmovinApi bitmovinApi,
+      ConfigProvider configProvider,
+      Encoding encoding,
+      Input input,
+      String inputPath,
+      StreamSelectionMode selectionMode,
+      Integer position)
+      throws BitmovinException {
+    return createStreamInput(
+        bitmovinApi, configProvider, encoding, input.getId(), inputPath, selectionMode, position);
+  }
+
+  /**
+   * Creates a stream input reading the same track of the same input file as a stream input of
+   * another encoding, e.g. when an encoding is repeated with different settings. Stream inputs
+   * referencing an input stream are returned unchanged, as the input stream has been trimmed
+   * already when it was created.
+   *
+   * @param bitmovinApi the API client used to create the input streams
+   * @param configProvider the config provider the preview duration is read from
+   * @param encoding the encoding the stream input is used in
+   * @param sourceStreamInput the stream input of the other encoding
+   */
+  public static StreamInput copyStreamInput(
+      BitmovinApi bitmovinApi,
+      ConfigProvider configProvider,
+      Encoding encoding,
+      StreamInput sourceStreamInput)
+      throws BitmovinException {
+    if (sourceStreamInput.getInputId() == null) {
+      return sourceStreamInput;
+    }
+
+    return createStreamInput(
+        bitmovinApi,
+        configProvider,
+        encoding,
+        sourceStreamInput.getInputId(),
+        sourceStreamInput.getInputPath(),
+        sourceStreamInput.getSelectionMode() != null
+            ? sourceStreamInput.getSelectionMode()
+            : StreamSelectionMode.AUTO,
+        sourceStreamInput.getPosition());
+  }
+
+  /**
+   * Returns the input stream to be used instead of an input stream created by an example, e.g. an
+   * ingest input stream. If a preview duration is configured, a time-based trimming input stream
+   * covering the first seconds of the given input stream is created and returned, otherwise the
+   * given input stream itself.
+   *
+   * <p>API endpoint:
+   * https://bitmovin.com/docs/encoding/api-reference/sections/encodings#/Encoding/PostEncodingEncodingsInputStreamsTrimmingTimeBasedByEncodingId
+   *
+   * @param bitmovinApi the API client used to create the trimming input stream
+   * @param configProvider the config provider the preview duration is read from
+   * @param encoding the encoding the input stream belongs to
+   * @param inputStream the input stream to be trimmed
+   */
+  public static InputStream trimInputStream(
+      BitmovinApi bitmovinApi,
+      ConfigProvider configProvider,
+      Encoding encoding,
+      InputStream inputStream)
+      throws BitmovinException {
+    Double previewDuration = getPreviewDuration(configProvider);
+    if (previewDuration == null) {
+      return inputStream;
+    }
+
+    TimeBasedTrimmingInputStream trimmingInputStream = new TimeBasedTrimmingInputStream();
+    trimmingInputStream.setInputStreamId(inputStream.getId());
+    trimmingInputStream.setOffset(0.0);
+    trimmingInputStream.setDuration(previewDuration);
+
+    logger.info(
+        "Preview mode: encoding {} only reads the first {} seconds of input stream {}",
+        encoding.getId(),
+        previewDuration,
+        inputStream.getId());
+    return bitmovinApi.encoding.encodings.inputStreams.trimming.timeBased.create(
+        encoding.getId(), trimmingInputStream);
+  }
+
+  /**
+   * Returns the configured preview duration in seconds, or null if the example is not run in
+   * preview mode. Examples which trim their input on their own use it to limit the time range
+   * they encode.
+   *
+   * @param configProvider the config provider the preview duration is read from
+   */
+  public static Double getPreviewDuration(ConfigProvider configProvider) {
+    String value = configProvider.getParameterByKey(PREVIEW_DURATION_KEY, null);
+    if (value == null || value.trim().isEmpty()) {
+      return null;
+    }
+
+    double previewDuration = Double.parseDouble(value.trim());
+    if (previewDuration <= 0) {
+      throw new IllegalArgumentException(
+          PREVIEW_DURATION_KEY + " has to be a positive number of seconds, but is " + value);
+    }
+    return previewDuration;
+  }
+
+  private static StreamInput createStreamInput(
+      BitmovinApi bitmovinApi,
+      ConfigProvider configProvider,
+      Encoding encoding,
+      String inputId,
+      String inputPath,
+      StreamSelectionMode selectionMode,
+      Integer position)
+      throws BitmovinException {
+    Double previewDuration = getPreviewDuration(configProvider);
+    StreamInput streamInput = new StreamInput();
+
+    if (previewDuration == null) {
+      streamInput.setInputId(inputId);
+      streamInput.setInputPath(inputPath);
+      streamInput.setSelectionMode(selectionMode);
+      streamInput.setPosition(position);
+      return streamInput;
+    }
+
+    String key =
+        String.join("|", encoding.getId(), inputId, inputPath, selectionMode.toString())
+            + "|"
+            + position;
+    String trimmingInputStreamId = trimmingInputStreamIds.get(key);
+    if (trimmingInputStreamId == null) {
+      IngestInputStream ingestInputStream = new IngestInputStream();
+      ingestInputStream.setInputId(inputId);
+      ingestInputStream.setInputPath(inputPath);
+      ingestInputStream.setSelectionMode(selectionMode);
+      ingestInputStream.setPosition(position);
+      ingestInputStream =
+          bitmovinApi.encoding.encodings.inputStreams.ingest.create(
+              encoding.getId(), ingestInputStream);
+
+      TimeBasedTrimmingInputStream trimmingInputStream = new TimeBasedTrimmingInputStream();
+      trimmingInputStream.setInputStreamId(ingestInputStream.getId());
+      trimmingInputStream.setOffset(0.0);
+      trimmingInputStream.setDuration(previewDuration);
+      trimmingInputStreamId =
+          bitmovinApi.encoding.encodings.inputStreams.trimming.timeBased
+              .create(encoding.getId(), trimmingInputStream)
+              .getId();
+
+      logger.info(
+          "Preview mode: encoding {} only reads the first {} seconds of {}",
+          encoding.getId(),
+          previewDuration,
+          inputPath);
+      trimmingInputStreamIds.put(key, trimmingInputStreamId);
+    }
+
+    streamInput.setInputStreamId(trimmingInputStreamId);
+    return streamInput;
+  }
+}
@@ -0,0 +1,134 @@
+package common;
+
+import java.io.IOException;
+import java.nio.charset.StandardCharsets;
+import java.nio.file.Files;
+import java.nio.file.Path;
+import java.util.LinkedHashMap;
+import java.util.LinkedHashSet;
+import java.util.Map;
+import java.util.Set;
+
+/**
+ * This class records the resources created by an example and how they are connected, e.g. which
+ * input a stream reads from and which stream a muxing packages, and renders them as a Graphviz or
+ * Mermaid diagram. As the diagram contains the IDs of the resources, it documents what a run
+ * actually created, and the resources can be looked up in the dashboard or via the API.
+ *
+ * <p>Graphviz diagrams are rendered with e.g. "dot -Tsvg graph.dot -o graph.svg". Mermaid diagrams
+ * are rendered by GitHub and GitLab when embedded in Markdown, or with the Mermaid CLI.
+ */
+public class ResourceGraph {
+
+  /** The diagram formats a graph can be rendered in */
+  public enum Format {
+    GRAPHVIZ,
+    MERMAID
+  }
+
+  private final Map<String, String> nodeNames = new LinkedHashMap<>();
+  private final Map<String, String> labels = new LinkedHashMap<>();
+  private final Map<String, Set<String>> edges = new LinkedHashMap<>();
+  private String title;
+
+  /** @param title the title of the diagram, e.g. the name and ID of the encoding */
+  public void setTitle(String title) {
+    this.title = title;
+  }
+
+  /**
+   * Adds a resource to the graph. Adding a resource twice replaces its description.
+   *
+   * @param id the ID of the resource
+   * @param type the type of the resource, e.g. "Stream"
+   * @param description a short description of the resource, e.g. its codec configuration, or null
+   */
+  public void addResource(String id, String type, String description) {
+    nodeNames.putIfAbsent(id, "r" + (nodeNames.size() + 1));
+    labels.put(id, description != null ? type + "\n" + description + "\n" + id : type + "\n" + id);
+  }
+
+  /**
+   * Connects two resources in the direction the media flows, e.g. from a stream to its muxing
+   *
+   * @param fromId the ID of the resource the media comes from
+   * @param toId the ID of the resource the media goes to
+   */
+  public void connect(String fromId, String toId) {
+    if (!nodeNames.containsKey(fromId) || !nodeNames.containsKey(toId)) {
+      throw new IllegalArgumentException("Resources have to be added before they are connected");
+    }
+    edges.computeIfAbsent(fromId, key -> new LinkedHashSet<>()).add(toId);
+  }
+
+  /**
+   * Renders the graph in the format matching the extension of the file and writes it to the file.
+   * Files ending with .dot or .gv are written as Graphviz diagram, all others as Mermaid diagram.
+   *
+   * @param file the file the diagram is written to
+   */
+  public void write(Path file) throws IOException {
+    String fileName = file.getFileName().toString().toLowerCase();
+    Format format =
+        fileName.endsWith(".dot") || fileName.endsWith(".gv") ? Format.GRAPHVIZ : Format.MERMAID;
+    Files.write(file, render(format).getBytes(StandardCharsets.UTF_8));
+  }
+
+  /** @param format the format to render the graph in */
+  public String render(Format format) {
+    return format == Format.GRAPHVIZ ? renderGraphviz() : renderMermaid();
+  }
+
+  private String renderGraphviz() {
+    StringBuilder diagram = new StringBuilder();
+    diagram.append("digraph resources {\n");
+    diagram.append("  rankdir=LR;\n");
+    diagram.append("  node [shape=box, fontname=\"Helvetica\"];\n");
+    if (title != null) {
+      diagram.append(String.format("  labelloc=t;\n  label=\"%s\";\n", escapeGraphviz(title)));
+    }
+    for (Map.Entry<String, String> node : nodeNames.entrySet()) {
+      diagram.append(
+          String.format(
+              "  %s [label=\"%s\"];\n",
+              node.getValue(),
+              escapeGraphviz(labels.get(node.getKey()))));
+    }
+    for (Map.Entry<String, Set<String>> edge : edges.entrySet()) {
+      for (String toId : edge.getValue()) {
+        diagram.append(
+            String.format("  %s -> %s;\n", nodeNames.get(edge.getKey()), nodeNames.get(toId)));
+      }
+    }
+    diagram.append("}\n");
+    return diagram.toString();
+  }
+
+  private String renderMermaid() {
+    StringBuilder diagram = new StringBuilder();
+    if (title != null) {
+      diagram.append(String.format("---\ntitle: \"%s\"\n---\n", title.replace("\"", "'")));
+    }
+    diagram.append("flowchart LR\n");
+    for (Map.Entry<String, String> node : nodeNames.entrySet()) {
+      diagram.append(
+          String.format(
+              "  %s[\"%s\"]\n", node.getValue(), escapeMermaid(labels.get(node.getKey()))));
+    }
+    for (Map.Entry<String, Set<String>> edge : edges.entrySet()) {
+      for (String toId : edge.getValue()) {
+        diagram.append(
+            String.format("  %s --> %s\n", nodeNames.get(edge.getKey()), nodeNames.get(toId)));
+      }
+    }
+    return diagram.toString();
+  }
+
+  private static String escapeGraphviz(String text) {
+    return text.replace("\\", "\\\\").replace("\"", "\\\"").replace("\n", "\\n");
+  }
+
+  private static String escapeMermaid(String text) {
+    return text.replace("\"", "#quot;").replace("\n", "<br/>");
+  }
+}
@@ -0,0 +1,106 @@
+package common;
+
+import com.bitmovin.api.sdk.model.CmafMuxing;
+import com.bitmovin.api.sdk.model.Fmp4Muxing;
+import com.bitmovin.api.sdk.model.TsMuxing;
+
+/**
+ * This class distributes the segments of muxings across prefixes in the output bucket. S3 scales
+ * its request rate per key prefix, so assets with a very high number of requests (e.g. large
+ * catalogs served without a CDN cache) may be throttled if all segments share the same prefix.
+ * With sharding enabled, the name of every segment starts with random characters, e.g.
+ * "3fa2_segment_12.m4s" instead of "segment_12.m4s", which lets S3 split the requests of a single
+ * muxing across partitions.
+ *
+ * <p>The random characters are generated per segment by the Bitmovin API, using the
+ * {segment_rand_chars:x} placeholder of the segment naming template. A hash of the segment number
+ * can't be used instead, as the template has no placeholder for it. The %number% placeholder of an
+ * existing segment naming is converted to {number}, which is supported by the templates of fMP4,
+ * TS and CMAF muxings alike. Manifests created by the Bitmovin API reference the segment names
+ * that have actually been written, but players and tools relying on a numbered segment template
+ * cannot be used with sharded segments.
+ *
+ * <p>The VoD examples writing fMP4, CMAF or TS segments call {@link #apply} in their muxing
+ * builders. Live encodings don't support sharding.
+ *
+ * <p>The following configuration parameters are supported:
+ *
+ * <ul>
+ *   <li>SEGMENT_SHARDING - (optional) Set to true to start the name of every segment with random
+ *       characters. Default: false
+ *   <li>SEGMENT_SHARDING_PREFIX_LENGTH - (optional) The number of random characters. Default: 4
+ * </ul>
+ */
+public class SegmentSharding {
+  private static final String SHARDING_KEY = "SEGMENT_SHARDING";
+  private static final String PREFIX_LENGTH_KEY = "SEGMENT_SHARDING_PREFIX_LENGTH";
+
+  /**
+   * Replaces the segment naming of the muxing with a segment naming template with a random prefix
+   * per segment, if sharding is enabled. Has to be called after the segment naming of the muxing
+   * has been set, if any.
+   *
+   * @param configProvider the config provider the settings are read from
+   * @param muxing the muxing to be sharded
+   */
+  public static void apply(ConfigProvider configProvider, Fmp4Muxing muxing) {
+    if (isEnabled(configProvider)) {
+      muxing.setSegmentNamingTemplate(
+          buildTemplate(configProvider, muxing.getSegmentNaming(), "segment_%number%.m4s"));
+      // only one of segment naming and segment naming template may be set
+      muxing.setSegmentNaming(null);
+    }
+  }
+
+  /**
+   * Replaces the segment naming of the muxing with a segment naming template with a random prefix
+   * per segment, if sharding is enabled. Has to be called after the segment naming of the muxing
+   * has been set, if any.
+   *
+   * @param configProvider the config provider the settings are read from
+   * @param muxing the muxing to be sharded
+   */
+  public static void apply(ConfigProvider configProvider, TsMuxing muxing) {
+    if (isEnabled(configProvider)) {
+      muxing.setSegmentNamingTemplate(
+          buildTemplate(configProvider, muxing.getSegmentNaming(), "segment_%number%.ts"));
+      // only one of segment naming and segment naming template may be set
+      muxing.setSegmentNaming(null);
+    }
+  }
+
+  /**
+   * Replaces the segment naming of the muxing with a segment naming template with a random prefix
+   * per segment, if sharding is enabled. Has to be called after the segment naming of the muxing
+   * has been set, if any.
+   *
+   * @param configProvider the config provider the settings are read from
+   * @param muxing the muxing to be sharded
+   */
+  public static void apply(ConfigProvider configProvider, CmafMuxing muxing) {
+    if (isEnabled(configProvider)) {
+      muxing.setSegmentNamingTemplate(
+          buildTemplate(configProvider, muxing.getSegmentNaming(), "segment_%number%.m4s"));
+      // only one of segment naming and segment naming template may be set
+      muxing.setSegmentNaming(null);
+    }
+  }
+
+  private static boolean isEnabled(ConfigProvider configProvider) {
+    return Boolean.parseBoolean(configProvider.getParameterByKey(SHARDING_KEY, "false"));
+  }
+
+  private static String buildTemplate(
+      ConfigProvider configProvider, String segmentNaming, String defaultSegmentNaming) {
+    String value = configProvider.getParameterByKey(PREFIX_LENGTH_KEY, "4");
+    int prefixLength = Integer.parseInt(value.trim());
+    if (prefixLength <= 0) {
+      throw new IllegalArgumentException(
+          PREFIX_LENGTH_KEY + " has to be a positive number, but is " + value);
+    }
+
+    String name = segmentNaming != null ? segmentNaming : defaultSegmentNaming;
+    return String.format(
+        "{segment_rand_chars:%d}_%s", prefixLength, name.replace("%number%", "{number}"));
+  }
+}
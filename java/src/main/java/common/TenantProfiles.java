@@ -0,0 +1,74 @@
+package common;
+
+import com.bitmovin.api.sdk.BitmovinApi;
+import feign.Logger.Level;
+import java.util.HashMap;
+import java.util.Locale;
+import java.util.Map;
+
+/**
+ * This class resolves the credentials of tenant profiles from the configuration and provides an API
+ * client for each profile. It can be used to encode on behalf of several organisations, e.g. by an
+ * agency serving multiple clients.
+ *
+ * <p>A profile named "acme" is configured with the following parameters:
+ *
+ * <ul>
+ *   <li>TENANT_PROFILE_ACME_API_KEY - (optional) The API key used for the profile. Default: the
+ *       value of BITMOVIN_API_KEY
+ *   <li>TENANT_PROFILE_ACME_ORG_ID - (optional) The ID of the organisation the profile encodes in.
+ *       If not set, the organisation of the API key is used
+ * </ul>
+ *
+ * <p>API clients are created once per profile and reused afterwards. Like the API clients of all
+ * other examples, they are created by {@link ApiClientFactory} and share its settings.
+ */
+public class TenantProfiles {
+  private final ConfigProvider configProvider;
+  private final Map<String, BitmovinApi> apiClients = new HashMap<>();
+
+  /** @param configProvider the config provider the profiles are read from */
+  public TenantProfiles(ConfigProvider configProvider) {
+    this.configProvider = configProvider;
+  }
+
+  /**
+   * Returns the API client of the given profile, and creates it on the first call.
+   *
+   * @param profile the name of the profile, e.g. "acme"
+   */
+  public BitmovinApi getApi(String profile) {
+    return apiClients.computeIfAbsent(profile, this::createApi);
+  }
+
+  /**
+   * Returns the ID of the organisation the given profile encodes in, or null if the organisation of
+   * the API key is used.
+   *
+   * @param profile the name of the profile, e.g. "acme"
+   */
+  public String getOrgId(String profile) {
+    return configProvider.getParameterByKey(buildKey(profile, "ORG_ID"), null);
+  }
+
+  private BitmovinApi createApi(String profile) {
+    String apiKey =
+        configProvider.getParameterByKey(
+            buildKey(profile, "API_KEY"), configProvider.getBitmovinApiKey());
+
+    // the organisation of a profile replaces BITMOVIN_TENANT_ORG_ID, and defaults to the API key's
+    BitmovinApi.Builder builder =
+        ApiClientFactory.accountBuilder(configProvider, Level.BASIC).withApiKey(apiKey);
+
+    String orgId = getOrgId(profile);
+    if (orgId != null) {
+      builder.withTenantOrgId(orgId);
+    }
+
+    return builder.build();
+  }
+
+  private static String buildKey(String profile, String suffix) {
+    return String.format("TENANT_PROFILE_%s_%s", profile.toUpperCase(Locale.ROOT), suffix);
+  }
+}
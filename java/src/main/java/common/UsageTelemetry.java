@@ -0,0 +1,79 @@
+package common;
+
+import com.fasterxml.jackson.databind.ObjectMapper;
+import java.io.IOException;
+import java.io.OutputStream;
+import java.net.HttpURLConnection;
+import java.net.URL;
+import java.time.Duration;
+import java.util.LinkedHashMap;
+import java.util.Map;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+
+/**
+ * This class reports anonymous usage statistics of the examples to an endpoint operated by the
+ * user, so platform teams running the examples internally can track which workflows are used and
+ * how they perform. Nothing is reported unless EXAMPLES_TELEMETRY_ENDPOINT is configured.
+ *
+ * <p>For every run, a JSON document like the following is posted to the endpoint:
+ *
+ * <pre>
+ * {"workflow": "FixedBitrateLadder", "outcome": "SUCCESS", "success": true, "durationSeconds": 312}
+ * </pre>
+ *
+ * <p>No configuration values, API keys, resource IDs or host information are included. Failures to
+ * report are logged and never affect the outcome of the example.
+ */
+public class UsageTelemetry {
+  private static final Logger logger = LoggerFactory.getLogger(UsageTelemetry.class);
+
+  private static final int TIMEOUT_MILLIS = 5000;
+
+  private final String endpoint;
+
+  /** @param configProvider the configuration the telemetry endpoint is read from */
+  public UsageTelemetry(ConfigProvider configProvider) {
+    this.endpoint = configProvider.getParameterByKey("EXAMPLES_TELEMETRY_ENDPOINT", null);
+  }
+
+  /**
+   * Posts the usage statistics of a run to the configured endpoint, if any
+   *
+   * @param workflow the name of the example that has been run
+   * @param outcome the outcome of the run
+   * @param duration the time the run took
+   */
+  public void report(String workflow, ExampleLauncher.Outcome outcome, Duration duration) {
+    if (endpoint == null) {
+      return;
+    }
+
+    Map<String, Object> usage = new LinkedHashMap<>();
+    usage.put("workflow", workflow);
+    usage.put("outcome", outcome.name());
+    usage.put("success", outcome == ExampleLauncher.Outcome.SUCCESS);
+    usage.put("durationSeconds", duration.getSeconds());
+
+    try {
+      HttpURLConnection connection = (HttpURLConnection) new URL(endpoint).openConnection();
+      connection.setRequestMethod("POST");
+      connection.setRequestProperty("Content-Type", "application/json");
+      connection.setConnectTimeout(TIMEOUT_MILLIS);
+      connection.setReadTimeout(TIMEOUT_MILLIS);
+      connection.setDoOutput(true);
+
+      try (OutputStream body = connection.getOutputStream()) {
+        new ObjectMapper().writeValue(body, usage);
+      }
+
+      int responseCode = connection.getResponseCode();
+      if (responseCode >= 300) {
+        logger.warn("Telemetry endpoint responded with status {}", responseCode);
+      }
+      connection.disconnect();
+    } catch (IOException e) {
+      logger.warn("Could not report usage statistics to {}: {}", endpoint, e.getMessage());
+    }
+  }
+}
@@ -0,0 +1,254 @@
+package common;
+
+import ch.qos.logback.classic.spi.ILoggingEvent;
+import ch.qos.logback.core.AppenderBase;
+import com.fasterxml.jackson.databind.ObjectMapper;
+import java.io.IOException;
+import java.io.OutputStream;
+import java.net.HttpURLConnection;
+import java.net.URL;
+import java.nio.charset.StandardCharsets;
+import java.time.Duration;
+import java.time.Instant;
+import java.util.LinkedHashMap;
+import java.util.Map;
+import java.util.Properties;
+import java.util.UUID;
+import java.util.concurrent.TimeUnit;
+import org.apache.kafka.clients.producer.KafkaProducer;
+import org.apache.kafka.clients.producer.ProducerConfig;
+import org.apache.kafka.clients.producer.ProducerRecord;
+import org.apache.kafka.common.serialization.StringSerializer;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+
+/**
+ * This class emits the lifecycle of an example run as <a href="https://cloudevents.io">CloudEvents
+ * 1.0</a>, so event-driven platforms can react to the examples without parsing their logs or exit
+ * codes. Nothing is emitted unless EXAMPLES_EVENTS_HTTP_ENDPOINT or
+ * EXAMPLES_EVENTS_KAFKA_BOOTSTRAP_SERVERS is configured.
+ *
+ * <p>The following event types are emitted, all events of a run carrying the same runId in their
+ * data:
+ *
+ * <ul>
+ *   <li>com.bitmovin.examples.workflow.started - The example has been started
+ *   <li>com.bitmovin.examples.workflow.progress - The status or progress of an encoding changed,
+ *       with status and progress in the data
+ *   <li>com.bitmovin.examples.workflow.finished - The example finished successfully
+ *   <li>com.bitmovin.examples.workflow.failed - The example failed, with the outcome of {@link
+ *       ExampleLauncher} and the error message in the data
+ * </ul>
+ *
+ * <p>Events are sent in the structured content mode, i.e. the whole event is a JSON document with
+ * the content type application/cloudevents+json:
+ *
+ * <pre>
+ * {"specversion": "1.0", "id": "...", "source": "/bitmovin-api-sdk-examples/FixedBitrateLadder",
+ *  "type": "com.bitmovin.examples.workflow.progress", "time": "2021-12-01T10:15:30Z",
+ *  "datacontenttype": "application/json", "subject": "FixedBitrateLadder",
+ *  "data": {"runId": "...", "status": "RUNNING", "progress": 42}}
+ * </pre>
+ *
+ * <p>The progress events are derived from the status messages the examples log while polling their
+ * encodings, so every example that waits for an encoding reports its progress without changes.
+ * Failures to emit an event are logged and never affect the outcome of the example.
+ *
+ * <p>The following configuration parameters are evaluated:
+ *
+ * <ul>
+ *   <li>EXAMPLES_EVENTS_HTTP_ENDPOINT - (optional) The URL of an HTTP sink the events are posted to
+ *   <li>EXAMPLES_EVENTS_KAFKA_BOOTSTRAP_SERVERS - (optional) The Kafka brokers the events are
+ *       produced to. Example: kafka-1:9092,kafka-2:9092
+ *   <li>EXAMPLES_EVENTS_KAFKA_TOPIC - (optional) The Kafka topic the events are produced to.
+ *       Default: bitmovin-examples-events
+ * </ul>
+ */
+public class WorkflowEvents implements AutoCloseable {
+  private static final Logger logger = LoggerFactory.getLogger(WorkflowEvents.class);
+
+  private static final String TYPE_PREFIX = "com.bitmovin.examples.workflow.";
+  private static final String CONTENT_TYPE = "application/cloudevents+json";
+  private static final int TIMEOUT_MILLIS = 5000;
+
+  /** The status message logged by the executeEncoding method of the examples */
+  private static final String PROGRESS_MESSAGE = "encoding status is {} (progress: {} %)";
+
+  private final ObjectMapper objectMapper = new ObjectMapper();
+  private final String workflow;
+  private final String runId = UUID.randomUUID().toString();
+  private final String httpEndpoint;
+  private final String kafkaTopic;
+  private final KafkaProducer<String, String> kafkaProducer;
+
+  /**
+   * @param configProvider the configuration the event sinks are read from
+   * @param workflow the name of the example that is run
+   */
+  public WorkflowEvents(ConfigProvider configProvider, String workflow) {
+    this.workflow = workflow;
+    this.httpEndpoint = configProvider.getParameterByKey("EXAMPLES_EVENTS_HTTP_ENDPOINT", null);
+    this.kafkaTopic =
+        configProvider.getParameterByKey(
+            "EXAMPLES_EVENTS_KAFKA_TOPIC", "bitmovin-examples-events");
+
+    String bootstrapServers =
+        configProvider.getParameterByKey("EXAMPLES_EVENTS_KAFKA_BOOTSTRAP_SERVERS", null);
+    if (bootstrapServers != null) {
+      Properties properties = new Properties();
+      properties.put(ProducerConfig.BOOTSTRAP_SERVERS_CONFIG, bootstrapServers);
+      properties.put(ProducerConfig.KEY_SERIALIZER_CLASS_CONFIG, StringSerializer.class.getName());
+      properties.put(
+          ProducerConfig.VALUE_SERIALIZER_CLASS_CONFIG, StringSerializer.class.getName());
+      properties.put(ProducerConfig.MAX_BLOCK_MS_CONFIG, TIMEOUT_MILLIS);
+      this.kafkaProducer = new KafkaProducer<>(properties);
+    } else {
+      this.kafkaProducer = null;
+    }
+  }
+
+  /** Returns whether any event sink is configured */
+  public boolean isEnabled() {
+    return httpEndpoint != null || kafkaProducer != null;
+  }
+
+  public void started() {
+    emit("started", new LinkedHashMap<>());
+  }
+
+  /**
+   * @param status the status of the encoding, e.g. RUNNING
+   * @param progress the progress of the encoding in percent, if known
+   */
+  public void progress(String status, Integer progress) {
+    Map<String, Object> data = new LinkedHashMap<>();
+    data.put("status", status);
+    data.put("progress", progress);
+    emit("progress", data);
+  }
+
+  /** @param duration the time the run took */
+  public void finished(Duration duration) {
+    Map<String, Object> data = new LinkedHashMap<>();
+    data.put("durationSeconds", duration.getSeconds());
+    emit("finished", data);
+  }
+
+  /**
+   * @param outcome the outcome the run failed with
+   * @param message the error message, if any
+   * @param duration the time the run took
+   */
+  public void failed(ExampleLauncher.Outcome outcome, String message, Duration duration) {
+    Map<String, Object> data = new LinkedHashMap<>();
+    data.put("outcome", outcome.name());
+    data.put("message", message);
+    data.put("durationSeconds", duration.getSeconds());
+    emit("failed", data);
+  }
+
+  /**
+   * Creates a logback appender which turns the encoding status messages logged by the examples into
+   * progress events. An event is only emitted if the status or progress changed since the last
+   * one.
+   */
+  public AppenderBase<ILoggingEvent> createProgressAppender() {
+    return new AppenderBase<ILoggingEvent>() {
+      private String lastProgress;
+
+      @Override
+      protected void append(ILoggingEvent event) {
+        Object[] arguments = event.getArgumentArray();
+        if (!PROGRESS_MESSAGE.equalsIgnoreCase(event.getMessage())
+            || arguments == null
+            || arguments.length != 2) {
+          return;
+        }
+
+        String status = String.valueOf(arguments[0]);
+        Integer percent = arguments[1] instanceof Integer ? (Integer) arguments[1] : null;
+        String current = status + "/" + percent;
+        if (!current.equals(lastProgress)) {
+          lastProgress = current;
+          progress(status, percent);
+        }
+      }
+    };
+  }
+
+  @Override
+  public void close() {
+    if (kafkaProducer != null) {
+      kafkaProducer.close(Duration.ofMillis(TIMEOUT_MILLIS));
+    }
+  }
+
+  private void emit(String type, Map<String, Object> data) {
+    if (!isEnabled()) {
+      return;
+    }
+
+    data.put("runId", runId);
+    Map<String, Object> event = new LinkedHashMap<>();
+    event.put("specversion", "1.0");
+    event.put("id", UUID.randomUUID().toString());
+    event.put("source", "/bitmovin-api-sdk-examples/" + workflow);
+    event.put("type", TYPE_PREFIX + type);
+    event.put("time", Instant.now().toString());
+    event.put("datacontenttype", "application/json");
+    event.put("subject", workflow);
+    event.put("data", data);
+
+    String json;
+    try {
+      json = objectMapper.writeValueAsString(event);
+    } catch (IOException e) {
+      logger.warn("Could not serialize {} event: {}", type, e.getMessage());
+      return;
+    }
+
+    if (httpEndpoint != null) {
+      postToHttpEndpoint(json);
+    }
+    if (kafkaProducer != null) {
+      produceToKafka(json);
+    }
+  }
+
+  private void postToHttpEndpoint(String json) {
+    try {
+      HttpURLConnection connection = (HttpURLConnection) new URL(httpEndpoint).openConnection();
+      connection.setRequestMethod("POST");
+      connection.setRequestProperty("Content-Type", CONTENT_TYPE);
+      connection.setConnectTimeout(TIMEOUT_MILLIS);
+      connection.setReadTimeout(TIMEOUT_MILLIS);
+      connection.setDoOutput(true);
+
+      try (OutputStream body = connection.getOutputStream()) {
+        body.write(json.getBytes(StandardCharsets.UTF_8));
+      }
+
+      int responseCode = connection.getResponseCode();
+      if (responseCode >= 300) {
+        logger.warn("Event endpoint responded with status {}", responseCode);
+      }
+      connection.disconnect();
+    } catch (IOException e) {
+      logger.warn("Could not post event to {}: {}", httpEndpoint, e.getMessage());
+    }
+  }
+
+  /**
+   * Produces the event to the Kafka topic, keyed by the run ID so all events of a run end up in the
+   * same partition and keep their order
+   */
+  private void produceToKafka(String json) {
+    ProducerRecord<String, String> record = new ProducerRecord<>(kafkaTopic, runId, json);
+    record.headers().add("content-type", CONTENT_TYPE.getBytes(StandardCharsets.UTF_8));
+    try {
+      kafkaProducer.send(record).get(TIMEOUT_MILLIS, TimeUnit.MILLISECONDS);
+    } catch (Exception e) {
+      logger.warn("Could not produce event to topic {}: {}", kafkaTopic, e.getMessage());
+    }
+  }
+}
@@ -0,0 +1,118 @@
+package common;
+
+import java.io.IOException;
+import java.io.Reader;
+import java.io.UncheckedIOException;
+import java.io.Writer;
+import java.nio.charset.StandardCharsets;
+import java.nio.file.Files;
+import java.nio.file.Path;
+import java.nio.file.StandardCopyOption;
+import java.util.Properties;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+
+/**
+ * This class persists the progress of an example workflow to a state file, so that a crashed
+ * example process can be restarted and continue where it left off, e.g. by polling an encoding that
+ * has already been started instead of creating and starting a new one.
+ *
+ * <p>The state file is a properties file containing the current {@link Phase}, the ID of the
+ * encoding and the IDs of other created resources. It is written after every change.
+ */
+public class WorkflowState {
+  private static final Logger logger = LoggerFactory.getLogger(WorkflowState.class);
+
+  private static final String PHASE_KEY = "phase";
+  private static final String ENCODING_ID_KEY = "encodingId";
+  private static final String RESOURCE_KEY_PREFIX = "resource.";
+
+  /** The phases of a workflow, in the order in which they are passed */
+  public enum Phase {
+    CREATING_RESOURCES,
+    RESOURCES_CREATED,
+    ENCODING_STARTED,
+    ENCODING_FINISHED,
+    MANIFESTS_CREATED
+  }
+
+  private final Path stateFile;
+  private final Properties properties;
+
+  private WorkflowState(Path stateFile, Properties properties) {
+    this.stateFile = stateFile;
+    this.properties = properties;
+  }
+
+  /** Creates a new, empty state, which replaces the given state file once it is modified */
+  public static WorkflowState create(Path stateFile) {
+    return new WorkflowState(stateFile, new Properties());
+  }
+
+  /**
+   * Loads the state from the given state file. If the file does not exist, an empty state is
+   * returned.
+   */
+  public static WorkflowState load(Path stateFile) {
+    Properties properties = new Properties();
+    if (Files.exists(stateFile)) {
+      try (Reader reader = Files.newBufferedReader(stateFile, StandardCharsets.UTF_8)) {
+        properties.load(reader);
+      } catch (IOException e) {
+        throw new UncheckedIOException("Error reading state file: " + stateFile, e);
+      }
+      logger.info("Loaded workflow state from {}", stateFile.toAbsolutePath());
+    }
+
+    return new WorkflowState(stateFile, properties);
+  }
+
+  /** Returns the current phase of the workflow, or null if the workflow has not been started */
+  public Phase getPhase() {
+    String phase = properties.getProperty(PHASE_KEY);
+    return phase != null ? Phase.valueOf(phase) : null;
+  }
+
+  public void setPhase(Phase phase) {
+    properties.setProperty(PHASE_KEY, phase.name());
+    save();
+    logger.info("Workflow reached phase {}", phase);
+  }
+
+  public String getEncodingId() {
+    return properties.getProperty(ENCODING_ID_KEY);
+  }
+
+  public void setEncodingId(String encodingId) {
+    properties.setProperty(ENCODING_ID_KEY, encodingId);
+    save();
+  }
+
+  /** Returns the ID of the created resource with the given name, or null if it does not exist */
+  public String getResourceId(String name) {
+    return properties.getProperty(RESOURCE_KEY_PREFIX + name);
+  }
+
+  /** Records the ID of a created resource under the given name */
+  public void putResourceId(String name, String id) {
+    properties.setProperty(RESOURCE_KEY_PREFIX + name, id);
+    save();
+  }
+
+  /**
+   * Writes the state to a temporary file first, which then replaces the state file. This way the
+   * state file is never left half-written if the process crashes while saving.
+   */
+  private void save() {
+    Path tempFile = stateFile.resolveSibling(stateFile.getFileName() + ".tmp");
+    try {
+      try (Writer writer = Files.newBufferedWriter(tempFile, StandardCharsets.UTF_8)) {
+        properties.store(writer, "Bitmovin example workflow state");
+      }
+      Files.move(
+          tempFile, stateFile, StandardCopyOption.REPLACE_EXISTING, StandardCopyOption.ATOMIC_MOVE);
+    } catch (IOException e) {
+      throw new UncheckedIOException("Error writing state file: " + stateFile, e);
+    }
+  }
+}
@@ -26,9 +26,11 @@ import com.bitmovin.api.sdk.model.StreamInput;
 import com.bitmovin.api.sdk.model.StreamMode;
 import com.bitmovin.api.sdk.model.StreamSelectionMode;
 import com.bitmovin.api.sdk.model.Task;
+import common.ApiClientFactory;
+import common.BudgetTag;
 import common.ConfigProvider;
-import feign.Logger.Level;
-import feign.slf4j.Slf4jLogger;
+import common.EncodingFailedException;
+import common.PreviewTrimming;
 import java.nio.file.Paths;
 import java.util.Arrays;
 import java.util.List;
@@ -45,6 +47,10 @@ import org.slf4j.LoggerFactory;
  *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin API
  *   <li>BITMOVIN_TENANT_ORG_ID - (optional) The ID of the Organisation in which you want to perform
  *       the encoding.
+ *   <li>BITMOVIN_API_BASE_URL - (optional) The base URL of the Bitmovin API, e.g. of a mock server
+ *       or an API gateway. Default: https://api.bitmovin.com/v1
+ *   <li>HTTP_INPUT_HOST - The Hostname or IP address of the HTTP server hosting your input files,
+ *       e.g.: my-storage.biz
  *   <li>INPUT_FILE_1TRACK_2CHANNELS - the path to a file containing a video with a single audio
  *       stereo stream
  *   <li>S3_OUTPUT_BUCKET_NAME - The name of your S3 output bucket. Example: my-bucket-name
@@ -76,12 +82,8 @@ public class AudioChannelManipulation_1_Baseline {
   public static void main(String[] args) throws Exception {
     configProvider = new ConfigProvider(args);
     bitmovinApi =
-        BitmovinApi.builder()
+        ApiClientFactory.builder(configProvider)
             .withApiKey(configProvider.getBitmovinApiKey())
-            // uncomment the following line if you are working with a multi-tenant account
-            // .withTenantOrgId(configProvider.getBitmovinTenantOrgId())
-            .withLogger(
-                new Slf4jLogger(), Level.BASIC) // set the logger and log level for the API client
             .build();
 
     Encoding encoding =
@@ -99,7 +101,7 @@ public class AudioChannelManipulation_1_Baseline {
     AacAudioConfiguration aacConfig = createAacStereoAudioConfig();
 
     String inputFilePath = configProvider.getParameterByKey("INPUT_FILE_1TRACK_2CHANNELS");
-    IngestInputStream ingestInputStream = createIngestInputStream(encoding, input, inputFilePath);
+    InputStream ingestInputStream = createIngestInputStream(encoding, input, inputFilePath);
 
     Stream videoStream = createStream(encoding, ingestInputStream, h264Config);
     Stream audioStream = createStream(encoding, ingestInputStream, aacConfig);
@@ -124,6 +126,7 @@ public class AudioChannelManipulation_1_Baseline {
     Encoding encoding = new Encoding();
     encoding.setName(name);
     encoding.setDescription(description);
+    new BudgetTag(configProvider).applyTo(encoding);
 
     return bitmovinApi.encoding.encodings.create(encoding);
   }
@@ -186,7 +189,8 @@ public class AudioChannelManipulation_1_Baseline {
   }
 
   /**
-   * Creates an IngestInputStream and adds it to an encoding
+   * Creates an IngestInputStream and adds it to an encoding. In preview mode, a trimming input
+   * stream covering the first seconds of the IngestInputStream is returned instead.
    *
    * <p>The IngestInputStream is used to define where a file to read a stream from is located
    *
@@ -197,15 +201,19 @@ public class AudioChannelManipulation_1_Baseline {
    * @param input The input resource providing the input file
    * @param inputPath The path to the input file
    */
-  private static IngestInputStream createIngestInputStream(
+  private static InputStream createIngestInputStream(
       Encoding encoding, Input input, String inputPath) throws BitmovinException {
     IngestInputStream ingestInputStream = new IngestInputStream();
     ingestInputStream.setInputId(input.getId());
     ingestInputStream.setInputPath(inputPath);
     ingestInputStream.setSelectionMode(StreamSelectionMode.AUTO);
 
-    return bitmovinApi.encoding.encodings.inputStreams.ingest.create(
-        encoding.getId(), ingestInputStream);
+    ingestInputStream =
+        bitmovinApi.encoding.encodings.inputStreams.ingest.create(
+            encoding.getId(), ingestInputStream);
+
+    return PreviewTrimming.trimInputStream(
+        bitmovinApi, configProvider, encoding, ingestInputStream);
   }
 
   /**
@@ -355,11 +363,13 @@ public class AudioChannelManipulation_1_Baseline {
       Thread.sleep(5000);
       task = bitmovinApi.encoding.encodings.status(encoding.getId());
       logger.info("Encoding status is {} (progress: {} %)", task.getStatus(), task.getProgress());
-    } while (task.getStatus() != Status.FINISHED && task.getStatus() != Status.ERROR);
+    } while (task.getStatus() != Status.FINISHED
+        && task.getStatus() != Status.ERROR
+        && task.getStatus() != Status.CANCELED);
 
-    if (task.getStatus() == Status.ERROR) {
+    if (task.getStatus() != Status.FINISHED) {
       logTaskErrors(task);
-      throw new RuntimeException("Encoding failed");
+      throw new EncodingFailedException(task.getStatus());
     }
     logger.info("Encoding finished successfully");
   }
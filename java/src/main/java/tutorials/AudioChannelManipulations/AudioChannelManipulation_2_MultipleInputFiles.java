@@ -28,9 +28,11 @@ import com.bitmovin.api.sdk.model.StreamInput;
 import com.bitmovin.api.sdk.model.StreamMode;
 import com.bitmovin.api.sdk.model.StreamSelectionMode;
 import com.bitmovin.api.sdk.model.Task;
+import common.ApiClientFactory;
+import common.BudgetTag;
 import common.ConfigProvider;
-import feign.Logger.Level;
-import feign.slf4j.Slf4jLogger;
+import common.EncodingFailedException;
+import common.PreviewTrimming;
 import java.nio.file.Paths;
 import java.util.Arrays;
 import java.util.List;
@@ -47,6 +49,8 @@ import org.slf4j.LoggerFactory;
  *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin API
  *   <li>BITMOVIN_TENANT_ORG_ID - (optional) The ID of the Organisation in which you want to perform
  *       the encoding.
+ *   <li>BITMOVIN_API_BASE_URL - (optional) The base URL of the Bitmovin API, e.g. of a mock server
+ *       or an API gateway. Default: https://api.bitmovin.com/v1
  *   <li>HTTP_INPUT_HOST - The Hostname or IP address of the HTTP server hosting your input files,
  *       e.g.: my-storage.biz
  *   <li>INPUT_FILE_VIDEO - the path to a file containing a video stream
@@ -81,12 +85,8 @@ public class AudioChannelManipulation_2_MultipleInputFiles {
   public static void main(String[] args) throws Exception {
     configProvider = new ConfigProvider(args);
     bitmovinApi =
-        BitmovinApi.builder()
+        ApiClientFactory.builder(configProvider)
             .withApiKey(configProvider.getBitmovinApiKey())
-            // uncomment the following line if you are working with a multi-tenant account
-            // .withTenantOrgId(configProvider.getBitmovinTenantOrgId())
-            .withLogger(
-                new Slf4jLogger(), Level.BASIC) // set the logger and log level for the API client
             .build();
 
     Encoding encoding =
@@ -109,11 +109,11 @@ public class AudioChannelManipulation_2_MultipleInputFiles {
     String stereoInputFilePath = configProvider.getParameterByKey("INPUT_FILE_1TRACK_2CHANNELS");
     String surroundInputFilePath = configProvider.getParameterByKey("INPUT_FILE_1TRACK_6CHANNELS");
 
-    IngestInputStream videoIngestInputStream =
+    InputStream videoIngestInputStream =
         createIngestInputStream(encoding, input, videoInputFilePath);
-    IngestInputStream stereoIngestInputStream =
+    InputStream stereoIngestInputStream =
         createIngestInputStream(encoding, input, stereoInputFilePath);
-    IngestInputStream surroundIngestInputStream =
+    InputStream surroundIngestInputStream =
         createIngestInputStream(encoding, input, surroundInputFilePath);
 
     Stream videoStream = createStream(encoding, videoIngestInputStream, h264Config);
@@ -145,6 +145,7 @@ public class AudioChannelManipulation_2_MultipleInputFiles {
     encoding.setName(name);
     encoding.setDescription(description);
     encoding.setEncoderVersion("2.60.0");
+    new BudgetTag(configProvider).applyTo(encoding);
 
     return bitmovinApi.encoding.encodings.create(encoding);
   }
@@ -207,7 +208,8 @@ public class AudioChannelManipulation_2_MultipleInputFiles {
   }
 
   /**
-   * Creates an IngestInputStream and adds it to an encoding
+   * Creates an IngestInputStream and adds it to an encoding. In preview mode, a trimming input
+   * stream covering the first seconds of the IngestInputStream is returned instead.
    *
    * <p>The IngestInputStream is used to define where a file to read a stream from is located
    *
@@ -218,15 +220,19 @@ public class AudioChannelManipulation_2_MultipleInputFiles {
    * @param input The input resource providing the input file
    * @param inputPath The path to the input file
    */
-  private static IngestInputStream createIngestInputStream(
+  private static InputStream createIngestInputStream(
       Encoding encoding, Input input, String inputPath) throws BitmovinException {
     IngestInputStream ingestInputStream = new IngestInputStream();
     ingestInputStream.setInputId(input.getId());
     ingestInputStream.setInputPath(inputPath);
     ingestInputStream.setSelectionMode(StreamSelectionMode.AUTO);
 
-    return bitmovinApi.encoding.encodings.inputStreams.ingest.create(
-        encoding.getId(), ingestInputStream);
+    ingestInputStream =
+        bitmovinApi.encoding.encodings.inputStreams.ingest.create(
+            encoding.getId(), ingestInputStream);
+
+    return PreviewTrimming.trimInputStream(
+        bitmovinApi, configProvider, encoding, ingestInputStream);
   }
 
   /**
@@ -391,11 +397,13 @@ public class AudioChannelManipulation_2_MultipleInputFiles {
       Thread.sleep(5000);
       task = bitmovinApi.encoding.encodings.status(encoding.getId());
       logger.info("Encoding status is {} (progress: {} %)", task.getStatus(), task.getProgress());
-    } while (task.getStatus() != Status.FINISHED && task.getStatus() != Status.ERROR);
+    } while (task.getStatus() != Status.FINISHED
+        && task.getStatus() != Status.ERROR
+        && task.getStatus() != Status.CANCELED);
 
-    if (task.getStatus() == Status.ERROR) {
+    if (task.getStatus() != Status.FINISHED) {
       logTaskErrors(task);
-      throw new RuntimeException("Encoding failed");
+      throw new EncodingFailedException(task.getStatus());
     }
     logger.info("Encoding finished successfully");
   }
@@ -32,9 +32,11 @@ import com.bitmovin.api.sdk.model.StreamInput;
 import com.bitmovin.api.sdk.model.StreamMode;
 import com.bitmovin.api.sdk.model.StreamSelectionMode;
 import com.bitmovin.api.sdk.model.Task;
+import common.ApiClientFactory;
+import common.BudgetTag;
 import common.ConfigProvider;
-import feign.Logger.Level;
-import feign.slf4j.Slf4jLogger;
+import common.EncodingFailedException;
+import common.PreviewTrimming;
 import java.nio.file.Paths;
 import java.util.ArrayList;
 import java.util.Arrays;
@@ -51,6 +53,8 @@ import org.slf4j.LoggerFactory;
  *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin API
  *   <li>BITMOVIN_TENANT_ORG_ID - (optional) The ID of the Organisation in which you want to perform
  *       the encoding.
+ *   <li>BITMOVIN_API_BASE_URL - (optional) The base URL of the Bitmovin API, e.g. of a mock server
+ *       or an API gateway. Default: https://api.bitmovin.com/v1
  *   <li>HTTP_INPUT_HOST - The Hostname or IP address of the HTTP server hosting your input files,
  *       e.g.: my-storage.biz
  *   <li>HTTP_INPUT_FILE_PATH - The base path to your input file on the provided HTTP server.
@@ -86,12 +90,8 @@ public class AudioChannelManipulation_4_Downmixing {
   public static void main(String[] args) throws Exception {
     configProvider = new ConfigProvider(args);
     bitmovinApi =
-        BitmovinApi.builder()
+        ApiClientFactory.builder(configProvider)
             .withApiKey(configProvider.getBitmovinApiKey())
-            // uncomment the following line if you are working with a multi-tenant account
-            // .withTenantOrgId(configProvider.getBitmovinTenantOrgId())
-            .withLogger(
-                new Slf4jLogger(), Level.BASIC) // set the logger and log level for the API client
             .build();
 
     Encoding encoding = createEncoding("Audio Mapping - Example 4", "Downmixing 5.1 to 2.0");
@@ -107,10 +107,8 @@ public class AudioChannelManipulation_4_Downmixing {
     AacAudioConfiguration aacConfig = createAacStereoAudioConfig();
 
     String inputFilePath = configProvider.getParameterByKey("INPUT_FILE_1TRACK_6CHANNELS");
-    IngestInputStream videoIngestInputStream =
-        createIngestInputStream(encoding, input, inputFilePath);
-    IngestInputStream audioIngestInputStream =
-        createIngestInputStream(encoding, input, inputFilePath);
+    InputStream videoIngestInputStream = createIngestInputStream(encoding, input, inputFilePath);
+    InputStream audioIngestInputStream = createIngestInputStream(encoding, input, inputFilePath);
 
     DownmixConfig channelConfigLeft = new DownmixConfig(AudioMixChannelType.FRONT_LEFT);
     channelConfigLeft.addSourceChannel(AudioMixSourceChannelType.FRONT_LEFT, 1.0);
@@ -180,6 +178,7 @@ public class AudioChannelManipulation_4_Downmixing {
     Encoding encoding = new Encoding();
     encoding.setName(name);
     encoding.setDescription(description);
+    new BudgetTag(configProvider).applyTo(encoding);
 
     return bitmovinApi.encoding.encodings.create(encoding);
   }
@@ -242,7 +241,8 @@ public class AudioChannelManipulation_4_Downmixing {
   }
 
   /**
-   * Creates an IngestInputStream and adds it to an encoding
+   * Creates an IngestInputStream and adds it to an encoding. In preview mode, a trimming input
+   * stream covering the first seconds of the IngestInputStream is returned instead.
    *
    * <p>The IngestInputStream is used to define where a file to read a stream from is located
    *
@@ -253,15 +253,19 @@ public class AudioChannelManipulation_4_Downmixing {
    * @param input The input resource providing the input file
    * @param inputPath The path to the input file
    */
-  private static IngestInputStream createIngestInputStream(
+  private static InputStream createIngestInputStream(
       Encoding encoding, Input input, String inputPath) throws BitmovinException {
     IngestInputStream ingestInputStream = new IngestInputStream();
     ingestInputStream.setInputId(input.getId());
     ingestInputStream.setInputPath(inputPath);
     ingestInputStream.setSelectionMode(StreamSelectionMode.AUTO);
 
-    return bitmovinApi.encoding.encodings.inputStreams.ingest.create(
-        encoding.getId(), ingestInputStream);
+    ingestInputStream =
+        bitmovinApi.encoding.encodings.inputStreams.ingest.create(
+            encoding.getId(), ingestInputStream);
+
+    return PreviewTrimming.trimInputStream(
+        bitmovinApi, configProvider, encoding, ingestInputStream);
   }
 
   /**
@@ -450,11 +454,13 @@ public class AudioChannelManipulation_4_Downmixing {
       Thread.sleep(5000);
       task = bitmovinApi.encoding.encodings.status(encoding.getId());
       logger.info("Encoding status is {} (progress: {} %)", task.getStatus(), task.getProgress());
-    } while (task.getStatus() != Status.FINISHED && task.getStatus() != Status.ERROR);
+    } while (task.getStatus() != Status.FINISHED
+        && task.getStatus() != Status.ERROR
+        && task.getStatus() != Status.CANCELED);
 
-    if (task.getStatus() == Status.ERROR) {
+    if (task.getStatus() != Status.FINISHED) {
       logTaskErrors(task);
-      throw new RuntimeException("Encoding failed");
+      throw new EncodingFailedException(task.getStatus());
     }
     logger.info("Encoding finished successfully");
   }
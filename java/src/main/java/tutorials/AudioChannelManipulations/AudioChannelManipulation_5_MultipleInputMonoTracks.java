@@ -34,9 +34,11 @@ import com.bitmovin.api.sdk.model.StreamInput;
 import com.bitmovin.api.sdk.model.StreamMode;
 import com.bitmovin.api.sdk.model.StreamSelectionMode;
 import com.bitmovin.api.sdk.model.Task;
+import common.ApiClientFactory;
+import common.BudgetTag;
 import common.ConfigProvider;
-import feign.Logger.Level;
-import feign.slf4j.Slf4jLogger;
+import common.EncodingFailedException;
+import common.PreviewTrimming;
 import java.nio.file.Paths;
 import java.util.ArrayList;
 import java.util.Arrays;
@@ -54,6 +56,8 @@ import org.slf4j.LoggerFactory;
  *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin API
  *   <li>BITMOVIN_TENANT_ORG_ID - (optional) The ID of the Organisation in which you want to perform
  *       the encoding.
+ *   <li>BITMOVIN_API_BASE_URL - (optional) The base URL of the Bitmovin API, e.g. of a mock server
+ *       or an API gateway. Default: https://api.bitmovin.com/v1
  *   <li>HTTP_INPUT_HOST - The Hostname or IP address of the HTTP server hosting your input files,
  *       e.g.: my-storage.biz
  *   <li>INPUT_FILE_8TRACKS_MONO - the path to a file containing a video with multiple mono audio
@@ -87,12 +91,8 @@ public class AudioChannelManipulation_5_MultipleInputMonoTracks {
   public static void main(String[] args) throws Exception {
     configProvider = new ConfigProvider(args);
     bitmovinApi =
-        BitmovinApi.builder()
+        ApiClientFactory.builder(configProvider)
             .withApiKey(configProvider.getBitmovinApiKey())
-            // uncomment the following line if you are working with a multi-tenant account
-            // .withTenantOrgId(configProvider.getBitmovinTenantOrgId())
-            .withLogger(
-                new Slf4jLogger(), Level.BASIC) // set the logger and log level for the API client
             .build();
 
     Encoding encoding =
@@ -112,8 +112,7 @@ public class AudioChannelManipulation_5_MultipleInputMonoTracks {
     DolbyDigitalAudioConfiguration ddConfig = createDdSurroundAudioConfig();
 
     String inputFilePath = configProvider.getParameterByKey("INPUT_FILE_8TRACKS_MONO");
-    IngestInputStream videoIngestInputStream =
-        createIngestInputStream(encoding, input, inputFilePath);
+    InputStream videoIngestInputStream = createIngestInputStream(encoding, input, inputFilePath);
 
     List<ChannelMappingConfig> stereoMap = new ArrayList<>();
     stereoMap.add(new ChannelMappingConfig(AudioMixChannelType.FRONT_LEFT, 0));
@@ -175,6 +174,7 @@ public class AudioChannelManipulation_5_MultipleInputMonoTracks {
     Encoding encoding = new Encoding();
     encoding.setName(name);
     encoding.setDescription(description);
+    new BudgetTag(configProvider).applyTo(encoding);
 
     return bitmovinApi.encoding.encodings.create(encoding);
   }
@@ -237,7 +237,8 @@ public class AudioChannelManipulation_5_MultipleInputMonoTracks {
   }
 
   /**
-   * Creates an IngestInputStream and adds it to an encoding
+   * Creates an IngestInputStream and adds it to an encoding. In preview mode, a trimming input
+   * stream covering the first seconds of the IngestInputStream is returned instead.
    *
    * <p>The IngestInputStream is used to define where a file to read a stream from is located
    *
@@ -248,20 +249,25 @@ public class AudioChannelManipulation_5_MultipleInputMonoTracks {
    * @param input The input resource providing the input file
    * @param inputPath The path to the input file
    */
-  private static IngestInputStream createIngestInputStream(
+  private static InputStream createIngestInputStream(
       Encoding encoding, Input input, String inputPath) throws BitmovinException {
     IngestInputStream ingestInputStream = new IngestInputStream();
     ingestInputStream.setInputId(input.getId());
     ingestInputStream.setInputPath(inputPath);
     ingestInputStream.setSelectionMode(StreamSelectionMode.AUTO);
 
-    return bitmovinApi.encoding.encodings.inputStreams.ingest.create(
-        encoding.getId(), ingestInputStream);
+    ingestInputStream =
+        bitmovinApi.encoding.encodings.inputStreams.ingest.create(
+            encoding.getId(), ingestInputStream);
+
+    return PreviewTrimming.trimInputStream(
+        bitmovinApi, configProvider, encoding, ingestInputStream);
   }
 
   /**
    * Creates an IngestInputStream to select a specific audio strack in the input, and adds it to an
-   * encoding
+   * encoding. In preview mode, a trimming input stream covering the first seconds of the
+   * IngestInputStream is returned instead.
    *
    * <p>The IngestInputStream is used to define where a file to read a stream from is located
    *
@@ -273,7 +279,7 @@ public class AudioChannelManipulation_5_MultipleInputMonoTracks {
    * @param inputPath The path to the input file
    * @param position The relative position of the audio track to select in the input file
    */
-  private static IngestInputStream createIngestInputStreamForAudioTrack(
+  private static InputStream createIngestInputStreamForAudioTrack(
       Encoding encoding, Input input, String inputPath, Integer position) throws BitmovinException {
     IngestInputStream ingestInputStream = new IngestInputStream();
     ingestInputStream.setInputId(input.getId());
@@ -281,8 +287,12 @@ public class AudioChannelManipulation_5_MultipleInputMonoTracks {
     ingestInputStream.setSelectionMode(StreamSelectionMode.AUDIO_RELATIVE);
     ingestInputStream.setPosition(position);
 
-    return bitmovinApi.encoding.encodings.inputStreams.ingest.create(
-        encoding.getId(), ingestInputStream);
+    ingestInputStream =
+        bitmovinApi.encoding.encodings.inputStreams.ingest.create(
+            encoding.getId(), ingestInputStream);
+
+    return PreviewTrimming.trimInputStream(
+        bitmovinApi, configProvider, encoding, ingestInputStream);
   }
 
   /**
@@ -305,7 +315,7 @@ public class AudioChannelManipulation_5_MultipleInputMonoTracks {
     audioMixInputStream.setChannelLayout(channelLayout);
 
     for (ChannelMappingConfig mappingConfig : mappingConfigs) {
-      IngestInputStream audioIngestInputStream =
+      InputStream audioIngestInputStream =
           createIngestInputStreamForAudioTrack(
               encoding, input, inputFilePath, mappingConfig.sourceChannelNumber);
 
@@ -490,11 +500,13 @@ public class AudioChannelManipulation_5_MultipleInputMonoTracks {
       Thread.sleep(5000);
       task = bitmovinApi.encoding.encodings.status(encoding.getId());
       logger.info("Encoding status is {} (progress: {} %)", task.getStatus(), task.getProgress());
-    } while (task.getStatus() != Status.FINISHED && task.getStatus() != Status.ERROR);
+    } while (task.getStatus() != Status.FINISHED
+        && task.getStatus() != Status.ERROR
+        && task.getStatus() != Status.CANCELED);
 
-    if (task.getStatus() == Status.ERROR) {
+    if (task.getStatus() != Status.FINISHED) {
       logTaskErrors(task);
-      throw new RuntimeException("Encoding failed");
+      throw new EncodingFailedException(task.getStatus());
     }
     logger.info("Encoding finished successfully");
   }
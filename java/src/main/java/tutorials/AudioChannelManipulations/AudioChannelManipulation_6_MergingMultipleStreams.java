@@ -32,9 +32,11 @@ import com.bitmovin.api.sdk.model.StreamInput;
 import com.bitmovin.api.sdk.model.StreamMode;
 import com.bitmovin.api.sdk.model.StreamSelectionMode;
 import com.bitmovin.api.sdk.model.Task;
+import common.ApiClientFactory;
+import common.BudgetTag;
 import common.ConfigProvider;
-import feign.Logger.Level;
-import feign.slf4j.Slf4jLogger;
+import common.EncodingFailedException;
+import common.PreviewTrimming;
 import java.nio.file.Paths;
 import java.util.Arrays;
 import java.util.Collections;
@@ -52,6 +54,8 @@ import org.slf4j.LoggerFactory;
  *   <li>BITMOVIN_API_KEY - Your API key for the Bitmovin API
  *   <li>BITMOVIN_TENANT_ORG_ID - (optional) The ID of the Organisation in which you want to perform
  *       the encoding.
+ *   <li>BITMOVIN_API_BASE_URL - (optional) The base URL of the Bitmovin API, e.g. of a mock server
+ *       or an API gateway. Default: https://api.bitmovin.com/v1
  *   <li>HTTP_INPUT_HOST - The Hostname or IP address of the HTTP server hosting your input files,
  *       e.g.: my-storage.biz
  *   <li>INPUT_FILE_2TRACKS_STEREO - the path to a file containing a video with 2 stereo tracks
@@ -84,12 +88,8 @@ public class AudioChannelManipulation_6_MergingMultipleStreams {
   public static void main(String[] args) throws Exception {
     configProvider = new ConfigProvider(args);
     bitmovinApi =
-        BitmovinApi.builder()
+        ApiClientFactory.builder(configProvider)
             .withApiKey(configProvider.getBitmovinApiKey())
-            // uncomment the following line if you are working with a multi-tenant account
-            // .withTenantOrgId(configProvider.getBitmovinTenantOrgId())
-            .withLogger(
-                new Slf4jLogger(), Level.BASIC) // set the logger and log level for the API client
             .build();
 
     Encoding encoding =
@@ -108,12 +108,11 @@ public class AudioChannelManipulation_6_MergingMultipleStreams {
     AacAudioConfiguration aacConfig = createAacStereoAudioConfig();
 
     String inputFilePath = configProvider.getParameterByKey("INPUT_FILE_2TRACKS_STEREO");
-    IngestInputStream videoIngestInputStream =
-        createIngestInputStream(encoding, input, inputFilePath);
+    InputStream videoIngestInputStream = createIngestInputStream(encoding, input, inputFilePath);
 
-    IngestInputStream mainAudioIngestInputStream =
+    InputStream mainAudioIngestInputStream =
         createIngestInputStreamForAudioTrack(encoding, input, inputFilePath, 0);
-    IngestInputStream secondaryAudioIngestInputStream =
+    InputStream secondaryAudioIngestInputStream =
         createIngestInputStreamForAudioTrack(encoding, input, inputFilePath, 1);
 
     AudioMixInputStream secondaryAudioMixInputStream = new AudioMixInputStream();
@@ -178,6 +177,7 @@ public class AudioChannelManipulation_6_MergingMultipleStreams {
     Encoding encoding = new Encoding();
     encoding.setName(name);
     encoding.setDescription(description);
+    new BudgetTag(configProvider).applyTo(encoding);
 
     return bitmovinApi.encoding.encodings.create(encoding);
   }
@@ -240,7 +240,8 @@ public class AudioChannelManipulation_6_MergingMultipleStreams {
   }
 
   /**
-   * Creates an IngestInputStream and adds it to an encoding
+   * Creates an IngestInputStream and adds it to an encoding. In preview mode, a trimming input
+   * stream covering the first seconds of the IngestInputStream is returned instead.
    *
    * <p>The IngestInputStream is used to define where a file to read a stream from is located
    *
@@ -251,20 +252,25 @@ public class AudioChannelManipulation_6_MergingMultipleStreams {
    * @param input The input resource providing the input file
    * @param inputPath The path to the input file
    */
-  private static IngestInputStream createIngestInputStream(
+  private static InputStream createIngestInputStream(
       Encoding encoding, Input input, String inputPath) throws BitmovinException {
     IngestInputStream ingestInputStream = new IngestInputStream();
     ingestInputStream.setInputId(input.getId());
     ingestInputStream.setInputPath(inputPath);
     ingestInputStream.setSelectionMode(StreamSelectionMode.AUTO);
 
-    return bitmovinApi.encoding.encodings.inputStreams.ingest.create(
-        encoding.getId(), ingestInputStream);
+    ingestInputStream =
+        bitmovinApi.encoding.encodings.inputStreams.ingest.create(
+            encoding.getId(), ingestInputStream);
+
+    return PreviewTrimming.trimInputStream(
+        bitmovinApi, configProvider, encoding, ingestInputStream);
   }
 
   /**
    * Creates an IngestInputStream to select a specific audio strack in the input, and adds it to an
-   * encoding
+   * encoding. In preview mode, a trimming input stream covering the first seconds of the
+   * IngestInputStream is returned instead.
    *
    * <p>The IngestInputStream is used to define where a file to read a stream from is located
    *
@@ -276,7 +282,7 @@ public class AudioChannelManipulation_6_MergingMultipleStreams {
    * @param inputPath The path to the input file
    * @param position The relative position of the audio track to select in the input file
    */
-  private static IngestInputStream createIngestInputStreamForAudioTrack(
+  private static InputStream createIngestInputStreamForAudioTrack(
       Encoding encoding, Input input, String inputPath, Integer position) throws BitmovinException {
     IngestInputStream ingestInputStream = new IngestInputStream();
     ingestInputStream.setInputId(input.getId());
@@ -284,8 +290,12 @@ public class AudioChannelManipulation_6_MergingMultipleStreams {
     ingestInputStream.setSelectionMode(StreamSelectionMode.AUDIO_RELATIVE);
     ingestInputStream.setPosition(position);
 
-    return bitmovinApi.encoding.encodings.inputStreams.ingest.create(
-        encoding.getId(), ingestInputStream);
+    ingestInputStream =
+        bitmovinApi.encoding.encodings.inputStreams.ingest.create(
+            encoding.getId(), ingestInputStream);
+
+    return PreviewTrimming.trimInputStream(
+        bitmovinApi, configProvider, encoding, ingestInputStream);
   }
 
   /**
@@ -308,7 +318,7 @@ public class AudioChannelManipulation_6_MergingMultipleStreams {
     audioMixInputStream.setChannelLayout(channelLayout);
 
     for (ChannelMappingConfig mappingConfig : mappingConfigs) {
-      IngestInputStream audioIngestInputStream =
+      InputStream audioIngestInputStream =
           createIngestInputStreamForAudioTrack(
               encoding, input, inputFilePath, mappingConfig.sourceChannelNumber);
 
@@ -481,11 +491,13 @@ public class AudioChannelManipulation_6_MergingMultipleStreams {
       Thread.sleep(5000);
       task = bitmovinApi.encoding.encodings.status(encoding.getId());
       logger.info("Encoding status is {} (progress: {} %)", task.getStatus(), task.getProgress());
-    } while (task.getStatus() != Status.FINISHED && task.getStatus() != Status.ERROR);
+    } while (task.getStatus() != Status.FINISHED
+        && task.getStatus() != Status.ERROR
+        && task.getStatus() != Status.CANCELED);
 
-    if (task.getStatus() == Status.ERROR) {
+    if (task.getStatus() != Status.FINISHED) {
       logTaskErrors(task);
-      throw new RuntimeException("Encoding failed");
+      throw new EncodingFailedException(task.getStatus());
     }
     logger.info("Encoding finished successfully");
   }
@@ -0,0 +1,86 @@
+package common;
+
+import static org.junit.Assert.assertEquals;
+import static org.junit.Assert.assertNotNull;
+import static org.junit.Assert.assertTrue;
+
+import common.CommandRegistry.Command;
+import java.io.IOException;
+import java.nio.charset.StandardCharsets;
+import java.nio.file.Files;
+import java.nio.file.Path;
+import java.nio.file.Paths;
+import java.util.Arrays;
+import java.util.List;
+import java.util.Set;
+import java.util.TreeSet;
+import java.util.stream.Collectors;
+import java.util.stream.Stream;
+import org.junit.Test;
+
+public class CommandRegistryTest {
+  private static final Path SOURCES = Paths.get("src", "main", "java");
+
+  /** The classes with a main method which are not examples or tools themselves */
+  private static final List<String> LAUNCHERS =
+      Arrays.asList("common.ExampleLauncher", "common.ExamplesCli");
+
+  @Test
+  public void registersAllExamplesAndTools() throws IOException {
+    CommandRegistry registry = CommandRegistry.load();
+
+    Set<String> unregistered = new TreeSet<>();
+    for (String className : findClassesWithMainMethod()) {
+      if (!LAUNCHERS.contains(className) && registry.getCommand(className) == null) {
+        unregistered.add(className);
+      }
+    }
+
+    assertEquals("Classes missing in commands.properties", new TreeSet<>(), unregistered);
+  }
+
+  @Test
+  public void assignsAllCommandsToAGroup() {
+    for (Command command : CommandRegistry.load().getCommands()) {
+      assertTrue(
+          command.getName() + " has an unknown group " + command.getGroup(),
+          CommandRegistry.GROUPS.contains(command.getGroup()));
+      assertNotNull(command.getName() + " has no summary", command.getSummary());
+    }
+  }
+
+  @Test
+  public void findsCommandsCaseInsensitively() {
+    CommandRegistry registry = CommandRegistry.load();
+
+    assertEquals("HealthCheck", registry.getCommand("healthcheck").getName());
+    assertEquals(
+        "tutorials.SrtLiveEncoding", registry.getCommand("tutorials.srtliveencoding").getName());
+  }
+
+  private static List<String> findClassesWithMainMethod() throws IOException {
+    try (Stream<Path> files = Files.walk(SOURCES)) {
+      return files
+          .filter(file -> file.toString().endsWith(".java"))
+          .filter(CommandRegistryTest::hasMainMethod)
+          .map(CommandRegistryTest::toClassName)
+          .collect(Collectors.toList());
+    }
+  }
+
+  private static boolean hasMainMethod(Path file) {
+    try {
+      return new String(Files.readAllBytes(file), StandardCharsets.UTF_8)
+          .contains("public static void main(String[] args)");
+    } catch (IOException e) {
+      throw new RuntimeException("Error reading " + file, e);
+    }
+  }
+
+  private static String toClassName(Path file) {
+    String relativePath = SOURCES.relativize(file).toString();
+    return relativePath
+        .substring(0, relativePath.length() - ".java".length())
+        .replace(file.getFileSystem().getSeparator(), ".");
+  }
+}
@@ -0,0 +1,87 @@
+package common;
+
+import static org.junit.Assert.assertEquals;
+import static org.junit.Assert.assertTrue;
+import static org.junit.Assert.fail;
+
+import org.junit.Test;
+
+public class DrmKeyMaterialTest {
+  private static final String KID = "08eecef4b026deec395234d94218273d";
+  private static final String OTHER_KID = "00000000000000000000000000000000";
+
+  @Test
+  public void buildsPsshWithKeyId() {
+    assertEquals("EhAI7s70sCbe7DlSNNlCGCc9", DrmKeyMaterial.buildWidevinePssh(KID, null));
+  }
+
+  @Test
+  public void buildsPsshWithKeyIdAndContentId() {
+    assertEquals(
+        "EhAI7s70sCbe7DlSNNlCGCc9IgtteS1tb3ZpZS00Mg==",
+        DrmKeyMaterial.buildWidevinePssh(KID, "my-movie-42"));
+  }
+
+  @Test
+  public void validatesBuiltPssh() {
+    DrmKeyMaterial.validateWidevinePssh(
+        "DRM_WIDEVINE_PSSH", DrmKeyMaterial.buildWidevinePssh(KID, "my-movie-42"), KID);
+    DrmKeyMaterial.validateWidevinePssh(
+        "DRM_WIDEVINE_PSSH", DrmKeyMaterial.buildWidevinePssh(KID, null), KID.toUpperCase());
+  }
+
+  @Test
+  public void skipsVarintFields() {
+    // algorithm = 1, followed by a key ID
+    DrmKeyMaterial.validateWidevinePssh(
+        "DRM_WIDEVINE_PSSH", "CAESEAAAAAAAAAAAAAAAAAAAAAA=", OTHER_KID);
+  }
+
+  @Test
+  public void acceptsPaddedVarints() {
+    // the tag and length of the key ID are padded with continuation bytes
+    DrmKeyMaterial.validateWidevinePssh("DRM_WIDEVINE_PSSH", "kgCQgAAI7s70sCbe7DlSNNlCGCc9", KID);
+    // algorithm = 1 padded to three bytes, followed by the key ID
+    DrmKeyMaterial.validateWidevinePssh(
+        "DRM_WIDEVINE_PSSH", "CIGAABIQCO7O9LAm3uw5UjTZQhgnPQ==", KID);
+  }
+
+  @Test
+  public void rejectsPsshOfOtherKeyId() {
+    assertInvalid(
+        DrmKeyMaterial.buildWidevinePssh(OTHER_KID, null),
+        "keys.csv line 3, column pssh references the key IDs [" + OTHER_KID + "]");
+  }
+
+  @Test
+  public void rejectsTruncatedPssh() {
+    // the key ID field announces 16 bytes, but contains only 2
+    assertInvalid("EhAI7g==", "keys.csv line 3, column pssh is truncated");
+  }
+
+  @Test
+  public void rejectsInvalidBase64() {
+    assertInvalid("not base64!", "keys.csv line 3, column pssh is not valid Base64");
+  }
+
+  @Test
+  public void namesTheSourceOfInvalidHexValues() {
+    try {
+      DrmKeyMaterial.parseHex("keys.csv line 3, column kid", "08eecef4");
+      fail("Expected an IllegalArgumentException");
+    } catch (IllegalArgumentException e) {
+      assertEquals(
+          "keys.csv line 3, column kid has to be 32 hexadecimal characters, but is 08eecef4",
+          e.getMessage());
+    }
+  }
+
+  private static void assertInvalid(String pssh, String expectedMessage) {
+    try {
+      DrmKeyMaterial.validateWidevinePssh("keys.csv line 3, column pssh", pssh, KID);
+      fail("Expected an IllegalArgumentException");
+    } catch (IllegalArgumentException e) {
+      assertTrue(e.getMessage(), e.getMessage().startsWith(expectedMessage));
+    }
+  }
+}
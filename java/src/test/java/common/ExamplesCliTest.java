@@ -0,0 +1,87 @@
+package common;
+
+import static org.junit.Assert.assertEquals;
+import static org.junit.Assert.assertFalse;
+import static org.junit.Assert.assertNull;
+import static org.junit.Assert.assertTrue;
+import static org.junit.Assert.fail;
+
+import common.ExamplesCli.Options;
+import java.util.Arrays;
+import java.util.Collections;
+import org.junit.Test;
+
+public class ExamplesCliTest {
+
+  @Test
+  public void parsesFlagsParametersAndPositionals() {
+    Options options =
+        Options.parse(
+            new String[] {
+              "--quiet",
+              "encode",
+              "--config",
+              "staging.properties",
+              "FixedBitrateLadder",
+              "--tenant=my-org-id",
+              "HTTP_INPUT_HOST=my-storage.biz",
+              "--dry-run"
+            });
+
+    assertEquals(Arrays.asList("encode", "FixedBitrateLadder"), options.positionals);
+    assertEquals(Collections.singletonList("HTTP_INPUT_HOST=my-storage.biz"), options.parameters);
+    assertEquals("staging.properties", options.configFile);
+    assertEquals("my-org-id", options.tenant);
+    assertTrue(options.quiet);
+    assertTrue(options.dryRun);
+    assertFalse(options.printEffectiveConfig);
+    assertFalse(options.help);
+    assertTrue(options.commandFlags.isEmpty());
+  }
+
+  @Test
+  public void passesUnknownFlagsToTheCommand() {
+    Options options =
+        Options.parse(
+            new String[] {"encode", "ResumableEncoding", "--resume", "--quiet", "--retry-failed"});
+
+    assertEquals(Arrays.asList("encode", "ResumableEncoding"), options.positionals);
+    assertEquals(Arrays.asList("--resume", "--retry-failed"), options.commandFlags);
+    assertTrue(options.quiet);
+  }
+
+  @Test
+  public void parsesHelpFlags() {
+    assertTrue(Options.parse(new String[] {"encode", "--help"}).help);
+    assertTrue(Options.parse(new String[] {"encode", "-h"}).help);
+  }
+
+  @Test
+  public void keepsEqualsSignsInParameterValues() {
+    Options options = Options.parse(new String[] {"--config=a=b.properties", "KEY=x=y"});
+
+    assertEquals("a=b.properties", options.configFile);
+    assertEquals(Collections.singletonList("KEY=x=y"), options.parameters);
+  }
+
+  @Test
+  public void rejectsFlagsWithoutValue() {
+    try {
+      Options.parse(new String[] {"encode", "FixedBitrateLadder", "--config"});
+      fail("Expected an IllegalArgumentException");
+    } catch (IllegalArgumentException e) {
+      assertEquals("Flag --config requires a value", e.getMessage());
+    }
+  }
+
+  @Test
+  public void returnsEmptyOptionsWithoutArguments() {
+    Options options = Options.parse(new String[0]);
+
+    assertTrue(options.positionals.isEmpty());
+    assertTrue(options.parameters.isEmpty());
+    assertTrue(options.commandFlags.isEmpty());
+    assertNull(options.configFile);
+    assertNull(options.tenant);
+  }
+}